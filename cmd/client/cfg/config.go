@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"github.com/r4start/goph-keeper/internal/client"
+	"github.com/r4start/goph-keeper/internal/tracing"
 )
 
 type AuthorizationData struct {
@@ -12,11 +13,21 @@ type AuthorizationData struct {
 	RefreshToken string `json:"refresh_token"`
 }
 
+// Tracing selects an exporter of client spans, see tracing.Config.
+type Tracing struct {
+	Exporter string `json:"exporter"`
+	Endpoint string `json:"endpoint,omitempty"`
+	Insecure bool   `json:"insecure,omitempty"`
+	FilePath string `json:"file,omitempty"`
+}
+
 type Config struct {
-	Server        client.ServerEndpoint `json:"server"`
-	StoragePath   string                `json:"storage_path"`
-	SyncDirectory string                `json:"sync_dir"`
-	filePath      string
+	Server         client.ServerEndpoint `json:"server"`
+	StoragePath    string                `json:"storage_path"`
+	SyncDirectory  string                `json:"sync_dir"`
+	ChunkedUploads bool                  `json:"chunked_uploads,omitempty"`
+	Tracing        *Tracing              `json:"tracing,omitempty"`
+	filePath       string
 }
 
 func NewConfigFromFile(filePath string) (*Config, error) {
@@ -43,6 +54,18 @@ func NewConfig() *Config {
 	}
 }
 
+// TracingConfig returns tracing settings, tracing is off when they are missing.
+func (c *Config) TracingConfig() *tracing.Config {
+	cfg := &tracing.Config{ServiceName: "gkcli"}
+	if c.Tracing != nil {
+		cfg.Exporter = c.Tracing.Exporter
+		cfg.Endpoint = c.Tracing.Endpoint
+		cfg.Insecure = c.Tracing.Insecure
+		cfg.FilePath = c.Tracing.FilePath
+	}
+	return cfg
+}
+
 func (c *Config) Save() error {
 	data, err := json.Marshal(c)
 	if err != nil {
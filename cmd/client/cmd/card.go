@@ -6,7 +6,6 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/r4start/goph-keeper/cmd/client/cfg"
-	"github.com/r4start/goph-keeper/internal/client"
 	"github.com/r4start/goph-keeper/internal/client/grpc"
 	"github.com/r4start/goph-keeper/internal/client/storage"
 )
@@ -93,7 +92,7 @@ func (s *CardCommand) run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	uploader := client.NewUploader(c, s.storage, s.config.SyncDirectory)
+	uploader := newUploader(c, s.config, s.storage)
 	err = uploader.UploadCard(context.Background(), storage.CardData{
 		Name:         name,
 		Number:       number,
@@ -7,7 +7,6 @@ import (
 
 	"github.com/r4start/goph-keeper/cmd/client/cfg"
 
-	"github.com/r4start/goph-keeper/internal/client"
 	"github.com/r4start/goph-keeper/internal/client/grpc"
 	"github.com/r4start/goph-keeper/internal/client/storage"
 )
@@ -41,7 +40,7 @@ func (s *StoreFilesCommand) run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	uploader := client.NewUploader(c, s.storage, s.config.SyncDirectory)
+	uploader := newUploader(c, s.config, s.storage)
 	return uploader.UploadFiles(context.Background(), args)
 
 }
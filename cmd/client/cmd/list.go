@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/spf13/cobra"
@@ -11,6 +12,8 @@ import (
 	"github.com/alexeyco/simpletable"
 
 	"github.com/r4start/goph-keeper/cmd/client/cfg"
+	"github.com/r4start/goph-keeper/internal/client"
+	"github.com/r4start/goph-keeper/internal/client/grpc"
 	"github.com/r4start/goph-keeper/internal/client/storage"
 )
 
@@ -31,17 +34,25 @@ func NewListCommand(c *cfg.Config, storage storage.Storage) (*ListCommand, error
 	}
 
 	self.RunE = self.run
+	self.Flags().BoolP(CmdFlagRemote, "r", false, "List resources stored on a server.")
 	return self, nil
 }
 
 func (s *ListCommand) run(cmd *cobra.Command, args []string) error {
+	remote, err := cmd.Flags().GetBool(CmdFlagRemote)
+	if err != nil {
+		return err
+	}
+	if remote {
+		return s.listRemote()
+	}
+
 	var (
 		ctx            = context.Background()
 		localResources = make(chan *simpletable.Table)
 		errCh          = make(chan error)
 		exit           = make(chan any)
 		wg             sync.WaitGroup
-		err            error
 	)
 
 	wg.Add(3)
@@ -176,3 +187,46 @@ outerloop:
 
 	return nil
 }
+
+func (s *ListCommand) listRemote() error {
+	ctx := context.Background()
+	c, err := grpc.NewGrpcClient(&s.config.Server)
+	if err != nil {
+		return err
+	}
+
+	resources, err := client.NewRemoteLister(c, s.storage).List(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(resources) == 0 {
+		return nil
+	}
+
+	table := simpletable.New()
+	table.Header = &simpletable.Header{
+		Cells: []*simpletable.Cell{
+			{Align: simpletable.AlignCenter, Text: "#"},
+			{Align: simpletable.AlignCenter, Text: "ID"},
+			{Align: simpletable.AlignCenter, Text: "TYPE"},
+			{Align: simpletable.AlignCenter, Text: "NAME"},
+			{Align: simpletable.AlignCenter, Text: "SIZE"},
+			{Align: simpletable.AlignCenter, Text: "UPDATED"},
+		},
+	}
+	for i, e := range resources {
+		row := []*simpletable.Cell{
+			{Align: simpletable.AlignLeft, Text: fmt.Sprintf("%d", i+1)},
+			{Align: simpletable.AlignLeft, Text: e.ID},
+			{Align: simpletable.AlignLeft, Text: client.ResourceKind(e.Type)},
+			{Align: simpletable.AlignLeft, Text: e.Name},
+			{Align: simpletable.AlignRight, Text: fmt.Sprintf("%d", e.Size)},
+			{Align: simpletable.AlignLeft, Text: e.UpdatedAt.Local().Format(time.RFC3339)},
+		}
+		table.Body.Cells = append(table.Body.Cells, row)
+	}
+	table.Println()
+
+	return nil
+}
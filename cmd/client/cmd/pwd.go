@@ -6,7 +6,6 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/r4start/goph-keeper/cmd/client/cfg"
-	"github.com/r4start/goph-keeper/internal/client"
 	"github.com/r4start/goph-keeper/internal/client/grpc"
 	"github.com/r4start/goph-keeper/internal/client/storage"
 )
@@ -82,7 +81,7 @@ func (s *PwdCommand) run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	uploader := client.NewUploader(c, s.storage, s.config.SyncDirectory)
+	uploader := newUploader(c, s.config, s.storage)
 	err = uploader.UploadCredentials(context.Background(), storage.CredentialData{
 		Username:    username,
 		Password:    pwd,
@@ -8,6 +8,7 @@ const (
 	CmdFlagLogin          = "login"
 	CmdFlagPassword       = "password"
 	CmdFlagMasterPassword = "master-password"
+	CmdFlagRemote         = "remote"
 )
 
 type RootCommand struct {
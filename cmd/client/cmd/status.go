@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/r4start/goph-keeper/cmd/client/cfg"
+	"github.com/r4start/goph-keeper/internal/client"
+	"github.com/r4start/goph-keeper/internal/client/grpc"
+	"github.com/r4start/goph-keeper/internal/client/storage"
+)
+
+type StatusCommand struct {
+	*cobra.Command
+	config  *cfg.Config
+	storage storage.Storage
+}
+
+func NewStatusCommand(c *cfg.Config, storage storage.Storage) (*StatusCommand, error) {
+	self := &StatusCommand{
+		Command: &cobra.Command{
+			Use:   "status",
+			Short: "Show storage usage, quota and capabilities of a server.",
+		},
+		config:  c,
+		storage: storage,
+	}
+
+	self.RunE = self.run
+	return self, nil
+}
+
+func (s *StatusCommand) run(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	c, err := grpc.NewGrpcClient(&s.config.Server)
+	if err != nil {
+		return err
+	}
+
+	status, err := client.NewStatusReporter(c, s.storage).Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("User:  %s\n", status.UserID)
+	fmt.Printf("Bytes: %s\n", formatUsage(status.Usage.Bytes, status.Usage.MaxBytes))
+	fmt.Printf("Items: %s\n", formatUsage(status.Usage.Items, status.Usage.MaxItems))
+	fmt.Printf("Server protocol: %d\n", status.Server.ProtocolVersion)
+	fmt.Printf("Server features: %s\n", strings.Join(status.Server.Features, ", "))
+	return nil
+}
+
+func formatUsage(used, limit uint64) string {
+	if limit == 0 {
+		return fmt.Sprintf("%d (unlimited)", used)
+	}
+	return fmt.Sprintf("%d of %d (%.1f%%)", used, limit, float64(used)*100/float64(limit))
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/r4start/goph-keeper/cmd/client/cfg"
 
+	"github.com/r4start/goph-keeper/internal/client"
 	"github.com/r4start/goph-keeper/internal/client/storage"
 )
 
@@ -45,3 +46,11 @@ func NewStoreCommand(c *cfg.Config, storage storage.Storage) (*StoreCommand, err
 
 	return self, nil
 }
+
+func newUploader(c client.Client, config *cfg.Config, storage storage.Storage) *client.Uploader {
+	opts := make([]client.UploaderOption, 0)
+	if config.ChunkedUploads {
+		opts = append(opts, client.WithChunkedUploads())
+	}
+	return client.NewUploader(c, storage, config.SyncDirectory, opts...)
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alexeyco/simpletable"
+	"github.com/spf13/cobra"
+
+	"github.com/r4start/goph-keeper/cmd/client/cfg"
+	"github.com/r4start/goph-keeper/internal/client"
+	"github.com/r4start/goph-keeper/internal/client/grpc"
+	"github.com/r4start/goph-keeper/internal/client/storage"
+)
+
+type TrashCommand struct {
+	*cobra.Command
+	config  *cfg.Config
+	storage storage.Storage
+}
+
+func NewTrashCommand(c *cfg.Config, storage storage.Storage) (*TrashCommand, error) {
+	self := &TrashCommand{
+		Command: &cobra.Command{
+			Use:   "trash",
+			Short: "Manage deleted resources which can still be restored.",
+		},
+		config:  c,
+		storage: storage,
+	}
+
+	self.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List deleted resources.",
+		RunE:  self.list,
+	})
+	self.AddCommand(&cobra.Command{
+		Use:   "restore",
+		Short: "Restore deleted resources. They are downloaded by the next sync.",
+		RunE:  self.restore,
+	})
+	self.AddCommand(&cobra.Command{
+		Use:   "empty",
+		Short: "Remove deleted resources for good.",
+		RunE:  self.empty,
+	})
+
+	return self, nil
+}
+
+func (t *TrashCommand) list(cmd *cobra.Command, args []string) error {
+	trash, err := t.trash()
+	if err != nil {
+		return err
+	}
+
+	resources, err := trash.List(context.Background())
+	if err != nil {
+		return err
+	}
+
+	if len(resources) == 0 {
+		return nil
+	}
+
+	table := simpletable.New()
+	table.Header = &simpletable.Header{
+		Cells: []*simpletable.Cell{
+			{Align: simpletable.AlignCenter, Text: "#"},
+			{Align: simpletable.AlignCenter, Text: "ID"},
+			{Align: simpletable.AlignCenter, Text: "TYPE"},
+			{Align: simpletable.AlignCenter, Text: "NAME"},
+			{Align: simpletable.AlignCenter, Text: "DELETED"},
+			{Align: simpletable.AlignCenter, Text: "PURGE AT"},
+		},
+	}
+	for i, e := range resources {
+		row := []*simpletable.Cell{
+			{Align: simpletable.AlignLeft, Text: fmt.Sprintf("%d", i+1)},
+			{Align: simpletable.AlignLeft, Text: e.ID},
+			{Align: simpletable.AlignLeft, Text: client.ResourceKind(e.Type)},
+			{Align: simpletable.AlignLeft, Text: e.Name},
+			{Align: simpletable.AlignLeft, Text: e.UpdatedAt.Local().Format(time.RFC3339)},
+			{Align: simpletable.AlignLeft, Text: e.PurgeAt.Local().Format(time.RFC3339)},
+		}
+		table.Body.Cells = append(table.Body.Cells, row)
+	}
+	table.Println()
+
+	return nil
+}
+
+func (t *TrashCommand) restore(cmd *cobra.Command, args []string) error {
+	trash, err := t.trash()
+	if err != nil {
+		return err
+	}
+	return trash.Restore(context.Background(), args)
+}
+
+func (t *TrashCommand) empty(cmd *cobra.Command, args []string) error {
+	trash, err := t.trash()
+	if err != nil {
+		return err
+	}
+
+	purged, err := trash.Empty(context.Background())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Removed %d resources\n", purged)
+	return nil
+}
+
+func (t *TrashCommand) trash() (*client.Trash, error) {
+	c, err := grpc.NewGrpcClient(&t.config.Server)
+	if err != nil {
+		return nil, err
+	}
+	return client.NewTrash(c, t.storage), nil
+}
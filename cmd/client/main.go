@@ -1,14 +1,19 @@
 package main
 
 import (
+	"context"
 	"embed"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/r4start/goph-keeper/cmd/client/cfg"
 	"github.com/r4start/goph-keeper/cmd/client/cmd"
+	"github.com/r4start/goph-keeper/internal/client"
 	"github.com/r4start/goph-keeper/internal/client/storage"
+	"github.com/r4start/goph-keeper/internal/tracing"
 )
 
 //go:generate sh -c "git branch --show-current > branch.txt"
@@ -19,6 +24,8 @@ const (
 	_cfgDirName         = ".gophkeeper"
 	_cfgFileName        = "cfg.json"
 	_cfgStorageFileName = "main.db"
+
+	_tracingShutdownTimeout = 5 * time.Second
 )
 
 //go:embed *
@@ -26,17 +33,44 @@ var buildInfo embed.FS
 
 func main() {
 	if err := errMain(); err != nil {
-		fmt.Println(err)
+		fmt.Println(describeError(err))
 		os.Exit(1)
 	}
 }
 
-func errMain() error {
+// describeError adds a hint to failures a user can fix.
+func describeError(err error) string {
+	switch {
+	case errors.Is(err, client.ErrInvalidCredentials):
+		return fmt.Sprintf("%v: check the login and the password", err)
+	case errors.Is(err, client.ErrUnauthenticated):
+		return fmt.Sprintf("%v: authorize again with the auth command", err)
+	case errors.Is(err, client.ErrAlreadyExists):
+		return fmt.Sprintf("%v: choose another login", err)
+	case errors.Is(err, client.ErrQuotaExceeded):
+		return fmt.Sprintf("%v: delete or purge unused resources, see the status command", err)
+	}
+	return err.Error()
+}
+
+func errMain() (err error) {
 	c, err := prepareConfig()
 	if err != nil {
 		return err
 	}
 
+	shutdownTracing, err := tracing.Setup(context.Background(), c.TracingConfig())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), _tracingShutdownTimeout)
+		defer cancel()
+		if e := shutdownTracing(ctx); e != nil && err == nil {
+			err = e
+		}
+	}()
+
 	ls, err := storage.NewLocalStorage(c.StoragePath)
 	if err != nil {
 		return err
@@ -72,6 +106,16 @@ func errMain() error {
 		return err
 	}
 
+	trashCmd, err := cmd.NewTrashCommand(c, ls)
+	if err != nil {
+		return err
+	}
+
+	statusCmd, err := cmd.NewStatusCommand(c, ls)
+	if err != nil {
+		return err
+	}
+
 	rootCmd := cmd.NewRootCommand()
 	rootCmd.AddCommand(registerCmd.Command)
 	rootCmd.AddCommand(authCmd.Command)
@@ -79,6 +123,8 @@ func errMain() error {
 	rootCmd.AddCommand(syncCmd.Command)
 	rootCmd.AddCommand(delCmd.Command)
 	rootCmd.AddCommand(listCmd.Command)
+	rootCmd.AddCommand(trashCmd.Command)
+	rootCmd.AddCommand(statusCmd.Command)
 
 	rootCmd.Version = generateVersion()
 
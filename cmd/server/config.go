@@ -0,0 +1,315 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/heetch/confita"
+	"github.com/heetch/confita/backend"
+	"github.com/heetch/confita/backend/env"
+	"github.com/heetch/confita/backend/file"
+	"github.com/heetch/confita/backend/flags"
+	"go.uber.org/zap"
+
+	"github.com/r4start/goph-keeper/internal/server/app"
+	gsrv "github.com/r4start/goph-keeper/internal/server/grpc"
+	"github.com/r4start/goph-keeper/internal/server/keyring"
+	"github.com/r4start/goph-keeper/internal/server/listener"
+	"github.com/r4start/goph-keeper/internal/server/reload"
+	"github.com/r4start/goph-keeper/internal/server/storage"
+)
+
+const _configFileKey = "config_file"
+
+type config struct {
+	ConfigFile               string  `config:"config_file" yaml:"-" toml:"-"`
+	Dev                      bool    `config:"dev" yaml:"dev" toml:"dev"`
+	DatabaseConnectionString string  `config:"db_dsn" yaml:"db_dsn" toml:"db_dsn"`
+	DatabaseOperationTimeout uint32  `config:"db_timeout" yaml:"db_timeout" toml:"db_timeout"`
+	TokenSignKeyFilePath     string  `config:"token_key" yaml:"token_key" toml:"token_key"`
+	Listeners                string  `config:"listeners" yaml:"listeners" toml:"listeners"`
+	GrpcServerAddress        string  `config:"grpc_server_address" yaml:"grpc_server_address" toml:"grpc_server_address"`
+	GrpcServerBasePort       uint16  `config:"grpc_server_base_port" yaml:"grpc_server_base_port" toml:"grpc_server_base_port"`
+	GrpcServerRecvSize       int     `config:"grpc_server_recv_size" yaml:"grpc_server_recv_size" toml:"grpc_server_recv_size"`
+	GrpcServerSendSize       int     `config:"grpc_server_send_size" yaml:"grpc_server_send_size" toml:"grpc_server_send_size"`
+	HTTPGatewayAddress       string  `config:"http_gateway_address" yaml:"http_gateway_address" toml:"http_gateway_address"`
+	MetricsAddress           string  `config:"metrics_address" yaml:"metrics_address" toml:"metrics_address"`
+	TraceExporter            string  `config:"trace_exporter" yaml:"trace_exporter" toml:"trace_exporter"`
+	TraceEndpoint            string  `config:"trace_endpoint" yaml:"trace_endpoint" toml:"trace_endpoint"`
+	TraceInsecure            bool    `config:"trace_insecure" yaml:"trace_insecure" toml:"trace_insecure"`
+	TraceFilePath            string  `config:"trace_file" yaml:"trace_file" toml:"trace_file"`
+	TraceSampleRatio         float64 `config:"trace_sample_ratio" yaml:"trace_sample_ratio" toml:"trace_sample_ratio"`
+	ServeTLS                 bool    `config:"use_tls" yaml:"use_tls" toml:"use_tls"`
+	TLSKeyFilePath           string  `config:"key_file" yaml:"key_file" toml:"key_file"`
+	TLSCrtFilePath           string  `config:"crt_file" yaml:"crt_file" toml:"crt_file"`
+	RPSLimit                 uint32  `config:"rps_limit" yaml:"rps_limit" toml:"rps_limit"`
+	ListMaxPageSize          uint32  `config:"list_max_page_size" yaml:"list_max_page_size" toml:"list_max_page_size"`
+	UploadTTL                uint32  `config:"upload_ttl" yaml:"upload_ttl" toml:"upload_ttl"`
+	BlobStore                string  `config:"blob_store" yaml:"blob_store" toml:"blob_store"`
+	BlobStorePath            string  `config:"blob_store_path" yaml:"blob_store_path" toml:"blob_store_path"`
+	S3Endpoint               string  `config:"s3_endpoint" yaml:"s3_endpoint" toml:"s3_endpoint"`
+	S3Region                 string  `config:"s3_region" yaml:"s3_region" toml:"s3_region"`
+	S3Bucket                 string  `config:"s3_bucket" yaml:"s3_bucket" toml:"s3_bucket"`
+	S3Prefix                 string  `config:"s3_prefix" yaml:"s3_prefix" toml:"s3_prefix"`
+	S3AccessKeyID            string  `config:"s3_access_key_id" yaml:"s3_access_key_id" toml:"s3_access_key_id"`
+	S3SecretAccessKey        string  `config:"s3_secret_access_key" yaml:"s3_secret_access_key" toml:"s3_secret_access_key"`
+	S3PathStyle              bool    `config:"s3_path_style" yaml:"s3_path_style" toml:"s3_path_style"`
+	GCRetention              uint32  `config:"gc_retention" yaml:"gc_retention" toml:"gc_retention"`
+	GCInterval               uint32  `config:"gc_interval" yaml:"gc_interval" toml:"gc_interval"`
+	KEKFilePath              string  `config:"kek_file" yaml:"kek_file" toml:"kek_file"`
+	KEKRewrapInterval        uint32  `config:"kek_rewrap_interval" yaml:"kek_rewrap_interval" toml:"kek_rewrap_interval"`
+	AdminToken               string  `config:"admin_token" yaml:"admin_token" toml:"admin_token"`
+	QuotaMaxBytes            uint64  `config:"quota_max_bytes" yaml:"quota_max_bytes" toml:"quota_max_bytes"`
+	QuotaMaxItems            uint64  `config:"quota_max_items" yaml:"quota_max_items" toml:"quota_max_items"`
+	Reflection               bool    `config:"reflection" yaml:"reflection" toml:"reflection"`
+	HealthCheckInterval      uint32  `config:"health_check_interval" yaml:"health_check_interval" toml:"health_check_interval"`
+	DrainTimeout             uint32  `config:"drain_timeout" yaml:"drain_timeout" toml:"drain_timeout"`
+	ShutdownTimeout          uint32  `config:"shutdown_timeout" yaml:"shutdown_timeout" toml:"shutdown_timeout"`
+}
+
+func defaultConfig() *config {
+	return &config{
+		DatabaseOperationTimeout: 250,
+		GrpcServerBasePort:       8090,
+		GrpcServerRecvSize:       16 * 1024 * 1024, // 16 MiB
+		GrpcServerSendSize:       2 * 1024 * 1024,  // 2 MiB
+		RPSLimit:                 100,
+		ListMaxPageSize:          1000,
+		UploadTTL:                24 * 60 * 60,       // 1 day
+		GCRetention:              30 * 24 * 60 * 60,  // 30 days
+		GCInterval:               60 * 60,            // 1 hour
+		KEKRewrapInterval:        60 * 60,            // 1 hour
+		QuotaMaxBytes:            1024 * 1024 * 1024, // 1 GiB
+		QuotaMaxItems:            10000,
+		HealthCheckInterval:      5,
+		DrainTimeout:             5,
+		ShutdownTimeout:          30,
+	}
+}
+
+// validate checks settings which types don't restrict.
+func (c *config) validate() error {
+	if c.RPSLimit == 0 {
+		return errors.New("rps_limit must be positive")
+	}
+	if c.GCInterval == 0 {
+		return errors.New("gc_interval must be positive")
+	}
+	if c.HealthCheckInterval == 0 {
+		return errors.New("health_check_interval must be positive")
+	}
+	if len(c.KEKFilePath) != 0 && c.KEKRewrapInterval == 0 {
+		return errors.New("kek_rewrap_interval must be positive")
+	}
+	// Dev mode keeps data in memory and signs tokens with an ephemeral key.
+	if !c.Dev {
+		if len(c.DatabaseConnectionString) == 0 {
+			return errors.New("db_dsn is required")
+		}
+		if len(c.TokenSignKeyFilePath) == 0 {
+			return errors.New("token_key is required")
+		}
+	}
+	listeners, err := c.listeners()
+	if err != nil {
+		return err
+	}
+	if c.useTLS() || usesTLS(listeners) {
+		if len(c.TLSCrtFilePath) == 0 || len(c.TLSKeyFilePath) == 0 {
+			return errors.New("crt_file and key_file are required for TLS")
+		}
+	}
+	switch c.BlobStore {
+	case storage.BlobStoreMemory:
+		if !c.Dev {
+			return errors.New("blob_store memory is available in dev mode only")
+		}
+	case storage.BlobStorePostgresLO, storage.BlobStoreSQLite:
+		if c.BlobStore != c.databaseBlobStore() {
+			return fmt.Errorf("blob_store %s needs a database of the same kind in db_dsn", c.BlobStore)
+		}
+	}
+	return nil
+}
+
+// databaseBlobStore returns a blob store keeping blobs in the database of
+// db_dsn, or in memory in dev mode. It is used when blob_store isn't set.
+func (c *config) databaseBlobStore() string {
+	if c.Dev {
+		return storage.BlobStoreMemory
+	}
+	if storage.IsSQLiteDSN(c.DatabaseConnectionString) {
+		return storage.BlobStoreSQLite
+	}
+	return storage.BlobStorePostgresLO
+}
+
+// listeners returns specs of gRPC listeners, a single TCP listener at
+// grpc_server_address and grpc_server_base_port when none is set. Dev mode
+// disables TLS of all listeners.
+func (c *config) listeners() ([]*listener.Spec, error) {
+	listeners, err := listener.ParseList(c.Listeners, c.useTLS())
+	if err != nil {
+		return nil, err
+	}
+	if len(listeners) == 0 {
+		listeners = append(listeners, &listener.Spec{
+			Network: listener.NetworkTCP,
+			Address: net.JoinHostPort(c.GrpcServerAddress, strconv.Itoa(int(c.GrpcServerBasePort))),
+			TLS:     c.useTLS(),
+		})
+	}
+	if c.Dev {
+		for _, l := range listeners {
+			l.TLS = false
+		}
+	}
+	return listeners, nil
+}
+
+// useTLS reports whether the HTTP gateway and listeners without an explicit
+// setting serve TLS.
+func (c *config) useTLS() bool {
+	return c.ServeTLS && !c.Dev
+}
+
+func usesTLS(listeners []*listener.Spec) bool {
+	for _, l := range listeners {
+		if l.TLS {
+			return true
+		}
+	}
+	return false
+}
+
+// configLoader loads the configuration from a YAML, TOML or JSON file, env
+// and flags. Env overrides the file and flags override both. Flags are parsed
+// once, so reloads reuse values of flags set on start.
+type configLoader struct {
+	file  string
+	flags map[string]string
+}
+
+func newConfigLoader(ctx context.Context, args []string) (*configLoader, *config, error) {
+	l := &configLoader{
+		file:  configFilePath(args),
+		flags: make(map[string]string),
+	}
+
+	cfg := defaultConfig()
+	if err := confita.NewLoader(l.backends(flags.NewBackend())...).Load(ctx, cfg); err != nil {
+		return nil, nil, err
+	}
+	flag.Visit(func(f *flag.Flag) {
+		l.flags[f.Name] = f.Value.String()
+	})
+	return l, cfg, cfg.validate()
+}
+
+// Load reads the configuration again.
+func (l *configLoader) Load(ctx context.Context) (*config, error) {
+	cfg := defaultConfig()
+	if err := confita.NewLoader(l.backends(backend.Func("flags", l.flagValue))...).Load(ctx, cfg); err != nil {
+		return nil, err
+	}
+	return cfg, cfg.validate()
+}
+
+func (l *configLoader) backends(flagsBackend backend.Backend) []backend.Backend {
+	backends := make([]backend.Backend, 0, 3)
+	if len(l.file) != 0 {
+		backends = append(backends, file.NewBackend(l.file))
+	}
+	return append(backends, env.NewBackend(), flagsBackend)
+}
+
+func (l *configLoader) flagValue(_ context.Context, key string) ([]byte, error) {
+	if v, ok := l.flags[key]; ok {
+		return []byte(v), nil
+	}
+	return nil, backend.ErrNotFound
+}
+
+// configFilePath finds a path of a configuration file before the
+// configuration is loaded, since the file is one of its sources.
+func configFilePath(args []string) string {
+	path := os.Getenv(strings.ToUpper(_configFileKey))
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--" || !strings.HasPrefix(args[i], "-") {
+			break
+		}
+
+		name, value, hasValue := strings.Cut(strings.TrimLeft(args[i], "-"), "=")
+		if name != _configFileKey {
+			continue
+		}
+		if !hasValue && i+1 < len(args) {
+			i++
+			value = args[i]
+		}
+		path = value
+	}
+	return path
+}
+
+// reloader applies settings which can change without a restart: the TLS
+// certificate, key encryption keys, rate limits and default quotas. Other
+// changes need a restart.
+type reloader struct {
+	loader   *configLoader
+	logger   *zap.Logger
+	cert     *reload.Certificate
+	kek      *keyring.Keyring
+	limiters []*gsrv.Limiter
+	quotas   *app.Quotas
+}
+
+// files lists files which changes are applied by Reload.
+func (r *reloader) files() []string {
+	files := make([]string, 0, 4)
+	if len(r.loader.file) != 0 {
+		files = append(files, r.loader.file)
+	}
+	if r.cert != nil {
+		files = append(files, r.cert.Files()...)
+	}
+	if r.kek != nil {
+		files = append(files, r.kek.Files()...)
+	}
+	return files
+}
+
+// Reload applies a new configuration. An invalid configuration is rejected as
+// a whole and the current one stays in use.
+func (r *reloader) Reload(ctx context.Context) {
+	cfg, err := r.loader.Load(ctx)
+	if err == nil && r.cert != nil {
+		err = r.cert.Reload()
+	}
+	if err == nil && r.kek != nil {
+		err = r.kek.Reload()
+	}
+	if err != nil {
+		r.logger.Error("configuration reload rejected", zap.Error(err))
+		return
+	}
+
+	for _, l := range r.limiters {
+		l.SetLimit(int(cfg.RPSLimit))
+	}
+	r.quotas.SetDefaults(storage.Quota{
+		MaxBytes: cfg.QuotaMaxBytes,
+		MaxItems: cfg.QuotaMaxItems,
+	})
+
+	r.logger.Info("configuration reloaded",
+		zap.Uint32("rps_limit", cfg.RPSLimit),
+		zap.Uint64("quota_max_bytes", cfg.QuotaMaxBytes),
+		zap.Uint64("quota_max_items", cfg.QuotaMaxItems))
+}
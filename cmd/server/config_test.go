@@ -0,0 +1,64 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		change  func(c *config)
+		wantErr bool
+	}{
+		{
+			name:   "defaults",
+			change: func(*config) {},
+		},
+		{
+			name:    "no rps limit",
+			change:  func(c *config) { c.RPSLimit = 0 },
+			wantErr: true,
+		},
+		{
+			name:    "no gc interval",
+			change:  func(c *config) { c.GCInterval = 0 },
+			wantErr: true,
+		},
+		{
+			name:    "no health check interval",
+			change:  func(c *config) { c.HealthCheckInterval = 0 },
+			wantErr: true,
+		},
+		{
+			name: "no rewrap interval without kek",
+			change: func(c *config) {
+				c.KEKRewrapInterval = 0
+			},
+		},
+		{
+			name: "no rewrap interval",
+			change: func(c *config) {
+				c.KEKFilePath = "kek.keys"
+				c.KEKRewrapInterval = 0
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := defaultConfig()
+			cfg.DatabaseConnectionString = "postgres://localhost/keeper"
+			cfg.TokenSignKeyFilePath = "sign.key"
+			tt.change(cfg)
+
+			err := cfg.validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -2,8 +2,11 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
+	"errors"
 	"fmt"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -11,35 +14,35 @@ import (
 
 	grpc_auth "github.com/grpc-ecosystem/go-grpc-middleware/auth"
 	"github.com/grpc-ecosystem/go-grpc-middleware/ratelimit"
-	"github.com/heetch/confita"
-	"github.com/heetch/confita/backend/env"
-	"github.com/heetch/confita/backend/flags"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
 
 	"go.uber.org/zap"
 
+	"github.com/r4start/goph-keeper/internal/logging"
 	"github.com/r4start/goph-keeper/internal/server/app"
+	"github.com/r4start/goph-keeper/internal/server/gateway"
 	gsrv "github.com/r4start/goph-keeper/internal/server/grpc"
+	"github.com/r4start/goph-keeper/internal/server/keyring"
+	"github.com/r4start/goph-keeper/internal/server/listener"
+	"github.com/r4start/goph-keeper/internal/server/metrics"
+	"github.com/r4start/goph-keeper/internal/server/reload"
 	"github.com/r4start/goph-keeper/internal/server/storage"
+	"github.com/r4start/goph-keeper/internal/tracing"
 	pb "github.com/r4start/goph-keeper/pkg/grpc/proto"
 )
 
-type config struct {
-	DatabaseConnectionString string `config:"db_dsn,required"`
-	DatabaseOperationTimeout uint32 `config:"db_timeout"`
-	TokenSignKeyFilePath     string `config:"token_key,required"`
-	GrpcServerAddress        string `config:"grpc_server_address"`
-	GrpcServerBasePort       uint16 `config:"grpc_server_base_port"`
-	GrpcServerRecvSize       int    `config:"grpc_server_recv_size"`
-	GrpcServerSendSize       int    `config:"grpc_server_send_size"`
-	ServeTLS                 bool   `config:"use_tls"`
-	TLSKeyFilePath           string `config:"key_file"`
-	TLSCrtFilePath           string `config:"crt_file"`
-	RPSLimit                 uint32 `config:"rps_limit"`
-}
+const (
+	_uploadsExpirationInterval = time.Minute
+	_gatewayBufferSize         = 1024 * 1024
+	_tracingShutdownTimeout    = 5 * time.Second
+	_configReloadDelay         = 500 * time.Millisecond
+	_devSignKeySize            = 64
+	_keysInUseTimeout          = 10 * time.Second
+)
 
 func main() {
 	logger, err := zap.NewProduction()
@@ -53,44 +56,57 @@ func main() {
 		}
 	}()
 
-	cfg := &config{
-		DatabaseOperationTimeout: 250,
-		GrpcServerBasePort:       8090,
-		GrpcServerRecvSize:       16 * 1024 * 1024, // 16 MiB
-		GrpcServerSendSize:       2 * 1024 * 1024,  // 2 MiB
-		RPSLimit:                 100,
+	serverCtx := logging.WithLogger(context.Background(), logger)
+
+	configLoader, cfg, err := newConfigLoader(serverCtx, os.Args[1:])
+	if err != nil {
+		logger.Fatal("failed to load configuration", zap.Error(err))
 	}
-	loader := confita.NewLoader(
-		env.NewBackend(),
-		flags.NewBackend(),
-	)
 
-	serverCtx := context.Background()
+	shutdownTracing, err := tracing.Setup(serverCtx, &tracing.Config{
+		ServiceName: "gophkeeper-server",
+		Exporter:    cfg.TraceExporter,
+		Endpoint:    cfg.TraceEndpoint,
+		Insecure:    cfg.TraceInsecure,
+		FilePath:    cfg.TraceFilePath,
+		SampleRatio: cfg.TraceSampleRatio,
+	})
+	if err != nil {
+		logger.Fatal("failed to set up tracing", zap.Error(err))
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), _tracingShutdownTimeout)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			logger.Error("failed to flush traces", zap.Error(err))
+		}
+	}()
 
-	if err := loader.Load(serverCtx, cfg); err != nil {
-		logger.Fatal("failed to load configuration", zap.Error(err))
+	listeners, err := cfg.listeners()
+	if err != nil {
+		logger.Fatal("failed to parse listeners", zap.Error(err))
 	}
 
-	var creds credentials.TransportCredentials
-	if cfg.ServeTLS {
-		creds, err = credentials.NewServerTLSFromFile(cfg.TLSCrtFilePath, cfg.TLSKeyFilePath)
+	if cfg.Dev {
+		logger.Warn("running in dev mode: data is kept in memory, TLS is disabled and tokens are signed with an ephemeral key")
+	}
+
+	var cert *reload.Certificate
+	if cfg.useTLS() || usesTLS(listeners) {
+		cert, err = reload.NewCertificate(cfg.TLSCrtFilePath, cfg.TLSKeyFilePath)
 		if err != nil {
 			logger.Fatal("failed to prepare grpc transport creds", zap.Error(err))
 		}
-	} else {
-		creds = insecure.NewCredentials()
 	}
 
-	freePort := cfg.GrpcServerBasePort
-	services := make([]*grpc.Server, 0)
-
-	signKey, err := os.ReadFile(cfg.TokenSignKeyFilePath)
+	signKey, err := readSignKey(cfg)
 	if err != nil {
 		logger.Fatal("failed to read signing key", zap.Error(err))
 	}
 
-	ds, err := storage.NewDatabaseUserService(serverCtx, cfg.DatabaseConnectionString,
-		time.Duration(cfg.DatabaseOperationTimeout)*time.Millisecond)
+	serverMetrics := metrics.New()
+
+	ds, databaseBlobs, err := openDatabase(serverCtx, cfg, serverMetrics)
 	if err != nil {
 		logger.Fatal("failed to create user storage", zap.Error(err))
 	}
@@ -99,41 +115,210 @@ func main() {
 		_ = ds.Close()
 	}()
 
-	auth, err := app.NewAuthorizer(ds, signKey)
+	blobs, err := newBlobStore(cfg, databaseBlobs)
+	if err != nil {
+		logger.Fatal("failed to create blob storage", zap.Error(err))
+	}
+
+	var kek *keyring.Keyring
+	if len(cfg.KEKFilePath) != 0 {
+		// Keys which still wrap data keys can't be removed from the key file.
+		kek, err = keyring.Load(cfg.KEKFilePath, keyring.WithKeysInUse(func() ([]string, error) {
+			ctx, cancel := context.WithTimeout(serverCtx, _keysInUseTimeout)
+			defer cancel()
+			return ds.KEKIDs(ctx)
+		}))
+		if err != nil {
+			logger.Fatal("failed to load key encryption keys", zap.Error(err))
+		}
+		encrypted := storage.NewEncryptedBlobStore(blobs, ds, kek)
+		go rewrapKeys(serverCtx, logger, encrypted, time.Duration(cfg.KEKRewrapInterval)*time.Second)
+		blobs = encrypted
+	}
+
+	authorizer, err := app.NewAuthorizer(ds, signKey)
 	if err != nil {
 		logger.Fatal("failed to create authorizer", zap.Error(err))
 	}
+	auth := serverMetrics.Authorizer(authorizer)
+
+	blobStorage := storage.NewBlobStorage(ds, blobs)
+	gcWorker := app.NewGCWorker(blobStorage, time.Duration(cfg.GCRetention)*time.Second)
+	quotas := app.NewQuotas(blobStorage, storage.Quota{
+		MaxBytes: cfg.QuotaMaxBytes,
+		MaxItems: cfg.QuotaMaxItems,
+	})
 
 	authService := gsrv.NewAuthService(auth, time.Duration(cfg.DatabaseOperationTimeout)*time.Millisecond)
-	storageService, _ := gsrv.NewStorageService(ds, cfg.GrpcServerSendSize)
+	storageService, _ := gsrv.NewStorageService(blobStorage, cfg.GrpcServerSendSize,
+		gsrv.WithMaxListPageSize(int(cfg.ListMaxPageSize)),
+		gsrv.WithUploadTTL(time.Duration(cfg.UploadTTL)*time.Second),
+		gsrv.WithRetention(gcWorker.Retention()),
+		gsrv.WithQuotas(quotas))
 	authFunc := gsrv.BuildAuthorizationInterceptor(auth)
+	streamLimiter, unaryLimiter := gsrv.NewLimiter(int(cfg.RPSLimit)), gsrv.NewLimiter(int(cfg.RPSLimit))
 
-	grpcServer := grpc.NewServer(grpc.Creds(creds), grpc.MaxRecvMsgSize(cfg.GrpcServerRecvSize),
-		grpc.StreamInterceptor(grpc_auth.StreamServerInterceptor(authFunc)),
-		grpc.UnaryInterceptor(grpc_auth.UnaryServerInterceptor(authFunc)),
-		grpc.UnaryInterceptor(ratelimit.UnaryServerInterceptor(gsrv.NewLimiter(int(cfg.RPSLimit)))),
-		grpc.StreamInterceptor(ratelimit.StreamServerInterceptor(gsrv.NewLimiter(int(cfg.RPSLimit)))),
-	)
+	serverOpts := []grpc.ServerOption{
+		grpc.MaxRecvMsgSize(cfg.GrpcServerRecvSize),
+		grpc.ChainStreamInterceptor(
+			tracing.StreamServerInterceptor(),
+			serverMetrics.StreamServerInterceptor(),
+			gsrv.LoggingStreamInterceptor(logger),
+			gsrv.ErrorStreamInterceptor(),
+			grpc_auth.StreamServerInterceptor(authFunc),
+			ratelimit.StreamServerInterceptor(serverMetrics.Limiter(streamLimiter)),
+		),
+		grpc.ChainUnaryInterceptor(
+			tracing.UnaryServerInterceptor(),
+			serverMetrics.UnaryServerInterceptor(),
+			gsrv.LoggingUnaryInterceptor(logger),
+			gsrv.ErrorUnaryInterceptor(),
+			grpc_auth.UnaryServerInterceptor(authFunc),
+			ratelimit.UnaryServerInterceptor(serverMetrics.Limiter(unaryLimiter)),
+		),
+	}
+	registerServices := func(s *grpc.Server) {
+		pb.RegisterAuthorizationServiceServer(s, authService)
+		pb.RegisterStorageServer(s, storageService)
+		pb.RegisterInfoServer(s, gsrv.NewInfoService(storageService, cfg.GrpcServerRecvSize))
+		if len(cfg.AdminToken) != 0 {
+			pb.RegisterAdminServer(s, gsrv.NewAdminService(gcWorker, quotas, cfg.AdminToken))
+		}
+	}
+
+	// Every listener is served by its own server, so listeners may differ in
+	// credentials.
+	services := make([]*grpc.Server, 0, len(listeners)+1)
+	for _, spec := range listeners {
+		creds := insecure.NewCredentials()
+		if spec.TLS {
+			creds = credentials.NewTLS(cert.TLSConfig())
+		}
+
+		s := grpc.NewServer(append(serverOpts, grpc.Creds(creds))...)
+		registerServices(s)
+		services = append(services, s)
+	}
+
+	healthChecks := []gsrv.HealthServiceOption{
+		gsrv.WithHealthCheck("database", ds.Ping),
+		gsrv.WithHealthCheckInterval(time.Duration(cfg.HealthCheckInterval) * time.Second),
+	}
+	if p, ok := blobs.(storage.Pinger); ok {
+		healthChecks = append(healthChecks, gsrv.WithHealthCheck("blob store", p.Ping))
+	}
+	healthService := gsrv.NewHealthService(gsrv.ServiceNames(services[0]), healthChecks...)
+	go healthService.Run(serverCtx)
+
+	for i, spec := range listeners {
+		s := services[i]
+		healthService.Register(s)
+		if cfg.Reflection {
+			gsrv.RegisterReflection(s)
+		}
+
+		lis, err := spec.Listen()
+		if err != nil {
+			logger.Fatal("failed to start grpc listener", zap.Stringer("listener", spec), zap.Error(err))
+		}
+		logger.Info("serving grpc", zap.Stringer("listener", spec), zap.Bool("tls", spec.TLS))
+
+		go func(s *grpc.Server, lis net.Listener, spec *listener.Spec) {
+			if err := s.Serve(lis); err != nil {
+				logger.Fatal("failed to serve grpc", zap.Stringer("listener", spec), zap.Error(err))
+			}
+		}(s, lis, spec)
+	}
+
+	httpServers := make([]*http.Server, 0)
+	if len(cfg.HTTPGatewayAddress) != 0 {
+		// The gateway calls an in-process server with the same services and
+		// interceptors, so HTTP requests are authorized and limited as gRPC calls are.
+		gatewayServer := grpc.NewServer(serverOpts...)
+		registerServices(gatewayServer)
 
-	pb.RegisterAuthorizationServiceServer(grpcServer, authService)
-	pb.RegisterStorageServer(grpcServer, storageService)
+		listener := bufconn.Listen(_gatewayBufferSize)
+		go func() {
+			if err := gatewayServer.Serve(listener); err != nil {
+				logger.Fatal("failed to serve gateway grpc", zap.Error(err))
+			}
+		}()
+		services = append(services, gatewayServer)
 
-	go func(portNumber uint16) {
-		addr := fmt.Sprintf("%s:%d", cfg.GrpcServerAddress, portNumber)
-		listener, err := net.Listen("tcp", addr)
+		conn, err := grpc.DialContext(serverCtx, "bufnet",
+			grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+				return listener.DialContext(ctx)
+			}),
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			grpc.WithDefaultCallOptions(
+				grpc.MaxCallRecvMsgSize(cfg.GrpcServerRecvSize),
+				grpc.MaxCallSendMsgSize(cfg.GrpcServerRecvSize)))
 		if err != nil {
-			logger.Fatal("failed to start grpc listener", zap.Error(err))
+			logger.Fatal("failed to connect gateway", zap.Error(err))
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+
+		httpServer := &http.Server{
+			Addr:              cfg.HTTPGatewayAddress,
+			Handler:           gateway.New(conn),
+			ReadHeaderTimeout: 10 * time.Second,
 		}
+		go func() {
+			var err error
+			if cfg.useTLS() {
+				httpServer.TLSConfig = cert.TLSConfig()
+				err = httpServer.ListenAndServeTLS("", "")
+			} else {
+				err = httpServer.ListenAndServe()
+			}
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logger.Fatal("failed to serve http gateway", zap.Error(err))
+			}
+		}()
+		httpServers = append(httpServers, httpServer)
+	}
+
+	if len(cfg.MetricsAddress) != 0 {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", serverMetrics.Handler())
 
-		if err := grpcServer.Serve(listener); err != nil {
-			logger.Fatal("failed to serve grpc", zap.Error(err))
+		metricsServer := &http.Server{
+			Addr:              cfg.MetricsAddress,
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
 		}
-	}(freePort)
-	freePort++
+		go func() {
+			if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logger.Fatal("failed to serve metrics", zap.Error(err))
+			}
+		}()
+		httpServers = append(httpServers, metricsServer)
+	}
+
+	go expireUploads(serverCtx, logger, storageService, _uploadsExpirationInterval)
+	go collectGarbage(serverCtx, logger, gcWorker, time.Duration(cfg.GCInterval)*time.Second)
 
-	services = append(services, grpcServer)
+	configReloader := &reloader{
+		loader:   configLoader,
+		logger:   logger,
+		cert:     cert,
+		kek:      kek,
+		limiters: []*gsrv.Limiter{streamLimiter, unaryLimiter},
+		quotas:   quotas,
+	}
+	if err := reload.Watch(serverCtx, configReloader.files(), _configReloadDelay, func() {
+		configReloader.Reload(serverCtx)
+	}); err != nil {
+		logger.Fatal("failed to watch configuration", zap.Error(err))
+	}
+	go reloadOnHangup(serverCtx, configReloader)
 
-	sCh, err := prepareShutdown(services...)
+	sCh, err := prepareShutdown(logger, healthService, &shutdownConfig{
+		drain:   time.Duration(cfg.DrainTimeout) * time.Second,
+		timeout: time.Duration(cfg.ShutdownTimeout) * time.Second,
+	}, httpServers, services...)
 	if err != nil {
 		logger.Fatal("failed to prepare shutdown", zap.Error(err))
 	}
@@ -143,7 +328,171 @@ func main() {
 	fmt.Println("Server stopped")
 }
 
-func prepareShutdown(grpcServers ...*grpc.Server) (<-chan interface{}, error) {
+// database keeps users and resource metadata.
+type database interface {
+	storage.UserService
+	storage.MetadataStore
+	storage.BlobKeyStore
+	storage.Pinger
+}
+
+// readSignKey reads a token signing key. Dev mode generates an ephemeral key,
+// so tokens don't survive a restart.
+func readSignKey(cfg *config) ([]byte, error) {
+	if !cfg.Dev {
+		return os.ReadFile(cfg.TokenSignKeyFilePath)
+	}
+	key := make([]byte, _devSignKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// openDatabase opens Postgres or, for a sqlite:// DSN, an embedded SQLite
+// database. It returns a blob store keeping blobs in the same database as well.
+// Dev mode keeps both in memory.
+func openDatabase(ctx context.Context, cfg *config, m *metrics.Metrics) (database, storage.BlobStore, error) {
+	if cfg.Dev {
+		return storage.NewMemoryUserService(), storage.NewMemoryBlobStore(), nil
+	}
+
+	timeout := time.Duration(cfg.DatabaseOperationTimeout) * time.Millisecond
+	if storage.IsSQLiteDSN(cfg.DatabaseConnectionString) {
+		db, err := storage.NewSQLiteUserService(ctx, cfg.DatabaseConnectionString, timeout)
+		if err != nil {
+			return nil, nil, err
+		}
+		return db, storage.NewSQLiteBlobStore(db), nil
+	}
+
+	db, err := storage.NewDatabaseUserService(ctx, cfg.DatabaseConnectionString, timeout,
+		storage.WithQueryObserver(m.ObserveQuery))
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := m.Register(metrics.NewPoolCollector(db.PoolStat)); err != nil {
+		_ = db.Close()
+		return nil, nil, fmt.Errorf("failed to register pool metrics: %w", err)
+	}
+	return db, storage.NewLargeObjectBlobStore(db), nil
+}
+
+// newBlobStore selects a blob store by the configuration. Blobs kept in the
+// metadata database are served by a store a caller prepares.
+func newBlobStore(cfg *config, databaseBlobs storage.BlobStore) (storage.BlobStore, error) {
+	switch cfg.BlobStore {
+	case "", storage.BlobStorePostgresLO, storage.BlobStoreSQLite, storage.BlobStoreMemory:
+		return databaseBlobs, nil
+	case storage.BlobStoreFS:
+		return storage.NewFileBlobStore(cfg.BlobStorePath)
+	case storage.BlobStoreS3:
+		return storage.NewS3BlobStore(storage.S3Config{
+			Endpoint:        cfg.S3Endpoint,
+			Region:          cfg.S3Region,
+			Bucket:          cfg.S3Bucket,
+			Prefix:          cfg.S3Prefix,
+			AccessKeyID:     cfg.S3AccessKeyID,
+			SecretAccessKey: cfg.S3SecretAccessKey,
+			PathStyle:       cfg.S3PathStyle,
+		})
+	default:
+		return nil, fmt.Errorf("unknown blob store: %s", cfg.BlobStore)
+	}
+}
+
+func expireUploads(ctx context.Context, logger *zap.Logger, s *gsrv.StorageService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			expired, err := s.ExpireUploads(ctx)
+			if err != nil {
+				logger.Error("failed to expire upload sessions", zap.Error(err))
+				continue
+			}
+			if expired != 0 {
+				logger.Info("expired upload sessions", zap.Int("count", expired))
+			}
+		}
+	}
+}
+
+func rewrapKeys(ctx context.Context, logger *zap.Logger, r storage.KeyRewrapper, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			rewrapped, err := r.RewrapKeys(ctx)
+			if err != nil {
+				logger.Error("failed to re-wrap data keys", zap.Error(err))
+			}
+			if rewrapped != 0 {
+				logger.Info("re-wrapped data keys", zap.Int("count", rewrapped))
+			}
+		}
+	}
+}
+
+func collectGarbage(ctx context.Context, logger *zap.Logger, w *app.GCWorker, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			run, err := w.Collect(ctx)
+			if err != nil {
+				logger.Error("failed to collect garbage", zap.Error(err))
+			}
+			logger.Info("collected garbage",
+				zap.Int("tombstones", run.Stats.Tombstones),
+				zap.Int("chunks", run.Stats.Chunks),
+				zap.Int("blobs", run.Stats.Blobs),
+				zap.Int("orphans", run.Stats.Orphans),
+				zap.Int("orphans_deleted", run.Stats.OrphansDeleted),
+				zap.Duration("duration", run.Stats.FinishedAt.Sub(run.Stats.StartedAt)))
+		}
+	}
+}
+
+// reloadOnHangup reloads the configuration on SIGHUP, e.g. when files are
+// changed where watching doesn't work.
+func reloadOnHangup(ctx context.Context, r *reloader) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	defer signal.Stop(signals)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-signals:
+			r.Reload(ctx)
+		}
+	}
+}
+
+type shutdownConfig struct {
+	// drain is a time between switching health to NOT_SERVING and stopping
+	// servers, so load balancers notice the switch.
+	drain time.Duration
+	// timeout is a deadline for running calls, unfinished calls are cancelled.
+	timeout time.Duration
+}
+
+func prepareShutdown(logger *zap.Logger, health *gsrv.HealthService, cfg *shutdownConfig,
+	httpServers []*http.Server, grpcServers ...*grpc.Server) (<-chan interface{}, error) {
 	shutdownSig := make(chan interface{})
 	signals := make(chan os.Signal, 1)
 
@@ -154,8 +503,34 @@ func prepareShutdown(grpcServers ...*grpc.Server) (<-chan interface{}, error) {
 	go func() {
 		<-signals
 
-		for _, s := range grpcServers {
-			s.GracefulStop()
+		health.Shutdown()
+		logger.Info("draining connections", zap.Duration("drain", cfg.drain))
+		time.Sleep(cfg.drain)
+
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.timeout)
+		defer cancel()
+
+		// HTTP servers go first, their requests are served by gRPC servers.
+		for _, s := range httpServers {
+			_ = s.Shutdown(ctx)
+		}
+
+		stopped := make(chan struct{})
+		go func() {
+			for _, s := range grpcServers {
+				s.GracefulStop()
+			}
+			close(stopped)
+		}()
+
+		select {
+		case <-stopped:
+		case <-ctx.Done():
+			logger.Warn("shutdown deadline exceeded, cancelling running calls")
+			for _, s := range grpcServers {
+				s.Stop()
+			}
+			<-stopped
 		}
 
 		close(shutdownSig)
@@ -0,0 +1,79 @@
+package client
+
+const (
+	_minChunkSize = 512 * 1024      // 512 KiB
+	_avgChunkSize = 1024 * 1024     // 1 MiB
+	_maxChunkSize = 4 * 1024 * 1024 // 4 MiB
+
+	_gearSeed = 0x6a09e667f3bcc908
+)
+
+var _gearTable = newGearTable(_gearSeed)
+
+// chunker splits data into content-defined chunks with a gear rolling hash.
+// A boundary depends only on the bytes right before it, so an insertion
+// in the middle of data changes only the chunks around it.
+type chunker struct {
+	minSize int
+	maxSize int
+	mask    uint64
+}
+
+// newChunker creates a chunker producing chunks of avgSize bytes on average.
+// avgSize must be a power of two.
+func newChunker(minSize, avgSize, maxSize int) *chunker {
+	return &chunker{
+		minSize: minSize,
+		maxSize: maxSize,
+		mask:    uint64(avgSize - 1),
+	}
+}
+
+func newDefaultChunker() *chunker {
+	return newChunker(_minChunkSize, _avgChunkSize, _maxChunkSize)
+}
+
+// split returns chunks sharing memory with data.
+func (c *chunker) split(data []byte) [][]byte {
+	chunks := make([][]byte, 0, len(data)/c.minSize+1)
+	for len(data) > 0 {
+		n := c.boundary(data)
+		chunks = append(chunks, data[:n])
+		data = data[n:]
+	}
+	return chunks
+}
+
+func (c *chunker) boundary(data []byte) int {
+	if len(data) <= c.minSize {
+		return len(data)
+	}
+
+	end := len(data)
+	if end > c.maxSize {
+		end = c.maxSize
+	}
+
+	var hash uint64
+	for i := c.minSize; i < end; i++ {
+		hash = (hash << 1) + _gearTable[data[i]]
+		if hash&c.mask == 0 {
+			return i + 1
+		}
+	}
+	return end
+}
+
+// newGearTable fills a table of random values with splitmix64, so chunk
+// boundaries are the same for every client.
+func newGearTable(seed uint64) [256]uint64 {
+	var table [256]uint64
+	for i := range table {
+		seed += 0x9e3779b97f4a7c15
+		z := seed
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		table[i] = z ^ (z >> 31)
+	}
+	return table
+}
@@ -0,0 +1,46 @@
+package client
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChunker_Split(t *testing.T) {
+	c := newChunker(1024, 4096, 16*1024)
+
+	data := make([]byte, 1024*1024)
+	_, err := rand.Read(data)
+	assert.NoError(t, err)
+
+	chunks := c.split(data)
+	assert.Equal(t, data, bytes.Join(chunks, nil))
+	for _, chunk := range chunks[:len(chunks)-1] {
+		assert.GreaterOrEqual(t, len(chunk), 1024)
+		assert.LessOrEqual(t, len(chunk), 16*1024)
+	}
+
+	// An insertion changes only the chunks around it.
+	edited := make([]byte, 0, len(data)+10)
+	edited = append(edited, data[:len(data)/2]...)
+	edited = append(edited, []byte("0123456789")...)
+	edited = append(edited, data[len(data)/2:]...)
+
+	digests := make(map[[sha256.Size]byte]struct{})
+	for _, chunk := range chunks {
+		digests[sha256.Sum256(chunk)] = struct{}{}
+	}
+
+	editedChunks := c.split(edited)
+	changed := 0
+	for _, chunk := range editedChunks {
+		if _, ok := digests[sha256.Sum256(chunk)]; !ok {
+			changed++
+		}
+	}
+	assert.LessOrEqual(t, changed, 3)
+	assert.Greater(t, len(editedChunks), 100)
+}
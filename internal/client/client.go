@@ -3,16 +3,42 @@ package client
 import (
 	"context"
 	"io"
+	"net"
+	"strings"
+	"time"
 )
 
 type Client interface {
+	// ServerInfo describes a server the client talks to.
+	ServerInfo(ctx context.Context) (*ServerInfo, error)
+
 	Register(ctx context.Context, login, password string, salt []byte) (*UserAuthorization, error)
 	Authorize(ctx context.Context, login, password string) (*UserAuthorization, error)
 
-	Store(ctx context.Context, auth *UserAuthorization, salt []byte, fileSize uint64) (ResourceUploader, error)
+	Store(ctx context.Context, auth *UserAuthorization, meta *ResourceMeta) (ResourceUploader, error)
+	CreateUpload(ctx context.Context, auth *UserAuthorization, meta *ResourceMeta) (*UploadSession, error)
+	UploadStatus(ctx context.Context, auth *UserAuthorization, uploadID string) (*UploadSession, error)
+	AppendUpload(ctx context.Context, auth *UserAuthorization, uploadID string) (UploadAppender, error)
+	FinalizeUpload(ctx context.Context, auth *UserAuthorization, uploadID string) (*ResourceInfo, error)
+	// MissingChunks returns those of digests the server has no chunks for.
+	MissingChunks(ctx context.Context, auth *UserAuthorization, digests [][]byte) ([][]byte, error)
+	PutChunks(ctx context.Context, auth *UserAuthorization, chunks []Chunk) error
+	// AddChunked creates a resource which data is a concatenation of stored chunks.
+	AddChunked(ctx context.Context, auth *UserAuthorization, meta *ResourceMeta, digests [][]byte) (*ResourceInfo, error)
 	List(ctx context.Context, auth *UserAuthorization) (RemoteResourcesReader, error)
-	Get(ctx context.Context, auth *UserAuthorization, resourceId string) (ResourceDownloader, error)
+	Stat(ctx context.Context, auth *UserAuthorization, resourceId string) (*ResourceInfo, error)
+	// Get streams resource data starting from offset.
+	Get(ctx context.Context, auth *UserAuthorization, resourceId string, offset uint64) (ResourceDownloader, error)
 	Delete(ctx context.Context, auth *UserAuthorization, resourceId string) error
+	// ListDeleted lists deleted resources which can still be restored.
+	ListDeleted(ctx context.Context, auth *UserAuthorization) (RemoteResourcesReader, error)
+	Restore(ctx context.Context, auth *UserAuthorization, resourceId string) (*ResourceInfo, error)
+	// EmptyTrash removes all deleted resources for good and returns their number.
+	EmptyTrash(ctx context.Context, auth *UserAuthorization) (int, error)
+	Usage(ctx context.Context, auth *UserAuthorization) (*Usage, error)
+	// Batch applies items in one request. Failures of single items are reported
+	// in their results, an atomic batch is applied either completely or not at all.
+	Batch(ctx context.Context, auth *UserAuthorization, items []BatchItem, atomic bool) ([]BatchResult, error)
 }
 
 type ResourceDownloader interface {
@@ -27,13 +53,26 @@ type RemoteResourcesReader interface {
 	Recv(ctx context.Context) (*ResourceInfo, error)
 }
 
+const _unixScheme = "unix://"
+
 type ServerEndpoint struct {
+	// Addr is a host name or a path of a Unix socket with unix:// scheme,
+	// e.g. unix:///run/gophkeeper.sock.
 	Addr   string  `json:"address"`
 	Port   string  `json:"port"`
 	UseTLS bool    `json:"use_tls"`
 	CAPath *string `json:"ca_path,omitempty"`
 }
 
+// Target returns a gRPC dial target of the endpoint. Ports of Unix sockets
+// are ignored.
+func (e *ServerEndpoint) Target() string {
+	if strings.HasPrefix(e.Addr, _unixScheme) {
+		return e.Addr
+	}
+	return net.JoinHostPort(e.Addr, e.Port)
+}
+
 type UserAuthorization struct {
 	Token        string
 	RefreshToken string
@@ -41,10 +80,62 @@ type UserAuthorization struct {
 	Salt         []byte
 }
 
+// ResourceMeta describes a resource being uploaded.
+type ResourceMeta struct {
+	Salt []byte
+	// Metadata is an encrypted ResourceMetadata message.
+	Metadata []byte
+	// Kind is a plain text resource kind a server can filter by.
+	Kind string
+	Size uint64
+	// Digest is a SHA-256 digest of the encrypted data.
+	Digest []byte
+}
+
 type ResourceInfo struct {
 	ErrorCode int32
 	ID        string
 	IsDeleted bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Size      uint64
+	Version   uint64
+	Salt      []byte
+	Kind      string
+	Digest    []byte
+	// Metadata is an encrypted ResourceMetadata message supplied on upload.
+	Metadata []byte
+	// PurgeAt is a time after which a deleted resource can't be restored.
+	PurgeAt time.Time
+}
+
+// Usage is storage consumed by a user on a server. Zero limits are unlimited.
+type Usage struct {
+	Bytes    uint64
+	Items    uint64
+	MaxBytes uint64
+	MaxItems uint64
+}
+
+type BatchOperation int
+
+const (
+	BatchStat BatchOperation = iota
+	BatchDelete
+	BatchRestore
+)
+
+type BatchItem struct {
+	Op BatchOperation
+	ID string
+}
+
+// BatchResult is an outcome of a batch item. Info is set for successful items
+// and Err for failed ones.
+type BatchResult struct {
+	ID   string
+	Info *ResourceInfo
+	Err  error
 }
 
 type ResourceUploader interface {
@@ -54,8 +145,33 @@ type ResourceUploader interface {
 	Recv(ctx context.Context) (*ResourceInfo, error)
 }
 
+// UploadSession is a server side state of a resumable upload.
+type UploadSession struct {
+	ID string
+	// Offset is a number of bytes the server has already committed.
+	Offset    uint64
+	Size      uint64
+	ExpiresAt time.Time
+}
+
+// Chunk is a piece of encrypted resource data addressed by its SHA-256 digest.
+type Chunk struct {
+	Digest []byte
+	Data   []byte
+}
+
+type UploadAppender interface {
+	io.Closer
+
+	SendChunk(ctx context.Context, offset uint64, data []byte) error
+	Recv(ctx context.Context) (*UploadSession, error)
+}
+
+// ResourceChunck is a part of a downloaded resource. The first chunk carries
+// the salt, the digest and the total resource size, the rest carry data.
 type ResourceChunck struct {
-	Salt []byte
-	Data []byte
-	Size *uint64
+	Salt   []byte
+	Digest []byte
+	Data   []byte
+	Size   *uint64
 }
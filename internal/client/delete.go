@@ -2,11 +2,18 @@ package client
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"os"
 
+	"github.com/hashicorp/go-multierror"
+
 	"github.com/r4start/goph-keeper/internal/client/storage"
 )
 
+// _batchSize is a number of resources sent in one batch request.
+const _batchSize = 500
+
 type Deleter struct {
 	client  Client
 	storage storage.Storage
@@ -19,6 +26,9 @@ func NewDeleter(client Client, storage storage.Storage) *Deleter {
 	}
 }
 
+// Delete removes resources on a server in batches and then locally. A resource
+// already missing on the server is removed locally as well. Failures don't stop
+// deletion of the rest of resources and are returned together.
 func (d *Deleter) Delete(ctx context.Context, ids []string) error {
 	if len(ids) == 0 {
 		return nil
@@ -35,6 +45,9 @@ func (d *Deleter) Delete(ctx context.Context, ids []string) error {
 	}
 
 	localResources, err := listLocalResources(ctx, d.storage)
+	if err != nil {
+		return err
+	}
 
 	toDelete := make([]resourcePair, 0, len(ids))
 	for _, arg := range ids {
@@ -43,34 +56,54 @@ func (d *Deleter) Delete(ctx context.Context, ids []string) error {
 		}
 	}
 
-	for _, res := range toDelete {
-		if err := d.client.Delete(ctx, auth, res.ID); err != nil {
-			return err
+	var result error
+	for start := 0; start < len(toDelete); start += _batchSize {
+		end := start + _batchSize
+		if end > len(toDelete) {
+			end = len(toDelete)
 		}
 
-		switch res.Type {
-		case ResourceTypeBinary:
-			data, err := d.storage.FileData(ctx, res.ID)
-			if err != nil {
-				return err
-			}
+		batch := toDelete[start:end]
+		items := make([]BatchItem, 0, len(batch))
+		for _, res := range batch {
+			items = append(items, BatchItem{Op: BatchDelete, ID: res.ID})
+		}
 
-			if err := d.storage.DeleteFile(ctx, data); err != nil {
-				return err
-			}
-			if err := os.Remove(data.Path); err != nil {
-				return err
-			}
-		case ResourceTypeCredentials:
-			if err := d.storage.DeleteCredential(ctx, res.ID); err != nil {
-				return err
+		results, err := d.client.Batch(ctx, auth, items, false)
+		if err != nil {
+			return multierror.Append(result, err)
+		}
+
+		for i, r := range results {
+			if r.Err != nil && !errors.Is(r.Err, ErrNotFound) {
+				result = multierror.Append(result, fmt.Errorf("failed to delete %s: %w", r.ID, r.Err))
+				continue
 			}
-		case ResourceTypeCardCredentials:
-			if err := d.storage.DeleteCard(ctx, res.ID); err != nil {
-				return err
+			if err := d.deleteLocal(ctx, batch[i]); err != nil {
+				result = multierror.Append(result, err)
 			}
 		}
 	}
 
+	return result
+}
+
+func (d *Deleter) deleteLocal(ctx context.Context, res resourcePair) error {
+	switch res.Type {
+	case ResourceTypeBinary:
+		data, err := d.storage.FileData(ctx, res.ID)
+		if err != nil {
+			return err
+		}
+
+		if err := d.storage.DeleteFile(ctx, data); err != nil {
+			return err
+		}
+		return os.Remove(data.Path)
+	case ResourceTypeCredentials:
+		return d.storage.DeleteCredential(ctx, res.ID)
+	case ResourceTypeCardCredentials:
+		return d.storage.DeleteCard(ctx, res.ID)
+	}
 	return nil
 }
@@ -68,3 +68,29 @@ func TestDeleter_DeleteTestDeleter_Delete(t *testing.T) {
 	assert.NoError(t, deleter.Delete(ctx, files))
 	assert.Empty(t, st.Files)
 }
+
+func TestDeleter_DeleteMissingOnServer(t *testing.T) {
+	ctx := context.Background()
+	st := storage.NewMockStorage()
+	client := newMockClient()
+	up := NewUploader(client, st, t.TempDir())
+
+	for _, name := range []string{"first", "second"} {
+		assert.NoError(t, up.UploadCard(ctx, storage.CardData{Name: name, Number: "5555 5555 5555 5555"}))
+	}
+	assert.Equal(t, 2, len(st.Cards))
+
+	cards := make([]string, 0, len(st.Cards))
+	for k := range st.Cards {
+		cards = append(cards, k)
+	}
+
+	// The first card has been removed on the server by another client.
+	delete(client.Files, cards[0])
+
+	deleter := NewDeleter(client, st)
+	assert.NoError(t, deleter.Delete(ctx, cards))
+	assert.Empty(t, st.Cards)
+	assert.Len(t, client.Deleted, 1)
+	assert.Contains(t, client.Deleted, cards[1])
+}
@@ -0,0 +1,46 @@
+package client
+
+import (
+	"errors"
+)
+
+// Errors a server classifies its failures with. A ServerError wraps one of them,
+// so they are checked with errors.Is.
+var (
+	ErrNotFound           = errors.New("not found")
+	ErrAlreadyExists      = errors.New("already exists")
+	ErrInvalidArgument    = errors.New("invalid argument")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrUnauthenticated    = errors.New("unauthenticated")
+	ErrQuotaExceeded      = errors.New("quota exceeded")
+	// ErrConflict is a failure caused by a concurrent change. Items of an atomic
+	// batch not applied because of a failure of another item fail with it too.
+	ErrConflict           = errors.New("conflict")
+	ErrFailedPrecondition = errors.New("failed precondition")
+	ErrInternal           = errors.New("internal server error")
+	// ErrUnavailable is a failure which may pass on a retry, e.g. a server
+	// is restarting or didn't answer in time.
+	ErrUnavailable = errors.New("unavailable")
+)
+
+// ServerError is a failure reported by a server.
+type ServerError struct {
+	// Kind is one of the Err* errors of the package or nil when the server
+	// didn't classify the failure.
+	Kind    error
+	Message string
+}
+
+func (e *ServerError) Error() string {
+	if len(e.Message) != 0 {
+		return e.Message
+	}
+	if e.Kind != nil {
+		return e.Kind.Error()
+	}
+	return "unknown server error"
+}
+
+func (e *ServerError) Unwrap() error {
+	return e.Kind
+}
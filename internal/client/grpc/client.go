@@ -6,21 +6,33 @@ import (
 	"crypto/x509"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"time"
 
 	"github.com/grpc-ecosystem/go-grpc-middleware/util/metautils"
+	"github.com/hashicorp/go-multierror"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
 	"github.com/r4start/goph-keeper/internal/client"
+	"github.com/r4start/goph-keeper/internal/tracing"
 	pb "github.com/r4start/goph-keeper/pkg/grpc/proto"
 )
 
+const (
+	_listPageSize      = 500
+	_serverInfoTimeout = 10 * time.Second
+)
+
 var (
 	_ client.Client                = (*grpcClient)(nil)
 	_ client.ResourceUploader      = (*grpcResourceUploader)(nil)
+	_ client.UploadAppender        = (*grpcUploadAppender)(nil)
 	_ client.RemoteResourcesReader = (*grpcRemoteResourceReader)(nil)
 	_ client.ResourceDownloader    = (*grpcResourceDownloader)(nil)
 )
@@ -28,6 +40,7 @@ var (
 type grpcClient struct {
 	authC    pb.AuthorizationServiceClient
 	storageC pb.StorageClient
+	info     *client.ServerInfo
 }
 
 func NewGrpcClient(cfg *client.ServerEndpoint) (*grpcClient, error) {
@@ -50,18 +63,56 @@ func NewGrpcClient(cfg *client.ServerEndpoint) (*grpcClient, error) {
 		connSecurityOpt = grpc.WithTransportCredentials(insecure.NewCredentials())
 	}
 
-	cc, err := grpc.Dial(cfg.Addr+":"+cfg.Port, connSecurityOpt,
-		grpc.WithDefaultCallOptions(grpc.MaxCallSendMsgSize(16*1024*1024)))
+	cc, err := grpc.Dial(cfg.Target(), connSecurityOpt,
+		grpc.WithDefaultCallOptions(grpc.MaxCallSendMsgSize(16*1024*1024)),
+		grpc.WithChainUnaryInterceptor(errorUnaryInterceptor, tracing.UnaryClientInterceptor()),
+		grpc.WithChainStreamInterceptor(errorStreamInterceptor, tracing.StreamClientInterceptor()))
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := fetchServerInfo(pb.NewInfoClient(cc))
 	if err != nil {
+		if e := cc.Close(); e != nil {
+			err = multierror.Append(err, e)
+		}
 		return nil, err
 	}
+
 	c := &grpcClient{
 		authC:    pb.NewAuthorizationServiceClient(cc),
 		storageC: pb.NewStorageClient(cc),
+		info:     info,
 	}
 	return c, nil
 }
 
+// fetchServerInfo asks a server about its protocol and refuses incompatible servers.
+func fetchServerInfo(infoC pb.InfoClient) (*client.ServerInfo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), _serverInfoTimeout)
+	defer cancel()
+
+	version := uint32(client.ProtocolVersion)
+	m, err := infoC.ServerInfo(ctx, &pb.ServerInfoRequest{ProtocolVersion: &version})
+	if status.Code(err) == codes.Unimplemented {
+		return nil, fmt.Errorf("%w: server doesn't report its protocol version, update the server",
+			client.ErrIncompatibleServer)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	info := serverInfoFromProto(m)
+	if err := client.CheckCompatibility(info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+func (g *grpcClient) ServerInfo(context.Context) (*client.ServerInfo, error) {
+	return g.info, nil
+}
+
 func (g *grpcClient) Register(ctx context.Context, login, password string, salt []byte) (*client.UserAuthorization, error) {
 	auth, err := g.authC.Register(ctx, &pb.AuthorizationRequest{
 		Login:    &login,
@@ -98,7 +149,7 @@ func (g *grpcClient) Authorize(ctx context.Context, login, password string) (*cl
 	return result, nil
 }
 
-func (g *grpcClient) Store(ctx context.Context, auth *client.UserAuthorization, salt []byte, fileSize uint64) (client.ResourceUploader, error) {
+func (g *grpcClient) Store(ctx context.Context, auth *client.UserAuthorization, meta *client.ResourceMeta) (client.ResourceUploader, error) {
 	rctx := addAuth(ctx, auth)
 	streamingC, err := g.storageC.Add(rctx)
 	if err != nil {
@@ -107,8 +158,11 @@ func (g *grpcClient) Store(ctx context.Context, auth *client.UserAuthorization,
 
 	if err := streamingC.Send(&pb.ResourceOperationData{
 		Data: &pb.ResourceOperationData_Meta{Meta: &pb.ResourceOperationData_ResourceMeta{
-			Salt:             salt,
-			ResourceByteSize: &fileSize,
+			Salt:             meta.Salt,
+			ResourceByteSize: &meta.Size,
+			Metadata:         meta.Metadata,
+			Kind:             &meta.Kind,
+			Sha256:           meta.Digest,
 		}},
 	}); err != nil {
 		return nil, err
@@ -117,24 +171,150 @@ func (g *grpcClient) Store(ctx context.Context, auth *client.UserAuthorization,
 	return &grpcResourceUploader{sC: streamingC}, nil
 }
 
-func (g *grpcClient) List(ctx context.Context, auth *client.UserAuthorization) (client.RemoteResourcesReader, error) {
+func (g *grpcClient) CreateUpload(ctx context.Context, auth *client.UserAuthorization, meta *client.ResourceMeta) (*client.UploadSession, error) {
+	rctx := addAuth(ctx, auth)
+	session, err := g.storageC.CreateUpload(rctx, &pb.CreateUploadRequest{
+		Meta: &pb.ResourceOperationData_ResourceMeta{
+			Salt:             meta.Salt,
+			ResourceByteSize: &meta.Size,
+			Metadata:         meta.Metadata,
+			Kind:             &meta.Kind,
+			Sha256:           meta.Digest,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return uploadSessionFromProto(session), nil
+}
+
+func (g *grpcClient) UploadStatus(ctx context.Context, auth *client.UserAuthorization, uploadID string) (*client.UploadSession, error) {
 	rctx := addAuth(ctx, auth)
-	req := &pb.ListRequest{}
-	listC, err := g.storageC.List(rctx, req)
+	session, err := g.storageC.GetUpload(rctx, &pb.UploadSessionRequest{
+		UploadId: &uploadID,
+	})
 	if err != nil {
 		return nil, err
 	}
-	return &grpcRemoteResourceReader{lC: listC}, nil
+	return uploadSessionFromProto(session), nil
 }
 
-func (g *grpcClient) Get(ctx context.Context, auth *client.UserAuthorization, resourceId string) (client.ResourceDownloader, error) {
+func (g *grpcClient) AppendUpload(ctx context.Context, auth *client.UserAuthorization, uploadID string) (client.UploadAppender, error) {
 	rctx := addAuth(ctx, auth)
-	c, err := g.storageC.Get(rctx, &pb.Resource{
+	c, err := g.storageC.AppendUpload(rctx)
+	if err != nil {
+		return nil, err
+	}
+	return &grpcUploadAppender{
+		sC:       c,
+		uploadID: uploadID,
+	}, nil
+}
+
+func (g *grpcClient) FinalizeUpload(ctx context.Context, auth *client.UserAuthorization, uploadID string) (*client.ResourceInfo, error) {
+	rctx := addAuth(ctx, auth)
+	m, err := g.storageC.FinalizeUpload(rctx, &pb.UploadSessionRequest{
+		UploadId: &uploadID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	errCode := pb.ErrorCode(m.GetErrorCode())
+	if errCode != pb.ErrorCode_ERROR_CODE_OK {
+		return nil, fmt.Errorf("got an error from the server: %s", errCode.String())
+	}
+	return resourceInfoFromProto(m.GetResource()), nil
+}
+
+func (g *grpcClient) MissingChunks(ctx context.Context, auth *client.UserAuthorization, digests [][]byte) ([][]byte, error) {
+	rctx := addAuth(ctx, auth)
+	m, err := g.storageC.MissingChunks(rctx, &pb.ChunkList{Sha256: digests})
+	if err != nil {
+		return nil, err
+	}
+	return m.Sha256, nil
+}
+
+func (g *grpcClient) PutChunks(ctx context.Context, auth *client.UserAuthorization, chunks []client.Chunk) error {
+	rctx := addAuth(ctx, auth)
+	c, err := g.storageC.PutChunks(rctx)
+	if err != nil {
+		return err
+	}
+
+	for _, chunk := range chunks {
+		if err := c.Send(&pb.Chunk{Sha256: chunk.Digest, Data: chunk.Data}); err != nil {
+			_ = c.CloseSend()
+			return err
+		}
+	}
+
+	m, err := c.CloseAndRecv()
+	if err != nil {
+		return err
+	}
+	if int(m.GetChunksReceived()) != len(chunks) {
+		return fmt.Errorf("server received %d of %d chunks", m.GetChunksReceived(), len(chunks))
+	}
+	return nil
+}
+
+func (g *grpcClient) AddChunked(ctx context.Context, auth *client.UserAuthorization, meta *client.ResourceMeta, digests [][]byte) (*client.ResourceInfo, error) {
+	rctx := addAuth(ctx, auth)
+	m, err := g.storageC.AddChunked(rctx, &pb.AddChunkedRequest{
+		Meta: &pb.ResourceOperationData_ResourceMeta{
+			Salt:             meta.Salt,
+			ResourceByteSize: &meta.Size,
+			Metadata:         meta.Metadata,
+			Kind:             &meta.Kind,
+			Sha256:           meta.Digest,
+		},
+		Chunks: digests,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	errCode := pb.ErrorCode(m.GetErrorCode())
+	if errCode != pb.ErrorCode_ERROR_CODE_OK {
+		return nil, fmt.Errorf("got an error from the server: %s", errCode.String())
+	}
+	return resourceInfoFromProto(m.GetResource()), nil
+}
+
+func (g *grpcClient) List(ctx context.Context, auth *client.UserAuthorization) (client.RemoteResourcesReader, error) {
+	r := &grpcRemoteResourceReader{
+		storageC: g.storageC,
+		ctx:      addAuth(ctx, auth),
+		pageSize: _listPageSize,
+	}
+	if err := r.nextPage(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (g *grpcClient) Stat(ctx context.Context, auth *client.UserAuthorization, resourceId string) (*client.ResourceInfo, error) {
+	rctx := addAuth(ctx, auth)
+	res, err := g.storageC.Stat(rctx, &pb.Resource{
 		Id: &resourceId,
 	})
 	if err != nil {
 		return nil, err
 	}
+	return resourceInfoFromProto(res), nil
+}
+
+func (g *grpcClient) Get(ctx context.Context, auth *client.UserAuthorization, resourceId string, offset uint64) (client.ResourceDownloader, error) {
+	rctx := addAuth(ctx, auth)
+	c, err := g.storageC.Get(rctx, &pb.GetRequest{
+		Id:     &resourceId,
+		Offset: &offset,
+	})
+	if err != nil {
+		return nil, err
+	}
 	return &grpcResourceDownloader{
 		C: c,
 	}, nil
@@ -148,6 +328,150 @@ func (g *grpcClient) Delete(ctx context.Context, auth *client.UserAuthorization,
 	return err
 }
 
+func (g *grpcClient) ListDeleted(ctx context.Context, auth *client.UserAuthorization) (client.RemoteResourcesReader, error) {
+	r := &grpcRemoteResourceReader{
+		storageC: g.storageC,
+		ctx:      addAuth(ctx, auth),
+		pageSize: _listPageSize,
+		deleted:  true,
+	}
+	if err := r.nextPage(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (g *grpcClient) Restore(ctx context.Context, auth *client.UserAuthorization, resourceId string) (*client.ResourceInfo, error) {
+	rctx := addAuth(ctx, auth)
+	res, err := g.storageC.Restore(rctx, &pb.Resource{
+		Id: &resourceId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resourceInfoFromProto(res), nil
+}
+
+func (g *grpcClient) EmptyTrash(ctx context.Context, auth *client.UserAuthorization) (int, error) {
+	rctx := addAuth(ctx, auth)
+	m, err := g.storageC.EmptyTrash(rctx, &pb.EmptyTrashRequest{})
+	if err != nil {
+		return 0, err
+	}
+	return int(m.GetPurged()), nil
+}
+
+func (g *grpcClient) Usage(ctx context.Context, auth *client.UserAuthorization) (*client.Usage, error) {
+	rctx := addAuth(ctx, auth)
+	m, err := g.storageC.Usage(rctx, &pb.UsageRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return &client.Usage{
+		Bytes:    m.GetBytes(),
+		Items:    m.GetItems(),
+		MaxBytes: m.GetMaxBytes(),
+		MaxItems: m.GetMaxItems(),
+	}, nil
+}
+
+func (g *grpcClient) Batch(ctx context.Context, auth *client.UserAuthorization, items []client.BatchItem, atomic bool) ([]client.BatchResult, error) {
+	req := &pb.BatchRequest{
+		Items:  make([]*pb.BatchRequest_Item, 0, len(items)),
+		Atomic: &atomic,
+	}
+	for _, item := range items {
+		var op pb.BatchOperation
+		switch item.Op {
+		case client.BatchStat:
+			op = pb.BatchOperation_BATCH_OPERATION_STAT
+		case client.BatchDelete:
+			op = pb.BatchOperation_BATCH_OPERATION_DELETE
+		case client.BatchRestore:
+			op = pb.BatchOperation_BATCH_OPERATION_RESTORE
+		default:
+			return nil, fmt.Errorf("unknown batch operation %d", item.Op)
+		}
+		id := item.ID
+		req.Items = append(req.Items, &pb.BatchRequest_Item{Operation: &op, Id: &id})
+	}
+
+	rctx := addAuth(ctx, auth)
+	m, err := g.storageC.Batch(rctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]client.BatchResult, 0, len(m.GetResults()))
+	for _, r := range m.GetResults() {
+		result := client.BatchResult{ID: r.GetId()}
+		if code := codes.Code(r.GetCode()); code == codes.OK {
+			result.Info = resourceInfoFromProto(r.GetResource())
+		} else {
+			result.Err = serverError(r.GetErrorCode(), code, r.GetMessage())
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func serverInfoFromProto(m *pb.ServerInfo) *client.ServerInfo {
+	return &client.ServerInfo{
+		ProtocolVersion:    m.GetProtocolVersion(),
+		MinProtocolVersion: m.GetMinProtocolVersion(),
+		AuthMethods:        m.GetAuthMethods(),
+		KDF: client.KDFParameters{
+			Algorithm:  m.GetKdf().GetAlgorithm(),
+			Iterations: m.GetKdf().GetIterations(),
+			KeySize:    m.GetKdf().GetKeySize(),
+			SaltSize:   m.GetKdf().GetSaltSize(),
+		},
+		Limits: client.ServerLimits{
+			MaxMessageSize:  m.GetLimits().GetMaxMessageSize(),
+			SendChunkSize:   m.GetLimits().GetSendChunkSize(),
+			MaxChunkSize:    m.GetLimits().GetMaxChunkSize(),
+			MaxListPageSize: m.GetLimits().GetMaxListPageSize(),
+			MaxBatchSize:    m.GetLimits().GetMaxBatchSize(),
+		},
+		Features: m.GetFeatures(),
+	}
+}
+
+func resourceInfoFromProto(r *pb.Resource) *client.ResourceInfo {
+	info := &client.ResourceInfo{
+		ID:        r.GetId(),
+		IsDeleted: r.GetIsDeleted(),
+		Size:      r.GetByteSize(),
+		Version:   r.GetVersion(),
+		Salt:      r.GetSalt(),
+		Kind:      r.GetKind(),
+		Digest:    r.GetSha256(),
+		Metadata:  r.GetMetadata(),
+	}
+	if r.CreatedAt != nil {
+		info.CreatedAt = r.CreatedAt.AsTime()
+	}
+	if r.UpdatedAt != nil {
+		info.UpdatedAt = r.UpdatedAt.AsTime()
+	}
+	if r.PurgeAt != nil {
+		info.PurgeAt = r.PurgeAt.AsTime()
+	}
+	return info
+}
+
+func uploadSessionFromProto(s *pb.UploadSession) *client.UploadSession {
+	session := &client.UploadSession{
+		ID:     s.GetUploadId(),
+		Offset: s.GetCommittedOffset(),
+		Size:   s.GetResourceByteSize(),
+	}
+	if s.ExpiresAt != nil {
+		session.ExpiresAt = s.ExpiresAt.AsTime()
+	}
+	return session
+}
+
 func addAuth(c context.Context, auth *client.UserAuthorization) context.Context {
 	md := metadata.Pairs("authorization", fmt.Sprintf("jwt %v", auth.Token))
 	return metautils.NiceMD(md).ToOutgoing(c)
@@ -191,8 +515,40 @@ func (g *grpcResourceUploader) Recv(_ context.Context) (*client.ResourceInfo, er
 	return info, err
 }
 
+type grpcUploadAppender struct {
+	sC       pb.Storage_AppendUploadClient
+	uploadID string
+}
+
+func (g *grpcUploadAppender) Close() error {
+	return g.sC.CloseSend()
+}
+
+func (g *grpcUploadAppender) SendChunk(_ context.Context, offset uint64, data []byte) error {
+	return g.sC.Send(&pb.UploadChunk{
+		UploadId: &g.uploadID,
+		Offset:   &offset,
+		Data:     data,
+	})
+}
+
+func (g *grpcUploadAppender) Recv(_ context.Context) (*client.UploadSession, error) {
+	m, err := g.sC.CloseAndRecv()
+	if err != nil {
+		return nil, err
+	}
+	return uploadSessionFromProto(m), nil
+}
+
+// grpcRemoteResourceReader walks through all ListPage or ListDeleted pages
+// requesting the next one once the current page is exhausted.
 type grpcRemoteResourceReader struct {
-	lC pb.Storage_ListClient
+	storageC  pb.StorageClient
+	ctx       context.Context
+	pageSize  uint32
+	deleted   bool
+	lC        pb.Storage_ListPageClient
+	nextToken string
 }
 
 func (r *grpcRemoteResourceReader) Close() error {
@@ -200,13 +556,49 @@ func (r *grpcRemoteResourceReader) Close() error {
 }
 
 func (r *grpcRemoteResourceReader) Recv(ctx context.Context) (*client.ResourceInfo, error) {
-	resource, err := r.lC.Recv()
+	for {
+		m, err := r.lC.Recv()
+		if err == io.EOF && len(r.nextToken) != 0 {
+			if err := r.nextPage(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if resource := m.GetResource(); resource != nil {
+			return resourceInfoFromProto(resource), nil
+		}
+		r.nextToken = m.GetNextPageToken()
+	}
+}
+
+func (r *grpcRemoteResourceReader) nextPage() error {
+	req := &pb.ListRequest{
+		PageSize: &r.pageSize,
+	}
+	if len(r.nextToken) != 0 {
+		token := r.nextToken
+		req.PageToken = &token
+	}
+
+	var (
+		listC pb.Storage_ListPageClient
+		err   error
+	)
+	if r.deleted {
+		listC, err = r.storageC.ListDeleted(r.ctx, req)
+	} else {
+		listC, err = r.storageC.ListPage(r.ctx, req)
+	}
 	if err != nil {
-		return nil, err
+		return err
 	}
-	return &client.ResourceInfo{
-		ID: *resource.Id,
-	}, nil
+	r.lC = listC
+	r.nextToken = ""
+	return nil
 }
 
 type grpcResourceDownloader struct {
@@ -228,6 +620,7 @@ func (g *grpcResourceDownloader) Recv(context.Context) (*client.ResourceChunck,
 	result := &client.ResourceChunck{}
 	if meta := m.GetMeta(); meta != nil {
 		result.Salt = meta.Salt
+		result.Digest = meta.Sha256
 		result.Size = meta.ResourceByteSize
 	}
 
@@ -0,0 +1,131 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/r4start/goph-keeper/internal/client"
+	"github.com/r4start/goph-keeper/internal/crypto"
+	pb "github.com/r4start/goph-keeper/pkg/grpc/proto"
+)
+
+type mockInfoServer struct {
+	pb.UnimplementedInfoServer
+	info *pb.ServerInfo
+}
+
+func (m *mockInfoServer) ServerInfo(context.Context, *pb.ServerInfoRequest) (*pb.ServerInfo, error) {
+	return m.info, nil
+}
+
+func prepareInfoClient(t *testing.T, info *pb.ServerInfo) pb.InfoClient {
+	lis := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer()
+	if info != nil {
+		pb.RegisterInfoServer(srv, &mockInfoServer{info: info})
+	}
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.DialContext(context.Background(), "",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return lis.Dial()
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+	return pb.NewInfoClient(conn)
+}
+
+func TestFetchServerInfo(t *testing.T) {
+	var (
+		version    uint32 = client.ProtocolVersion
+		algorithm         = client.KDFAlgorithm
+		iterations uint32 = crypto.KeyRounds
+		keySize    uint32 = crypto.KeySize
+		saltSize   uint32 = crypto.SaltSize
+	)
+	info := &pb.ServerInfo{
+		ProtocolVersion:    &version,
+		MinProtocolVersion: &version,
+		AuthMethods:        []string{client.AuthMethodPassword},
+		Kdf: &pb.KdfParameters{
+			Algorithm:  &algorithm,
+			Iterations: &iterations,
+			KeySize:    &keySize,
+			SaltSize:   &saltSize,
+		},
+		Features: []string{"batch"},
+	}
+
+	got, err := fetchServerInfo(prepareInfoClient(t, info))
+	assert.NoError(t, err)
+	assert.Equal(t, version, got.ProtocolVersion)
+	assert.True(t, got.HasFeature("batch"))
+
+	newer := version + 1
+	info.MinProtocolVersion = &newer
+	_, err = fetchServerInfo(prepareInfoClient(t, info))
+	assert.ErrorIs(t, err, client.ErrIncompatibleServer)
+
+	// A server without the info service predates protocol negotiation.
+	_, err = fetchServerInfo(prepareInfoClient(t, nil))
+	assert.ErrorIs(t, err, client.ErrIncompatibleServer)
+}
+
+func TestNewGrpcClient_Unix(t *testing.T) {
+	dir, err := os.MkdirTemp("", "gk")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = os.RemoveAll(dir)
+	})
+
+	path := filepath.Join(dir, "server.sock")
+	lis, err := net.Listen("unix", path)
+	require.NoError(t, err)
+
+	var (
+		version  uint32 = client.ProtocolVersion
+		kdf             = client.KDFAlgorithm
+		rounds   uint32 = crypto.KeyRounds
+		keySize  uint32 = crypto.KeySize
+		saltSize uint32 = crypto.SaltSize
+	)
+	srv := grpc.NewServer()
+	pb.RegisterInfoServer(srv, &mockInfoServer{info: &pb.ServerInfo{
+		ProtocolVersion:    &version,
+		MinProtocolVersion: &version,
+		AuthMethods:        []string{client.AuthMethodPassword},
+		Kdf:                &pb.KdfParameters{Algorithm: &kdf, Iterations: &rounds, KeySize: &keySize, SaltSize: &saltSize},
+	}})
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+	t.Cleanup(srv.Stop)
+
+	c, err := NewGrpcClient(&client.ServerEndpoint{Addr: "unix://" + path})
+	require.NoError(t, err)
+	info, err := c.ServerInfo(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, version, info.ProtocolVersion)
+}
+
+func TestServerEndpoint_Target(t *testing.T) {
+	assert.Equal(t, "localhost:10081", (&client.ServerEndpoint{Addr: "localhost", Port: "10081"}).Target())
+	assert.Equal(t, "[::1]:10081", (&client.ServerEndpoint{Addr: "::1", Port: "10081"}).Target())
+	assert.Equal(t, "unix:///run/gophkeeper.sock",
+		(&client.ServerEndpoint{Addr: "unix:///run/gophkeeper.sock", Port: "10081"}).Target())
+}
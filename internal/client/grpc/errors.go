@@ -0,0 +1,113 @@
+package grpc
+
+import (
+	"context"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/r4start/goph-keeper/internal/client"
+	pb "github.com/r4start/goph-keeper/pkg/grpc/proto"
+)
+
+// _errorDomain is a domain of ErrorInfo details a server attaches to failures.
+const _errorDomain = "gophkeeper"
+
+var _errorKinds = map[pb.ErrorCode]error{
+	pb.ErrorCode_ERROR_CODE_INTERNAL:            client.ErrInternal,
+	pb.ErrorCode_ERROR_CODE_NOT_FOUND:           client.ErrNotFound,
+	pb.ErrorCode_ERROR_CODE_ALREADY_EXISTS:      client.ErrAlreadyExists,
+	pb.ErrorCode_ERROR_CODE_INVALID_ARGUMENT:    client.ErrInvalidArgument,
+	pb.ErrorCode_ERROR_CODE_INVALID_CREDENTIALS: client.ErrInvalidCredentials,
+	pb.ErrorCode_ERROR_CODE_UNAUTHENTICATED:     client.ErrUnauthenticated,
+	pb.ErrorCode_ERROR_CODE_QUOTA_EXCEEDED:      client.ErrQuotaExceeded,
+	pb.ErrorCode_ERROR_CODE_CONFLICT:            client.ErrConflict,
+	pb.ErrorCode_ERROR_CODE_FAILED_PRECONDITION: client.ErrFailedPrecondition,
+	pb.ErrorCode_ERROR_CODE_OUT_OF_RANGE:        client.ErrInvalidArgument,
+	pb.ErrorCode_ERROR_CODE_DATA_LOSS:           client.ErrInvalidArgument,
+}
+
+// _statusKinds classify failures without an ErrorInfo detail.
+var _statusKinds = map[codes.Code]error{
+	codes.Internal:           client.ErrInternal,
+	codes.NotFound:           client.ErrNotFound,
+	codes.AlreadyExists:      client.ErrAlreadyExists,
+	codes.InvalidArgument:    client.ErrInvalidArgument,
+	codes.OutOfRange:         client.ErrInvalidArgument,
+	codes.DataLoss:           client.ErrInvalidArgument,
+	codes.Unauthenticated:    client.ErrUnauthenticated,
+	codes.ResourceExhausted:  client.ErrQuotaExceeded,
+	codes.Aborted:            client.ErrConflict,
+	codes.FailedPrecondition: client.ErrFailedPrecondition,
+	codes.Unavailable:        client.ErrUnavailable,
+	codes.DeadlineExceeded:   client.ErrUnavailable,
+}
+
+func errorUnaryInterceptor(ctx context.Context, method string, req, reply interface{},
+	cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	return translateError(invoker(ctx, method, req, reply, cc, opts...))
+}
+
+func errorStreamInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn,
+	method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	stream, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &errorTranslatingStream{ClientStream: stream}, nil
+}
+
+// errorTranslatingStream translates errors of stream operations. io.EOF is
+// not a status and is returned as is.
+type errorTranslatingStream struct {
+	grpc.ClientStream
+}
+
+func (s *errorTranslatingStream) SendMsg(m interface{}) error {
+	return translateError(s.ClientStream.SendMsg(m))
+}
+
+func (s *errorTranslatingStream) RecvMsg(m interface{}) error {
+	return translateError(s.ClientStream.RecvMsg(m))
+}
+
+func (s *errorTranslatingStream) CloseSend() error {
+	return translateError(s.ClientStream.CloseSend())
+}
+
+// translateError converts a status error into client.ServerError. Unavailable
+// servers and expired deadlines are classified as client.ErrUnavailable, other
+// errors a server didn't classify are returned as is.
+func translateError(err error) error {
+	st, ok := status.FromError(err)
+	if err == nil || !ok {
+		return err
+	}
+
+	for _, detail := range st.Details() {
+		info, ok := detail.(*errdetails.ErrorInfo)
+		if !ok || info.GetDomain() != _errorDomain {
+			continue
+		}
+		if code, ok := pb.ErrorCode_value[info.GetReason()]; ok {
+			return serverError(pb.ErrorCode(code), st.Code(), st.Message())
+		}
+	}
+
+	if _, ok := _statusKinds[st.Code()]; !ok {
+		return err
+	}
+	return serverError(pb.ErrorCode_ERROR_CODE_OK, st.Code(), st.Message())
+}
+
+// serverError classifies a failure by its error code falling back to its
+// status code.
+func serverError(code pb.ErrorCode, statusCode codes.Code, msg string) *client.ServerError {
+	kind, ok := _errorKinds[code]
+	if !ok {
+		kind = _statusKinds[statusCode]
+	}
+	return &client.ServerError{Kind: kind, Message: msg}
+}
@@ -0,0 +1,51 @@
+package grpc
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/r4start/goph-keeper/internal/client"
+	pb "github.com/r4start/goph-keeper/pkg/grpc/proto"
+)
+
+func TestTranslateError(t *testing.T) {
+	withReason := func(code codes.Code, msg string, reason pb.ErrorCode) error {
+		st, err := status.New(code, msg).WithDetails(&errdetails.ErrorInfo{
+			Reason: reason.String(),
+			Domain: _errorDomain,
+		})
+		assert.NoError(t, err)
+		return st.Err()
+	}
+
+	err := translateError(withReason(codes.Unauthenticated, "invalid credentials", pb.ErrorCode_ERROR_CODE_INVALID_CREDENTIALS))
+	assert.ErrorIs(t, err, client.ErrInvalidCredentials)
+	assert.Equal(t, "invalid credentials", err.Error())
+
+	err = translateError(withReason(codes.ResourceExhausted, "quota exceeded", pb.ErrorCode_ERROR_CODE_QUOTA_EXCEEDED))
+	assert.ErrorIs(t, err, client.ErrQuotaExceeded)
+
+	// A server without error details is classified by status codes.
+	err = translateError(status.Error(codes.NotFound, "no such resource"))
+	assert.ErrorIs(t, err, client.ErrNotFound)
+	var serverErr *client.ServerError
+	assert.True(t, errors.As(err, &serverErr))
+	assert.Equal(t, "no such resource", serverErr.Message)
+
+	err = translateError(status.Error(codes.Unavailable, "connection refused"))
+	assert.ErrorIs(t, err, client.ErrUnavailable)
+	err = translateError(status.Error(codes.DeadlineExceeded, "context deadline exceeded"))
+	assert.ErrorIs(t, err, client.ErrUnavailable)
+
+	canceled := status.Error(codes.Canceled, "context canceled")
+	assert.Equal(t, canceled, translateError(canceled))
+
+	assert.Equal(t, io.EOF, translateError(io.EOF))
+	assert.NoError(t, translateError(nil))
+}
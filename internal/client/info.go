@@ -0,0 +1,87 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/r4start/goph-keeper/internal/crypto"
+)
+
+const (
+	// ProtocolVersion is a protocol version the client speaks.
+	ProtocolVersion = 1
+	// MinServerProtocolVersion is the oldest protocol version of supported servers.
+	MinServerProtocolVersion = 1
+
+	AuthMethodPassword = "password"
+	// KDFAlgorithm is the algorithm the client derives master keys with.
+	KDFAlgorithm = "pbkdf2-sha3-512"
+)
+
+var ErrIncompatibleServer = errors.New("incompatible server")
+
+// ServerInfo describes a server and the protocol it speaks.
+type ServerInfo struct {
+	ProtocolVersion    uint32
+	MinProtocolVersion uint32
+	AuthMethods        []string
+	KDF                KDFParameters
+	Limits             ServerLimits
+	Features           []string
+}
+
+// KDFParameters describe derivation of a master key from a user password.
+type KDFParameters struct {
+	Algorithm  string
+	Iterations uint32
+	KeySize    uint32
+	SaltSize   uint32
+}
+
+// ServerLimits are limits of a server. Zero values are unknown.
+type ServerLimits struct {
+	MaxMessageSize  uint64
+	SendChunkSize   uint64
+	MaxChunkSize    uint64
+	MaxListPageSize uint32
+	MaxBatchSize    uint32
+}
+
+func (i *ServerInfo) HasFeature(feature string) bool {
+	for _, f := range i.Features {
+		if f == feature {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckCompatibility fails with ErrIncompatibleServer when the client can't
+// work with a server described by info.
+func CheckCompatibility(info *ServerInfo) error {
+	if info.ProtocolVersion < MinServerProtocolVersion {
+		return fmt.Errorf("%w: server speaks protocol version %d while at least %d is required, update the server",
+			ErrIncompatibleServer, info.ProtocolVersion, MinServerProtocolVersion)
+	}
+
+	if info.MinProtocolVersion > ProtocolVersion {
+		return fmt.Errorf("%w: server requires protocol version %d while the client speaks %d, update the client",
+			ErrIncompatibleServer, info.MinProtocolVersion, ProtocolVersion)
+	}
+
+	hasPassword := false
+	for _, m := range info.AuthMethods {
+		hasPassword = hasPassword || m == AuthMethodPassword
+	}
+	if !hasPassword {
+		return fmt.Errorf("%w: server doesn't support password authorization", ErrIncompatibleServer)
+	}
+
+	kdf := info.KDF
+	if kdf.Algorithm != KDFAlgorithm || kdf.Iterations != crypto.KeyRounds ||
+		kdf.KeySize != crypto.KeySize || kdf.SaltSize != crypto.SaltSize {
+		return fmt.Errorf("%w: server expects master keys derived with %s (%d iterations, %d byte keys, %d byte salts)",
+			ErrIncompatibleServer, kdf.Algorithm, kdf.Iterations, kdf.KeySize, kdf.SaltSize)
+	}
+	return nil
+}
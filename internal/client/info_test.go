@@ -0,0 +1,48 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckCompatibility(t *testing.T) {
+	compatible, err := newMockClient().ServerInfo(context.Background())
+	assert.NoError(t, err)
+	assert.NoError(t, CheckCompatibility(compatible))
+
+	tests := []struct {
+		name   string
+		modify func(info *ServerInfo)
+	}{
+		{
+			name:   "old server",
+			modify: func(info *ServerInfo) { info.ProtocolVersion = 0 },
+		},
+		{
+			name:   "new server",
+			modify: func(info *ServerInfo) { info.MinProtocolVersion = ProtocolVersion + 1 },
+		},
+		{
+			name:   "no password authorization",
+			modify: func(info *ServerInfo) { info.AuthMethods = []string{"oidc"} },
+		},
+		{
+			name:   "other kdf",
+			modify: func(info *ServerInfo) { info.KDF.Algorithm = "argon2id" },
+		},
+		{
+			name:   "other kdf iterations",
+			modify: func(info *ServerInfo) { info.KDF.Iterations /= 2 },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := *compatible
+			tt.modify(&info)
+			assert.ErrorIs(t, CheckCompatibility(&info), ErrIncompatibleServer)
+		})
+	}
+}
@@ -1,31 +1,91 @@
 package client
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"fmt"
 	"io"
+	"time"
 
 	"github.com/google/uuid"
 
 	"github.com/r4start/goph-keeper/internal/client/storage"
+	"github.com/r4start/goph-keeper/internal/crypto"
 )
 
 type mockResource struct {
-	ID   string
-	Salt []byte
-	Data []byte
+	ID       string
+	Salt     []byte
+	Metadata []byte
+	Kind     string
+	Digest   []byte
+	Data     []byte
+	Created  time.Time
+}
+
+func (r *mockResource) Info() ResourceInfo {
+	return ResourceInfo{
+		ID:        r.ID,
+		CreatedAt: r.Created,
+		UpdatedAt: r.Created,
+		Size:      uint64(len(r.Data)),
+		Version:   1,
+		Salt:      r.Salt,
+		Kind:      r.Kind,
+		Digest:    r.Digest,
+		Metadata:  r.Metadata,
+	}
+}
+
+type mockUpload struct {
+	Resource *mockResource
+	Size     uint64
 }
 
 type mockClient struct {
-	User  storage.UserData
-	Files map[string]*mockResource
+	User    storage.UserData
+	Files   map[string]*mockResource
+	Deleted map[string]*mockResource
+	Uploads map[string]*mockUpload
+	Chunks  map[string][]byte
+	// Quota sets limits reported by Usage.
+	Quota Usage
+	// SentChunks is a number of chunks received by PutChunks.
+	SentChunks int
+	// AppendFailures is a number of append streams that break after the first chunk.
+	AppendFailures int
+	// DownloadFailures is a number of downloads that break after the first data chunk.
+	DownloadFailures int
+	// DownloadError is an error broken downloads fail with, ErrUnavailable by default.
+	DownloadError error
+	// DownloadOffsets are offsets downloads started at.
+	DownloadOffsets []uint64
 }
 
 func newMockClient() *mockClient {
 	return &mockClient{
-		Files: make(map[string]*mockResource),
+		Files:   make(map[string]*mockResource),
+		Deleted: make(map[string]*mockResource),
+		Uploads: make(map[string]*mockUpload),
+		Chunks:  make(map[string][]byte),
 	}
 }
 
+func (m *mockClient) ServerInfo(context.Context) (*ServerInfo, error) {
+	return &ServerInfo{
+		ProtocolVersion:    ProtocolVersion,
+		MinProtocolVersion: ProtocolVersion,
+		AuthMethods:        []string{AuthMethodPassword},
+		KDF: KDFParameters{
+			Algorithm:  KDFAlgorithm,
+			Iterations: crypto.KeyRounds,
+			KeySize:    crypto.KeySize,
+			SaltSize:   crypto.SaltSize,
+		},
+	}, nil
+}
+
 func (m *mockClient) Register(ctx context.Context, login, password string, salt []byte) (*UserAuthorization, error) {
 	return nil, nil
 }
@@ -34,34 +94,247 @@ func (m *mockClient) Authorize(ctx context.Context, login, password string) (*Us
 	return nil, nil
 }
 
-func (m *mockClient) Store(ctx context.Context, auth *UserAuthorization, salt []byte, fileSize uint64) (ResourceUploader, error) {
-	return newMockResourceUploader(m, salt, fileSize), nil
+func (m *mockClient) Store(ctx context.Context, auth *UserAuthorization, meta *ResourceMeta) (ResourceUploader, error) {
+	return newMockResourceUploader(m, meta), nil
+}
+
+func (m *mockClient) CreateUpload(ctx context.Context, auth *UserAuthorization, meta *ResourceMeta) (*UploadSession, error) {
+	up := newMockResourceUploader(m, meta)
+	id := up.Resource.ID
+	delete(m.Files, id)
+	m.Uploads[id] = &mockUpload{
+		Resource: up.Resource,
+		Size:     meta.Size,
+	}
+	return m.UploadStatus(ctx, auth, id)
+}
+
+func (m *mockClient) UploadStatus(_ context.Context, _ *UserAuthorization, uploadID string) (*UploadSession, error) {
+	upload, ok := m.Uploads[uploadID]
+	if !ok {
+		return nil, fmt.Errorf("no upload with id:%s", uploadID)
+	}
+	return upload.Session(uploadID), nil
+}
+
+func (m *mockClient) AppendUpload(_ context.Context, _ *UserAuthorization, uploadID string) (UploadAppender, error) {
+	upload, ok := m.Uploads[uploadID]
+	if !ok {
+		return nil, fmt.Errorf("no upload with id:%s", uploadID)
+	}
+
+	a := &mockUploadAppender{
+		ID:     uploadID,
+		Upload: upload,
+	}
+	if m.AppendFailures > 0 {
+		m.AppendFailures--
+		a.BreakAfter = 1
+	}
+	return a, nil
+}
+
+func (m *mockClient) FinalizeUpload(_ context.Context, _ *UserAuthorization, uploadID string) (*ResourceInfo, error) {
+	upload, ok := m.Uploads[uploadID]
+	if !ok {
+		return nil, fmt.Errorf("no upload with id:%s", uploadID)
+	}
+	if uint64(len(upload.Resource.Data)) != upload.Size {
+		return nil, fmt.Errorf("upload %s is incomplete", uploadID)
+	}
+	if digest := sha256.Sum256(upload.Resource.Data); len(upload.Resource.Digest) != 0 &&
+		!bytes.Equal(digest[:], upload.Resource.Digest) {
+		return nil, fmt.Errorf("upload %s is corrupted", uploadID)
+	}
+
+	delete(m.Uploads, uploadID)
+	m.Files[uploadID] = upload.Resource
+	info := upload.Resource.Info()
+	return &info, nil
+}
+
+func (m *mockClient) MissingChunks(_ context.Context, _ *UserAuthorization, digests [][]byte) ([][]byte, error) {
+	missing := make([][]byte, 0)
+	for _, d := range digests {
+		if _, ok := m.Chunks[string(d)]; !ok {
+			missing = append(missing, d)
+		}
+	}
+	return missing, nil
+}
+
+func (m *mockClient) PutChunks(_ context.Context, _ *UserAuthorization, chunks []Chunk) error {
+	for _, c := range chunks {
+		if digest := sha256.Sum256(c.Data); !bytes.Equal(digest[:], c.Digest) {
+			return fmt.Errorf("chunk %x is corrupted", c.Digest)
+		}
+		m.Chunks[string(c.Digest)] = c.Data
+		m.SentChunks++
+	}
+	return nil
+}
+
+func (m *mockClient) AddChunked(ctx context.Context, _ *UserAuthorization, meta *ResourceMeta, digests [][]byte) (*ResourceInfo, error) {
+	up := newMockResourceUploader(m, meta)
+	for _, d := range digests {
+		data, ok := m.Chunks[string(d)]
+		if !ok {
+			delete(m.Files, up.Resource.ID)
+			return nil, fmt.Errorf("chunk %x is missing", d)
+		}
+		up.Resource.Data = append(up.Resource.Data, data...)
+	}
+	if digest := sha256.Sum256(up.Resource.Data); !bytes.Equal(digest[:], meta.Digest) {
+		delete(m.Files, up.Resource.ID)
+		return nil, fmt.Errorf("resource %s is corrupted", up.Resource.ID)
+	}
+
+	info := up.Resource.Info()
+	return &info, nil
 }
 
 func (m *mockClient) List(_ context.Context, _ *UserAuthorization) (RemoteResourcesReader, error) {
 	return newMockResourceReader(m), nil
 }
 
-func (m *mockClient) Get(ctx context.Context, auth *UserAuthorization, resourceId string) (ResourceDownloader, error) {
-	return newMockResourceDownloader(m.Files[resourceId]), nil
+func (m *mockClient) Stat(_ context.Context, _ *UserAuthorization, resourceId string) (*ResourceInfo, error) {
+	res, ok := m.Files[resourceId]
+	if !ok {
+		return nil, fmt.Errorf("no resource with id:%s", resourceId)
+	}
+	info := res.Info()
+	return &info, nil
+}
+
+func (m *mockClient) Get(ctx context.Context, auth *UserAuthorization, resourceId string, offset uint64) (ResourceDownloader, error) {
+	res, ok := m.Files[resourceId]
+	if !ok {
+		return nil, fmt.Errorf("no resource with id:%s", resourceId)
+	}
+	if offset > uint64(len(res.Data)) {
+		return nil, fmt.Errorf("bad offset %d", offset)
+	}
+
+	m.DownloadOffsets = append(m.DownloadOffsets, offset)
+	d := newMockResourceDownloader(res, int(offset))
+	if m.DownloadFailures > 0 {
+		m.DownloadFailures--
+		d.BreakAfter = 1
+		d.BreakErr = m.DownloadError
+	}
+	return d, nil
 }
 
 func (m *mockClient) Delete(ctx context.Context, auth *UserAuthorization, resourceId string) error {
+	if res, ok := m.Files[resourceId]; ok {
+		m.Deleted[resourceId] = res
+		delete(m.Files, resourceId)
+	}
 	return nil
 }
 
+func (m *mockClient) ListDeleted(_ context.Context, _ *UserAuthorization) (RemoteResourcesReader, error) {
+	res := make([]ResourceInfo, 0, len(m.Deleted))
+	for _, v := range m.Deleted {
+		info := v.Info()
+		info.IsDeleted = true
+		res = append(res, info)
+	}
+	return &mockResourceReader{Resources: res}, nil
+}
+
+func (m *mockClient) Restore(_ context.Context, _ *UserAuthorization, resourceId string) (*ResourceInfo, error) {
+	res, ok := m.Deleted[resourceId]
+	if !ok {
+		return nil, fmt.Errorf("no deleted resource with id:%s", resourceId)
+	}
+	m.Files[resourceId] = res
+	delete(m.Deleted, resourceId)
+	info := res.Info()
+	return &info, nil
+}
+
+func (m *mockClient) EmptyTrash(_ context.Context, _ *UserAuthorization) (int, error) {
+	purged := len(m.Deleted)
+	m.Deleted = make(map[string]*mockResource)
+	return purged, nil
+}
+
+func (m *mockClient) Usage(_ context.Context, _ *UserAuthorization) (*Usage, error) {
+	usage := &Usage{MaxBytes: m.Quota.MaxBytes, MaxItems: m.Quota.MaxItems}
+	for _, resources := range []map[string]*mockResource{m.Files, m.Deleted} {
+		for _, res := range resources {
+			usage.Bytes += uint64(len(res.Data))
+			usage.Items++
+		}
+	}
+	return usage, nil
+}
+
+func (m *mockClient) Batch(ctx context.Context, auth *UserAuthorization, items []BatchItem, atomic bool) ([]BatchResult, error) {
+	files := make(map[string]*mockResource, len(m.Files))
+	for k, v := range m.Files {
+		files[k] = v
+	}
+	deleted := make(map[string]*mockResource, len(m.Deleted))
+	for k, v := range m.Deleted {
+		deleted[k] = v
+	}
+
+	results := make([]BatchResult, len(items))
+	for i, item := range items {
+		result := BatchResult{ID: item.ID}
+		switch item.Op {
+		case BatchStat:
+			result.Info, result.Err = m.Stat(ctx, auth, item.ID)
+		case BatchDelete:
+			if res, ok := m.Files[item.ID]; ok {
+				info := res.Info()
+				info.IsDeleted = true
+				result.Info = &info
+				result.Err = m.Delete(ctx, auth, item.ID)
+			} else {
+				result.Err = fmt.Errorf("no resource with id:%s", item.ID)
+			}
+		case BatchRestore:
+			result.Info, result.Err = m.Restore(ctx, auth, item.ID)
+		}
+		if result.Err != nil {
+			result.Info, result.Err = nil, fmt.Errorf("%w: %v", ErrNotFound, result.Err)
+		}
+		results[i] = result
+
+		if result.Err != nil && atomic {
+			m.Files, m.Deleted = files, deleted
+			for j := range results {
+				if j != i {
+					results[j] = BatchResult{ID: items[j].ID, Err: ErrConflict}
+				}
+			}
+			break
+		}
+	}
+	return results, nil
+}
+
 type mockResourceUploader struct {
 	Resource *mockResource
 }
 
-func newMockResourceUploader(c *mockClient, salt []byte, fileSize uint64) *mockResourceUploader {
+func newMockResourceUploader(c *mockClient, meta *ResourceMeta) *mockResourceUploader {
 	id, _ := uuid.NewRandom()
-	s := make([]byte, len(salt))
-	copy(s, salt)
+	s := make([]byte, len(meta.Salt))
+	copy(s, meta.Salt)
+	md := make([]byte, len(meta.Metadata))
+	copy(md, meta.Metadata)
 	res := &mockResource{
-		ID:   id.String(),
-		Salt: s,
-		Data: make([]byte, 0, int(fileSize)),
+		ID:       id.String(),
+		Salt:     s,
+		Metadata: md,
+		Kind:     meta.Kind,
+		Digest:   meta.Digest,
+		Data:     make([]byte, 0, int(meta.Size)),
+		Created:  time.Now().UTC(),
 	}
 	c.Files[id.String()] = res
 	return &mockResourceUploader{
@@ -84,6 +357,53 @@ func (mru *mockResourceUploader) SendChunk(_ context.Context, data []byte) error
 	return nil
 }
 
+func (u *mockUpload) Session(id string) *UploadSession {
+	return &UploadSession{
+		ID:        id,
+		Offset:    uint64(len(u.Resource.Data)),
+		Size:      u.Size,
+		ExpiresAt: u.Resource.Created.Add(time.Hour),
+	}
+}
+
+type mockUploadAppender struct {
+	ID     string
+	Upload *mockUpload
+	// BreakAfter is a number of chunks after which the stream fails. Zero means never.
+	BreakAfter int
+	broken     bool
+}
+
+func (*mockUploadAppender) Close() error {
+	return nil
+}
+
+func (a *mockUploadAppender) SendChunk(_ context.Context, offset uint64, data []byte) error {
+	if a.broken {
+		return io.ErrUnexpectedEOF
+	}
+	if offset != uint64(len(a.Upload.Resource.Data)) {
+		return fmt.Errorf("bad offset %d", offset)
+	}
+	if offset+uint64(len(data)) > a.Upload.Size {
+		return fmt.Errorf("upload is too large")
+	}
+	a.Upload.Resource.Data = append(a.Upload.Resource.Data, data...)
+
+	if a.BreakAfter > 0 {
+		a.BreakAfter--
+		a.broken = a.BreakAfter == 0
+	}
+	return nil
+}
+
+func (a *mockUploadAppender) Recv(_ context.Context) (*UploadSession, error) {
+	if a.broken {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return a.Upload.Session(a.ID), nil
+}
+
 type mockResourceReader struct {
 	Resources  []ResourceInfo
 	ReadOffset int
@@ -92,9 +412,7 @@ type mockResourceReader struct {
 func newMockResourceReader(client *mockClient) *mockResourceReader {
 	res := make([]ResourceInfo, 0, len(client.Files))
 	for _, v := range client.Files {
-		res = append(res, ResourceInfo{
-			ID: v.ID,
-		})
+		res = append(res, v.Info())
 	}
 	return &mockResourceReader{
 		Resources:  res,
@@ -115,15 +433,23 @@ func (mrr *mockResourceReader) Recv(_ context.Context) (*ResourceInfo, error) {
 	return &mrr.Resources[pos], nil
 }
 
+const _mockDownloadChunkSize = 1024 * 1024
+
 type mockResourceDownloader struct {
 	Resource   *mockResource
 	ReadOffset int
+	// BreakAfter is a number of data chunks after which the stream fails. Zero means never.
+	BreakAfter int
+	// BreakErr is an error the broken stream fails with, ErrUnavailable by default.
+	BreakErr error
+	metaSent bool
+	broken   bool
 }
 
-func newMockResourceDownloader(r *mockResource) *mockResourceDownloader {
+func newMockResourceDownloader(r *mockResource, offset int) *mockResourceDownloader {
 	return &mockResourceDownloader{
 		Resource:   r,
-		ReadOffset: -1,
+		ReadOffset: offset,
 	}
 }
 
@@ -132,28 +458,39 @@ func (*mockResourceDownloader) Close() error {
 }
 
 func (mrd *mockResourceDownloader) Recv(ctx context.Context) (*ResourceChunck, error) {
-	if mrd.ReadOffset == -2 {
-		return nil, io.EOF
-	}
-	if mrd.ReadOffset == -1 {
+	if !mrd.metaSent {
 		salt := make([]byte, len(mrd.Resource.Salt))
 		copy(salt, mrd.Resource.Salt)
-		mrd.ReadOffset = 0
+		size := uint64(len(mrd.Resource.Data))
+		mrd.metaSent = true
 		return &ResourceChunck{
-			Salt: salt,
+			Salt:   salt,
+			Digest: mrd.Resource.Digest,
+			Size:   &size,
 		}, nil
 	}
+	if mrd.broken && mrd.BreakErr != nil {
+		return nil, mrd.BreakErr
+	}
+	if mrd.broken {
+		return nil, &ServerError{Kind: ErrUnavailable}
+	}
 	if mrd.ReadOffset == len(mrd.Resource.Data) {
-		size := uint64(mrd.ReadOffset)
-		mrd.ReadOffset = -2
-		return &ResourceChunck{
-			Size: &size,
-		}, nil
+		return nil, io.EOF
 	}
 
-	data := make([]byte, len(mrd.Resource.Data))
-	copy(data, mrd.Resource.Data)
-	mrd.ReadOffset = len(mrd.Resource.Data)
+	end := mrd.ReadOffset + _mockDownloadChunkSize
+	if end > len(mrd.Resource.Data) {
+		end = len(mrd.Resource.Data)
+	}
+	data := make([]byte, end-mrd.ReadOffset)
+	copy(data, mrd.Resource.Data[mrd.ReadOffset:end])
+	mrd.ReadOffset = end
+
+	if mrd.BreakAfter > 0 {
+		mrd.BreakAfter--
+		mrd.broken = mrd.BreakAfter == 0
+	}
 	return &ResourceChunck{
 		Data: data,
 	}, nil
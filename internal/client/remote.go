@@ -0,0 +1,123 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/r4start/goph-keeper/pkg/client/proto"
+
+	"github.com/r4start/goph-keeper/internal/client/storage"
+	"github.com/r4start/goph-keeper/internal/crypto"
+)
+
+const (
+	ResourceKindFile     = "file"
+	ResourceKindPassword = "password"
+	ResourceKindCard     = "card"
+)
+
+// ResourceKind returns a plain text kind of a data type which is sent to a server along with a resource.
+func ResourceKind(t pb.DataType) string {
+	switch t {
+	case pb.DataType_DATA_TYPE_BINARY:
+		return ResourceKindFile
+	case pb.DataType_DATA_TYPE_CREDENTIALS:
+		return ResourceKindPassword
+	case pb.DataType_DATA_TYPE_CARD_CREDENTIALS:
+		return ResourceKindCard
+	}
+	return ""
+}
+
+// RemoteResource is a server side resource with decrypted metadata.
+type RemoteResource struct {
+	ResourceInfo
+	Type pb.DataType
+	Name string
+}
+
+// RemoteLister lists resources stored on a server without downloading their content.
+type RemoteLister struct {
+	client  Client
+	storage storage.Storage
+}
+
+func NewRemoteLister(client Client, storage storage.Storage) *RemoteLister {
+	return &RemoteLister{
+		client:  client,
+		storage: storage,
+	}
+}
+
+func (l *RemoteLister) List(ctx context.Context) ([]RemoteResource, error) {
+	userData, err := l.storage.UserData(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	auth := &UserAuthorization{
+		Token:  userData.Token,
+		UserID: userData.UserID,
+	}
+
+	reader, err := l.client.List(ctx, auth)
+	if err != nil {
+		return nil, err
+	}
+	return decodeRemoteResources(ctx, userData.MasterKey, reader)
+}
+
+// decodeRemoteResources reads all resources of reader and closes it.
+func decodeRemoteResources(ctx context.Context, masterKey []byte, reader RemoteResourcesReader) ([]RemoteResource, error) {
+	defer func() {
+		_ = reader.Close()
+	}()
+
+	result := make([]RemoteResource, 0)
+	for {
+		info, err := reader.Recv(ctx)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		res, err := decodeRemoteResource(ctx, masterKey, info)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *res)
+	}
+
+	return result, nil
+}
+
+func decodeRemoteResource(ctx context.Context, masterKey []byte, info *ResourceInfo) (*RemoteResource, error) {
+	res := &RemoteResource{ResourceInfo: *info}
+	if len(info.Metadata) == 0 {
+		return res, nil
+	}
+
+	decoder, err := crypto.RestoreAesGcmEncoder(masterKey, info.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore decoder for resource %s: %w", info.ID, err)
+	}
+
+	data, err := decoder.Decode(ctx, info.Metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode metadata of resource %s: %w", info.ID, err)
+	}
+
+	var md pb.ResourceMetadata
+	if err := proto.Unmarshal(data, &md); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal metadata of resource %s: %w", info.ID, err)
+	}
+
+	res.Type = md.GetType()
+	res.Name = md.GetName()
+	return res, nil
+}
@@ -0,0 +1,52 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	pb "github.com/r4start/goph-keeper/pkg/client/proto"
+
+	"github.com/r4start/goph-keeper/internal/client/storage"
+)
+
+func TestRemoteLister_List(t *testing.T) {
+	ctx := context.Background()
+	tempDir := t.TempDir()
+	st := storage.NewMockStorage()
+	client := newMockClient()
+	up := NewUploader(client, st, tempDir)
+
+	card := storage.CardData{
+		Name:         "Test card",
+		Number:       "5555 5555 5555 5555",
+		Holder:       "Tririr Eritndcxh",
+		ExpiryDate:   "11/22",
+		SecurityCode: "111",
+	}
+	assert.NoError(t, up.UploadCard(ctx, card))
+
+	cred := storage.CredentialData{
+		Username:    "uu1",
+		Password:    "sjksjs",
+		Uri:         "snshjs",
+		Description: "dsjdsjd",
+	}
+	assert.NoError(t, up.UploadCredentials(ctx, cred))
+
+	lister := NewRemoteLister(client, st)
+	resources, err := lister.List(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(resources))
+
+	names := make(map[pb.DataType]string)
+	for _, r := range resources {
+		assert.NotZero(t, r.Size)
+		assert.NotZero(t, r.CreatedAt)
+		names[r.Type] = r.Name
+	}
+
+	assert.Equal(t, card.Name, names[pb.DataType_DATA_TYPE_CARD_CREDENTIALS])
+	assert.Equal(t, cred.Uri, names[pb.DataType_DATA_TYPE_CREDENTIALS])
+}
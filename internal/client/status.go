@@ -0,0 +1,52 @@
+package client
+
+import (
+	"context"
+
+	"github.com/r4start/goph-keeper/internal/client/storage"
+)
+
+// Status describes an account of an authorized user.
+type Status struct {
+	UserID string
+	Usage  Usage
+	Server ServerInfo
+}
+
+type StatusReporter struct {
+	client  Client
+	storage storage.Storage
+}
+
+func NewStatusReporter(client Client, storage storage.Storage) *StatusReporter {
+	return &StatusReporter{
+		client:  client,
+		storage: storage,
+	}
+}
+
+func (r *StatusReporter) Status(ctx context.Context) (*Status, error) {
+	userData, err := r.storage.UserData(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	usage, err := r.client.Usage(ctx, &UserAuthorization{
+		Token:  userData.Token,
+		UserID: userData.UserID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := r.client.ServerInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Status{
+		UserID: userData.UserID,
+		Usage:  *usage,
+		Server: *info,
+	}, nil
+}
@@ -0,0 +1,34 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/r4start/goph-keeper/internal/client/storage"
+)
+
+func TestStatusReporter_Status(t *testing.T) {
+	ctx := context.Background()
+	st := storage.NewMockStorage()
+	client := newMockClient()
+	client.Quota = Usage{MaxBytes: 1024 * 1024, MaxItems: 100}
+	up := NewUploader(client, st, t.TempDir())
+
+	cred := storage.CredentialData{
+		Username:    "uu1",
+		Password:    "sjksjs",
+		Uri:         "snshjs",
+		Description: "dsjdsjd",
+	}
+	assert.NoError(t, up.UploadCredentials(ctx, cred))
+
+	status, err := NewStatusReporter(client, st).Status(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), status.Usage.Items)
+	assert.NotZero(t, status.Usage.Bytes)
+	assert.Equal(t, uint64(1024*1024), status.Usage.MaxBytes)
+	assert.Equal(t, uint64(100), status.Usage.MaxItems)
+	assert.Equal(t, uint32(ProtocolVersion), status.Server.ProtocolVersion)
+}
@@ -1,13 +1,18 @@
 package client
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/hashicorp/go-multierror"
+	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/protobuf/proto"
 
@@ -15,16 +20,23 @@ import (
 
 	"github.com/r4start/goph-keeper/internal/client/storage"
 	"github.com/r4start/goph-keeper/internal/crypto"
+	"github.com/r4start/goph-keeper/internal/tracing"
 )
 
 const (
 	_operationTimeout = 500 * time.Millisecond
 
+	_defaultDownloadRetries = 3
+	_downloadRetryDelay     = 500 * time.Millisecond
+	_partialDownloadSuffix  = ".part"
+
 	ResourceTypeBinary = resourceType(iota)
 	ResourceTypeCredentials
 	ResourceTypeCardCredentials
 )
 
+var ErrResourceCorrupted = errors.New("resource data doesn't match its digest")
+
 type resourceType int
 
 type resourcePair struct {
@@ -40,6 +52,8 @@ type Synchronizer struct {
 	syncDirectory    string
 	limit            int
 	operationTimeout time.Duration
+	retries          int
+	retryDelay       time.Duration
 }
 
 func NewSynchronizer(
@@ -54,6 +68,8 @@ func NewSynchronizer(
 		syncDirectory:    syncDirectory,
 		limit:            1,
 		operationTimeout: _operationTimeout,
+		retries:          _defaultDownloadRetries,
+		retryDelay:       _downloadRetryDelay,
 	}
 
 	for _, op := range opts {
@@ -63,7 +79,12 @@ func NewSynchronizer(
 	return s
 }
 
-func (s *Synchronizer) Sync(ctx context.Context) error {
+func (s *Synchronizer) Sync(ctx context.Context) (err error) {
+	ctx, span := tracing.Start(ctx, "Synchronizer.Sync")
+	defer func() {
+		tracing.End(span, err)
+	}()
+
 	userData, err := s.storage.UserData(ctx)
 	if err != nil {
 		return err
@@ -102,6 +123,11 @@ func (s *Synchronizer) Sync(ctx context.Context) error {
 	}
 
 	toDownload, toDelete := createSyncLists(local, serverFiles)
+	span.SetAttributes(
+		attribute.Int("sync.remote", len(serverFiles)),
+		attribute.Int("sync.local", len(local)),
+		attribute.Int("sync.download", len(toDownload)),
+		attribute.Int("sync.delete", len(toDelete)))
 
 	errCh := make(chan error)
 	go func() {
@@ -230,50 +256,18 @@ func (s *Synchronizer) downloadResources(ctx context.Context, auth *UserAuthoriz
 }
 
 func (s *Synchronizer) downloadResource(ctx context.Context, auth *UserAuthorization, ud *storage.UserData, id string) error {
-	downloader, err := s.client.Get(ctx, auth, id)
+	d, err := s.fetchResource(ctx, auth, id)
 	if err != nil {
-		return fmt.Errorf("failed to get resource %s: %w", id, err)
-	}
-	defer func() {
-		_ = downloader.Close()
-	}()
-
-	var (
-		salt     []byte
-		fileSize uint64
-	)
-
-	buffer := make([]byte, 0)
-	for {
-		chunk, err := downloader.Recv(ctx)
-		if err != nil {
-			return fmt.Errorf("failed to download resource %s: %w", id, err)
-		}
-
-		if chunk.Salt != nil {
-			if salt != nil {
-				return fmt.Errorf("salt has been already received")
-			}
-			salt = chunk.Salt
-			continue
-		}
-		if chunk.Size != nil {
-			if fileSize != *chunk.Size {
-				return fmt.Errorf("bad files size: %d recvd; %d sent", fileSize, *chunk.Size)
-			}
-			break
-		}
-
-		fileSize += uint64(len(chunk.Data))
-		buffer = append(buffer, chunk.Data...)
+		return err
 	}
+	defer d.remove()
 
-	decoder, err := crypto.RestoreAesGcmEncoder(ud.MasterKey, salt)
+	decoder, err := crypto.RestoreAesGcmEncoder(ud.MasterKey, d.salt)
 	if err != nil {
 		return fmt.Errorf("failed to restore decoder for resource %s: %w", id, err)
 	}
 
-	buffer, err = decoder.Decode(ctx, buffer)
+	buffer, err := decoder.DecodeReader(ctx, d.reader())
 	if err != nil {
 		return fmt.Errorf("failed to decode resource %s: %w", id, err)
 	}
@@ -367,6 +361,194 @@ func (s *Synchronizer) downloadResource(ctx context.Context, auth *UserAuthoriza
 	return nil
 }
 
+// fetchResource downloads encrypted resource data into a file in the sync directory.
+// The file is kept when a download fails, a later download resumes from its end
+// while the resource has the same digest and size.
+func (s *Synchronizer) fetchResource(ctx context.Context, auth *UserAuthorization, id string) (*resourceDownload, error) {
+	d := &resourceDownload{id: id, dir: s.syncDirectory}
+	for attempt := 0; ; {
+		offset := d.offset
+		err := s.fetchRange(ctx, auth, d)
+		if err == nil && d.size != nil && d.offset == *d.size {
+			break
+		}
+		// A stream which ended early or a resumed file is continued at once.
+		if err == nil && d.offset > offset {
+			continue
+		}
+		if err == nil {
+			err = fmt.Errorf("resource %s is truncated", id)
+		}
+		if !retryable(err) || attempt >= s.retries {
+			d.close()
+			return nil, fmt.Errorf("failed to download resource %s: %w", id, err)
+		}
+
+		attempt++
+		timer := time.NewTimer(s.retryDelay * time.Duration(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			d.close()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	// Resources uploaded by older clients have no digest.
+	if len(d.digest) != 0 {
+		hash := sha256.New()
+		if _, err := io.Copy(hash, d.reader()); err != nil {
+			d.close()
+			return nil, fmt.Errorf("failed to read downloaded resource %s: %w", id, err)
+		}
+		if !bytes.Equal(d.digest, hash.Sum(nil)) {
+			d.remove()
+			return nil, fmt.Errorf("%w: %s", ErrResourceCorrupted, id)
+		}
+	}
+	return d, nil
+}
+
+// retryable reports whether a download may pass on a retry. Downloads use no
+// quota, so an exceeded quota is a server out of resources, e.g. rate limited.
+func retryable(err error) bool {
+	return errors.Is(err, ErrUnavailable) || errors.Is(err, ErrQuotaExceeded)
+}
+
+type resourceDownload struct {
+	id     string
+	dir    string
+	file   *os.File
+	salt   []byte
+	digest []byte
+	size   *uint64
+	offset uint64
+}
+
+// partPath names a download file after the digest and the size of a resource,
+// so a file of another version of the resource is never resumed.
+func (d *resourceDownload) partPath() string {
+	return filepath.Join(d.dir, fmt.Sprintf(".%s.%x.%d%s", d.id, d.digest, *d.size, _partialDownloadSuffix))
+}
+
+// open opens a download file of the resource, received data is kept unless
+// the resource has no digest to check it with. Files of other versions of
+// the resource are removed.
+func (d *resourceDownload) open() error {
+	path := d.partPath()
+	if stale, err := filepath.Glob(filepath.Join(d.dir, "."+d.id+".*"+_partialDownloadSuffix)); err == nil {
+		for _, p := range stale {
+			if p != path {
+				_ = os.Remove(p)
+			}
+		}
+	}
+
+	flags := os.O_RDWR | os.O_CREATE
+	if len(d.digest) == 0 {
+		flags |= os.O_TRUNC
+	}
+	f, err := os.OpenFile(path, flags, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create a download file for resource %s: %w", d.id, err)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	if uint64(info.Size()) > *d.size {
+		if err := f.Truncate(0); err != nil {
+			_ = f.Close()
+			return err
+		}
+		info = nil
+	}
+
+	d.file = f
+	if info != nil {
+		d.offset = uint64(info.Size())
+	}
+	return nil
+}
+
+// reader reads downloaded data from the start.
+func (d *resourceDownload) reader() io.Reader {
+	return io.NewSectionReader(d.file, 0, int64(d.offset))
+}
+
+func (d *resourceDownload) close() {
+	if d.file != nil {
+		_ = d.file.Close()
+	}
+}
+
+// remove closes and removes the download file once its data isn't needed.
+func (d *resourceDownload) remove() {
+	if d.file != nil {
+		_ = d.file.Close()
+		_ = os.Remove(d.file.Name())
+	}
+}
+
+// fetchRange receives resource data starting from the current download offset.
+// A download file is opened on the first call, if it keeps data already the call
+// returns, so data is requested from its end.
+func (s *Synchronizer) fetchRange(ctx context.Context, auth *UserAuthorization, d *resourceDownload) error {
+	downloader, err := s.client.Get(ctx, auth, d.id, d.offset)
+	if err != nil {
+		return fmt.Errorf("failed to get resource %s: %w", d.id, err)
+	}
+	defer func() {
+		_ = downloader.Close()
+	}()
+
+	chunk, err := downloader.Recv(ctx)
+	if err != nil {
+		return err
+	}
+	if chunk.Salt == nil || chunk.Size == nil {
+		return fmt.Errorf("no resource meta received")
+	}
+
+	if d.size == nil {
+		d.salt = chunk.Salt
+		d.digest = chunk.Digest
+		d.size = chunk.Size
+		if err := d.open(); err != nil {
+			return err
+		}
+		if d.offset != 0 {
+			return nil
+		}
+	} else if *d.size != *chunk.Size || !bytes.Equal(d.salt, chunk.Salt) || !bytes.Equal(d.digest, chunk.Digest) {
+		return fmt.Errorf("resource %s has been changed during download", d.id)
+	}
+
+	for {
+		chunk, err := downloader.Recv(ctx)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if chunk.Salt != nil || chunk.Size != nil {
+			return fmt.Errorf("unexpected resource meta")
+		}
+
+		if d.offset+uint64(len(chunk.Data)) > *d.size {
+			return fmt.Errorf("bad files size: more than %d bytes sent", *d.size)
+		}
+		if _, err := d.file.WriteAt(chunk.Data, int64(d.offset)); err != nil {
+			return err
+		}
+		d.offset += uint64(len(chunk.Data))
+	}
+}
+
 func listLocalResources(ctx context.Context, storage storage.Storage) (map[string]resourcePair, error) {
 	local := make(map[string]resourcePair)
 
@@ -442,6 +624,15 @@ func WithOperationTimeout(t time.Duration) SynchronizerOption {
 	}
 }
 
+// WithDownloadRetries sets how many times an interrupted download is resumed.
+// Every next attempt waits delay longer than the previous one.
+func WithDownloadRetries(retries int, delay time.Duration) SynchronizerOption {
+	return func(s *Synchronizer) {
+		s.retries = retries
+		s.retryDelay = delay
+	}
+}
+
 type number interface {
 	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint8 | ~uint16 | ~uint32 | ~uint64
 }
@@ -2,7 +2,10 @@ package client
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/r4start/goph-keeper/internal/client/storage"
 	"github.com/stretchr/testify/assert"
@@ -133,3 +136,151 @@ func TestSynchronizer_SyncCred(t *testing.T) {
 	assert.NoError(t, sync.Sync(ctx))
 	assert.Equal(t, 1, len(st.Creds))
 }
+
+func TestSynchronizer_ResumeDownload(t *testing.T) {
+	ctx := context.Background()
+	tempDir := t.TempDir()
+	st := storage.NewMockStorage()
+	client := newMockClient()
+	up := NewUploader(client, st, tempDir)
+
+	tmpDir := t.TempDir()
+	testFile, err := createTestFile(tmpDir)
+	assert.NoError(t, err)
+
+	assert.NoError(t, up.UploadFiles(ctx, []string{testFile}))
+	assert.Equal(t, 1, len(st.Files))
+
+	expected, err := os.ReadFile(testFile)
+	assert.NoError(t, err)
+
+	st.Files = make(map[string]storage.FileData)
+	syncDir := t.TempDir()
+
+	client.DownloadFailures = 2
+	sync := NewSynchronizer(client, st, syncDir, WithDownloadRetries(2, time.Millisecond))
+	assert.NoError(t, sync.Sync(ctx))
+	assert.Equal(t, 1, len(st.Files))
+
+	for _, f := range st.Files {
+		data, err := os.ReadFile(f.Path)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, data)
+	}
+
+	// Only the downloaded file is left in the sync directory.
+	entries, err := os.ReadDir(syncDir)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(entries))
+
+	st.Files = make(map[string]storage.FileData)
+	client.DownloadFailures = 3
+	sync = NewSynchronizer(client, st, t.TempDir(), WithDownloadRetries(2, time.Millisecond))
+	assert.Error(t, sync.Sync(ctx))
+	assert.Empty(t, st.Files)
+}
+
+func TestSynchronizer_CorruptedDownload(t *testing.T) {
+	ctx := context.Background()
+	st := storage.NewMockStorage()
+	client := newMockClient()
+	up := NewUploader(client, st, t.TempDir())
+
+	cred := storage.CredentialData{
+		Username: "uu1",
+		Password: "sjksjs",
+	}
+	assert.NoError(t, up.UploadCredentials(ctx, cred))
+	assert.Equal(t, 1, len(st.Creds))
+
+	for _, f := range client.Files {
+		assert.NotEmpty(t, f.Digest)
+		f.Data[len(f.Data)-1] ^= 0xff
+	}
+
+	st.Creds = make(map[string]storage.CredentialData)
+	sync := NewSynchronizer(client, st, t.TempDir())
+	err := sync.Sync(ctx)
+	assert.ErrorIs(t, err, ErrResourceCorrupted)
+	assert.Empty(t, st.Creds)
+}
+
+func TestSynchronizer_ResumeDownloadAcrossRuns(t *testing.T) {
+	ctx := context.Background()
+	st := storage.NewMockStorage()
+	client := newMockClient()
+	up := NewUploader(client, st, t.TempDir())
+
+	testFile, err := createTestFile(t.TempDir())
+	assert.NoError(t, err)
+	assert.NoError(t, up.UploadFiles(ctx, []string{testFile}))
+	expected, err := os.ReadFile(testFile)
+	assert.NoError(t, err)
+
+	var id string
+	for id = range client.Files {
+	}
+
+	st.Files = make(map[string]storage.FileData)
+	syncDir := t.TempDir()
+
+	client.DownloadFailures = 1
+	sync := NewSynchronizer(client, st, syncDir, WithDownloadRetries(0, time.Millisecond))
+	assert.ErrorIs(t, sync.Sync(ctx), ErrUnavailable)
+	assert.Empty(t, st.Files)
+
+	// Received data is kept for the next run.
+	parts, err := filepath.Glob(filepath.Join(syncDir, "."+id+".*"+_partialDownloadSuffix))
+	assert.NoError(t, err)
+	assert.Len(t, parts, 1)
+	info, err := os.Stat(parts[0])
+	assert.NoError(t, err)
+	assert.EqualValues(t, _mockDownloadChunkSize, info.Size())
+
+	// A file of another version of the resource isn't resumed.
+	stale := filepath.Join(syncDir, "."+id+".00.1"+_partialDownloadSuffix)
+	assert.NoError(t, os.WriteFile(stale, []byte{1}, 0600))
+
+	client.DownloadOffsets = nil
+	sync = NewSynchronizer(client, st, syncDir)
+	assert.NoError(t, sync.Sync(ctx))
+	assert.Equal(t, []uint64{0, _mockDownloadChunkSize}, client.DownloadOffsets)
+	assert.Equal(t, 1, len(st.Files))
+	for _, f := range st.Files {
+		data, err := os.ReadFile(f.Path)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, data)
+	}
+
+	entries, err := os.ReadDir(syncDir)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(entries))
+}
+
+func TestSynchronizer_DownloadNotRetried(t *testing.T) {
+	ctx := context.Background()
+	st := storage.NewMockStorage()
+	client := newMockClient()
+	up := NewUploader(client, st, t.TempDir())
+
+	testFile, err := createTestFile(t.TempDir())
+	assert.NoError(t, err)
+	assert.NoError(t, up.UploadFiles(ctx, []string{testFile}))
+	st.Files = make(map[string]storage.FileData)
+
+	// Only failures which may pass on a retry are retried.
+	client.DownloadFailures = 2
+	client.DownloadError = &ServerError{Kind: ErrInternal}
+	sync := NewSynchronizer(client, st, t.TempDir(), WithDownloadRetries(2, time.Millisecond))
+	assert.ErrorIs(t, sync.Sync(ctx), ErrInternal)
+	assert.Empty(t, st.Files)
+	assert.Len(t, client.DownloadOffsets, 1)
+
+	// The second run resumes the kept file and retries a rate limited download.
+	client.DownloadFailures = 2
+	client.DownloadError = &ServerError{Kind: ErrQuotaExceeded}
+	client.DownloadOffsets = nil
+	assert.NoError(t, sync.Sync(ctx))
+	assert.Equal(t, []uint64{0, _mockDownloadChunkSize, 2 * _mockDownloadChunkSize}, client.DownloadOffsets)
+	assert.Equal(t, 1, len(st.Files))
+}
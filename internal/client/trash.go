@@ -0,0 +1,92 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/r4start/goph-keeper/internal/client/storage"
+)
+
+// Trash manages resources deleted on a server which are not removed by its
+// garbage collection yet. A restored resource is downloaded by the next sync.
+type Trash struct {
+	client  Client
+	storage storage.Storage
+}
+
+func NewTrash(client Client, storage storage.Storage) *Trash {
+	return &Trash{
+		client:  client,
+		storage: storage,
+	}
+}
+
+func (t *Trash) List(ctx context.Context) ([]RemoteResource, error) {
+	userData, auth, err := t.authorization(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := t.client.ListDeleted(ctx, auth)
+	if err != nil {
+		return nil, err
+	}
+	return decodeRemoteResources(ctx, userData.MasterKey, reader)
+}
+
+// Restore restores resources in batches. Resources which can't be restored
+// don't prevent restoring the others and are reported together.
+func (t *Trash) Restore(ctx context.Context, ids []string) error {
+	_, auth, err := t.authorization(ctx)
+	if err != nil {
+		return err
+	}
+
+	var result error
+	for start := 0; start < len(ids); start += _batchSize {
+		end := start + _batchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		items := make([]BatchItem, 0, end-start)
+		for _, id := range ids[start:end] {
+			items = append(items, BatchItem{Op: BatchRestore, ID: id})
+		}
+
+		results, err := t.client.Batch(ctx, auth, items, false)
+		if err != nil {
+			return multierror.Append(result, err)
+		}
+
+		for _, r := range results {
+			if r.Err != nil {
+				result = multierror.Append(result, fmt.Errorf("failed to restore %s: %w", r.ID, r.Err))
+			}
+		}
+	}
+	return result
+}
+
+// Empty removes all deleted resources for good and returns their number.
+func (t *Trash) Empty(ctx context.Context) (int, error) {
+	_, auth, err := t.authorization(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return t.client.EmptyTrash(ctx, auth)
+}
+
+func (t *Trash) authorization(ctx context.Context) (*storage.UserData, *UserAuthorization, error) {
+	userData, err := t.storage.UserData(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return userData, &UserAuthorization{
+		Token:  userData.Token,
+		UserID: userData.UserID,
+	}, nil
+}
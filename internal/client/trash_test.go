@@ -0,0 +1,75 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/r4start/goph-keeper/internal/client/storage"
+)
+
+func TestTrash(t *testing.T) {
+	ctx := context.Background()
+	tempDir := t.TempDir()
+	st := storage.NewMockStorage()
+	client := newMockClient()
+	up := NewUploader(client, st, tempDir)
+
+	card := storage.CardData{
+		Name:         "Test card",
+		Number:       "5555 5555 5555 5555",
+		Holder:       "Tririr Eritndcxh",
+		ExpiryDate:   "11/22",
+		SecurityCode: "111",
+	}
+	assert.NoError(t, up.UploadCard(ctx, card))
+
+	cred := storage.CredentialData{
+		Username:    "uu1",
+		Password:    "sjksjs",
+		Uri:         "snshjs",
+		Description: "dsjdsjd",
+	}
+	assert.NoError(t, up.UploadCredentials(ctx, cred))
+
+	ids := make([]string, 0, 2)
+	for k := range st.Cards {
+		ids = append(ids, k)
+	}
+	for k := range st.Creds {
+		ids = append(ids, k)
+	}
+
+	assert.NoError(t, NewDeleter(client, st).Delete(ctx, ids))
+	assert.Empty(t, st.Cards)
+	assert.Empty(t, st.Creds)
+
+	trash := NewTrash(client, st)
+	deleted, err := trash.List(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, deleted, 2)
+	for _, r := range deleted {
+		assert.True(t, r.IsDeleted)
+		if r.ID == ids[0] {
+			assert.Equal(t, card.Name, r.Name)
+		}
+	}
+
+	assert.NoError(t, trash.Restore(ctx, ids[:1]))
+	assert.Error(t, trash.Restore(ctx, ids[:1]))
+
+	// The next sync brings the restored card back.
+	assert.NoError(t, NewSynchronizer(client, st, tempDir).Sync(ctx))
+	assert.Len(t, st.Cards, 1)
+	assert.Equal(t, card.Number, st.Cards[ids[0]].Number)
+	assert.Empty(t, st.Creds)
+
+	purged, err := trash.Empty(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, purged)
+
+	deleted, err = trash.List(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, deleted)
+}
@@ -1,15 +1,15 @@
 package client
 
 import (
-	"bytes"
 	"context"
+	"crypto/sha256"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/protobuf/proto"
 
@@ -18,12 +18,16 @@ import (
 
 	"github.com/r4start/goph-keeper/internal/client/storage"
 	"github.com/r4start/goph-keeper/internal/crypto"
+	"github.com/r4start/goph-keeper/internal/tracing"
 )
 
 const (
 	_minimalFileSize = 1
 
 	_bufferReadSize = 4 * 1024 * 1024 // 4 MiB
+
+	_defaultUploadRetries = 3
+	_uploadRetryDelay     = 500 * time.Millisecond
 )
 
 type UploaderOption func(u *Uploader)
@@ -34,6 +38,9 @@ type Uploader struct {
 	syncDirectory    string
 	limit            int
 	operationTimeout time.Duration
+	retries          int
+	retryDelay       time.Duration
+	chunker          *chunker
 }
 
 func NewUploader(
@@ -48,6 +55,8 @@ func NewUploader(
 		syncDirectory:    syncDirectory,
 		limit:            1,
 		operationTimeout: time.Second,
+		retries:          _defaultUploadRetries,
+		retryDelay:       _uploadRetryDelay,
 	}
 
 	for _, o := range opts {
@@ -93,7 +102,8 @@ func (u *Uploader) UploadFiles(ctx context.Context, files []string) error {
 				return err
 			}
 
-			info, key, err := u.upload(grctx, userData, userAuth, blob)
+			info, key, err := u.upload(grctx, userData, userAuth, blob,
+				newResourceMetadata(pb.DataType_DATA_TYPE_BINARY, name))
 			if err != nil {
 				path := fmt.Sprintf("%s%c%s", u.syncDirectory, os.PathSeparator, name)
 				if e := os.Remove(path); e != nil {
@@ -126,7 +136,8 @@ func (u *Uploader) UploadCard(ctx context.Context, card storage.CardData) error
 		return err
 	}
 
-	info, _, err := u.upload(ctx, userData, userAuth, data)
+	info, _, err := u.upload(ctx, userData, userAuth, data,
+		newResourceMetadata(pb.DataType_DATA_TYPE_CARD_CREDENTIALS, card.Name))
 	if err != nil {
 		return err
 	}
@@ -152,7 +163,8 @@ func (u *Uploader) UploadCredentials(ctx context.Context, cred storage.Credentia
 		return err
 	}
 
-	info, _, err := u.upload(ctx, userData, userAuth, data)
+	info, _, err := u.upload(ctx, userData, userAuth, data,
+		newResourceMetadata(pb.DataType_DATA_TYPE_CREDENTIALS, cred.Uri))
 	if err != nil {
 		return err
 	}
@@ -244,64 +256,241 @@ func (u *Uploader) upload(
 	user *storage.UserData,
 	authData *UserAuthorization,
 	data []byte,
+	md *pb.ResourceMetadata,
 ) (
-	*ResourceInfo,
-	*crypto.Secret,
-	error,
+	_ *ResourceInfo,
+	_ *crypto.Secret,
+	err error,
 ) {
+	ctx, span := tracing.Start(ctx, "Uploader.upload")
+	span.SetAttributes(
+		attribute.Int("upload.data_size", len(data)),
+		attribute.Bool("upload.chunked", u.chunker != nil))
+	defer func() {
+		tracing.End(span, err)
+	}()
+
 	encoder, err := crypto.NewAesGcmEncoder(user.MasterKey)
 	if err != nil {
 		return nil, nil, err
 	}
-	msg, err := encoder.Encode(ctx, data)
+
+	// Chunks are split from plain data and sealed one by one, so unchanged
+	// parts of data are sealed as before and the server has them already.
+	var (
+		msg    []byte
+		chunks []Chunk
+	)
+	if u.chunker != nil {
+		chunks, msg, err = sealChunks(ctx, user.MasterKey, u.chunker.split(data))
+	} else {
+		msg, err = encoder.Encode(ctx, data)
+	}
 	if err != nil {
 		return nil, nil, err
 	}
 
-	writer, err := u.client.Store(ctx, authData, encoder.Salt(), uint64(len(msg)))
+	rawMetadata, err := proto.Marshal(md)
+	if err != nil {
+		return nil, nil, err
+	}
+	metadata, err := encoder.Encode(ctx, rawMetadata)
 	if err != nil {
 		return nil, nil, err
 	}
-	defer func() {
-		_ = writer.Close()
-	}()
 
-	br := bytes.NewReader(msg)
-	buffer := make([]byte, 0, _bufferReadSize)
-	for {
-		read, err := br.Read(buffer[:cap(buffer)])
-		if read == 0 {
-			if err == nil {
-				continue
-			}
-			if err == io.EOF {
-				break
-			}
-			return nil, nil, err
+	digest := sha256.Sum256(msg)
+	meta := &ResourceMeta{
+		Salt:     encoder.Salt(),
+		Metadata: metadata,
+		Kind:     ResourceKind(md.GetType()),
+		Size:     uint64(len(msg)),
+		Digest:   digest[:],
+	}
+
+	var result *ResourceInfo
+	if u.chunker != nil {
+		result, err = u.uploadChunks(ctx, authData, meta, chunks)
+	} else {
+		result, err = u.uploadSession(ctx, authData, meta, msg)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if result.ErrorCode != 0 {
+		return nil, nil, fmt.Errorf("got an error from a server: %d", result.ErrorCode)
+	}
+	return result, &crypto.Secret{
+		Key:  encoder.Key(),
+		Salt: encoder.Salt(),
+	}, nil
+}
+
+// uploadSession sends msg through a resumable upload session.
+func (u *Uploader) uploadSession(
+	ctx context.Context,
+	authData *UserAuthorization,
+	meta *ResourceMeta,
+	msg []byte,
+) (*ResourceInfo, error) {
+	session, err := u.client.CreateUpload(ctx, authData, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	uploadID := session.ID
+	offset := session.Offset
+	for attempt := 0; ; attempt++ {
+		session, err = u.appendUpload(ctx, authData, uploadID, offset, msg)
+		if err == nil {
+			break
+		}
+		if err := u.waitRetry(ctx, attempt, err); err != nil {
+			return nil, fmt.Errorf("failed to upload %s: %w", uploadID, err)
 		}
 
+		// The server knows how much data it has committed, so continue from there.
+		if status, err := u.client.UploadStatus(ctx, authData, uploadID); err == nil {
+			offset = status.Offset
+		}
+	}
+
+	if session.Offset != session.Size {
+		return nil, fmt.Errorf("upload %s is incomplete: %d of %d bytes", uploadID, session.Offset, session.Size)
+	}
+
+	return u.client.FinalizeUpload(ctx, authData, uploadID)
+}
+
+// sealChunks seals content-defined chunks of plain data. It returns
+// the sealed chunks and their concatenation, which is resource data.
+func sealChunks(ctx context.Context, masterKey []byte, parts [][]byte) ([]Chunk, []byte, error) {
+	chunks := make([]Chunk, 0, len(parts))
+	msg := make([]byte, 0)
+	for _, p := range parts {
+		sealed, err := crypto.EncodeChunk(ctx, masterKey, p)
 		if err != nil {
 			return nil, nil, err
 		}
+		digest := sha256.Sum256(sealed)
+		chunks = append(chunks, Chunk{Digest: digest[:], Data: sealed})
+		msg = append(msg, sealed...)
+	}
+	return chunks, msg, nil
+}
 
-		if err := writer.SendChunk(ctx, buffer[:read]); err != nil {
-			return nil, nil, err
+// uploadChunks sends only those of chunks the server doesn't have yet.
+// A failed attempt is retried by asking the server again, so chunks sent
+// before the failure aren't sent twice.
+func (u *Uploader) uploadChunks(
+	ctx context.Context,
+	authData *UserAuthorization,
+	meta *ResourceMeta,
+	chunks []Chunk,
+) (*ResourceInfo, error) {
+	digests := make([][]byte, 0, len(chunks))
+	byDigest := make(map[string]Chunk, len(chunks))
+	for _, c := range chunks {
+		digests = append(digests, c.Digest)
+		byDigest[string(c.Digest)] = c
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := u.putMissingChunks(ctx, authData, digests, byDigest)
+		if err == nil {
+			break
+		}
+		if err := u.waitRetry(ctx, attempt, err); err != nil {
+			return nil, fmt.Errorf("failed to upload chunks: %w", err)
 		}
+	}
+
+	return u.client.AddChunked(ctx, authData, meta, digests)
+}
 
+func (u *Uploader) putMissingChunks(
+	ctx context.Context,
+	authData *UserAuthorization,
+	digests [][]byte,
+	chunks map[string]Chunk,
+) error {
+	missing, err := u.client.MissingChunks(ctx, authData, digests)
+	if err != nil {
+		return err
 	}
 
-	result, err := writer.Recv(ctx)
+	send := make([]Chunk, 0, len(missing))
+	seen := make(map[string]struct{}, len(missing))
+	for _, d := range missing {
+		if _, ok := seen[string(d)]; ok {
+			continue
+		}
+		seen[string(d)] = struct{}{}
+
+		chunk, ok := chunks[string(d)]
+		if !ok {
+			return fmt.Errorf("server asked for unknown chunk %x", d)
+		}
+		send = append(send, chunk)
+	}
+
+	if len(send) == 0 {
+		return nil
+	}
+	return u.client.PutChunks(ctx, authData, send)
+}
+
+// waitRetry waits before the next attempt. It returns err when there are no attempts left.
+func (u *Uploader) waitRetry(ctx context.Context, attempt int, err error) error {
+	if attempt >= u.retries {
+		return err
+	}
+
+	timer := time.NewTimer(u.retryDelay * time.Duration(attempt+1))
+	select {
+	case <-ctx.Done():
+		timer.Stop()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// appendUpload sends msg to an upload session starting from offset.
+func (u *Uploader) appendUpload(
+	ctx context.Context,
+	authData *UserAuthorization,
+	uploadID string,
+	offset uint64,
+	msg []byte,
+) (*UploadSession, error) {
+	appender, err := u.client.AppendUpload(ctx, authData, uploadID)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
+	defer func() {
+		_ = appender.Close()
+	}()
 
-	if result.ErrorCode != 0 {
-		return nil, nil, fmt.Errorf("got an error from a server: %d", result.ErrorCode)
+	for pos := offset; pos < uint64(len(msg)); pos += _bufferReadSize {
+		end := pos + _bufferReadSize
+		if end > uint64(len(msg)) {
+			end = uint64(len(msg))
+		}
+		if err := appender.SendChunk(ctx, pos, msg[pos:end]); err != nil {
+			return nil, err
+		}
+	}
+
+	return appender.Recv(ctx)
+}
+
+func newResourceMetadata(dt pb.DataType, name string) *pb.ResourceMetadata {
+	return &pb.ResourceMetadata{
+		Type: &dt,
+		Name: &name,
 	}
-	return result, &crypto.Secret{
-		Key:  encoder.Key(),
-		Salt: encoder.Salt(),
-	}, nil
 }
 
 func (u *Uploader) filterFiles(ctx context.Context, files []string) ([]string, error) {
@@ -386,3 +575,20 @@ func WithUploaderOperationTimeout(t time.Duration) UploaderOption {
 		u.operationTimeout = t
 	}
 }
+
+// WithUploaderRetries sets how many times an interrupted upload is resumed.
+// Every next attempt waits delay longer than the previous one.
+func WithUploaderRetries(retries int, delay time.Duration) UploaderOption {
+	return func(u *Uploader) {
+		u.retries = retries
+		u.retryDelay = delay
+	}
+}
+
+// WithChunkedUploads makes the uploader send resources as content-defined chunks,
+// skipping chunks the server already has.
+func WithChunkedUploads() UploaderOption {
+	return func(u *Uploader) {
+		u.chunker = newDefaultChunker()
+	}
+}
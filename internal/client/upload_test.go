@@ -5,10 +5,18 @@ import (
 	"crypto/rand"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/r4start/goph-keeper/internal/client/storage"
+	"github.com/r4start/goph-keeper/internal/crypto"
+	pb "github.com/r4start/goph-keeper/pkg/client/proto"
 )
 
 func TestUploader_UploadCard(t *testing.T) {
@@ -37,6 +45,24 @@ func TestUploader_UploadCard(t *testing.T) {
 	}
 }
 
+func TestUploader_UploadSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(trace.NewNoopTracerProvider())
+
+	up := NewUploader(newMockClient(), storage.NewMockStorage(), t.TempDir())
+	assert.NoError(t, up.UploadCredentials(context.Background(), storage.CredentialData{Username: "uu1"}))
+
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, s := range recorder.Ended() {
+		spans[s.Name()] = s
+	}
+	upload, encode := spans["Uploader.upload"], spans["crypto.Encode"]
+	assert.NotNil(t, upload)
+	assert.NotNil(t, encode)
+	assert.Equal(t, upload.SpanContext().SpanID(), encode.Parent().SpanID())
+}
+
 func TestUploader_UploadCredentials(t *testing.T) {
 	ctx := context.Background()
 	tempDir := t.TempDir()
@@ -96,3 +122,93 @@ func createTestFile(tmpDir string) (string, error) {
 
 	return fileName, nil
 }
+
+func TestUploader_ResumeUpload(t *testing.T) {
+	ctx := context.Background()
+	syncDir := t.TempDir()
+	st := storage.NewMockStorage()
+	c := newMockClient()
+	c.AppendFailures = 2
+	up := NewUploader(c, st, syncDir, WithUploaderRetries(2, time.Millisecond))
+
+	tmpDir := t.TempDir()
+	testFile, err := createTestFile(tmpDir)
+	assert.NoError(t, err)
+
+	assert.NoError(t, up.UploadFiles(ctx, []string{testFile}))
+	assert.Equal(t, 1, len(st.Files))
+	assert.Empty(t, c.Uploads)
+	assert.Equal(t, 1, len(c.Files))
+}
+
+func TestUploader_ResumeUploadFailure(t *testing.T) {
+	ctx := context.Background()
+	st := storage.NewMockStorage()
+	c := newMockClient()
+	c.AppendFailures = 3
+	up := NewUploader(c, st, t.TempDir(), WithUploaderRetries(2, time.Millisecond))
+
+	cred := storage.CredentialData{
+		Username: "uu1",
+		Password: "sjksjs",
+	}
+
+	// A single chunk upload is committed before the stream breaks.
+	assert.NoError(t, up.UploadCredentials(ctx, cred))
+
+	c.AppendFailures = 3
+	tmpDir := t.TempDir()
+	testFile, err := createTestFile(tmpDir)
+	assert.NoError(t, err)
+
+	assert.Error(t, up.UploadFiles(ctx, []string{testFile}))
+	assert.Empty(t, st.Files)
+	assert.Equal(t, 1, len(c.Uploads))
+}
+
+func TestUploader_ChunkedUpload(t *testing.T) {
+	ctx := context.Background()
+	syncDir := t.TempDir()
+	st := storage.NewMockStorage()
+	c := newMockClient()
+	up := NewUploader(c, st, syncDir, WithChunkedUploads())
+
+	tmpDir := t.TempDir()
+	testFile, err := createTestFile(tmpDir)
+	assert.NoError(t, err)
+
+	assert.NoError(t, up.UploadFiles(ctx, []string{testFile}))
+	assert.Equal(t, 1, len(st.Files))
+	assert.Empty(t, c.Uploads)
+	assert.Equal(t, 1, len(c.Files))
+	assert.Greater(t, c.SentChunks, 1)
+
+	var uploaded *mockResource
+	for _, f := range c.Files {
+		uploaded = f
+	}
+
+	// The same file uploaded by another device of the user is made of chunks
+	// the server already has, so no chunks are sent again.
+	otherSt := storage.NewMockStorage()
+	otherSt.User = st.User
+	sent := c.SentChunks
+	other := NewUploader(c, otherSt, t.TempDir(), WithChunkedUploads())
+	assert.NoError(t, other.UploadFiles(ctx, []string{testFile}))
+	assert.Equal(t, sent, c.SentChunks)
+	assert.Equal(t, 2, len(c.Files))
+
+	original, err := os.ReadFile(testFile)
+	assert.NoError(t, err)
+	for _, f := range c.Files {
+		assert.Equal(t, uploaded.Data, f.Data)
+
+		decoder, err := crypto.RestoreAesGcmEncoder(st.User.MasterKey, f.Salt)
+		assert.NoError(t, err)
+		data, err := decoder.Decode(ctx, f.Data)
+		assert.NoError(t, err)
+		var dr pb.DataResource
+		assert.NoError(t, proto.Unmarshal(data, &dr))
+		assert.Equal(t, original, dr.GetData())
+	}
+}
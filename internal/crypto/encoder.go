@@ -1,18 +1,24 @@
 package crypto
 
 import (
+	"bufio"
 	"context"
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/hmac"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
 
+	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/crypto/hkdf"
 	"golang.org/x/crypto/sha3"
 
 	"github.com/r4start/goph-keeper/internal/client/resource"
+	"github.com/r4start/goph-keeper/internal/tracing"
 )
 
 var (
@@ -23,16 +29,23 @@ var (
 const (
 	nonceSize = 12
 	keySize   = 32
+
+	chunkIDSize = sha256.Size
+	// chunkMarker starts every sealed chunk. A message of Encode starts with
+	// a nonce size, which is never zero, so Decode tells them apart.
+	chunkMarker = 0
 )
 
+var chunkInfo = []byte("goph-keeper chunk")
+
 type aesGCMEncoder struct {
-	bc      cipher.Block
-	aead    cipher.AEAD
-	key     []byte
-	salt    []byte
-	nonce   []byte
-	wr      io.Writer
-	written int
+	masterKey []byte
+	bc        cipher.Block
+	aead      cipher.AEAD
+	key       []byte
+	salt      []byte
+	wr        io.Writer
+	written   int
 }
 
 func NewAesGcmEncoder(masterKey []byte) (*aesGCMEncoder, error) {
@@ -49,11 +62,6 @@ func NewAesGcmEncoder(masterKey []byte) (*aesGCMEncoder, error) {
 		return nil, err
 	}
 
-	nonce := make([]byte, nonceSize)
-	if _, err := rand.Read(nonce); err != nil {
-		return nil, err
-	}
-
 	bc, err := aes.NewCipher(key)
 	if err != nil {
 		return nil, err
@@ -65,11 +73,11 @@ func NewAesGcmEncoder(masterKey []byte) (*aesGCMEncoder, error) {
 	}
 
 	return &aesGCMEncoder{
-		bc:    bc,
-		aead:  aesgcm,
-		key:   key,
-		salt:  salt,
-		nonce: nonce,
+		masterKey: masterKey,
+		bc:        bc,
+		aead:      aesgcm,
+		key:       key,
+		salt:      salt,
 	}, nil
 }
 
@@ -86,11 +94,6 @@ func RestoreAesGcmEncoder(masterKey, salt []byte) (*aesGCMEncoder, error) {
 		return nil, err
 	}
 
-	nonce := make([]byte, nonceSize)
-	if _, err := rand.Read(nonce); err != nil {
-		return nil, err
-	}
-
 	bc, err := aes.NewCipher(key)
 	if err != nil {
 		return nil, err
@@ -102,29 +105,53 @@ func RestoreAesGcmEncoder(masterKey, salt []byte) (*aesGCMEncoder, error) {
 	}
 
 	return &aesGCMEncoder{
-		bc:    bc,
-		aead:  aesgcm,
-		key:   key,
-		salt:  salt,
-		nonce: nonce,
+		masterKey: masterKey,
+		bc:        bc,
+		aead:      aesgcm,
+		key:       key,
+		salt:      salt,
 	}, nil
 }
 
-func (a *aesGCMEncoder) Encode(ctx context.Context, data []byte) ([]byte, error) {
-	nonceLen := uint64(len(a.nonce))
-	if nonceLen == 0 || len(a.key) == 0 {
-		return nil, fmt.Errorf("bad encryption parameters: %d key len; %d nonce len", len(a.key), nonceLen)
+// Encode seals data with a fresh random nonce, so the same encoder can be used
+// for several messages, e.g. for resource content and its metadata.
+func (a *aesGCMEncoder) Encode(ctx context.Context, data []byte) (_ []byte, err error) {
+	_, span := tracing.Start(ctx, "crypto.Encode")
+	span.SetAttributes(attribute.Int("crypto.data_size", len(data)))
+	defer func() {
+		tracing.End(span, err)
+	}()
+
+	if len(a.key) == 0 {
+		return nil, fmt.Errorf("bad encryption parameters: %d key len", len(a.key))
+	}
+
+	nonce := make([]byte, nonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
 	}
 
 	res := make([]byte, binary.MaxVarintLen64)
-	n := binary.PutUvarint(res, nonceLen)
+	n := binary.PutUvarint(res, uint64(len(nonce)))
 	res = res[:n]
-	res = append(res, a.nonce...)
-	res = append(res, a.aead.Seal(nil, a.nonce, data, nil)...)
+	res = append(res, nonce...)
+	res = append(res, a.aead.Seal(nil, nonce, data, nil)...)
 	return res, nil
 }
 
-func (a *aesGCMEncoder) Decode(ctx context.Context, data []byte) ([]byte, error) {
+// Decode opens a message of Encode or a concatenation of chunks sealed
+// by EncodeChunk.
+func (a *aesGCMEncoder) Decode(ctx context.Context, data []byte) (_ []byte, err error) {
+	_, span := tracing.Start(ctx, "crypto.Decode")
+	span.SetAttributes(attribute.Int("crypto.data_size", len(data)))
+	defer func() {
+		tracing.End(span, err)
+	}()
+
+	if len(data) != 0 && data[0] == chunkMarker {
+		return decodeChunks(a.masterKey, data)
+	}
+
 	nonceLen, n := binary.Uvarint(data)
 	if n < 1 {
 		return nil, fmt.Errorf("data format is invalid")
@@ -135,6 +162,40 @@ func (a *aesGCMEncoder) Decode(ctx context.Context, data []byte) ([]byte, error)
 	return a.aead.Open(nil, nonce, buf, nil)
 }
 
+// DecodeReader opens a message read from r like Decode. Sealed chunks are read
+// and opened one by one, so only the opened data is kept in memory.
+func (a *aesGCMEncoder) DecodeReader(ctx context.Context, r io.Reader) (_ []byte, err error) {
+	_, span := tracing.Start(ctx, "crypto.DecodeReader")
+	defer func() {
+		tracing.End(span, err)
+	}()
+
+	br := bufio.NewReader(r)
+	marker, err := br.Peek(1)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	if len(marker) == 0 || marker[0] != chunkMarker {
+		data, err := io.ReadAll(br)
+		if err != nil {
+			return nil, err
+		}
+		return a.Decode(ctx, data)
+	}
+
+	var res []byte
+	for {
+		chunk, err := readChunk(a.masterKey, br)
+		if err == io.EOF {
+			return res, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, chunk...)
+	}
+}
+
 func (a *aesGCMEncoder) Salt() []byte {
 	return a.salt
 }
@@ -142,3 +203,126 @@ func (a *aesGCMEncoder) Salt() []byte {
 func (a *aesGCMEncoder) Key() []byte {
 	return a.key
 }
+
+// EncodeChunk seals a chunk of resource data convergently: a key and a nonce
+// are derived from the master key and a hash of the chunk keyed with it.
+// Equal chunks of a user are sealed equally, so a server deduplicates them,
+// while a server which doesn't know the master key can't confirm a guess
+// of chunk content. A sealed chunk keeps the hash, so it is opened without
+// other data and a concatenation of sealed chunks is opened by Decode.
+func EncodeChunk(ctx context.Context, masterKey, chunk []byte) (_ []byte, err error) {
+	_, span := tracing.Start(ctx, "crypto.EncodeChunk")
+	span.SetAttributes(attribute.Int("crypto.data_size", len(chunk)))
+	defer func() {
+		tracing.End(span, err)
+	}()
+
+	id := chunkID(masterKey, chunk)
+	aead, nonce, err := chunkCipher(masterKey, id)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]byte, 1+chunkIDSize+binary.MaxVarintLen64, 1+chunkIDSize+binary.MaxVarintLen64+len(chunk)+aead.Overhead())
+	res[0] = chunkMarker
+	copy(res[1:], id)
+	n := binary.PutUvarint(res[1+chunkIDSize:], uint64(len(chunk)+aead.Overhead()))
+	res = res[:1+chunkIDSize+n]
+	return aead.Seal(res, nonce, chunk, id), nil
+}
+
+func decodeChunks(masterKey, data []byte) ([]byte, error) {
+	res := make([]byte, 0, len(data))
+	for len(data) != 0 {
+		if data[0] != chunkMarker || len(data) < 1+chunkIDSize {
+			return nil, errors.New("chunk format is invalid")
+		}
+		id := data[1 : 1+chunkIDSize]
+		data = data[1+chunkIDSize:]
+
+		size, n := binary.Uvarint(data)
+		if n < 1 || size > uint64(len(data)-n) {
+			return nil, errors.New("chunk format is invalid")
+		}
+		sealed := data[n : n+int(size)]
+		data = data[n+int(size):]
+
+		aead, nonce, err := chunkCipher(masterKey, id)
+		if err != nil {
+			return nil, err
+		}
+		chunk, err := aead.Open(nil, nonce, sealed, id)
+		if err != nil {
+			return nil, err
+		}
+		if !hmac.Equal(id, chunkID(masterKey, chunk)) {
+			return nil, errors.New("chunk doesn't match its hash")
+		}
+		res = append(res, chunk...)
+	}
+	return res, nil
+}
+
+// readChunk reads and opens a sealed chunk, io.EOF is returned when r has
+// no more chunks.
+func readChunk(masterKey []byte, r *bufio.Reader) ([]byte, error) {
+	marker, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	id := make([]byte, chunkIDSize)
+	if _, err := io.ReadFull(r, id); marker != chunkMarker || err != nil {
+		return nil, errors.New("chunk format is invalid")
+	}
+
+	size, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, errors.New("chunk format is invalid")
+	}
+	sealed, err := io.ReadAll(io.LimitReader(r, int64(size)))
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(sealed)) != size {
+		return nil, errors.New("chunk format is invalid")
+	}
+
+	aead, nonce, err := chunkCipher(masterKey, id)
+	if err != nil {
+		return nil, err
+	}
+	chunk, err := aead.Open(nil, nonce, sealed, id)
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal(id, chunkID(masterKey, chunk)) {
+		return nil, errors.New("chunk doesn't match its hash")
+	}
+	return chunk, nil
+}
+
+func chunkID(masterKey, chunk []byte) []byte {
+	mac := hmac.New(sha256.New, masterKey)
+	mac.Write(chunk)
+	return mac.Sum(nil)
+}
+
+// chunkCipher derives a cipher and a nonce of a chunk. A key is used for
+// chunks with the same content only, so the nonce is derived as well.
+func chunkCipher(masterKey, id []byte) (cipher.AEAD, []byte, error) {
+	h := hkdf.New(sha3.New512, masterKey, id, chunkInfo)
+	material := make([]byte, keySize+nonceSize)
+	if _, err := io.ReadFull(h, material); err != nil {
+		return nil, nil, err
+	}
+
+	bc, err := aes.NewCipher(material[:keySize])
+	if err != nil {
+		return nil, nil, err
+	}
+	aead, err := cipher.NewGCM(bc)
+	if err != nil {
+		return nil, nil, err
+	}
+	return aead, material[keySize:], nil
+}
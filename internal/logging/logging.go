@@ -0,0 +1,54 @@
+// Package logging passes request scoped loggers through contexts, so handlers
+// and storage log with fields of a request, e.g. its id and user.
+package logging
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// RequestIDKey is a gRPC metadata key and an HTTP header with request ids.
+const RequestIDKey = "x-request-id"
+
+const _maxRequestIDSize = 128
+
+type loggerKey struct{}
+
+// WithLogger returns a context carrying l.
+func WithLogger(ctx context.Context, l *zap.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// FromContext returns a logger of a request. Logs are dropped when ctx has no
+// logger.
+func FromContext(ctx context.Context) *zap.Logger {
+	if l, ok := ctx.Value(loggerKey{}).(*zap.Logger); ok {
+		return l
+	}
+	return zap.NewNop()
+}
+
+// With adds fields to a logger of ctx.
+func With(ctx context.Context, fields ...zap.Field) context.Context {
+	return WithLogger(ctx, FromContext(ctx).With(fields...))
+}
+
+// NewRequestID generates an id of a request without one.
+func NewRequestID() string {
+	return uuid.NewString()
+}
+
+// ValidRequestID tells whether an id received from a client is safe to log.
+func ValidRequestID(id string) bool {
+	if len(id) == 0 || len(id) > _maxRequestIDSize {
+		return false
+	}
+	for _, c := range id {
+		if c < '!' || c > '~' {
+			return false
+		}
+	}
+	return true
+}
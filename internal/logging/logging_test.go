@@ -0,0 +1,61 @@
+package logging
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	pb "github.com/r4start/goph-keeper/pkg/grpc/proto"
+)
+
+func TestRedact(t *testing.T) {
+	login, password := "user", "secret password"
+	req := &pb.AuthorizationRequest{Login: &login, Password: &password, Salt: []byte("salt")}
+
+	redacted := Redact(req).(*pb.AuthorizationRequest)
+	assert.Equal(t, login, redacted.GetLogin())
+	assert.Equal(t, Redacted, redacted.GetPassword())
+	assert.Empty(t, redacted.GetSalt())
+	assert.Equal(t, password, req.GetPassword(), "the original message must stay intact")
+
+	token := "jwt"
+	resp := &pb.AuthorizationResponse{Token: &token, RefreshToken: &token}
+	field := Message("response", resp)
+	logged := string(field.Interface.([]byte))
+	assert.NotContains(t, logged, token)
+	assert.Contains(t, logged, Redacted)
+}
+
+func TestRedact_Nested(t *testing.T) {
+	kind := "file"
+	req := &pb.CreateUploadRequest{Meta: &pb.ResourceOperationData_ResourceMeta{Kind: &kind, Salt: []byte("salt")}}
+
+	redacted := Redact(req).(*pb.CreateUploadRequest)
+	assert.Equal(t, kind, redacted.GetMeta().GetKind())
+	assert.Empty(t, redacted.GetMeta().GetSalt())
+}
+
+func TestFromContext(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+
+	ctx := WithLogger(context.Background(), zap.New(core))
+	ctx = With(ctx, zap.String("request_id", "id"))
+	FromContext(ctx).Info("message")
+
+	assert.Equal(t, 1, logs.Len())
+	assert.Equal(t, "id", logs.All()[0].ContextMap()["request_id"])
+
+	FromContext(context.Background()).Info("dropped")
+	assert.Equal(t, 1, logs.Len())
+}
+
+func TestValidRequestID(t *testing.T) {
+	assert.True(t, ValidRequestID(NewRequestID()))
+	assert.False(t, ValidRequestID(""))
+	assert.False(t, ValidRequestID("id\nforged log line"))
+	assert.False(t, ValidRequestID(strings.Repeat("a", _maxRequestIDSize+1)))
+}
@@ -0,0 +1,73 @@
+package logging
+
+import (
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Redacted replaces values of sensitive fields.
+const Redacted = "[REDACTED]"
+
+// _sensitiveFields are names of message fields with secrets.
+var _sensitiveFields = map[protoreflect.Name]bool{
+	"password":      true,
+	"old_password":  true,
+	"new_password":  true,
+	"token":         true,
+	"refresh_token": true,
+	"secret":        true,
+}
+
+// Message logs m as JSON with secrets redacted. Bytes fields are dropped
+// since they keep salts, digests and encrypted data which are of no use in
+// logs.
+func Message(key string, m interface{}) zap.Field {
+	msg, ok := m.(proto.Message)
+	if !ok || msg == nil {
+		return zap.Skip()
+	}
+
+	data, err := protojson.Marshal(Redact(msg))
+	if err != nil {
+		return zap.NamedError(key, err)
+	}
+	return zap.ByteString(key, data)
+}
+
+// Redact returns a copy of m without secrets.
+func Redact(m proto.Message) proto.Message {
+	c := proto.Clone(m)
+	redact(c.ProtoReflect())
+	return c
+}
+
+func redact(m protoreflect.Message) {
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.Kind() == protoreflect.BytesKind:
+			m.Clear(fd)
+		case _sensitiveFields[fd.Name()] && fd.Kind() == protoreflect.StringKind && !fd.IsList() && !fd.IsMap():
+			m.Set(fd, protoreflect.ValueOfString(Redacted))
+		case fd.Kind() == protoreflect.MessageKind || fd.Kind() == protoreflect.GroupKind:
+			switch {
+			case fd.IsList():
+				list := v.List()
+				for i := 0; i < list.Len(); i++ {
+					redact(list.Get(i).Message())
+				}
+			case fd.IsMap():
+				if fd.MapValue().Kind() == protoreflect.MessageKind {
+					v.Map().Range(func(_ protoreflect.MapKey, mv protoreflect.Value) bool {
+						redact(mv.Message())
+						return true
+					})
+				}
+			default:
+				redact(v.Message())
+			}
+		}
+		return true
+	})
+}
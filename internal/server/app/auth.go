@@ -29,11 +29,11 @@ var (
 
 	signingMethod = jwt.SigningMethodHS512
 
-	ErrBadCredentials     = errors.New("bad credentials")
-	ErrInvalidCredentials = errors.New("invalid credentials")
-	ErrBadSignMethod      = errors.New("bad sign method")
-	ErrExpiredToken       = errors.New("expired token")
-	ErrInvalidToken       = errors.New("invalid token")
+	ErrBadCredentials     = storage.NewError(storage.CodeInvalidArgument, "bad credentials")
+	ErrInvalidCredentials = storage.NewError(storage.CodeInvalidCredentials, "invalid credentials")
+	ErrBadSignMethod      = storage.NewError(storage.CodeUnauthenticated, "bad sign method")
+	ErrExpiredToken       = storage.NewError(storage.CodeUnauthenticated, "expired token")
+	ErrInvalidToken       = storage.NewError(storage.CodeUnauthenticated, "invalid token")
 )
 
 type AuthData struct {
@@ -105,6 +105,10 @@ func (a *authorizerImpl) Authorize(ctx context.Context, login, password string)
 	}
 
 	u, err := a.userService.GetByLogin(ctx, login)
+	if errors.Is(err, storage.ErrUserNotFound) {
+		// Unknown logins are not told apart from wrong passwords.
+		return nil, ErrInvalidCredentials
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -152,11 +156,14 @@ func (a *authorizerImpl) AuthorizeWithToken(ctx context.Context, token string) (
 	}
 
 	user, err := a.userService.GetByID(ctx, claims.UserID)
+	if errors.Is(err, storage.ErrUserNotFound) {
+		return nil, ErrInvalidToken
+	}
 	if err != nil {
 		return nil, err
 	}
 	if user.IsDeleted {
-		return nil, errors.New("unauthenticated")
+		return nil, ErrInvalidToken
 	}
 
 	return &AuthData{
@@ -3,7 +3,6 @@ package app
 import (
 	"context"
 	"crypto/rand"
-	"errors"
 	"fmt"
 	"sync"
 	"testing"
@@ -16,11 +15,6 @@ import (
 	"github.com/r4start/goph-keeper/internal/server/storage"
 )
 
-var (
-	errUserAlreadyExist = errors.New("user already exist")
-	errUserNotFound     = errors.New("user not found")
-)
-
 func generateToken(t *testing.T, uid string, key []byte, ts time.Time) string {
 	outputToken, err := createSignedToken(signingMethod, ts, tokenSize, tokenAudience, uid, key)
 	assert.NoError(t, err)
@@ -61,7 +55,7 @@ func (m *mockUserService) Add(_ context.Context, login string, keySalt, salt, se
 	})
 
 	if loaded {
-		return nil, errUserAlreadyExist
+		return nil, storage.ErrUserExists
 	}
 
 	return value.(authData).User, nil
@@ -70,7 +64,7 @@ func (m *mockUserService) Add(_ context.Context, login string, keySalt, salt, se
 func (m *mockUserService) GetByLogin(_ context.Context, login string) (*storage.User, error) {
 	u, loaded := m.Users.Load(login)
 	if !loaded {
-		return nil, errUserNotFound
+		return nil, storage.ErrUserNotFound
 	}
 
 	auth := u.(authData)
@@ -204,7 +198,7 @@ func Test_authorizerImpl_Register(t *testing.T) {
 	a, err := NewAuthorizer(usersStorage, signKey)
 	assert.NoError(t, err)
 	_, err = a.Register(ctx, tests[0].args.login, tests[0].args.password, keySalt)
-	assert.Error(t, err)
+	assert.ErrorIs(t, err, storage.ErrUserExists)
 
 	samePassLogin := "samePassTest"
 	_, err = a.Register(ctx, samePassLogin, tests[0].args.password, keySalt)
@@ -344,7 +338,11 @@ func Test_authorizerImpl_Authorize(t *testing.T) {
 	}
 
 	_, err = a.Authorize(ctx, tests[0].args.login, tests[1].args.login)
-	assert.Error(t, err)
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+
+	_, err = a.Authorize(ctx, "unknown", "unknown")
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	assert.Equal(t, storage.CodeInvalidCredentials, storage.CodeOf(err))
 }
 
 func Test_authorizerImpl_IsValidToken(t *testing.T) {
@@ -0,0 +1,63 @@
+package app
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/r4start/goph-keeper/internal/server/storage"
+)
+
+// GCRun is a result of a garbage collection run.
+type GCRun struct {
+	Stats storage.GCStats
+	Err   error
+}
+
+// GCWorker runs garbage collection of a storage one run at a time and keeps
+// the result of the last run. Deleted resources are kept for the retention period.
+type GCWorker struct {
+	gc        storage.GarbageCollector
+	retention time.Duration
+
+	runMutex  sync.Mutex
+	lastMutex sync.RWMutex
+	last      *GCRun
+}
+
+func NewGCWorker(gc storage.GarbageCollector, retention time.Duration) *GCWorker {
+	return &GCWorker{
+		gc:        gc,
+		retention: retention,
+	}
+}
+
+// Collect runs garbage collection. It waits for a run in progress to finish first.
+func (w *GCWorker) Collect(ctx context.Context) (*GCRun, error) {
+	w.runMutex.Lock()
+	defer w.runMutex.Unlock()
+
+	stats, err := w.gc.CollectGarbage(ctx, time.Now().UTC().Add(-w.retention))
+
+	run := &GCRun{Err: err}
+	if stats != nil {
+		run.Stats = *stats
+	}
+
+	w.lastMutex.Lock()
+	w.last = run
+	w.lastMutex.Unlock()
+
+	return run, err
+}
+
+// LastRun returns the result of the last run or nil if there were no runs yet.
+func (w *GCWorker) LastRun() *GCRun {
+	w.lastMutex.RLock()
+	defer w.lastMutex.RUnlock()
+	return w.last
+}
+
+func (w *GCWorker) Retention() time.Duration {
+	return w.retention
+}
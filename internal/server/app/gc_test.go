@@ -0,0 +1,40 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/r4start/goph-keeper/internal/server/storage"
+)
+
+type mockGarbageCollector struct {
+	deletedBefore time.Time
+	err           error
+}
+
+func (m *mockGarbageCollector) CollectGarbage(_ context.Context, deletedBefore time.Time) (*storage.GCStats, error) {
+	m.deletedBefore = deletedBefore
+	return &storage.GCStats{Tombstones: 3, StartedAt: time.Now()}, m.err
+}
+
+func TestGCWorker_Collect(t *testing.T) {
+	gc := &mockGarbageCollector{}
+	w := NewGCWorker(gc, time.Hour)
+	assert.Nil(t, w.LastRun())
+
+	run, err := w.Collect(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 3, run.Stats.Tombstones)
+	assert.WithinDuration(t, time.Now().Add(-time.Hour), gc.deletedBefore, time.Minute)
+	assert.Equal(t, run, w.LastRun())
+
+	gc.err = errors.New("failure")
+	_, err = w.Collect(context.Background())
+	assert.ErrorIs(t, err, gc.err)
+	assert.ErrorIs(t, w.LastRun().Err, gc.err)
+	assert.Equal(t, 3, w.LastRun().Stats.Tombstones)
+}
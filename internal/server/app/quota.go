@@ -0,0 +1,94 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/r4start/goph-keeper/internal/server/storage"
+)
+
+var ErrQuotaExceeded = storage.NewError(storage.CodeQuotaExceeded, "quota exceeded")
+
+// Quotas applies per user quotas falling back to a server wide default one.
+type Quotas struct {
+	store    storage.QuotaStorage
+	mu       sync.RWMutex
+	defaults storage.Quota
+}
+
+func NewQuotas(store storage.QuotaStorage, defaults storage.Quota) *Quotas {
+	return &Quotas{
+		store:    store,
+		defaults: defaults,
+	}
+}
+
+// Defaults returns the server wide default quota.
+func (q *Quotas) Defaults() storage.Quota {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	return q.defaults
+}
+
+// SetDefaults replaces the default quota, e.g. on configuration reload.
+// Users with their own quotas are not affected.
+func (q *Quotas) SetDefaults(defaults storage.Quota) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.defaults = defaults
+}
+
+// Limits returns a quota of a user and whether it is the default one.
+func (q *Quotas) Limits(ctx context.Context, user *storage.UserID) (*storage.Quota, bool, error) {
+	quota, err := q.store.Quota(ctx, user)
+	if errors.Is(err, storage.ErrQuotaNotSet) {
+		defaults := q.Defaults()
+		return &defaults, true, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return quota, false, nil
+}
+
+// Usage returns current usage of a user along with its quota.
+func (q *Quotas) Usage(ctx context.Context, user *storage.UserID) (*storage.Usage, *storage.Quota, error) {
+	quota, _, err := q.Limits(ctx, user)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	usage, err := q.store.Usage(ctx, user)
+	if err != nil {
+		return nil, nil, err
+	}
+	return usage, quota, nil
+}
+
+// Check fails with ErrQuotaExceeded when adding bytes and items would exceed
+// a quota of a user. Concurrent additions are checked independently, so they
+// may overshoot the quota a little.
+func (q *Quotas) Check(ctx context.Context, user *storage.UserID, bytes, items uint64) error {
+	usage, quota, err := q.Usage(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	if !quota.Allows(usage, bytes, items) {
+		return fmt.Errorf("%w: %d of %d bytes and %d of %d items are used",
+			ErrQuotaExceeded, usage.Bytes, quota.MaxBytes, usage.Items, quota.MaxItems)
+	}
+	return nil
+}
+
+// Override sets a quota of a user replacing the default one.
+func (q *Quotas) Override(ctx context.Context, user *storage.UserID, quota *storage.Quota) error {
+	return q.store.SetQuota(ctx, user, quota)
+}
+
+// Reset brings back the default quota of a user.
+func (q *Quotas) Reset(ctx context.Context, user *storage.UserID) error {
+	return q.store.DeleteQuota(ctx, user)
+}
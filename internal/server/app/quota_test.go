@@ -0,0 +1,87 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/r4start/goph-keeper/internal/server/storage"
+)
+
+type mockQuotaStorage struct {
+	usage  storage.Usage
+	quotas map[storage.UserID]storage.Quota
+}
+
+func (m *mockQuotaStorage) Usage(context.Context, *storage.UserID) (*storage.Usage, error) {
+	usage := m.usage
+	return &usage, nil
+}
+
+func (m *mockQuotaStorage) Quota(_ context.Context, user *storage.UserID) (*storage.Quota, error) {
+	quota, ok := m.quotas[*user]
+	if !ok {
+		return nil, storage.ErrQuotaNotSet
+	}
+	return &quota, nil
+}
+
+func (m *mockQuotaStorage) SetQuota(_ context.Context, user *storage.UserID, quota *storage.Quota) error {
+	m.quotas[*user] = *quota
+	return nil
+}
+
+func (m *mockQuotaStorage) DeleteQuota(_ context.Context, user *storage.UserID) error {
+	delete(m.quotas, *user)
+	return nil
+}
+
+func TestQuotas_Check(t *testing.T) {
+	ctx := context.Background()
+	store := &mockQuotaStorage{
+		usage:  storage.Usage{Bytes: 900, Items: 9},
+		quotas: make(map[storage.UserID]storage.Quota),
+	}
+	q := NewQuotas(store, storage.Quota{MaxBytes: 1000, MaxItems: 10})
+	user := storage.UserID(uuid.New())
+
+	assert.NoError(t, q.Check(ctx, &user, 100, 1))
+	assert.ErrorIs(t, q.Check(ctx, &user, 101, 1), ErrQuotaExceeded)
+	assert.ErrorIs(t, q.Check(ctx, &user, 10, 2), ErrQuotaExceeded)
+
+	quota, isDefault, err := q.Limits(ctx, &user)
+	assert.NoError(t, err)
+	assert.True(t, isDefault)
+	assert.Equal(t, uint64(1000), quota.MaxBytes)
+
+	// Zero limits are unlimited.
+	assert.NoError(t, q.Override(ctx, &user, &storage.Quota{MaxItems: 20}))
+	assert.NoError(t, q.Check(ctx, &user, 1<<40, 11))
+	assert.ErrorIs(t, q.Check(ctx, &user, 0, 12), ErrQuotaExceeded)
+
+	quota, isDefault, err = q.Limits(ctx, &user)
+	assert.NoError(t, err)
+	assert.False(t, isDefault)
+	assert.Equal(t, storage.Quota{MaxItems: 20}, *quota)
+
+	assert.NoError(t, q.Reset(ctx, &user))
+	assert.ErrorIs(t, q.Check(ctx, &user, 101, 1), ErrQuotaExceeded)
+}
+
+func TestQuotas_SetDefaults(t *testing.T) {
+	ctx := context.Background()
+	store := &mockQuotaStorage{
+		usage:  storage.Usage{Bytes: 900, Items: 9},
+		quotas: make(map[storage.UserID]storage.Quota),
+	}
+	q := NewQuotas(store, storage.Quota{MaxBytes: 1000, MaxItems: 10})
+	user := storage.UserID(uuid.New())
+
+	assert.ErrorIs(t, q.Check(ctx, &user, 101, 1), ErrQuotaExceeded)
+
+	q.SetDefaults(storage.Quota{MaxBytes: 2000, MaxItems: 10})
+	assert.Equal(t, storage.Quota{MaxBytes: 2000, MaxItems: 10}, q.Defaults())
+	assert.NoError(t, q.Check(ctx, &user, 101, 1))
+}
@@ -0,0 +1,57 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// _httpStatuses maps gRPC codes to HTTP statuses of error responses.
+var _httpStatuses = map[codes.Code]int{
+	codes.OK:                 http.StatusOK,
+	codes.Canceled:           499,
+	codes.Unknown:            http.StatusInternalServerError,
+	codes.InvalidArgument:    http.StatusBadRequest,
+	codes.DeadlineExceeded:   http.StatusGatewayTimeout,
+	codes.NotFound:           http.StatusNotFound,
+	codes.AlreadyExists:      http.StatusConflict,
+	codes.PermissionDenied:   http.StatusForbidden,
+	codes.ResourceExhausted:  http.StatusTooManyRequests,
+	codes.FailedPrecondition: http.StatusPreconditionFailed,
+	codes.Aborted:            http.StatusConflict,
+	codes.OutOfRange:         http.StatusRequestedRangeNotSatisfiable,
+	codes.Unimplemented:      http.StatusMethodNotAllowed,
+	codes.Internal:           http.StatusInternalServerError,
+	codes.Unavailable:        http.StatusServiceUnavailable,
+	codes.DataLoss:           http.StatusInternalServerError,
+	codes.Unauthenticated:    http.StatusUnauthorized,
+}
+
+// HTTPStatus returns an HTTP status of responses failed with code.
+func HTTPStatus(code codes.Code) int {
+	if s, ok := _httpStatuses[code]; ok {
+		return s
+	}
+	return http.StatusInternalServerError
+}
+
+// writeError writes err as a JSON encoded google.rpc.Status, so HTTP clients
+// get the same error details gRPC clients do.
+func writeError(w http.ResponseWriter, err error) {
+	s, ok := status.FromError(err)
+	if !ok {
+		switch {
+		case errors.Is(err, context.Canceled):
+			s = status.New(codes.Canceled, err.Error())
+		case errors.Is(err, context.DeadlineExceeded):
+			s = status.New(codes.DeadlineExceeded, err.Error())
+		default:
+			s = status.New(codes.Internal, "internal error")
+		}
+	}
+
+	_ = writeJSON(w, HTTPStatus(s.Code()), s.Proto())
+}
@@ -0,0 +1,185 @@
+package gateway
+
+//go:generate go run ./gen -out ../../../pkg/grpc/openapi.json
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/r4start/goph-keeper/internal/logging"
+	gsrv "github.com/r4start/goph-keeper/internal/server/grpc"
+	pb "github.com/r4start/goph-keeper/pkg/grpc/proto"
+)
+
+const (
+	_defaultChunkSize = 1024 * 1024
+	// _maxJSONBodySize limits bodies of requests with JSON messages.
+	_maxJSONBodySize = 16 * 1024 * 1024
+
+	_contentTypeJSON   = "application/json"
+	_contentTypeNDJSON = "application/x-ndjson"
+	_contentTypeBinary = "application/octet-stream"
+)
+
+var (
+	_marshaler   = protojson.MarshalOptions{}
+	_unmarshaler = protojson.UnmarshalOptions{DiscardUnknown: true}
+)
+
+type Option func(g *Gateway)
+
+// WithChunkSize sets a size of data messages uploaded bodies are split into.
+func WithChunkSize(size int) Option {
+	return func(g *Gateway) {
+		if size > 0 {
+			g.chunkSize = size
+		}
+	}
+}
+
+// Gateway is an HTTP/JSON front end of the gRPC API. It forwards requests to a
+// gRPC server, so they pass the same interceptors as gRPC calls do, including
+// authorization with bearer tokens.
+type Gateway struct {
+	authC     pb.AuthorizationServiceClient
+	storageC  pb.StorageClient
+	infoC     pb.InfoClient
+	chunkSize int
+	routes    []route
+}
+
+func New(conn grpc.ClientConnInterface, opts ...Option) *Gateway {
+	g := &Gateway{
+		authC:     pb.NewAuthorizationServiceClient(conn),
+		storageC:  pb.NewStorageClient(conn),
+		infoC:     pb.NewInfoClient(conn),
+		chunkSize: _defaultChunkSize,
+	}
+
+	for _, o := range opts {
+		o(g)
+	}
+
+	g.routes = g.buildRoutes()
+	return g
+}
+
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	segments := splitPath(r.URL.Path)
+
+	methodMismatch := false
+	for _, rt := range g.routes {
+		params, ok := rt.match(segments)
+		if !ok {
+			continue
+		}
+		if rt.Method != r.Method {
+			methodMismatch = true
+			continue
+		}
+
+		requestID := r.Header.Get(logging.RequestIDKey)
+		if !logging.ValidRequestID(requestID) {
+			requestID = logging.NewRequestID()
+		}
+		w.Header().Set(logging.RequestIDKey, requestID)
+
+		if err := rt.handler(w, r.WithContext(outgoingContext(r, requestID)), params); err != nil {
+			writeError(w, err)
+		}
+		return
+	}
+
+	if methodMismatch {
+		writeError(w, status.Errorf(codes.Unimplemented, "method %s is not allowed", r.Method))
+		return
+	}
+	writeError(w, status.Errorf(codes.NotFound, "no route for %s", r.URL.Path))
+}
+
+// outgoingContext passes a request id and a bearer token of a request to the
+// gRPC server.
+func outgoingContext(r *http.Request, requestID string) context.Context {
+	ctx := metadata.AppendToOutgoingContext(r.Context(), logging.RequestIDKey, requestID)
+
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok {
+		return ctx
+	}
+	if !strings.EqualFold(scheme, "bearer") && !strings.EqualFold(scheme, gsrv.AuthScheme) {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, "authorization", gsrv.AuthScheme+" "+strings.TrimSpace(token))
+}
+
+func readJSON(r *http.Request, m proto.Message) error {
+	data, err := io.ReadAll(io.LimitReader(r.Body, _maxJSONBodySize))
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	if err := _unmarshaler.Unmarshal(data, m); err != nil {
+		return status.Errorf(codes.InvalidArgument, "bad request body: %v", err)
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, code int, m proto.Message) error {
+	data, err := _marshaler.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", _contentTypeJSON)
+	w.WriteHeader(code)
+	_, err = w.Write(data)
+	return err
+}
+
+// ndjsonWriter writes messages of a server stream as newline delimited JSON.
+type ndjsonWriter struct {
+	w       http.ResponseWriter
+	started bool
+}
+
+func (n *ndjsonWriter) Write(m proto.Message) error {
+	data, err := _marshaler.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	if !n.started {
+		n.w.Header().Set("Content-Type", _contentTypeNDJSON)
+		n.w.WriteHeader(http.StatusOK)
+		n.started = true
+	}
+
+	var compact json.RawMessage = data
+	if _, err := n.w.Write(append(compact, '\n')); err != nil {
+		return err
+	}
+	flush(n.w)
+	return nil
+}
+
+func flush(w http.ResponseWriter) {
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func splitPath(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
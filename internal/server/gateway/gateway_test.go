@@ -0,0 +1,479 @@
+package gateway
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	grpc_auth "github.com/grpc-ecosystem/go-grpc-middleware/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/r4start/goph-keeper/internal/logging"
+	"github.com/r4start/goph-keeper/internal/server/app"
+	gsrv "github.com/r4start/goph-keeper/internal/server/grpc"
+	"github.com/r4start/goph-keeper/internal/server/storage"
+	pb "github.com/r4start/goph-keeper/pkg/grpc/proto"
+)
+
+const _testToken = "valid-token"
+
+type mockAuth struct{}
+
+func (mockAuth) Register(_ context.Context, login, _ string, _ []byte) (*app.AuthData, error) {
+	if login == "exists" {
+		return nil, storage.ErrUserExists
+	}
+	return &app.AuthData{ID: uuid.NewString(), Token: _testToken, RefreshToken: _testToken}, nil
+}
+
+func (mockAuth) Authorize(context.Context, string, string) (*app.AuthData, error) {
+	return nil, app.ErrInvalidCredentials
+}
+
+func (mockAuth) RefreshToken(context.Context, string) (*app.AuthData, error) {
+	panic("unimplemented")
+}
+
+func (mockAuth) IsValidToken(context.Context, string) error {
+	panic("unimplemented")
+}
+
+func (mockAuth) AuthorizeWithToken(_ context.Context, token string) (*app.AuthData, error) {
+	if token != _testToken {
+		return nil, app.ErrInvalidToken
+	}
+	return &app.AuthData{ID: uuid.NewString(), Token: token}, nil
+}
+
+// mockStorage keeps resources in memory.
+type mockStorage struct {
+	pb.UnimplementedStorageServer
+	mu        sync.Mutex
+	resources map[string]*pb.Resource
+	uploaded  []byte
+	offsets   []uint64
+}
+
+func newMockStorage() *mockStorage {
+	return &mockStorage{resources: make(map[string]*pb.Resource)}
+}
+
+func (s *mockStorage) Add(stream pb.Storage_AddServer) error {
+	msg, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	meta := msg.GetMeta()
+	if meta == nil {
+		return status.Error(codes.InvalidArgument, "meta is missing")
+	}
+
+	var data []byte
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		data = append(data, msg.GetChunk().GetData()...)
+	}
+	if uint64(len(data)) != meta.GetResourceByteSize() {
+		return status.Error(codes.InvalidArgument, "size mismatch")
+	}
+
+	id := uuid.NewString()
+	size := uint64(len(data))
+	res := &pb.Resource{Id: &id, Data: data, Salt: meta.Salt, ByteSize: &size, Kind: meta.Kind}
+
+	s.mu.Lock()
+	s.resources[id] = res
+	s.mu.Unlock()
+
+	return stream.SendAndClose(&pb.ResourceOperationResponse{
+		Result: &pb.ResourceOperationResponse_Resource{Resource: &pb.Resource{Id: &id, ByteSize: &size}},
+	})
+}
+
+func (s *mockStorage) lookup(id string) (*pb.Resource, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "bad resource id: %v", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	res, ok := s.resources[id]
+	if !ok {
+		return nil, storage.ErrResourceNotFound
+	}
+	return res, nil
+}
+
+func (s *mockStorage) Stat(_ context.Context, r *pb.Resource) (*pb.Resource, error) {
+	res, err := s.lookup(r.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return &pb.Resource{Id: res.Id, ByteSize: res.ByteSize, Kind: res.Kind}, nil
+}
+
+func (s *mockStorage) Get(r *pb.GetRequest, stream pb.Storage_GetServer) error {
+	res, err := s.lookup(r.GetId())
+	if err != nil {
+		return err
+	}
+
+	err = stream.Send(&pb.ResourceOperationData{
+		Data: &pb.ResourceOperationData_Meta{
+			Meta: &pb.ResourceOperationData_ResourceMeta{Salt: res.Salt, ResourceByteSize: res.ByteSize},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	data := res.Data[r.GetOffset():]
+	for len(data) != 0 {
+		n := 1000
+		if n > len(data) {
+			n = len(data)
+		}
+		err := stream.Send(&pb.ResourceOperationData{
+			Data: &pb.ResourceOperationData_Chunk{Chunk: &pb.ResourceOperationData_DataChunk{Data: data[:n]}},
+		})
+		if err != nil {
+			return err
+		}
+		data = data[n:]
+	}
+	return nil
+}
+
+func (s *mockStorage) ListPage(_ *pb.ListRequest, stream pb.Storage_ListPageServer) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id := range s.resources {
+		id := id
+		if err := stream.Send(&pb.ListResponse{Item: &pb.ListResponse_Resource{Resource: &pb.Resource{Id: &id}}}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *mockStorage) AppendUpload(stream pb.Storage_AppendUploadServer) error {
+	var (
+		id     string
+		offset uint64
+	)
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			return stream.SendAndClose(&pb.UploadSession{UploadId: &id, CommittedOffset: &offset})
+		}
+		if err != nil {
+			return err
+		}
+
+		id = chunk.GetUploadId()
+		s.mu.Lock()
+		if chunk.GetOffset() != uint64(len(s.uploaded)) {
+			s.mu.Unlock()
+			return storage.ErrUploadOffsetMismatch
+		}
+		s.offsets = append(s.offsets, chunk.GetOffset())
+		s.uploaded = append(s.uploaded, chunk.Data...)
+		offset = uint64(len(s.uploaded))
+		s.mu.Unlock()
+	}
+}
+
+func prepareGateway(t *testing.T, s *mockStorage, opts ...Option) *httptest.Server {
+	const bufSize = 1024 * 1024
+
+	authFunc := gsrv.BuildAuthorizationInterceptor(mockAuth{})
+	lis := bufconn.Listen(bufSize)
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(gsrv.ErrorUnaryInterceptor(), grpc_auth.UnaryServerInterceptor(authFunc)),
+		grpc.ChainStreamInterceptor(gsrv.ErrorStreamInterceptor(), grpc_auth.StreamServerInterceptor(authFunc)),
+	)
+	pb.RegisterAuthorizationServiceServer(grpcServer, gsrv.NewAuthService(mockAuth{}, time.Second))
+	pb.RegisterStorageServer(grpcServer, s)
+	go func() {
+		_ = grpcServer.Serve(lis)
+	}()
+
+	conn, err := grpc.DialContext(context.Background(), "",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return lis.Dial()
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(New(conn, opts...))
+	t.Cleanup(func() {
+		srv.Close()
+		_ = conn.Close()
+		grpcServer.Stop()
+	})
+	return srv
+}
+
+func doRequest(t *testing.T, method, url string, body io.Reader, headers map[string]string) *http.Response {
+	req, err := http.NewRequest(method, url, body)
+	require.NoError(t, err)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = resp.Body.Close()
+	})
+	return resp
+}
+
+func bearer() map[string]string {
+	return map[string]string{"Authorization": "Bearer " + _testToken}
+}
+
+func decodeStatus(t *testing.T, resp *http.Response) *status.Status {
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	s := &struct {
+		Code    int32  `json:"code"`
+		Message string `json:"message"`
+	}{}
+	require.NoError(t, json.Unmarshal(data, s))
+	return status.New(codes.Code(s.Code), s.Message)
+}
+
+func TestGateway_Register(t *testing.T) {
+	srv := prepareGateway(t, newMockStorage())
+
+	body := `{"login": "user", "password": "pwd", "salt": "AAAA"}`
+	resp := doRequest(t, http.MethodPost, srv.URL+"/v1/auth/register", bytes.NewBufferString(body), nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	authResp := &pb.AuthorizationResponse{}
+	require.NoError(t, protojson.Unmarshal(data, authResp))
+	assert.Equal(t, _testToken, authResp.GetToken())
+
+	body = `{"login": "exists", "password": "pwd", "salt": "AAAA"}`
+	resp = doRequest(t, http.MethodPost, srv.URL+"/v1/auth/register", bytes.NewBufferString(body), nil)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	assert.Equal(t, codes.AlreadyExists, decodeStatus(t, resp).Code())
+}
+
+func TestGateway_RequestID(t *testing.T) {
+	srv := prepareGateway(t, newMockStorage())
+
+	resp := doRequest(t, http.MethodGet, srv.URL+"/v1/info", nil, map[string]string{logging.RequestIDKey: "gw-request"})
+	assert.Equal(t, "gw-request", resp.Header.Get(logging.RequestIDKey))
+
+	resp = doRequest(t, http.MethodGet, srv.URL+"/v1/info", nil, map[string]string{logging.RequestIDKey: "bad\tid"})
+	assert.NotEqual(t, "bad\tid", resp.Header.Get(logging.RequestIDKey))
+	assert.True(t, logging.ValidRequestID(resp.Header.Get(logging.RequestIDKey)))
+}
+
+func TestGateway_Unauthenticated(t *testing.T) {
+	srv := prepareGateway(t, newMockStorage())
+
+	tests := []map[string]string{
+		nil,
+		{"Authorization": "Bearer bad-token"},
+		{"Authorization": "Basic " + _testToken},
+	}
+
+	for _, h := range tests {
+		resp := doRequest(t, http.MethodGet, srv.URL+"/v1/resources/"+uuid.NewString(), nil, h)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		assert.Equal(t, codes.Unauthenticated, decodeStatus(t, resp).Code())
+	}
+
+	resp := doRequest(t, http.MethodGet, srv.URL+"/v1/resources/"+uuid.NewString(), nil,
+		map[string]string{"Authorization": gsrv.AuthScheme + " " + _testToken})
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestGateway_AddAndGet(t *testing.T) {
+	s := newMockStorage()
+	srv := prepareGateway(t, s, WithChunkSize(4096))
+
+	data := make([]byte, 100_000)
+	_, err := rand.Read(data)
+	require.NoError(t, err)
+	salt := []byte("salt")
+
+	headers := bearer()
+	headers[_headerSalt] = base64.StdEncoding.EncodeToString(salt)
+	headers[_headerKind] = "file"
+	resp := doRequest(t, http.MethodPost, srv.URL+"/v1/resources", bytes.NewReader(data), headers)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	addResp := &pb.ResourceOperationResponse{}
+	require.NoError(t, protojson.Unmarshal(body, addResp))
+	id := addResp.GetResource().GetId()
+	assert.Equal(t, uint64(len(data)), addResp.GetResource().GetByteSize())
+	assert.Equal(t, "file", s.resources[id].GetKind())
+
+	resp = doRequest(t, http.MethodGet, srv.URL+"/v1/resources/"+id+"/data", nil, bearer())
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, strconv.Itoa(len(data)), resp.Header.Get(_headerSize))
+	assert.Equal(t, base64.StdEncoding.EncodeToString(salt), resp.Header.Get(_headerSalt))
+
+	downloaded, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, data, downloaded)
+
+	resp = doRequest(t, http.MethodGet, fmt.Sprintf("%s/v1/resources/%s/data?offset=%d", srv.URL, id, 1000), nil, bearer())
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	downloaded, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, data[1000:], downloaded)
+}
+
+func TestGateway_AddSizeMismatch(t *testing.T) {
+	srv := prepareGateway(t, newMockStorage())
+
+	headers := bearer()
+	headers[_headerSize] = "10"
+	resp := doRequest(t, http.MethodPost, srv.URL+"/v1/resources", bytes.NewBufferString("data"), headers)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, codes.InvalidArgument, decodeStatus(t, resp).Code())
+}
+
+func TestGateway_AppendUpload(t *testing.T) {
+	s := newMockStorage()
+	srv := prepareGateway(t, s, WithChunkSize(1000))
+
+	data := make([]byte, 2500)
+	_, err := rand.Read(data)
+	require.NoError(t, err)
+
+	url := srv.URL + "/v1/uploads/" + uuid.NewString()
+	resp := doRequest(t, http.MethodPut, url+"?offset=0", bytes.NewReader(data), bearer())
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	session := &pb.UploadSession{}
+	require.NoError(t, protojson.Unmarshal(body, session))
+	assert.Equal(t, uint64(len(data)), session.GetCommittedOffset())
+	assert.Equal(t, []uint64{0, 1000, 2000}, s.offsets)
+	assert.Equal(t, data, s.uploaded)
+
+	resp = doRequest(t, http.MethodPut, url+"?offset=0", bytes.NewReader(data), bearer())
+	assert.Equal(t, http.StatusPreconditionFailed, resp.StatusCode)
+
+	resp = doRequest(t, http.MethodPut, url, bytes.NewReader(data), bearer())
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestGateway_List(t *testing.T) {
+	s := newMockStorage()
+	for i := 0; i < 3; i++ {
+		id := uuid.NewString()
+		s.resources[id] = &pb.Resource{Id: &id}
+	}
+	srv := prepareGateway(t, s)
+
+	resp := doRequest(t, http.MethodGet, srv.URL+"/v1/resources?page_size=10&order_by=LIST_ORDER_UPDATED", nil, bearer())
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, _contentTypeNDJSON, resp.Header.Get("Content-Type"))
+
+	lines := 0
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		item := &pb.ListResponse{}
+		require.NoError(t, protojson.Unmarshal(scanner.Bytes(), item))
+		assert.Contains(t, s.resources, item.GetResource().GetId())
+		lines++
+	}
+	assert.Equal(t, 3, lines)
+
+	resp = doRequest(t, http.MethodGet, srv.URL+"/v1/resources?order_by=NAME", nil, bearer())
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestGateway_Errors(t *testing.T) {
+	srv := prepareGateway(t, newMockStorage())
+
+	tests := []struct {
+		method string
+		path   string
+		status int
+		code   codes.Code
+	}{
+		{http.MethodGet, "/v1/resources/bad-id", http.StatusBadRequest, codes.InvalidArgument},
+		{http.MethodGet, "/v1/resources/" + uuid.NewString(), http.StatusNotFound, codes.NotFound},
+		{http.MethodGet, "/v1/resources/" + uuid.NewString() + "/data", http.StatusNotFound, codes.NotFound},
+		{http.MethodGet, "/v1/unknown", http.StatusNotFound, codes.NotFound},
+		{http.MethodPatch, "/v1/resources", http.StatusMethodNotAllowed, codes.Unimplemented},
+		{http.MethodGet, "/v1/usage", http.StatusMethodNotAllowed, codes.Unimplemented},
+	}
+
+	for _, tt := range tests {
+		resp := doRequest(t, tt.method, srv.URL+tt.path, nil, bearer())
+		assert.Equal(t, tt.status, resp.StatusCode, tt.path)
+		assert.Equal(t, tt.code, decodeStatus(t, resp).Code(), tt.path)
+	}
+}
+
+func TestGateway_ErrorDetails(t *testing.T) {
+	srv := prepareGateway(t, newMockStorage())
+
+	resp := doRequest(t, http.MethodGet, srv.URL+"/v1/resources/"+uuid.NewString(), nil, bearer())
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), pb.ErrorCode_ERROR_CODE_NOT_FOUND.String())
+	assert.Contains(t, string(body), gsrv.ErrorDomain)
+}
+
+func TestOpenAPIDocument_UpToDate(t *testing.T) {
+	doc, err := OpenAPIDocument()
+	require.NoError(t, err)
+
+	committed, err := os.ReadFile("../../../pkg/grpc/openapi.json")
+	require.NoError(t, err)
+	assert.Equal(t, string(committed), string(doc)+"\n", "run go generate ./internal/server/gateway")
+
+	parsed := struct {
+		Paths map[string]map[string]interface{} `json:"paths"`
+	}{}
+	require.NoError(t, json.Unmarshal(doc, &parsed))
+	assert.Contains(t, parsed.Paths["/v1/resources/{id}/data"], "get")
+	assert.Contains(t, parsed.Paths["/v1/uploads/{upload_id}"], "put")
+}
@@ -0,0 +1,26 @@
+// Command gen writes the OpenAPI document of the HTTP gateway.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/r4start/goph-keeper/internal/server/gateway"
+)
+
+func main() {
+	out := flag.String("out", "openapi.json", "path of the generated document")
+	flag.Parse()
+
+	doc, err := gateway.OpenAPIDocument()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to build document: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := os.WriteFile(*out, append(doc, '\n'), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write document: %v\n", err)
+		os.Exit(1)
+	}
+}
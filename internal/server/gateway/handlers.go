@@ -0,0 +1,535 @@
+package gateway
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/r4start/goph-keeper/internal/server/storage"
+	pb "github.com/r4start/goph-keeper/pkg/grpc/proto"
+)
+
+// Headers with resource information of binary bodies.
+const (
+	_headerSize     = "X-Resource-Size"
+	_headerSalt     = "X-Resource-Salt"
+	_headerSha256   = "X-Resource-Sha256"
+	_headerKind     = "X-Resource-Kind"
+	_headerMetadata = "X-Resource-Metadata"
+)
+
+func (g *Gateway) register(w http.ResponseWriter, r *http.Request, _ map[string]string) error {
+	req := &pb.AuthorizationRequest{}
+	if err := readJSON(r, req); err != nil {
+		return err
+	}
+
+	resp, err := g.authC.Register(r.Context(), req)
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusOK, resp)
+}
+
+func (g *Gateway) authorize(w http.ResponseWriter, r *http.Request, _ map[string]string) error {
+	req := &pb.AuthorizationRequest{}
+	if err := readJSON(r, req); err != nil {
+		return err
+	}
+
+	resp, err := g.authC.Authorize(r.Context(), req)
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusOK, resp)
+}
+
+func (g *Gateway) serverInfo(w http.ResponseWriter, r *http.Request, _ map[string]string) error {
+	resp, err := g.infoC.ServerInfo(r.Context(), &pb.ServerInfoRequest{})
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusOK, resp)
+}
+
+func (g *Gateway) list(w http.ResponseWriter, r *http.Request, _ map[string]string) error {
+	req, err := listRequest(r)
+	if err != nil {
+		return err
+	}
+
+	stream, err := g.storageC.ListPage(r.Context(), req)
+	if err != nil {
+		return err
+	}
+	return copyList(w, stream)
+}
+
+func (g *Gateway) listDeleted(w http.ResponseWriter, r *http.Request, _ map[string]string) error {
+	req, err := listRequest(r)
+	if err != nil {
+		return err
+	}
+
+	stream, err := g.storageC.ListDeleted(r.Context(), req)
+	if err != nil {
+		return err
+	}
+	return copyList(w, stream)
+}
+
+type listStream interface {
+	Recv() (*pb.ListResponse, error)
+}
+
+// copyList writes a listing as NDJSON. An error before the first message is
+// returned to be written as a regular error response, a connection is aborted
+// on later errors since the status has already been sent.
+func copyList(w http.ResponseWriter, stream listStream) error {
+	out := &ndjsonWriter{w: w}
+	for {
+		item, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if out.started {
+				panic(http.ErrAbortHandler)
+			}
+			return err
+		}
+
+		if err := out.Write(item); err != nil {
+			return nil
+		}
+	}
+
+	if !out.started {
+		w.Header().Set("Content-Type", _contentTypeNDJSON)
+		w.WriteHeader(http.StatusOK)
+	}
+	return nil
+}
+
+func (g *Gateway) add(w http.ResponseWriter, r *http.Request, _ map[string]string) error {
+	meta, err := resourceMeta(r)
+	if err != nil {
+		return err
+	}
+
+	// The call is canceled when a body can't be read, so a server doesn't take
+	// a part of data for the whole resource.
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	stream, err := g.storageC.Add(ctx)
+	if err != nil {
+		return err
+	}
+	closeAndRecv := func() error {
+		_, err := stream.CloseAndRecv()
+		return err
+	}
+
+	err = stream.Send(&pb.ResourceOperationData{Data: &pb.ResourceOperationData_Meta{Meta: meta}})
+	if err == nil {
+		err = g.readChunks(r.Body, func(data []byte) error {
+			return stream.Send(&pb.ResourceOperationData{
+				Data: &pb.ResourceOperationData_Chunk{
+					Chunk: &pb.ResourceOperationData_DataChunk{Data: data},
+				},
+			})
+		})
+	}
+	if err != nil {
+		return sendError(err, closeAndRecv)
+	}
+
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusOK, resp)
+}
+
+func (g *Gateway) addChunked(w http.ResponseWriter, r *http.Request, _ map[string]string) error {
+	req := &pb.AddChunkedRequest{}
+	if err := readJSON(r, req); err != nil {
+		return err
+	}
+
+	resp, err := g.storageC.AddChunked(r.Context(), req)
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusOK, resp)
+}
+
+func (g *Gateway) stat(w http.ResponseWriter, r *http.Request, params map[string]string) error {
+	id := params["id"]
+	resp, err := g.storageC.Stat(r.Context(), &pb.Resource{Id: &id})
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusOK, resp)
+}
+
+func (g *Gateway) delete(w http.ResponseWriter, r *http.Request, params map[string]string) error {
+	id := params["id"]
+	resp, err := g.storageC.Delete(r.Context(), &pb.Resource{Id: &id})
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusOK, resp)
+}
+
+func (g *Gateway) get(w http.ResponseWriter, r *http.Request, params map[string]string) error {
+	id := params["id"]
+	req := &pb.GetRequest{Id: &id}
+
+	var err error
+	if req.Offset, err = queryUint(r, "offset"); err != nil {
+		return err
+	}
+	if req.Length, err = queryUint(r, "length"); err != nil {
+		return err
+	}
+
+	stream, err := g.storageC.Get(r.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	// The status is sent with the first message, errors before it are reported
+	// as usual. A download is aborted on later errors, so clients never take
+	// a truncated body for complete data.
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	meta := first.GetMeta()
+	if meta == nil {
+		return status.Error(codes.Internal, "resource meta is missing")
+	}
+
+	h := w.Header()
+	h.Set("Content-Type", _contentTypeBinary)
+	h.Set(_headerSize, strconv.FormatUint(meta.GetResourceByteSize(), 10))
+	h.Set(_headerSalt, base64.StdEncoding.EncodeToString(meta.Salt))
+	if len(meta.Sha256) != 0 {
+		h.Set(_headerSha256, hex.EncodeToString(meta.Sha256))
+	}
+	w.WriteHeader(http.StatusOK)
+
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			panic(http.ErrAbortHandler)
+		}
+
+		if _, err := w.Write(msg.GetChunk().GetData()); err != nil {
+			return nil
+		}
+		flush(w)
+	}
+}
+
+func (g *Gateway) createUpload(w http.ResponseWriter, r *http.Request, _ map[string]string) error {
+	req := &pb.CreateUploadRequest{}
+	if err := readJSON(r, req); err != nil {
+		return err
+	}
+
+	resp, err := g.storageC.CreateUpload(r.Context(), req)
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusOK, resp)
+}
+
+func (g *Gateway) getUpload(w http.ResponseWriter, r *http.Request, params map[string]string) error {
+	id := params["upload_id"]
+	resp, err := g.storageC.GetUpload(r.Context(), &pb.UploadSessionRequest{UploadId: &id})
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusOK, resp)
+}
+
+func (g *Gateway) appendUpload(w http.ResponseWriter, r *http.Request, params map[string]string) error {
+	id := params["upload_id"]
+	offset, err := queryUint(r, "offset")
+	if err != nil {
+		return err
+	}
+	if offset == nil {
+		return status.Error(codes.InvalidArgument, "offset is required")
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	stream, err := g.storageC.AppendUpload(ctx)
+	if err != nil {
+		return err
+	}
+
+	next := *offset
+	err = g.readChunks(r.Body, func(data []byte) error {
+		chunkOffset := next
+		next += uint64(len(data))
+		return stream.Send(&pb.UploadChunk{UploadId: &id, Offset: &chunkOffset, Data: data})
+	})
+	if err != nil {
+		return sendError(err, func() error {
+			_, err := stream.CloseAndRecv()
+			return err
+		})
+	}
+
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusOK, resp)
+}
+
+func (g *Gateway) finalizeUpload(w http.ResponseWriter, r *http.Request, params map[string]string) error {
+	id := params["upload_id"]
+	resp, err := g.storageC.FinalizeUpload(r.Context(), &pb.UploadSessionRequest{UploadId: &id})
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusOK, resp)
+}
+
+func (g *Gateway) missingChunks(w http.ResponseWriter, r *http.Request, _ map[string]string) error {
+	req := &pb.ChunkList{}
+	if err := readJSON(r, req); err != nil {
+		return err
+	}
+
+	resp, err := g.storageC.MissingChunks(r.Context(), req)
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusOK, resp)
+}
+
+func (g *Gateway) putChunk(w http.ResponseWriter, r *http.Request, params map[string]string) error {
+	digest, err := hex.DecodeString(params["sha256"])
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "bad chunk digest: %v", err)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r.Body, storage.MaxChunkSize+1))
+	if err != nil {
+		return err
+	}
+	if len(data) > storage.MaxChunkSize {
+		return status.Errorf(codes.InvalidArgument, "chunk is larger than %d bytes", storage.MaxChunkSize)
+	}
+
+	stream, err := g.storageC.PutChunks(r.Context())
+	if err != nil {
+		return err
+	}
+	if err := stream.Send(&pb.Chunk{Sha256: digest, Data: data}); err != nil {
+		return sendError(err, func() error {
+			_, err := stream.CloseAndRecv()
+			return err
+		})
+	}
+
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusOK, resp)
+}
+
+func (g *Gateway) emptyTrash(w http.ResponseWriter, r *http.Request, _ map[string]string) error {
+	resp, err := g.storageC.EmptyTrash(r.Context(), &pb.EmptyTrashRequest{})
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusOK, resp)
+}
+
+func (g *Gateway) restore(w http.ResponseWriter, r *http.Request, params map[string]string) error {
+	id := params["id"]
+	resp, err := g.storageC.Restore(r.Context(), &pb.Resource{Id: &id})
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusOK, resp)
+}
+
+func (g *Gateway) usage(w http.ResponseWriter, r *http.Request, _ map[string]string) error {
+	resp, err := g.storageC.Usage(r.Context(), &pb.UsageRequest{})
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusOK, resp)
+}
+
+func (g *Gateway) batch(w http.ResponseWriter, r *http.Request, _ map[string]string) error {
+	req := &pb.BatchRequest{}
+	if err := readJSON(r, req); err != nil {
+		return err
+	}
+
+	resp, err := g.storageC.Batch(r.Context(), req)
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusOK, resp)
+}
+
+// readChunks splits body into chunks of the gateway chunk size.
+func (g *Gateway) readChunks(body io.Reader, send func(data []byte) error) error {
+	buffer := make([]byte, g.chunkSize)
+	for {
+		n, err := io.ReadFull(body, buffer)
+		if n != 0 {
+			data := make([]byte, n)
+			copy(data, buffer[:n])
+			if err := send(data); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil
+		}
+		if err != nil {
+			return status.Errorf(codes.Canceled, "failed to read request body: %v", err)
+		}
+	}
+}
+
+// sendError returns an error of a client stream which failed to send data.
+// Send returns io.EOF when a server has closed the stream, the status of the
+// call tells why.
+func sendError(err error, closeAndRecv func() error) error {
+	if err != io.EOF {
+		return err
+	}
+	if err := closeAndRecv(); err != nil {
+		return err
+	}
+	return status.Error(codes.Internal, "stream closed unexpectedly")
+}
+
+func resourceMeta(r *http.Request) (*pb.ResourceOperationData_ResourceMeta, error) {
+	meta := &pb.ResourceOperationData_ResourceMeta{}
+
+	h := r.Header
+	if v := h.Get(_headerSize); len(v) != 0 {
+		size, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "bad %s header: %v", _headerSize, err)
+		}
+		meta.ResourceByteSize = &size
+	} else if r.ContentLength >= 0 {
+		size := uint64(r.ContentLength)
+		meta.ResourceByteSize = &size
+	}
+
+	var err error
+	if meta.Salt, err = base64Header(h, _headerSalt); err != nil {
+		return nil, err
+	}
+	if meta.Metadata, err = base64Header(h, _headerMetadata); err != nil {
+		return nil, err
+	}
+	if v := h.Get(_headerSha256); len(v) != 0 {
+		if meta.Sha256, err = hex.DecodeString(v); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "bad %s header: %v", _headerSha256, err)
+		}
+	}
+	if v := h.Get(_headerKind); len(v) != 0 {
+		meta.Kind = &v
+	}
+	return meta, nil
+}
+
+func base64Header(h http.Header, name string) ([]byte, error) {
+	v := h.Get(name)
+	if len(v) == 0 {
+		return nil, nil
+	}
+
+	data, err := base64.StdEncoding.DecodeString(v)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "bad %s header: %v", name, err)
+	}
+	return data, nil
+}
+
+func listRequest(r *http.Request) (*pb.ListRequest, error) {
+	req := &pb.ListRequest{}
+	q := r.URL.Query()
+
+	if v := q.Get("page_size"); len(v) != 0 {
+		size, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "bad page_size: %v", err)
+		}
+		pageSize := uint32(size)
+		req.PageSize = &pageSize
+	}
+	if v := q.Get("page_token"); len(v) != 0 {
+		req.PageToken = &v
+	}
+	if v := q.Get("order_by"); len(v) != 0 {
+		order, ok := pb.ListOrder_value[v]
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "bad order_by: %s", v)
+		}
+		req.OrderBy = pb.ListOrder(order).Enum()
+	}
+	if v := q.Get("updated_after"); len(v) != 0 {
+		t, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "bad updated_after: %v", err)
+		}
+		req.UpdatedAfter = timestamppb.New(t)
+	}
+	if v := q.Get("include_deleted"); len(v) != 0 {
+		include, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "bad include_deleted: %v", err)
+		}
+		req.IncludeDeleted = &include
+	}
+	if v := q.Get("kind"); len(v) != 0 {
+		req.Kind = &v
+	}
+	return req, nil
+}
+
+func queryUint(r *http.Request, name string) (*uint64, error) {
+	v := r.URL.Query().Get(name)
+	if len(v) == 0 {
+		return nil, nil
+	}
+
+	n, err := strconv.ParseUint(v, 10, 64)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("bad %s: %v", name, err))
+	}
+	return &n, nil
+}
@@ -0,0 +1,276 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	gsrv "github.com/r4start/goph-keeper/internal/server/grpc"
+)
+
+const (
+	_openAPIVersion = "3.0.3"
+	_schemaPrefix   = "#/components/schemas/"
+	_bearerAuth     = "bearerAuth"
+)
+
+// jsonObject keeps parts of the document. encoding/json sorts keys of maps, so
+// the document is the same for the same routes.
+type jsonObject = map[string]interface{}
+
+// OpenAPIDocument describes the gateway API in OpenAPI 3 format. Schemas of
+// messages are built from the protobuf descriptors and follow the protojson
+// mapping the gateway uses.
+func OpenAPIDocument() ([]byte, error) {
+	g := &Gateway{}
+	return openAPIDocument(g.buildRoutes())
+}
+
+func (g *Gateway) openAPI(w http.ResponseWriter, _ *http.Request, _ map[string]string) error {
+	doc, err := openAPIDocument(g.routes)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", _contentTypeJSON)
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(doc)
+	return err
+}
+
+func openAPIDocument(routes []route) ([]byte, error) {
+	schemas := newSchemaSet()
+	errorRef := schemas.ref((&spb.Status{}).ProtoReflect().Descriptor())
+
+	paths := jsonObject{}
+	for _, rt := range routes {
+		item, ok := paths[rt.Path].(jsonObject)
+		if !ok {
+			item = jsonObject{}
+			paths[rt.Path] = item
+		}
+		item[strings.ToLower(rt.Method)] = operation(&rt, schemas, errorRef)
+	}
+
+	doc := jsonObject{
+		"openapi": _openAPIVersion,
+		"info": jsonObject{
+			"title":       "GophKeeper",
+			"description": "HTTP/JSON gateway of the GophKeeper gRPC API.",
+			"version":     fmt.Sprintf("%d", gsrv.ProtocolVersion),
+		},
+		"paths": paths,
+		"components": jsonObject{
+			"schemas": schemas.schemas,
+			"securitySchemes": jsonObject{
+				_bearerAuth: jsonObject{
+					"type":         "http",
+					"scheme":       "bearer",
+					"bearerFormat": "JWT",
+				},
+			},
+		},
+	}
+
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+func operation(rt *route, schemas *schemaSet, errorRef jsonObject) jsonObject {
+	op := jsonObject{
+		"operationId": rt.Operation,
+		"summary":     rt.Summary,
+	}
+
+	var params []interface{}
+	for _, s := range rt.segments {
+		if name, ok := pathParam(s); ok {
+			params = append(params, jsonObject{
+				"name":     name,
+				"in":       "path",
+				"required": true,
+				"schema":   jsonObject{"type": string(paramString)},
+			})
+		}
+	}
+	params = append(params, parameters("query", rt.Query)...)
+	params = append(params, parameters("header", rt.Headers)...)
+	if len(params) != 0 {
+		op["parameters"] = params
+	}
+
+	switch rt.RequestBody {
+	case bodyJSON:
+		op["requestBody"] = jsonObject{
+			"required": true,
+			"content": jsonObject{
+				_contentTypeJSON: jsonObject{"schema": schemas.ref(rt.Request.ProtoReflect().Descriptor())},
+			},
+		}
+	case bodyBinary:
+		op["requestBody"] = jsonObject{
+			"required": true,
+			"content": jsonObject{
+				_contentTypeBinary: jsonObject{"schema": jsonObject{"type": "string", "format": "binary"}},
+			},
+		}
+	}
+
+	var schema jsonObject
+	if rt.Response != nil {
+		schema = schemas.ref(rt.Response.ProtoReflect().Descriptor())
+	} else {
+		schema = jsonObject{"type": "object"}
+	}
+
+	ok := jsonObject{"description": "OK"}
+	switch rt.ResponseBody {
+	case bodyJSON:
+		ok["content"] = jsonObject{_contentTypeJSON: jsonObject{"schema": schema}}
+	case bodyNDJSON:
+		ok["content"] = jsonObject{_contentTypeNDJSON: jsonObject{"schema": schema}}
+	case bodyBinary:
+		ok["content"] = jsonObject{
+			_contentTypeBinary: jsonObject{"schema": jsonObject{"type": "string", "format": "binary"}},
+		}
+		headers := jsonObject{}
+		for _, h := range rt.ResponseHeaders {
+			headers[h.Name] = jsonObject{
+				"description": h.Description,
+				"schema":      jsonObject{"type": string(h.Type)},
+			}
+		}
+		ok["headers"] = headers
+	}
+
+	op["responses"] = jsonObject{
+		"200": ok,
+		"default": jsonObject{
+			"description": "Failed call, the status carries a google.rpc.ErrorInfo detail.",
+			"content":     jsonObject{_contentTypeJSON: jsonObject{"schema": errorRef}},
+		},
+	}
+
+	if !rt.Public {
+		op["security"] = []interface{}{jsonObject{_bearerAuth: []interface{}{}}}
+	}
+	return op
+}
+
+func parameters(in string, params []param) []interface{} {
+	result := make([]interface{}, 0, len(params))
+	for _, p := range params {
+		result = append(result, jsonObject{
+			"name":        p.Name,
+			"in":          in,
+			"description": p.Description,
+			"required":    p.Required,
+			"schema":      jsonObject{"type": string(p.Type)},
+		})
+	}
+	return result
+}
+
+// schemaSet collects schemas of messages referenced by the document.
+type schemaSet struct {
+	schemas jsonObject
+}
+
+func newSchemaSet() *schemaSet {
+	return &schemaSet{schemas: jsonObject{}}
+}
+
+// ref returns a reference to a schema of md adding the schema on first use.
+// Well known types are inlined with their JSON representation.
+func (s *schemaSet) ref(md protoreflect.MessageDescriptor) jsonObject {
+	switch md.FullName() {
+	case "google.protobuf.Timestamp":
+		return jsonObject{"type": "string", "format": "date-time"}
+	case "google.protobuf.Any":
+		return jsonObject{
+			"type":                 "object",
+			"properties":           jsonObject{"@type": jsonObject{"type": "string"}},
+			"additionalProperties": true,
+		}
+	}
+
+	name := schemaName(md)
+	if _, ok := s.schemas[name]; !ok {
+		schema := jsonObject{"type": "object"}
+		// Registers the name before fields are visited, messages may refer to themselves.
+		s.schemas[name] = schema
+
+		properties := jsonObject{}
+		fields := md.Fields()
+		for i := 0; i < fields.Len(); i++ {
+			fd := fields.Get(i)
+			properties[fd.JSONName()] = s.field(fd)
+		}
+		if len(properties) != 0 {
+			schema["properties"] = properties
+		}
+	}
+
+	return jsonObject{"$ref": _schemaPrefix + name}
+}
+
+func (s *schemaSet) field(fd protoreflect.FieldDescriptor) jsonObject {
+	if fd.IsMap() {
+		return jsonObject{
+			"type":                 "object",
+			"additionalProperties": s.value(fd.MapValue()),
+		}
+	}
+	if fd.IsList() {
+		return jsonObject{
+			"type":  "array",
+			"items": s.value(fd),
+		}
+	}
+	return s.value(fd)
+}
+
+// value returns a schema of a single value of a field following protojson.
+func (s *schemaSet) value(fd protoreflect.FieldDescriptor) jsonObject {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return jsonObject{"type": "boolean"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return jsonObject{"type": "integer", "format": "int32"}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return jsonObject{"type": "integer", "format": "uint32"}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return jsonObject{"type": "string", "format": "int64"}
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return jsonObject{"type": "string", "format": "uint64"}
+	case protoreflect.FloatKind:
+		return jsonObject{"type": "number", "format": "float"}
+	case protoreflect.DoubleKind:
+		return jsonObject{"type": "number", "format": "double"}
+	case protoreflect.StringKind:
+		return jsonObject{"type": "string"}
+	case protoreflect.BytesKind:
+		return jsonObject{"type": "string", "format": "byte"}
+	case protoreflect.EnumKind:
+		values := fd.Enum().Values()
+		names := make([]interface{}, 0, values.Len())
+		for i := 0; i < values.Len(); i++ {
+			names = append(names, string(values.Get(i).Name()))
+		}
+		return jsonObject{"type": "string", "enum": names}
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return s.ref(fd.Message())
+	default:
+		return jsonObject{}
+	}
+}
+
+// schemaName turns gophkeeper.ResourceOperationData.ResourceMeta into
+// ResourceOperationData_ResourceMeta. Names of foreign messages keep packages.
+func schemaName(md protoreflect.MessageDescriptor) string {
+	name := strings.TrimPrefix(string(md.FullName()), "gophkeeper.")
+	return strings.ReplaceAll(name, ".", "_")
+}
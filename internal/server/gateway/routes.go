@@ -0,0 +1,269 @@
+package gateway
+
+import (
+	"net/http"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/r4start/goph-keeper/pkg/grpc/proto"
+)
+
+type bodyKind int
+
+const (
+	bodyNone bodyKind = iota
+	bodyJSON
+	// bodyNDJSON is a stream of JSON messages delimited by new lines.
+	bodyNDJSON
+	// bodyBinary is raw resource data.
+	bodyBinary
+)
+
+type paramType string
+
+const (
+	paramString  paramType = "string"
+	paramInteger paramType = "integer"
+	paramBoolean paramType = "boolean"
+)
+
+type param struct {
+	Name        string
+	Type        paramType
+	Description string
+	Required    bool
+}
+
+type handlerFunc func(w http.ResponseWriter, r *http.Request, params map[string]string) error
+
+// route describes an HTTP endpoint. Besides a handler it keeps everything
+// needed to describe the endpoint in the OpenAPI document.
+type route struct {
+	Method    string
+	Path      string
+	Operation string
+	Summary   string
+	// Public routes don't require a bearer token.
+	Public   bool
+	Request  proto.Message
+	Response proto.Message
+	// RequestBody and ResponseBody tell how messages are encoded in bodies.
+	RequestBody  bodyKind
+	ResponseBody bodyKind
+	Query        []param
+	Headers      []param
+	// ResponseHeaders are set by binary responses.
+	ResponseHeaders []param
+
+	handler  handlerFunc
+	segments []string
+}
+
+func (rt *route) match(segments []string) (map[string]string, bool) {
+	if len(segments) != len(rt.segments) {
+		return nil, false
+	}
+
+	var params map[string]string
+	for i, s := range rt.segments {
+		if name, ok := pathParam(s); ok {
+			if len(segments[i]) == 0 {
+				return nil, false
+			}
+			if params == nil {
+				params = make(map[string]string)
+			}
+			params[name] = segments[i]
+			continue
+		}
+		if s != segments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+func pathParam(segment string) (string, bool) {
+	if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+		return segment[1 : len(segment)-1], true
+	}
+	return "", false
+}
+
+var (
+	_listQuery = []param{
+		{Name: "page_size", Type: paramInteger, Description: "Maximum number of resources in a page."},
+		{Name: "page_token", Type: paramString, Description: "Token of a page returned by a previous call."},
+		{Name: "order_by", Type: paramString, Description: "LIST_ORDER_ID or LIST_ORDER_UPDATED."},
+		{Name: "updated_after", Type: paramString, Description: "RFC 3339 time, only resources updated after it are listed."},
+		{Name: "include_deleted", Type: paramBoolean, Description: "List tombstones of deleted resources too."},
+		{Name: "kind", Type: paramString, Description: "Kind of listed resources."},
+	}
+	_metaHeaders = []param{
+		{Name: _headerSize, Type: paramInteger, Description: "Size of resource data, the content length by default."},
+		{Name: _headerSalt, Type: paramString, Description: "Base64 encoded salt of the resource key.", Required: true},
+		{Name: _headerSha256, Type: paramString, Description: "Hex encoded SHA-256 digest of resource data."},
+		{Name: _headerKind, Type: paramString, Description: "Kind of the resource."},
+		{Name: _headerMetadata, Type: paramString, Description: "Base64 encoded encrypted metadata."},
+	}
+)
+
+func (g *Gateway) buildRoutes() []route {
+	routes := []route{
+		{
+			Method: http.MethodPost, Path: "/v1/auth/register", Operation: "Register",
+			Summary: "Registers a user.", Public: true,
+			Request: &pb.AuthorizationRequest{}, RequestBody: bodyJSON,
+			Response: &pb.AuthorizationResponse{}, ResponseBody: bodyJSON,
+			handler: g.register,
+		},
+		{
+			Method: http.MethodPost, Path: "/v1/auth/authorize", Operation: "Authorize",
+			Summary: "Exchanges a login and a password for tokens.", Public: true,
+			Request: &pb.AuthorizationRequest{}, RequestBody: bodyJSON,
+			Response: &pb.AuthorizationResponse{}, ResponseBody: bodyJSON,
+			handler: g.authorize,
+		},
+		{
+			Method: http.MethodGet, Path: "/v1/info", Operation: "ServerInfo",
+			Summary: "Describes the server and the protocol it speaks.", Public: true,
+			Response: &pb.ServerInfo{}, ResponseBody: bodyJSON,
+			handler: g.serverInfo,
+		},
+		{
+			Method: http.MethodGet, Path: "/v1/resources", Operation: "List",
+			Summary:  "Lists resources of a user, one ListResponse message per line.",
+			Query:    _listQuery,
+			Response: &pb.ListResponse{}, ResponseBody: bodyNDJSON,
+			handler: g.list,
+		},
+		{
+			Method: http.MethodPost, Path: "/v1/resources", Operation: "Add",
+			Summary:     "Adds a resource with data of a request body.",
+			RequestBody: bodyBinary, Headers: _metaHeaders,
+			Response: &pb.ResourceOperationResponse{}, ResponseBody: bodyJSON,
+			handler: g.add,
+		},
+		{
+			Method: http.MethodPost, Path: "/v1/resources/chunked", Operation: "AddChunked",
+			Summary: "Adds a resource which data is a concatenation of stored chunks.",
+			Request: &pb.AddChunkedRequest{}, RequestBody: bodyJSON,
+			Response: &pb.ResourceOperationResponse{}, ResponseBody: bodyJSON,
+			handler: g.addChunked,
+		},
+		{
+			Method: http.MethodGet, Path: "/v1/resources/{id}", Operation: "Stat",
+			Summary:  "Returns information about a resource.",
+			Response: &pb.Resource{}, ResponseBody: bodyJSON,
+			handler: g.stat,
+		},
+		{
+			Method: http.MethodDelete, Path: "/v1/resources/{id}", Operation: "Delete",
+			Summary:  "Moves a resource to the trash.",
+			Response: &pb.ResourceOperationResponse{}, ResponseBody: bodyJSON,
+			handler: g.delete,
+		},
+		{
+			Method: http.MethodGet, Path: "/v1/resources/{id}/data", Operation: "Get",
+			Summary: "Downloads resource data or a byte range of it.",
+			Query: []param{
+				{Name: "offset", Type: paramInteger, Description: "Offset of the first returned byte."},
+				{Name: "length", Type: paramInteger, Description: "Number of returned bytes, the rest of data by default."},
+			},
+			ResponseBody: bodyBinary,
+			ResponseHeaders: []param{
+				{Name: _headerSize, Type: paramInteger, Description: "Total size of resource data."},
+				{Name: _headerSalt, Type: paramString, Description: "Base64 encoded salt of the resource key."},
+				{Name: _headerSha256, Type: paramString, Description: "Hex encoded SHA-256 digest of resource data."},
+			},
+			handler: g.get,
+		},
+		{
+			Method: http.MethodPost, Path: "/v1/uploads", Operation: "CreateUpload",
+			Summary: "Starts a resumable upload.",
+			Request: &pb.CreateUploadRequest{}, RequestBody: bodyJSON,
+			Response: &pb.UploadSession{}, ResponseBody: bodyJSON,
+			handler: g.createUpload,
+		},
+		{
+			Method: http.MethodGet, Path: "/v1/uploads/{upload_id}", Operation: "GetUpload",
+			Summary:  "Returns a state of an upload.",
+			Response: &pb.UploadSession{}, ResponseBody: bodyJSON,
+			handler: g.getUpload,
+		},
+		{
+			Method: http.MethodPut, Path: "/v1/uploads/{upload_id}", Operation: "AppendUpload",
+			Summary: "Appends data of a request body to an upload.",
+			Query: []param{
+				{Name: "offset", Type: paramInteger, Description: "Offset of the appended data, the committed offset.", Required: true},
+			},
+			RequestBody: bodyBinary,
+			Response:    &pb.UploadSession{}, ResponseBody: bodyJSON,
+			handler: g.appendUpload,
+		},
+		{
+			Method: http.MethodPost, Path: "/v1/uploads/{upload_id}/finalize", Operation: "FinalizeUpload",
+			Summary:  "Turns a complete upload into a resource.",
+			Response: &pb.ResourceOperationResponse{}, ResponseBody: bodyJSON,
+			handler: g.finalizeUpload,
+		},
+		{
+			Method: http.MethodPost, Path: "/v1/chunks/missing", Operation: "MissingChunks",
+			Summary: "Returns chunks of a list the server doesn't store.",
+			Request: &pb.ChunkList{}, RequestBody: bodyJSON,
+			Response: &pb.ChunkList{}, ResponseBody: bodyJSON,
+			handler: g.missingChunks,
+		},
+		{
+			Method: http.MethodPut, Path: "/v1/chunks/{sha256}", Operation: "PutChunks",
+			Summary:     "Stores a chunk with data of a request body. The chunk is addressed by a hex encoded digest.",
+			RequestBody: bodyBinary,
+			Response:    &pb.PutChunksResponse{}, ResponseBody: bodyJSON,
+			handler: g.putChunk,
+		},
+		{
+			Method: http.MethodGet, Path: "/v1/trash", Operation: "ListDeleted",
+			Summary:  "Lists restorable resources, one ListResponse message per line.",
+			Query:    _listQuery[:2],
+			Response: &pb.ListResponse{}, ResponseBody: bodyNDJSON,
+			handler: g.listDeleted,
+		},
+		{
+			Method: http.MethodDelete, Path: "/v1/trash", Operation: "EmptyTrash",
+			Summary:  "Purges all deleted resources.",
+			Response: &pb.EmptyTrashResponse{}, ResponseBody: bodyJSON,
+			handler: g.emptyTrash,
+		},
+		{
+			Method: http.MethodPost, Path: "/v1/trash/{id}/restore", Operation: "Restore",
+			Summary:  "Restores a deleted resource.",
+			Response: &pb.Resource{}, ResponseBody: bodyJSON,
+			handler: g.restore,
+		},
+		{
+			Method: http.MethodGet, Path: "/v1/usage", Operation: "Usage",
+			Summary:  "Returns storage usage and quota of a user.",
+			Response: &pb.UsageResponse{}, ResponseBody: bodyJSON,
+			handler: g.usage,
+		},
+		{
+			Method: http.MethodPost, Path: "/v1/batch", Operation: "Batch",
+			Summary: "Applies operations to many resources at once.",
+			Request: &pb.BatchRequest{}, RequestBody: bodyJSON,
+			Response: &pb.BatchResponse{}, ResponseBody: bodyJSON,
+			handler: g.batch,
+		},
+		{
+			Method: http.MethodGet, Path: "/v1/openapi.json", Operation: "OpenAPI",
+			Summary: "Returns this document.", Public: true,
+			ResponseBody: bodyJSON,
+			handler:      g.openAPI,
+		},
+	}
+
+	for i := range routes {
+		routes[i].segments = splitPath(routes[i].Path)
+	}
+	return routes
+}
@@ -0,0 +1,149 @@
+package grpc
+
+import (
+	"context"
+	"crypto/subtle"
+
+	grpc_auth "github.com/grpc-ecosystem/go-grpc-middleware/auth"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/r4start/goph-keeper/internal/server/app"
+	"github.com/r4start/goph-keeper/internal/server/storage"
+	pb "github.com/r4start/goph-keeper/pkg/grpc/proto"
+)
+
+const _adminScheme = "bearer"
+
+type AdminService struct {
+	pb.UnimplementedAdminServer
+
+	gc     *app.GCWorker
+	quotas *app.Quotas
+	token  []byte
+}
+
+// NewAdminService creates a service authorizing every call with token.
+func NewAdminService(gc *app.GCWorker, quotas *app.Quotas, token string) *AdminService {
+	return &AdminService{
+		gc:     gc,
+		quotas: quotas,
+		token:  []byte(token),
+	}
+}
+
+// AuthFuncOverride replaces user authorization with a check of the admin token.
+func (a *AdminService) AuthFuncOverride(ctx context.Context, _ string) (context.Context, error) {
+	token, err := grpc_auth.AuthFromMD(ctx, _adminScheme)
+	if err != nil {
+		return ctx, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	if len(a.token) == 0 || subtle.ConstantTimeCompare([]byte(token), a.token) != 1 {
+		return ctx, status.Error(codes.PermissionDenied, "admin token mismatch")
+	}
+	return ctx, nil
+}
+
+func (a *AdminService) CollectGarbage(ctx context.Context, _ *pb.GarbageCollectionRequest) (*pb.GarbageCollectionStats, error) {
+	run, _ := a.gc.Collect(ctx)
+	return gcRunToProto(run), nil
+}
+
+func (a *AdminService) GarbageCollectionStatus(context.Context, *pb.GarbageCollectionRequest) (*pb.GarbageCollectionStats, error) {
+	run := a.gc.LastRun()
+	if run == nil {
+		return nil, status.Error(codes.NotFound, "garbage collection has not run yet")
+	}
+	return gcRunToProto(run), nil
+}
+
+func (a *AdminService) GetQuota(ctx context.Context, r *pb.QuotaRequest) (*pb.Quota, error) {
+	userID, err := parseUserID(r.UserId)
+	if err != nil {
+		return nil, err
+	}
+	return a.quota(ctx, userID)
+}
+
+func (a *AdminService) SetQuota(ctx context.Context, r *pb.Quota) (*pb.Quota, error) {
+	userID, err := parseUserID(r.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	quota := &storage.Quota{
+		MaxBytes: r.GetMaxBytes(),
+		MaxItems: r.GetMaxItems(),
+	}
+	if err := a.quotas.Override(ctx, userID, quota); err != nil {
+		return nil, err
+	}
+	return a.quota(ctx, userID)
+}
+
+func (a *AdminService) ResetQuota(ctx context.Context, r *pb.QuotaRequest) (*pb.Quota, error) {
+	userID, err := parseUserID(r.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.quotas.Reset(ctx, userID); err != nil {
+		return nil, err
+	}
+	return a.quota(ctx, userID)
+}
+
+func (a *AdminService) quota(ctx context.Context, userID *storage.UserID) (*pb.Quota, error) {
+	quota, isDefault, err := a.quotas.Limits(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	id := userID.String()
+	return &pb.Quota{
+		UserId:    &id,
+		MaxBytes:  &quota.MaxBytes,
+		MaxItems:  &quota.MaxItems,
+		IsDefault: &isDefault,
+	}, nil
+}
+
+func parseUserID(id *string) (*storage.UserID, error) {
+	if id == nil {
+		return nil, status.Error(codes.InvalidArgument, "user id is empty")
+	}
+
+	userID, err := storage.NewUserIDFromString(*id)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "bad user id: %v", err)
+	}
+	return userID, nil
+}
+
+func gcRunToProto(run *app.GCRun) *pb.GarbageCollectionStats {
+	var (
+		tombstones     = uint64(run.Stats.Tombstones)
+		chunks         = uint64(run.Stats.Chunks)
+		blobs          = uint64(run.Stats.Blobs)
+		orphans        = uint64(run.Stats.Orphans)
+		orphansDeleted = uint64(run.Stats.OrphansDeleted)
+	)
+
+	stats := &pb.GarbageCollectionStats{
+		StartedAt:      timestamppb.New(run.Stats.StartedAt),
+		FinishedAt:     timestamppb.New(run.Stats.FinishedAt),
+		Tombstones:     &tombstones,
+		Chunks:         &chunks,
+		Blobs:          &blobs,
+		Orphans:        &orphans,
+		OrphansDeleted: &orphansDeleted,
+	}
+	if run.Err != nil {
+		msg := run.Err.Error()
+		stats.Error = &msg
+	}
+	return stats
+}
@@ -0,0 +1,121 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	grpc_auth "github.com/grpc-ecosystem/go-grpc-middleware/auth"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/r4start/goph-keeper/internal/server/app"
+	"github.com/r4start/goph-keeper/internal/server/storage"
+	pb "github.com/r4start/goph-keeper/pkg/grpc/proto"
+)
+
+type mockGarbageCollector struct {
+	runs int
+}
+
+func (m *mockGarbageCollector) CollectGarbage(context.Context, time.Time) (*storage.GCStats, error) {
+	m.runs++
+	return &storage.GCStats{
+		StartedAt:  time.Now(),
+		FinishedAt: time.Now(),
+		Tombstones: m.runs,
+		Orphans:    2,
+	}, nil
+}
+
+func TestAdminService_CollectGarbage(t *testing.T) {
+	gc := &mockGarbageCollector{}
+	s := NewAdminService(app.NewGCWorker(gc, time.Hour), app.NewQuotas(newTestStorage(t), storage.Quota{}), "admin-token")
+
+	reg := func(srv *grpc.Server) {
+		pb.RegisterAdminServer(srv, s)
+	}
+
+	// User authorization must not be applied to the admin service.
+	userAuth := func(ctx context.Context) (context.Context, error) {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	srv, conn := prepareTestEnv(t, reg, grpc.UnaryInterceptor(grpc_auth.UnaryServerInterceptor(userAuth)))
+	defer srv.Stop()
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	client := pb.NewAdminClient(conn)
+
+	_, err := client.CollectGarbage(context.Background(), &pb.GarbageCollectionRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	badCtx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "bearer user-token")
+	_, err = client.CollectGarbage(badCtx, &pb.GarbageCollectionRequest{})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Zero(t, gc.runs)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "bearer admin-token")
+	_, err = client.GarbageCollectionStatus(ctx, &pb.GarbageCollectionRequest{})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	stats, err := client.CollectGarbage(ctx, &pb.GarbageCollectionRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), stats.GetTombstones())
+	assert.Equal(t, uint64(2), stats.GetOrphans())
+	assert.Empty(t, stats.GetError())
+
+	last, err := client.GarbageCollectionStatus(ctx, &pb.GarbageCollectionRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, stats.GetTombstones(), last.GetTombstones())
+	assert.Equal(t, 1, gc.runs)
+}
+
+func TestAdminService_Quota(t *testing.T) {
+	wh := newTestStorage(t)
+	quotas := app.NewQuotas(wh, storage.Quota{MaxBytes: 1024, MaxItems: 10})
+	s := NewAdminService(app.NewGCWorker(&mockGarbageCollector{}, time.Hour), quotas, "admin-token")
+
+	reg := func(srv *grpc.Server) {
+		pb.RegisterAdminServer(srv, s)
+	}
+
+	srv, conn := prepareTestEnv(t, reg)
+	defer srv.Stop()
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	client := pb.NewAdminClient(conn)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "bearer admin-token")
+
+	userID := wh.user.String()
+	quota, err := client.GetQuota(ctx, &pb.QuotaRequest{UserId: &userID})
+	assert.NoError(t, err)
+	assert.True(t, quota.GetIsDefault())
+	assert.Equal(t, uint64(1024), quota.GetMaxBytes())
+	assert.Equal(t, uint64(10), quota.GetMaxItems())
+
+	maxBytes := uint64(4096)
+	quota, err = client.SetQuota(ctx, &pb.Quota{UserId: &userID, MaxBytes: &maxBytes})
+	assert.NoError(t, err)
+	assert.False(t, quota.GetIsDefault())
+	assert.Equal(t, maxBytes, quota.GetMaxBytes())
+	assert.Zero(t, quota.GetMaxItems())
+
+	quota, err = client.ResetQuota(ctx, &pb.QuotaRequest{UserId: &userID})
+	assert.NoError(t, err)
+	assert.True(t, quota.GetIsDefault())
+	assert.Equal(t, uint64(1024), quota.GetMaxBytes())
+
+	bad := "not an id"
+	_, err = client.GetQuota(ctx, &pb.QuotaRequest{UserId: &bad})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = client.SetQuota(ctx, &pb.Quota{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
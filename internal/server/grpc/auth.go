@@ -5,18 +5,20 @@ import (
 	"time"
 
 	grpc_auth "github.com/grpc-ecosystem/go-grpc-middleware/auth"
+	"go.uber.org/zap"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	"github.com/r4start/goph-keeper/internal/logging"
 	"github.com/r4start/goph-keeper/internal/server/app"
 	pb "github.com/r4start/goph-keeper/pkg/grpc/proto"
 )
 
-const (
-	_userAuthKey    = "UserAuth"
-	_expectedScheme = "jwt"
-)
+// AuthScheme is a scheme of authorization metadata with user tokens.
+const AuthScheme = "jwt"
+
+const _userAuthKey = "UserAuth"
 
 type AuthService struct {
 	pb.UnimplementedAuthorizationServiceServer
@@ -41,7 +43,7 @@ func (a *AuthService) Register(ctx context.Context, r *pb.AuthorizationRequest)
 
 	authData, err := a.auth.Register(authCtx, *r.Login, *r.Password, r.Salt)
 	if err != nil {
-		return nil, status.Error(codes.Unknown, err.Error())
+		return nil, err
 	}
 	return &pb.AuthorizationResponse{
 		UserId:       &authData.ID,
@@ -60,7 +62,7 @@ func (a *AuthService) Authorize(ctx context.Context, r *pb.AuthorizationRequest)
 
 	token, err := a.auth.Authorize(authCtx, *r.Login, *r.Password)
 	if err != nil {
-		return nil, status.Error(codes.Unknown, err.Error())
+		return nil, err
 	}
 	return &pb.AuthorizationResponse{
 		Token:        &token.Token,
@@ -77,16 +79,19 @@ func (a *AuthService) AuthFuncOverride(ctx context.Context, fullMethodName strin
 
 func BuildAuthorizationInterceptor(a app.Authorizer) grpc_auth.AuthFunc {
 	return func(ctx context.Context) (context.Context, error) {
-		token, err := grpc_auth.AuthFromMD(ctx, _expectedScheme)
+		token, err := grpc_auth.AuthFromMD(ctx, AuthScheme)
 		if err != nil {
 			return ctx, status.Error(codes.Unauthenticated, "unauthorized")
 		}
 
 		auth, err := a.AuthorizeWithToken(ctx, token)
 		if err != nil {
+			logging.FromContext(ctx).Debug("token rejected", zap.Error(err))
 			return ctx, status.Error(codes.Unauthenticated, "unauthorized")
 		}
 
+		setCallUser(ctx, auth.ID)
+		ctx = logging.With(ctx, zap.String("user_id", auth.ID))
 		return context.WithValue(ctx, _userAuthKey, auth), nil
 	}
 }
@@ -82,6 +82,9 @@ func prepareServer(t *testing.T, register serviceRegisterer, opts ...grpc.Server
 	const bufSize = 1024 * 1024
 
 	lis := bufconn.Listen(bufSize)
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(ErrorUnaryInterceptor()),
+		grpc.ChainStreamInterceptor(ErrorStreamInterceptor()))
 	grpcServer := grpc.NewServer(opts...)
 	register(grpcServer)
 	go func(t *testing.T) {
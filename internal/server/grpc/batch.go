@@ -0,0 +1,72 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/r4start/goph-keeper/internal/server/storage"
+	pb "github.com/r4start/goph-keeper/pkg/grpc/proto"
+)
+
+const _maxBatchSize = 1000
+
+func (s *StorageService) Batch(ctx context.Context, r *pb.BatchRequest) (*pb.BatchResponse, error) {
+	userID, err := authorizedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(r.Items) > _maxBatchSize {
+		return nil, status.Errorf(codes.InvalidArgument, "batch is larger than %d items", _maxBatchSize)
+	}
+
+	items := make([]storage.BatchItem, 0, len(r.Items))
+	for _, item := range r.Items {
+		id, err := uuid.Parse(item.GetId())
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "bad resource id %q: %v", item.GetId(), err)
+		}
+
+		var op storage.BatchOp
+		switch item.GetOperation() {
+		case pb.BatchOperation_BATCH_OPERATION_STAT:
+			op = storage.BatchStat
+		case pb.BatchOperation_BATCH_OPERATION_DELETE:
+			op = storage.BatchDelete
+		case pb.BatchOperation_BATCH_OPERATION_RESTORE:
+			op = storage.BatchRestore
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "unknown batch operation %v", item.GetOperation())
+		}
+
+		items = append(items, storage.BatchItem{Op: op, ID: storage.ResourceID(id)})
+	}
+
+	results, err := s.wh.Batch(ctx, userID, items, &storage.BatchOptions{
+		Atomic:          r.GetAtomic(),
+		RestorableSince: s.restorableSince(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &pb.BatchResponse{Results: make([]*pb.BatchResponse_Result, 0, len(results))}
+	for i, result := range results {
+		id := items[i].ID.String()
+		res := &pb.BatchResponse_Result{Id: &id}
+		if result.Err != nil {
+			errCode, msg := errorCode(result.Err)
+			code := uint32(_errorMappings[storage.CodeOf(result.Err)].status)
+			res.Code, res.Message, res.ErrorCode = &code, &msg, errCode
+		} else {
+			code := uint32(codes.OK)
+			res.Code = &code
+			res.Resource = resourceInfoToProto(result.Info)
+		}
+		response.Results = append(response.Results, res)
+	}
+	return response, nil
+}
@@ -0,0 +1,101 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	grpc_auth "github.com/grpc-ecosystem/go-grpc-middleware/auth"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/r4start/goph-keeper/internal/server/storage"
+	pb "github.com/r4start/goph-keeper/pkg/grpc/proto"
+)
+
+func TestStorageService_Batch(t *testing.T) {
+	wh := newTestStorage(t)
+	s, err := NewStorageService(wh, 1024)
+	assert.NoError(t, err)
+
+	reg := func(srv *grpc.Server) {
+		pb.RegisterStorageServer(srv, s)
+	}
+
+	authFunc := authGenerator(wh.user.String())
+
+	ctx := context.Background()
+	srv, conn := prepareTestEnv(t, reg,
+		grpc.StreamInterceptor(grpc_auth.StreamServerInterceptor(authFunc)),
+		grpc.UnaryInterceptor(grpc_auth.UnaryServerInterceptor(authFunc)))
+	defer srv.Stop()
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	client := pb.NewStorageClient(conn)
+
+	ids := make([]string, 3)
+	for i := range ids {
+		res, err := wh.Create(ctx, wh.user, &storage.ResourceMeta{})
+		assert.NoError(t, err)
+		assert.NoError(t, res.Close())
+		ids[i] = res.GetId().String()
+	}
+	missing := uuid.NewString()
+
+	item := func(op pb.BatchOperation, id string) *pb.BatchRequest_Item {
+		return &pb.BatchRequest_Item{Operation: &op, Id: &id}
+	}
+	codesOf := func(r *pb.BatchResponse) []codes.Code {
+		result := make([]codes.Code, 0, len(r.GetResults()))
+		for _, res := range r.GetResults() {
+			result = append(result, codes.Code(res.GetCode()))
+		}
+		return result
+	}
+
+	atomic := true
+	r, err := client.Batch(ctx, &pb.BatchRequest{
+		Items: []*pb.BatchRequest_Item{
+			item(pb.BatchOperation_BATCH_OPERATION_DELETE, ids[0]),
+			item(pb.BatchOperation_BATCH_OPERATION_DELETE, missing),
+		},
+		Atomic: &atomic,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []codes.Code{codes.Aborted, codes.NotFound}, codesOf(r))
+	assert.Len(t, wh.resources(t), 3)
+
+	r, err = client.Batch(ctx, &pb.BatchRequest{
+		Items: []*pb.BatchRequest_Item{
+			item(pb.BatchOperation_BATCH_OPERATION_DELETE, ids[0]),
+			item(pb.BatchOperation_BATCH_OPERATION_DELETE, missing),
+			item(pb.BatchOperation_BATCH_OPERATION_DELETE, ids[1]),
+			item(pb.BatchOperation_BATCH_OPERATION_STAT, ids[2]),
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []codes.Code{codes.OK, codes.NotFound, codes.OK, codes.OK}, codesOf(r))
+	assert.Equal(t, ids[0], r.GetResults()[0].GetResource().GetId())
+	assert.True(t, r.GetResults()[0].GetResource().GetIsDeleted())
+	assert.Equal(t, ids[2], r.GetResults()[3].GetResource().GetId())
+	assert.Len(t, wh.resources(t), 1)
+
+	r, err = client.Batch(ctx, &pb.BatchRequest{
+		Items: []*pb.BatchRequest_Item{
+			item(pb.BatchOperation_BATCH_OPERATION_RESTORE, ids[0]),
+			item(pb.BatchOperation_BATCH_OPERATION_RESTORE, ids[2]),
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []codes.Code{codes.OK, codes.NotFound}, codesOf(r))
+	assert.Len(t, wh.resources(t), 2)
+
+	_, err = client.Batch(ctx, &pb.BatchRequest{
+		Items: []*pb.BatchRequest_Item{item(pb.BatchOperation_BATCH_OPERATION_STAT, "not an id")},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
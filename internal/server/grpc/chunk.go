@@ -0,0 +1,103 @@
+package grpc
+
+import (
+	"context"
+	"crypto/sha256"
+	"io"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/r4start/goph-keeper/pkg/grpc/proto"
+)
+
+func (s *StorageService) MissingChunks(ctx context.Context, r *pb.ChunkList) (*pb.ChunkList, error) {
+	userID, err := authorizedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateDigests(r.Sha256); err != nil {
+		return nil, err
+	}
+
+	missing, err := s.wh.MissingChunks(ctx, userID, r.Sha256)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ChunkList{Sha256: missing}, nil
+}
+
+func (s *StorageService) PutChunks(stream pb.Storage_PutChunksServer) error {
+	ctx := stream.Context()
+	userID, err := authorizedUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	var received uint32
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			return stream.SendAndClose(&pb.PutChunksResponse{ChunksReceived: &received})
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := validateDigests([][]byte{chunk.Sha256}); err != nil {
+			return err
+		}
+
+		if err := s.checkQuota(ctx, userID, uint64(len(chunk.Data)), 0); err != nil {
+			return err
+		}
+
+		if err := s.wh.PutChunk(ctx, userID, chunk.Sha256, chunk.Data); err != nil {
+			return err
+		}
+		received++
+	}
+}
+
+func (s *StorageService) AddChunked(ctx context.Context, r *pb.AddChunkedRequest) (*pb.ResourceOperationResponse, error) {
+	userID, err := authorizedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	meta, err := resourceMetaFromProto(r.Meta)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateDigests(r.Chunks); err != nil {
+		return nil, err
+	}
+
+	// Bytes are counted when chunks are put.
+	if err := s.checkQuota(ctx, userID, 0, 1); err != nil {
+		return nil, err
+	}
+
+	info, err := s.wh.CreateChunked(ctx, userID, meta, r.Chunks)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ResourceOperationResponse{
+		Result: &pb.ResourceOperationResponse_Resource{
+			Resource: resourceInfoToProto(info),
+		},
+	}, nil
+}
+
+func validateDigests(digests [][]byte) error {
+	for _, d := range digests {
+		if len(d) != sha256.Size {
+			return status.Errorf(codes.InvalidArgument, "bad sha256 digest length %d", len(d))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package grpc
+
+import (
+	"context"
+	"crypto/sha256"
+	"io"
+	"testing"
+
+	grpc_auth "github.com/grpc-ecosystem/go-grpc-middleware/auth"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/r4start/goph-keeper/pkg/grpc/proto"
+)
+
+func TestStorageService_ChunkedUpload(t *testing.T) {
+	wh := newTestStorage(t)
+	s, err := NewStorageService(wh, 1024)
+	assert.NoError(t, err)
+
+	reg := func(srv *grpc.Server) {
+		pb.RegisterStorageServer(srv, s)
+	}
+
+	authFunc := authGenerator(wh.user.String())
+
+	ctx := context.Background()
+	srv, conn := prepareTestEnv(t, reg,
+		grpc.StreamInterceptor(grpc_auth.StreamServerInterceptor(authFunc)),
+		grpc.UnaryInterceptor(grpc_auth.UnaryServerInterceptor(authFunc)))
+	defer srv.Stop()
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	client := pb.NewStorageClient(conn)
+
+	chunks := make([][]byte, 3)
+	digests := make([][]byte, 3)
+	data := make([]byte, 0)
+	for i := range chunks {
+		chunks[i], err = generateRandom(16 * 1024)
+		assert.NoError(t, err)
+		digest := sha256.Sum256(chunks[i])
+		digests[i] = digest[:]
+		data = append(data, chunks[i]...)
+	}
+
+	// The first chunk is stored already, so only the rest are sent.
+	putC, err := client.PutChunks(ctx)
+	assert.NoError(t, err)
+	assert.NoError(t, putC.Send(&pb.Chunk{Sha256: digests[0], Data: chunks[0]}))
+	putResp, err := putC.CloseAndRecv()
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(1), putResp.GetChunksReceived())
+
+	missing, err := client.MissingChunks(ctx, &pb.ChunkList{Sha256: digests})
+	assert.NoError(t, err)
+	assert.Equal(t, digests[1:], missing.Sha256)
+
+	size := uint64(len(data))
+	digest := sha256.Sum256(data)
+	meta := &pb.ResourceOperationData_ResourceMeta{
+		Salt:             []byte("salt"),
+		ResourceByteSize: &size,
+		Sha256:           digest[:],
+	}
+
+	// A resource can't refer chunks which haven't been stored.
+	_, err = client.AddChunked(ctx, &pb.AddChunkedRequest{Meta: meta, Chunks: digests})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	putC, err = client.PutChunks(ctx)
+	assert.NoError(t, err)
+	for i := 1; i < len(chunks); i++ {
+		assert.NoError(t, putC.Send(&pb.Chunk{Sha256: digests[i], Data: chunks[i]}))
+	}
+	_, err = putC.CloseAndRecv()
+	assert.NoError(t, err)
+
+	missing, err = client.MissingChunks(ctx, &pb.ChunkList{Sha256: digests})
+	assert.NoError(t, err)
+	assert.Empty(t, missing.Sha256)
+
+	resp, err := client.AddChunked(ctx, &pb.AddChunkedRequest{Meta: meta, Chunks: digests})
+	assert.NoError(t, err)
+	assert.Equal(t, size, resp.GetResource().GetByteSize())
+
+	getC, err := client.Get(ctx, &pb.GetRequest{Id: resp.GetResource().Id})
+	assert.NoError(t, err)
+	remote := make([]byte, 0, len(data))
+	for {
+		msg, err := getC.Recv()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		remote = append(remote, msg.GetChunk().GetData()...)
+	}
+	assert.Equal(t, data, remote)
+
+	// The manifest must match the declared size and digest.
+	_, err = client.AddChunked(ctx, &pb.AddChunkedRequest{Meta: meta, Chunks: digests[:2]})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestStorageService_PutCorruptedChunk(t *testing.T) {
+	wh := newTestStorage(t)
+	s, err := NewStorageService(wh, 1024)
+	assert.NoError(t, err)
+
+	reg := func(srv *grpc.Server) {
+		pb.RegisterStorageServer(srv, s)
+	}
+
+	authFunc := authGenerator(wh.user.String())
+
+	ctx := context.Background()
+	srv, conn := prepareTestEnv(t, reg,
+		grpc.StreamInterceptor(grpc_auth.StreamServerInterceptor(authFunc)),
+		grpc.UnaryInterceptor(grpc_auth.UnaryServerInterceptor(authFunc)))
+	defer srv.Stop()
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	client := pb.NewStorageClient(conn)
+
+	chunk, err := generateRandom(1024)
+	assert.NoError(t, err)
+	digest := sha256.Sum256(chunk)
+	chunk[0] ^= 0xff
+
+	putC, err := client.PutChunks(ctx)
+	assert.NoError(t, err)
+	assert.NoError(t, putC.Send(&pb.Chunk{Sha256: digest[:], Data: chunk}))
+	_, err = putC.CloseAndRecv()
+	assert.Equal(t, codes.DataLoss, status.Code(err))
+	missing, err := wh.MissingChunks(ctx, wh.user, [][]byte{digest[:]})
+	assert.NoError(t, err)
+	assert.Len(t, missing, 1)
+
+	putC, err = client.PutChunks(ctx)
+	assert.NoError(t, err)
+	assert.NoError(t, putC.Send(&pb.Chunk{Sha256: digest[:4], Data: chunk}))
+	_, err = putC.CloseAndRecv()
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -0,0 +1,107 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/r4start/goph-keeper/internal/logging"
+	"github.com/r4start/goph-keeper/internal/server/storage"
+	pb "github.com/r4start/goph-keeper/pkg/grpc/proto"
+)
+
+// ErrorDomain is a domain of ErrorInfo details attached to failed calls.
+const ErrorDomain = "gophkeeper"
+
+type errorMapping struct {
+	status codes.Code
+	code   pb.ErrorCode
+}
+
+var _errorMappings = map[storage.Code]errorMapping{
+	storage.CodeInternal:           {codes.Internal, pb.ErrorCode_ERROR_CODE_INTERNAL},
+	storage.CodeNotFound:           {codes.NotFound, pb.ErrorCode_ERROR_CODE_NOT_FOUND},
+	storage.CodeAlreadyExists:      {codes.AlreadyExists, pb.ErrorCode_ERROR_CODE_ALREADY_EXISTS},
+	storage.CodeInvalidArgument:    {codes.InvalidArgument, pb.ErrorCode_ERROR_CODE_INVALID_ARGUMENT},
+	storage.CodeInvalidCredentials: {codes.Unauthenticated, pb.ErrorCode_ERROR_CODE_INVALID_CREDENTIALS},
+	storage.CodeUnauthenticated:    {codes.Unauthenticated, pb.ErrorCode_ERROR_CODE_UNAUTHENTICATED},
+	storage.CodeQuotaExceeded:      {codes.ResourceExhausted, pb.ErrorCode_ERROR_CODE_QUOTA_EXCEEDED},
+	storage.CodeConflict:           {codes.Aborted, pb.ErrorCode_ERROR_CODE_CONFLICT},
+	storage.CodeFailedPrecondition: {codes.FailedPrecondition, pb.ErrorCode_ERROR_CODE_FAILED_PRECONDITION},
+	storage.CodeOutOfRange:         {codes.OutOfRange, pb.ErrorCode_ERROR_CODE_OUT_OF_RANGE},
+	storage.CodeDataLoss:           {codes.DataLoss, pb.ErrorCode_ERROR_CODE_DATA_LOSS},
+}
+
+// ErrorUnaryInterceptor converts errors returned by handlers with statusError.
+// Internal errors are logged before their text is hidden from clients.
+func ErrorUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		logInternalError(ctx, err)
+		return resp, statusError(err)
+	}
+}
+
+// ErrorStreamInterceptor converts errors returned by handlers with statusError.
+func ErrorStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		err := handler(srv, ss)
+		logInternalError(ss.Context(), err)
+		return statusError(err)
+	}
+}
+
+func logInternalError(ctx context.Context, err error) {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return
+	}
+	if _, ok := status.FromError(err); ok || storage.CodeOf(err) != storage.CodeInternal {
+		return
+	}
+	logging.FromContext(ctx).Error("internal error", zap.Error(err))
+}
+
+// statusError maps a domain error to a gRPC status with an ErrorInfo detail
+// which reason is a name of pb.ErrorCode. Status errors are returned as is.
+// Other errors become codes.Internal without their text, so database and
+// other internal details don't reach clients.
+func statusError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+
+	code, msg := errorCode(err)
+	st, e := status.New(_errorMappings[storage.CodeOf(err)].status, msg).
+		WithDetails(&errdetails.ErrorInfo{
+			Reason: code.String(),
+			Domain: ErrorDomain,
+		})
+	if e != nil {
+		return status.Error(codes.Internal, msg)
+	}
+	return st.Err()
+}
+
+// errorCode returns a code and a message of err safe to send to a client.
+func errorCode(err error) (pb.ErrorCode, string) {
+	code := storage.CodeOf(err)
+	if code == storage.CodeInternal {
+		return pb.ErrorCode_ERROR_CODE_INTERNAL, "internal error"
+	}
+	return _errorMappings[code].code, err.Error()
+}
@@ -0,0 +1,85 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/r4start/goph-keeper/internal/server/app"
+	"github.com/r4start/goph-keeper/internal/server/storage"
+	pb "github.com/r4start/goph-keeper/pkg/grpc/proto"
+)
+
+func TestStatusError(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		code    codes.Code
+		reason  pb.ErrorCode
+		message string
+	}{
+		{
+			name:    "not found",
+			err:     storage.ErrResourceNotFound,
+			code:    codes.NotFound,
+			reason:  pb.ErrorCode_ERROR_CODE_NOT_FOUND,
+			message: storage.ErrResourceNotFound.Error(),
+		},
+		{
+			name:    "wrapped",
+			err:     fmt.Errorf("%w: 10 bytes declared, 5 bytes received", storage.ErrSizeMismatch),
+			code:    codes.InvalidArgument,
+			reason:  pb.ErrorCode_ERROR_CODE_INVALID_ARGUMENT,
+			message: "resource size mismatch: 10 bytes declared, 5 bytes received",
+		},
+		{
+			name:    "invalid credentials",
+			err:     app.ErrInvalidCredentials,
+			code:    codes.Unauthenticated,
+			reason:  pb.ErrorCode_ERROR_CODE_INVALID_CREDENTIALS,
+			message: app.ErrInvalidCredentials.Error(),
+		},
+		{
+			name:    "quota",
+			err:     app.ErrQuotaExceeded,
+			code:    codes.ResourceExhausted,
+			reason:  pb.ErrorCode_ERROR_CODE_QUOTA_EXCEEDED,
+			message: app.ErrQuotaExceeded.Error(),
+		},
+		{
+			name:    "internal",
+			err:     errors.New(`relation "user_data" does not exist`),
+			code:    codes.Internal,
+			reason:  pb.ErrorCode_ERROR_CODE_INTERNAL,
+			message: "internal error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := status.Convert(statusError(tt.err))
+			assert.Equal(t, tt.code, st.Code())
+			assert.Equal(t, tt.message, st.Message())
+
+			details := st.Details()
+			assert.Len(t, details, 1)
+			info, ok := details[0].(*errdetails.ErrorInfo)
+			assert.True(t, ok)
+			assert.Equal(t, ErrorDomain, info.GetDomain())
+			assert.Equal(t, tt.reason.String(), info.GetReason())
+		})
+	}
+
+	assert.NoError(t, statusError(nil))
+
+	err := status.Error(codes.PermissionDenied, "denied")
+	assert.Equal(t, err, statusError(err))
+
+	assert.Equal(t, codes.Canceled, status.Code(statusError(fmt.Errorf("read: %w", context.Canceled))))
+}
@@ -0,0 +1,225 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	"github.com/r4start/goph-keeper/internal/logging"
+	pb "github.com/r4start/goph-keeper/pkg/grpc/proto"
+)
+
+const (
+	_defaultHealthCheckInterval = 5 * time.Second
+	_defaultHealthCheckTimeout  = 2 * time.Second
+)
+
+// HealthCheck reports an error when a dependency of the server is unavailable.
+type HealthCheck func(ctx context.Context) error
+
+type HealthServiceOption func(h *HealthService)
+
+// WithHealthCheck makes the server not serving while check fails.
+func WithHealthCheck(name string, check HealthCheck) HealthServiceOption {
+	return func(h *HealthService) {
+		h.checks = append(h.checks, namedCheck{name: name, check: check})
+	}
+}
+
+// WithHealthCheckInterval sets how often Run checks dependencies.
+// A non-positive interval keeps the default one.
+func WithHealthCheckInterval(interval time.Duration) HealthServiceOption {
+	return func(h *HealthService) {
+		if interval > 0 {
+			h.interval = interval
+		}
+	}
+}
+
+// WithHealthCheckTimeout limits the time of a single check.
+func WithHealthCheckTimeout(timeout time.Duration) HealthServiceOption {
+	return func(h *HealthService) {
+		h.timeout = timeout
+	}
+}
+
+type namedCheck struct {
+	name  string
+	check HealthCheck
+}
+
+// HealthService implements the standard gRPC health checking protocol. The
+// server and all of its services are serving while every check passes. The
+// status of the whole server is reported for an empty service name.
+// Shutdown switches the server to NOT_SERVING for good, so load balancers
+// stop sending new calls before the server stops.
+type HealthService struct {
+	*health.Server
+	services []string
+	checks   []namedCheck
+	interval time.Duration
+	timeout  time.Duration
+	serving  bool
+}
+
+// NewHealthService reports statuses of services, which are NOT_SERVING until
+// the first check.
+func NewHealthService(services []string, opts ...HealthServiceOption) *HealthService {
+	h := &HealthService{
+		Server:   health.NewServer(),
+		services: services,
+		interval: _defaultHealthCheckInterval,
+		timeout:  _defaultHealthCheckTimeout,
+	}
+	for _, o := range opts {
+		o(h)
+	}
+
+	h.setStatus(healthpb.HealthCheckResponse_NOT_SERVING)
+	return h
+}
+
+// Register adds the health service to s.
+func (h *HealthService) Register(s *grpc.Server) {
+	healthpb.RegisterHealthServer(s, h)
+}
+
+func (h *HealthService) AuthFuncOverride(ctx context.Context, _ string) (context.Context, error) {
+	// Health is checked by load balancers and orchestrators which have no user tokens.
+	return ctx, nil
+}
+
+// Update runs all checks and sets the status by their results. It returns
+// errors of failed checks.
+func (h *HealthService) Update(ctx context.Context) error {
+	var result error
+	for _, c := range h.checks {
+		checkCtx, cancel := context.WithTimeout(ctx, h.timeout)
+		if err := c.check(checkCtx); err != nil {
+			result = multierror.Append(result, fmt.Errorf("%s: %w", c.name, err))
+		}
+		cancel()
+	}
+
+	serving := result == nil
+	if serving != h.serving {
+		logger := logging.FromContext(ctx)
+		if serving {
+			logger.Info("server is serving")
+		} else {
+			logger.Warn("server is not serving", zap.Error(result))
+		}
+	}
+	h.serving = serving
+
+	if serving {
+		h.setStatus(healthpb.HealthCheckResponse_SERVING)
+	} else {
+		h.setStatus(healthpb.HealthCheckResponse_NOT_SERVING)
+	}
+	return result
+}
+
+// Run updates the status periodically until ctx is done.
+func (h *HealthService) Run(ctx context.Context) {
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+
+	for {
+		_ = h.Update(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (h *HealthService) setStatus(status healthpb.HealthCheckResponse_ServingStatus) {
+	h.SetServingStatus("", status)
+	for _, s := range h.services {
+		h.SetServingStatus(s, status)
+	}
+}
+
+// ServiceNames lists services registered on s.
+func ServiceNames(s *grpc.Server) []string {
+	info := s.GetServiceInfo()
+	names := make([]string, 0, len(info))
+	for name := range info {
+		names = append(names, name)
+	}
+	return names
+}
+
+// RegisterReflection adds the server reflection service to s. Reflection
+// is available without authorization, so it describes the public API only:
+// the admin service and its messages are neither listed nor resolved.
+func RegisterReflection(s *grpc.Server) {
+	reflectionpb.RegisterServerReflectionServer(s, &reflectionService{
+		ServerReflectionServer: reflection.NewServer(reflection.ServerOptions{
+			Services:           publicServices{s},
+			DescriptorResolver: publicDescriptors{protoregistry.GlobalFiles},
+		}),
+	})
+}
+
+// isPrivateFile reports whether a proto file describes the admin API.
+func isPrivateFile(path string) bool {
+	return path == pb.File_proto_admin_proto.Path()
+}
+
+type publicServices struct {
+	s *grpc.Server
+}
+
+func (p publicServices) GetServiceInfo() map[string]grpc.ServiceInfo {
+	info := p.s.GetServiceInfo()
+	for name, service := range info {
+		if path, ok := service.Metadata.(string); ok && isPrivateFile(path) {
+			delete(info, name)
+		}
+	}
+	return info
+}
+
+type publicDescriptors struct {
+	files *protoregistry.Files
+}
+
+func (p publicDescriptors) FindFileByPath(path string) (protoreflect.FileDescriptor, error) {
+	if isPrivateFile(path) {
+		return nil, protoregistry.NotFound
+	}
+	return p.files.FindFileByPath(path)
+}
+
+func (p publicDescriptors) FindDescriptorByName(name protoreflect.FullName) (protoreflect.Descriptor, error) {
+	d, err := p.files.FindDescriptorByName(name)
+	if err != nil {
+		return nil, err
+	}
+	if isPrivateFile(d.ParentFile().Path()) {
+		return nil, protoregistry.NotFound
+	}
+	return d, nil
+}
+
+type reflectionService struct {
+	reflectionpb.ServerReflectionServer
+}
+
+func (r *reflectionService) AuthFuncOverride(ctx context.Context, _ string) (context.Context, error) {
+	return ctx, nil
+}
@@ -0,0 +1,125 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	grpc_auth "github.com/grpc-ecosystem/go-grpc-middleware/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+
+	"github.com/r4start/goph-keeper/internal/server/app"
+	"github.com/r4start/goph-keeper/internal/server/storage"
+	pb "github.com/r4start/goph-keeper/pkg/grpc/proto"
+)
+
+func TestHealthService(t *testing.T) {
+	var dbDown atomic.Bool
+	dbDown.Store(true)
+
+	storageService, err := NewStorageService(newTestStorage(t), 1024)
+	require.NoError(t, err)
+
+	var h *HealthService
+	reg := func(srv *grpc.Server) {
+		pb.RegisterInfoServer(srv, NewInfoService(storageService, 4096))
+		pb.RegisterAdminServer(srv, NewAdminService(app.NewGCWorker(&mockGarbageCollector{}, time.Hour),
+			app.NewQuotas(newTestStorage(t), storage.Quota{}), "admin-token"))
+		h = NewHealthService(ServiceNames(srv),
+			WithHealthCheck("db", func(context.Context) error {
+				if dbDown.Load() {
+					return errors.New("connection refused")
+				}
+				return nil
+			}))
+		h.Register(srv)
+		RegisterReflection(srv)
+	}
+
+	// Health and reflection are available without authorization.
+	userAuth := func(ctx context.Context) (context.Context, error) {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	srv, conn := prepareTestEnv(t, reg,
+		grpc.UnaryInterceptor(grpc_auth.UnaryServerInterceptor(userAuth)),
+		grpc.StreamInterceptor(grpc_auth.StreamServerInterceptor(userAuth)))
+	defer srv.Stop()
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	ctx := context.Background()
+	client := healthpb.NewHealthClient(conn)
+	checkStatus := func(service string) healthpb.HealthCheckResponse_ServingStatus {
+		resp, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+		require.NoError(t, err)
+		return resp.GetStatus()
+	}
+	infoService := pb.Info_ServiceDesc.ServiceName
+
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, checkStatus(""))
+
+	assert.ErrorContains(t, h.Update(ctx), "db: connection refused")
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, checkStatus(""))
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, checkStatus(infoService))
+
+	dbDown.Store(false)
+	assert.NoError(t, h.Update(ctx))
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, checkStatus(""))
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, checkStatus(infoService))
+
+	// Draining servers stay not serving.
+	h.Shutdown()
+	assert.NoError(t, h.Update(ctx))
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, checkStatus(""))
+
+	reflection, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	require.NoError(t, err)
+	require.NoError(t, reflection.Send(&reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+	}))
+	resp, err := reflection.Recv()
+	require.NoError(t, err)
+
+	services := make([]string, 0)
+	for _, s := range resp.GetListServicesResponse().GetService() {
+		services = append(services, s.GetName())
+	}
+	assert.Contains(t, services, infoService)
+	assert.Contains(t, services, healthpb.Health_ServiceDesc.ServiceName)
+	assert.NotContains(t, services, pb.Admin_ServiceDesc.ServiceName)
+
+	// The admin API isn't described even when asked by name.
+	for _, symbol := range []string{pb.Admin_ServiceDesc.ServiceName, "gophkeeper.QuotaRequest"} {
+		require.NoError(t, reflection.Send(&reflectionpb.ServerReflectionRequest{
+			MessageRequest: &reflectionpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: symbol},
+		}))
+		resp, err = reflection.Recv()
+		require.NoError(t, err)
+		assert.NotNil(t, resp.GetErrorResponse(), symbol)
+	}
+	require.NoError(t, reflection.Send(&reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: infoService},
+	}))
+	resp, err = reflection.Recv()
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.GetFileDescriptorResponse().GetFileDescriptorProto())
+	require.NoError(t, reflection.CloseSend())
+}
+
+func TestHealthService_Interval(t *testing.T) {
+	h := NewHealthService(nil, WithHealthCheckInterval(0))
+	assert.Equal(t, _defaultHealthCheckInterval, h.interval)
+
+	h = NewHealthService(nil, WithHealthCheckInterval(time.Minute))
+	assert.Equal(t, time.Minute, h.interval)
+}
@@ -0,0 +1,99 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/r4start/goph-keeper/internal/crypto"
+	"github.com/r4start/goph-keeper/internal/server/storage"
+	pb "github.com/r4start/goph-keeper/pkg/grpc/proto"
+)
+
+const (
+	// ProtocolVersion is bumped on every change clients have to know about.
+	ProtocolVersion = 1
+	// MinProtocolVersion is the oldest protocol version of supported clients.
+	MinProtocolVersion = 1
+)
+
+// Authorization methods a server supports. A password is exchanged for tokens
+// with AuthorizationService, the rest of calls are authorized with jwt tokens.
+const (
+	AuthMethodPassword = "password"
+	AuthMethodJWT      = "jwt"
+)
+
+// KDFAlgorithm is the algorithm clients derive master keys with.
+const KDFAlgorithm = "pbkdf2-sha3-512"
+
+// Optional features a server reports to clients.
+const (
+	FeatureResumableUploads = "resumable_uploads"
+	FeatureChunkedUploads   = "chunked_uploads"
+	FeatureRangedDownloads  = "ranged_downloads"
+	FeatureTrash            = "trash"
+	FeatureQuotas           = "quotas"
+	FeatureBatch            = "batch"
+	FeatureErrorDetails     = "error_details"
+)
+
+type InfoService struct {
+	pb.UnimplementedInfoServer
+	info *pb.ServerInfo
+}
+
+// NewInfoService describes a server serving s which accepts messages up to
+// maxMessageSize bytes.
+func NewInfoService(s *StorageService, maxMessageSize int) *InfoService {
+	var (
+		protocolVersion    uint32 = ProtocolVersion
+		minProtocolVersion uint32 = MinProtocolVersion
+		algorithm                 = KDFAlgorithm
+		iterations                = uint32(crypto.KeyRounds)
+		keySize                   = uint32(crypto.KeySize)
+		saltSize                  = uint32(crypto.SaltSize)
+		messageSize               = uint64(maxMessageSize)
+		sendChunkSize             = uint64(s.sendBufferSize)
+		maxChunkSize       uint64 = storage.MaxChunkSize
+		maxListPageSize           = uint32(s.maxListPageSize)
+		maxBatchSize       uint32 = _maxBatchSize
+	)
+
+	return &InfoService{
+		info: &pb.ServerInfo{
+			ProtocolVersion:    &protocolVersion,
+			MinProtocolVersion: &minProtocolVersion,
+			AuthMethods:        []string{AuthMethodPassword, AuthMethodJWT},
+			Kdf: &pb.KdfParameters{
+				Algorithm:  &algorithm,
+				Iterations: &iterations,
+				KeySize:    &keySize,
+				SaltSize:   &saltSize,
+			},
+			Limits: &pb.ServerLimits{
+				MaxMessageSize:  &messageSize,
+				SendChunkSize:   &sendChunkSize,
+				MaxChunkSize:    &maxChunkSize,
+				MaxListPageSize: &maxListPageSize,
+				MaxBatchSize:    &maxBatchSize,
+			},
+			Features: []string{
+				FeatureResumableUploads,
+				FeatureChunkedUploads,
+				FeatureRangedDownloads,
+				FeatureTrash,
+				FeatureQuotas,
+				FeatureBatch,
+				FeatureErrorDetails,
+			},
+		},
+	}
+}
+
+// AuthFuncOverride lets clients ask for server information before they authorize.
+func (i *InfoService) AuthFuncOverride(ctx context.Context, _ string) (context.Context, error) {
+	return ctx, nil
+}
+
+func (i *InfoService) ServerInfo(context.Context, *pb.ServerInfoRequest) (*pb.ServerInfo, error) {
+	return i.info, nil
+}
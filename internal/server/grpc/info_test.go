@@ -0,0 +1,52 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	grpc_auth "github.com/grpc-ecosystem/go-grpc-middleware/auth"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/r4start/goph-keeper/internal/crypto"
+	pb "github.com/r4start/goph-keeper/pkg/grpc/proto"
+)
+
+func TestInfoService_ServerInfo(t *testing.T) {
+	storageService, err := NewStorageService(newTestStorage(t), 1024, WithMaxListPageSize(50))
+	assert.NoError(t, err)
+	s := NewInfoService(storageService, 4096)
+
+	reg := func(srv *grpc.Server) {
+		pb.RegisterInfoServer(srv, s)
+	}
+
+	// Server information is available without authorization.
+	userAuth := func(ctx context.Context) (context.Context, error) {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	srv, conn := prepareTestEnv(t, reg, grpc.UnaryInterceptor(grpc_auth.UnaryServerInterceptor(userAuth)))
+	defer srv.Stop()
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	client := pb.NewInfoClient(conn)
+
+	version := uint32(ProtocolVersion)
+	info, err := client.ServerInfo(context.Background(), &pb.ServerInfoRequest{ProtocolVersion: &version})
+	assert.NoError(t, err)
+
+	assert.Equal(t, uint32(ProtocolVersion), info.GetProtocolVersion())
+	assert.Equal(t, uint32(MinProtocolVersion), info.GetMinProtocolVersion())
+	assert.Contains(t, info.GetAuthMethods(), AuthMethodPassword)
+	assert.Equal(t, KDFAlgorithm, info.GetKdf().GetAlgorithm())
+	assert.Equal(t, uint32(crypto.KeyRounds), info.GetKdf().GetIterations())
+	assert.Equal(t, uint64(4096), info.GetLimits().GetMaxMessageSize())
+	assert.Equal(t, uint64(1024), info.GetLimits().GetSendChunkSize())
+	assert.Equal(t, uint32(50), info.GetLimits().GetMaxListPageSize())
+	assert.Contains(t, info.GetFeatures(), FeatureBatch)
+}
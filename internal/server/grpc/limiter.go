@@ -1,22 +1,35 @@
 package grpc
 
 import (
+	"sync/atomic"
+
 	grpc_ratelimit "github.com/grpc-ecosystem/go-grpc-middleware/ratelimit"
 	"go.uber.org/ratelimit"
 )
 
-type limiter struct {
-	ratelimit.Limiter
+var _ grpc_ratelimit.Limiter = (*Limiter)(nil)
+
+// Limiter delays calls to keep a rate of calls per second. Calls are never
+// rejected.
+type Limiter struct {
+	limiter atomic.Pointer[ratelimit.Limiter]
+}
+
+// NewLimiter return new go-grpc Limiter, specified the number of requests you want to limit as a counts per second.
+func NewLimiter(count int) *Limiter {
+	l := &Limiter{}
+	l.SetLimit(count)
+	return l
 }
 
-func (l *limiter) Limit() bool {
-	l.Take()
+func (l *Limiter) Limit() bool {
+	(*l.limiter.Load()).Take()
 	return false
 }
 
-// NewLimiter return new go-grpc Limiter, specified the number of requests you want to limit as a counts per second.
-func NewLimiter(count int) grpc_ratelimit.Limiter {
-	return &limiter{
-		Limiter: ratelimit.New(count),
-	}
+// SetLimit changes the rate, calls waiting for the old rate are not
+// affected.
+func (l *Limiter) SetLimit(count int) {
+	rl := ratelimit.New(count)
+	l.limiter.Store(&rl)
 }
@@ -0,0 +1,135 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/r4start/goph-keeper/internal/logging"
+)
+
+type callInfoKey struct{}
+
+// callInfo collects fields of a call which are known only after the call
+// passed inner interceptors, e.g. an authorized user.
+type callInfo struct {
+	userID string
+}
+
+// LoggingUnaryInterceptor logs finished calls and puts a logger with a request
+// id into contexts of handlers. The id is taken from x-request-id metadata or
+// generated, and returned to a client in a header. Requests are logged at
+// debug level with secrets redacted.
+func LoggingUnaryInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, requestID, call := startCall(ctx, logger, info.FullMethod)
+		_ = grpc.SetHeader(ctx, metadata.Pairs(logging.RequestIDKey, requestID))
+
+		logRequest(ctx, req)
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		call.finish(ctx, start, err)
+		return resp, err
+	}
+}
+
+// LoggingStreamInterceptor is LoggingUnaryInterceptor for streams.
+func LoggingStreamInterceptor(logger *zap.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, requestID, call := startCall(ss.Context(), logger, info.FullMethod)
+		_ = ss.SetHeader(metadata.Pairs(logging.RequestIDKey, requestID))
+
+		start := time.Now()
+		err := handler(srv, &loggedStream{ServerStream: ss, ctx: ctx})
+		call.finish(ctx, start, err)
+		return err
+	}
+}
+
+func startCall(ctx context.Context, logger *zap.Logger, method string) (context.Context, string, *callInfo) {
+	requestID := ""
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ids := md.Get(logging.RequestIDKey); len(ids) != 0 && logging.ValidRequestID(ids[0]) {
+			requestID = ids[0]
+		}
+	}
+	if len(requestID) == 0 {
+		requestID = logging.NewRequestID()
+	}
+
+	fields := []zap.Field{
+		zap.String("request_id", requestID),
+		zap.String("method", method),
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		fields = append(fields, zap.String("peer", p.Addr.String()))
+	}
+
+	call := &callInfo{}
+	ctx = logging.WithLogger(ctx, logger.With(fields...))
+	return context.WithValue(ctx, callInfoKey{}, call), requestID, call
+}
+
+func (c *callInfo) finish(ctx context.Context, start time.Time, err error) {
+	code := status.Code(err)
+	fields := []zap.Field{
+		zap.String("code", code.String()),
+		zap.Duration("duration", time.Since(start)),
+	}
+	if len(c.userID) != 0 {
+		fields = append(fields, zap.String("user_id", c.userID))
+	}
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+	logging.FromContext(ctx).Log(callLevel(code), "finished call", fields...)
+}
+
+// callLevel logs server failures as errors, client mistakes are expected.
+func callLevel(code codes.Code) zapcore.Level {
+	switch code {
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable:
+		return zapcore.ErrorLevel
+	default:
+		return zapcore.InfoLevel
+	}
+}
+
+// setCallUser records an authorized user of a call.
+func setCallUser(ctx context.Context, userID string) {
+	if call, ok := ctx.Value(callInfoKey{}).(*callInfo); ok {
+		call.userID = userID
+	}
+}
+
+type loggedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *loggedStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *loggedStream) RecvMsg(msg interface{}) error {
+	if err := s.ServerStream.RecvMsg(msg); err != nil {
+		return err
+	}
+	logRequest(s.ctx, msg)
+	return nil
+}
+
+// logRequest logs a request message, the message is marshaled only when debug
+// logs are on.
+func logRequest(ctx context.Context, req interface{}) {
+	if ce := logging.FromContext(ctx).Check(zapcore.DebugLevel, "request"); ce != nil {
+		ce.Write(logging.Message("request", req))
+	}
+}
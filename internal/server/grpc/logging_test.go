@@ -0,0 +1,136 @@
+package grpc
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	grpc_auth "github.com/grpc-ecosystem/go-grpc-middleware/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/r4start/goph-keeper/internal/logging"
+	"github.com/r4start/goph-keeper/internal/server/app"
+	pb "github.com/r4start/goph-keeper/pkg/grpc/proto"
+)
+
+type tokenAuth struct {
+	mockAuth
+	userID string
+}
+
+func (a *tokenAuth) AuthorizeWithToken(_ context.Context, token string) (*app.AuthData, error) {
+	if token != "token" {
+		return nil, app.ErrInvalidCredentials
+	}
+	return &app.AuthData{ID: a.userID}, nil
+}
+
+func TestLoggingInterceptor(t *testing.T) {
+	wh := newTestStorage(t)
+	userID := wh.user.String()
+	s, err := NewStorageService(wh, 1024)
+	require.NoError(t, err)
+
+	reg := func(srv *grpc.Server) {
+		pb.RegisterStorageServer(srv, s)
+	}
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger := zap.New(core)
+	authFunc := BuildAuthorizationInterceptor(&tokenAuth{userID: userID})
+
+	srv, conn := prepareTestEnv(t, reg,
+		grpc.ChainStreamInterceptor(LoggingStreamInterceptor(logger), grpc_auth.StreamServerInterceptor(authFunc)),
+		grpc.ChainUnaryInterceptor(LoggingUnaryInterceptor(logger), grpc_auth.UnaryServerInterceptor(authFunc)))
+	defer srv.Stop()
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	client := pb.NewStorageClient(conn)
+	ctx := metadata.AppendToOutgoingContext(context.Background(),
+		"authorization", AuthScheme+" token",
+		logging.RequestIDKey, "test-request")
+
+	var header metadata.MD
+	_, err = client.Usage(ctx, &pb.UsageRequest{}, grpc.Header(&header))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"test-request"}, header.Get(logging.RequestIDKey))
+
+	finished := logs.FilterMessage("finished call").TakeAll()
+	require.Len(t, finished, 1)
+	fields := finished[0].ContextMap()
+	assert.Equal(t, zapcore.InfoLevel, finished[0].Level)
+	assert.Equal(t, "test-request", fields["request_id"])
+	assert.Equal(t, "/gophkeeper.Storage/Usage", fields["method"])
+	assert.Equal(t, userID, fields["user_id"])
+	assert.Equal(t, codes.OK.String(), fields["code"])
+	assert.Contains(t, fields, "peer")
+	assert.Contains(t, fields, "duration")
+
+	// A stream without an id gets a generated one.
+	stream, err := client.List(metadata.AppendToOutgoingContext(context.Background(), "authorization", AuthScheme+" token"),
+		&pb.ListRequest{})
+	require.NoError(t, err)
+	for {
+		if _, err := stream.Recv(); err != nil {
+			break
+		}
+	}
+	header, err = stream.Header()
+	require.NoError(t, err)
+	require.Len(t, header.Get(logging.RequestIDKey), 1)
+	assert.True(t, logging.ValidRequestID(header.Get(logging.RequestIDKey)[0]))
+
+	// Rejected tokens are never logged.
+	_, err = client.Usage(metadata.AppendToOutgoingContext(context.Background(), "authorization", AuthScheme+" stolen-secret"),
+		&pb.UsageRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	for _, e := range logs.All() {
+		for _, v := range e.ContextMap() {
+			if s, ok := v.(string); ok {
+				assert.NotContains(t, s, "stolen-secret")
+			}
+		}
+	}
+}
+
+func TestLoggingInterceptor_InternalError(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	s := NewAuthService(&mockAuth{users: new(sync.Map)}, time.Second)
+	reg := func(srv *grpc.Server) {
+		pb.RegisterAuthorizationServiceServer(srv, s)
+	}
+
+	srv, conn := prepareTestEnv(t, reg, grpc.ChainUnaryInterceptor(LoggingUnaryInterceptor(zap.New(core))))
+	defer srv.Stop()
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	client := pb.NewAuthorizationServiceClient(conn)
+	_, err := client.Authorize(context.Background(), &pb.AuthorizationRequest{
+		Login:    strPtr("user"),
+		Password: strPtr("password"),
+	})
+	assert.Equal(t, codes.Internal, status.Code(err))
+
+	internal := logs.FilterMessage("internal error").TakeAll()
+	require.Len(t, internal, 1)
+	assert.Contains(t, internal[0].ContextMap()["error"], "user doesn't exist")
+	assert.Contains(t, internal[0].ContextMap(), "request_id")
+
+	finished := logs.FilterMessage("finished call").TakeAll()
+	require.Len(t, finished, 1)
+	assert.Equal(t, zapcore.ErrorLevel, finished[0].Level)
+	assert.Equal(t, codes.Internal.String(), finished[0].ContextMap()["code"])
+}
@@ -0,0 +1,43 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/r4start/goph-keeper/internal/server/app"
+	"github.com/r4start/goph-keeper/internal/server/storage"
+	pb "github.com/r4start/goph-keeper/pkg/grpc/proto"
+)
+
+// WithQuotas enforces user quotas. Storage is unlimited without them.
+func WithQuotas(quotas *app.Quotas) StorageServiceOption {
+	return func(s *StorageService) {
+		if quotas != nil {
+			s.quotas = quotas
+		}
+	}
+}
+
+func (s *StorageService) Usage(ctx context.Context, _ *pb.UsageRequest) (*pb.UsageResponse, error) {
+	userID, err := authorizedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	usage, quota, err := s.quotas.Usage(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.UsageResponse{
+		Bytes:    &usage.Bytes,
+		Items:    &usage.Items,
+		MaxBytes: &quota.MaxBytes,
+		MaxItems: &quota.MaxItems,
+	}, nil
+}
+
+// checkQuota fails with app.ErrQuotaExceeded when a user can't store
+// bytes and items more.
+func (s *StorageService) checkQuota(ctx context.Context, user *storage.UserID, bytes, items uint64) error {
+	return s.quotas.Check(ctx, user, bytes, items)
+}
@@ -0,0 +1,140 @@
+package grpc
+
+import (
+	"context"
+	"crypto/sha256"
+	"testing"
+
+	grpc_auth "github.com/grpc-ecosystem/go-grpc-middleware/auth"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/r4start/goph-keeper/internal/server/app"
+	"github.com/r4start/goph-keeper/internal/server/storage"
+	pb "github.com/r4start/goph-keeper/pkg/grpc/proto"
+)
+
+func TestStorageService_Quota(t *testing.T) {
+	wh := newTestStorage(t)
+	quotas := app.NewQuotas(wh, storage.Quota{MaxBytes: 64 * 1024, MaxItems: 2})
+	s, err := NewStorageService(wh, 1024, WithQuotas(quotas))
+	assert.NoError(t, err)
+
+	reg := func(srv *grpc.Server) {
+		pb.RegisterStorageServer(srv, s)
+	}
+
+	authFunc := authGenerator(wh.user.String())
+
+	ctx := context.Background()
+	srv, conn := prepareTestEnv(t, reg,
+		grpc.StreamInterceptor(grpc_auth.StreamServerInterceptor(authFunc)),
+		grpc.UnaryInterceptor(grpc_auth.UnaryServerInterceptor(authFunc)))
+	defer srv.Stop()
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	client := pb.NewStorageClient(conn)
+
+	add := func(size uint64) error {
+		data, err := generateRandom(int(size))
+		assert.NoError(t, err)
+
+		streamC, err := client.Add(ctx)
+		assert.NoError(t, err)
+		err = streamC.Send(&pb.ResourceOperationData{
+			Data: &pb.ResourceOperationData_Meta{Meta: &pb.ResourceOperationData_ResourceMeta{
+				ResourceByteSize: &size,
+			}},
+		})
+		assert.NoError(t, err)
+		_ = streamC.Send(&pb.ResourceOperationData{
+			Data: &pb.ResourceOperationData_Chunk{
+				Chunk: &pb.ResourceOperationData_DataChunk{Data: data},
+			},
+		})
+		_, err = streamC.CloseAndRecv()
+		return err
+	}
+
+	assert.NoError(t, add(48*1024))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(add(32*1024)))
+	assert.NoError(t, add(16*1024))
+	// The item limit is reached while bytes are still available.
+	_, err = client.CreateUpload(ctx, &pb.CreateUploadRequest{
+		Meta: &pb.ResourceOperationData_ResourceMeta{ResourceByteSize: new(uint64)},
+	})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Len(t, wh.resources(t), 2)
+	assert.Equal(t, 2, wh.blobCount(t))
+
+	usage, err := client.Usage(ctx, &pb.UsageRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(64*1024), usage.GetBytes())
+	assert.Equal(t, uint64(2), usage.GetItems())
+	assert.Equal(t, uint64(64*1024), usage.GetMaxBytes())
+	assert.Equal(t, uint64(2), usage.GetMaxItems())
+
+	// An admin override lifts the limits.
+	assert.NoError(t, quotas.Override(ctx, wh.user, &storage.Quota{}))
+	assert.NoError(t, add(32*1024))
+}
+
+func TestStorageService_ChunkQuota(t *testing.T) {
+	wh := newTestStorage(t)
+	quotas := app.NewQuotas(wh, storage.Quota{MaxBytes: 32 * 1024, MaxItems: 2})
+	s, err := NewStorageService(wh, 1024, WithQuotas(quotas))
+	assert.NoError(t, err)
+
+	reg := func(srv *grpc.Server) {
+		pb.RegisterStorageServer(srv, s)
+	}
+
+	authFunc := authGenerator(wh.user.String())
+
+	ctx := context.Background()
+	srv, conn := prepareTestEnv(t, reg,
+		grpc.StreamInterceptor(grpc_auth.StreamServerInterceptor(authFunc)),
+		grpc.UnaryInterceptor(grpc_auth.UnaryServerInterceptor(authFunc)))
+	defer srv.Stop()
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	client := pb.NewStorageClient(conn)
+
+	putChunk := func() ([]byte, error) {
+		data, err := generateRandom(16 * 1024)
+		assert.NoError(t, err)
+		digest := sha256.Sum256(data)
+
+		putC, err := client.PutChunks(ctx)
+		assert.NoError(t, err)
+		_ = putC.Send(&pb.Chunk{Sha256: digest[:], Data: data})
+		_, err = putC.CloseAndRecv()
+		return digest[:], err
+	}
+
+	// Chunks no resource refers count against the quota.
+	first, err := putChunk()
+	assert.NoError(t, err)
+	_, err = putChunk()
+	assert.NoError(t, err)
+	_, err = putChunk()
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	usage, err := client.Usage(ctx, &pb.UsageRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(32*1024), usage.GetBytes())
+
+	// A resource made of stored chunks takes an item only.
+	size := uint64(16 * 1024)
+	_, err = client.AddChunked(ctx, &pb.AddChunkedRequest{
+		Meta:   &pb.ResourceOperationData_ResourceMeta{Salt: []byte("salt"), ResourceByteSize: &size},
+		Chunks: [][]byte{first},
+	})
+	assert.NoError(t, err)
+}
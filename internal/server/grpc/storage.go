@@ -2,29 +2,68 @@ package grpc
 
 import (
 	"context"
+	"crypto/sha256"
 	"io"
+	"time"
 
+	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/google/uuid"
+	"github.com/hashicorp/go-multierror"
+	"github.com/r4start/goph-keeper/internal/logging"
 	"github.com/r4start/goph-keeper/internal/server/app"
 	"github.com/r4start/goph-keeper/internal/server/storage"
 	pb "github.com/r4start/goph-keeper/pkg/grpc/proto"
 )
 
+const (
+	_defaultListPageSize = 100
+	_maxListPageSize     = 1000
+)
+
+type StorageServiceOption func(s *StorageService)
+
 type StorageService struct {
 	pb.UnimplementedStorageServer
 
-	wh             storage.Storage
-	sendBufferSize int
+	wh              storage.Storage
+	sendBufferSize  int
+	maxListPageSize int
+	uploadTTL       time.Duration
+	retention       time.Duration
+	quotas          *app.Quotas
+}
+
+func NewStorageService(wh storage.Storage, sendBufferSize int, opts ...StorageServiceOption) (*StorageService, error) {
+	s := &StorageService{
+		wh:              wh,
+		sendBufferSize:  sendBufferSize,
+		maxListPageSize: _maxListPageSize,
+		uploadTTL:       _defaultUploadTTL,
+		retention:       _defaultRetention,
+		quotas:          app.NewQuotas(wh, storage.Quota{}),
+	}
+
+	for _, o := range opts {
+		o(s)
+	}
+
+	return s, nil
 }
 
-func NewStorageService(wh storage.Storage, sendBufferSize int) (*StorageService, error) {
-	return &StorageService{wh: wh, sendBufferSize: sendBufferSize}, nil
+// WithMaxListPageSize limits the number of resources returned by a single List call.
+func WithMaxListPageSize(size int) StorageServiceOption {
+	return func(s *StorageService) {
+		if size > 0 {
+			s.maxListPageSize = size
+		}
+	}
 }
 
-func (s *StorageService) Add(stream pb.Storage_AddServer) error {
+func (s *StorageService) Add(stream pb.Storage_AddServer) (err error) {
 	var (
 		res          storage.Resource
 		expectedSize = uint64(0)
@@ -42,15 +81,29 @@ func (s *StorageService) Add(stream pb.Storage_AddServer) error {
 		return status.Error(codes.Unauthenticated, "bad user id")
 	}
 
+	// A resource is either committed by Close or discarded here on any failure,
+	// including a client disconnect.
+	defer func() {
+		if res == nil {
+			return
+		}
+		if e := res.Abort(); e != nil {
+			err = multierror.Append(err, e)
+		}
+	}()
+
 	for {
 		data, err := stream.Recv()
 		if err == io.EOF {
 			if res == nil {
 				return status.Error(codes.FailedPrecondition, "must start with meta information")
 			}
+
 			id := res.GetId().String()
-			if err := res.Close(); err != nil {
-				return err
+			closeErr := res.Close()
+			res = nil
+			if closeErr != nil {
+				return closeErr
 			}
 
 			return stream.SendAndClose(&pb.ResourceOperationResponse{
@@ -66,19 +119,30 @@ func (s *StorageService) Add(stream pb.Storage_AddServer) error {
 		}
 		switch v := data.GetData().(type) {
 		case *pb.ResourceOperationData_Meta:
-			if res, err = s.wh.Create(ctx, userID, v.Meta.Salt); err != nil {
+			if res != nil {
+				return status.Error(codes.InvalidArgument, "meta information has been already received")
+			}
+			meta, err := resourceMetaFromProto(v.Meta)
+			if err != nil {
 				return err
 			}
-			if v.Meta.ResourceByteSize == nil {
-				return status.Errorf(codes.InvalidArgument, "resource byte size must be specified")
+			if err := s.checkQuota(ctx, userID, meta.ByteSize, 1); err != nil {
+				return err
+			}
+			if res, err = s.wh.Create(ctx, userID, meta); err != nil {
+				return err
 			}
-			expectedSize = *v.Meta.ResourceByteSize
+			expectedSize = meta.ByteSize
 
 		case *pb.ResourceOperationData_Chunk:
 			if res == nil {
 				return status.Error(codes.FailedPrecondition, "must start with meta information")
 			}
 
+			if readBytes+uint64(len(v.Chunk.Data)) > expectedSize {
+				return status.Error(codes.OutOfRange, "data is larger than expected")
+			}
+
 			n, err := res.Write(v.Chunk.Data)
 			if err != nil {
 				return err
@@ -89,54 +153,147 @@ func (s *StorageService) Add(stream pb.Storage_AddServer) error {
 			}
 
 			readBytes += uint64(len(v.Chunk.Data))
-			if readBytes > expectedSize {
-				return status.Error(codes.OutOfRange, "data is larger than expected")
-			}
 		}
 	}
 }
 
-func (s *StorageService) List(_ *pb.ListRequest, stream pb.Storage_ListServer) error {
-	var (
-		ctx          = stream.Context()
-		userAuth, ok = ctx.Value(_userAuthKey).(*app.AuthData)
-	)
+// List streams every resource matching the filters of a request. It keeps
+// the message shape of clients which don't know pages, ListPage pages them.
+func (s *StorageService) List(req *pb.ListRequest, stream pb.Storage_ListServer) error {
+	ctx := stream.Context()
+	userID, err := authorizedUser(ctx)
+	if err != nil {
+		return err
+	}
 
-	if !ok || userAuth == nil {
-		return status.Error(codes.Unauthenticated, "auth token missed")
+	opts := s.listOptions(req)
+	opts.PageSize = s.maxListPageSize
+	opts.PageToken = ""
+	for {
+		resources, nextToken, err := s.wh.List(ctx, userID, opts)
+		if err != nil {
+			return err
+		}
+		for i := range resources {
+			if err := stream.Send(resourceInfoToProto(&resources[i])); err != nil {
+				return err
+			}
+		}
+		if len(nextToken) == 0 {
+			return nil
+		}
+		opts.PageToken = nextToken
 	}
+}
 
-	userID, err := storage.NewUserIDFromString(userAuth.ID)
+func (s *StorageService) ListPage(req *pb.ListRequest, stream pb.Storage_ListPageServer) error {
+	ctx := stream.Context()
+	userID, err := authorizedUser(ctx)
 	if err != nil {
-		return status.Error(codes.Unauthenticated, "bad user id")
+		return err
 	}
 
-	resources, err := s.wh.List(ctx, userID)
+	resources, nextToken, err := s.wh.List(ctx, userID, s.listOptions(req))
 	if err != nil {
 		return err
 	}
 
-	for _, r := range resources {
-		id := r.GetId().String()
-		err := stream.Send(&pb.Resource{
-			Id: &id,
+	return sendList(stream, resources, nextToken, resourceInfoToProto)
+}
+
+// sendList streams a page of resources followed by a token of the next page if any.
+func sendList(
+	stream interface{ Send(*pb.ListResponse) error },
+	resources []storage.ResourceInfo,
+	nextToken string,
+	toProto func(*storage.ResourceInfo) *pb.Resource,
+) error {
+	for i := range resources {
+		err := stream.Send(&pb.ListResponse{
+			Item: &pb.ListResponse_Resource{
+				Resource: toProto(&resources[i]),
+			},
 		})
 		if err != nil {
 			return err
 		}
 	}
 
-	return nil
+	if len(nextToken) == 0 {
+		return nil
+	}
+
+	return stream.Send(&pb.ListResponse{
+		Item: &pb.ListResponse_NextPageToken{
+			NextPageToken: nextToken,
+		},
+	})
 }
 
-func (s *StorageService) Get(res *pb.Resource, stream pb.Storage_GetServer) error {
+func (s *StorageService) listOptions(req *pb.ListRequest) *storage.ListOptions {
+	opts := &storage.ListOptions{
+		PageSize:       int(req.GetPageSize()),
+		PageToken:      req.GetPageToken(),
+		IncludeDeleted: req.GetIncludeDeleted(),
+		Kind:           req.Kind,
+	}
+
+	if opts.PageSize == 0 {
+		opts.PageSize = _defaultListPageSize
+	}
+	if opts.PageSize > s.maxListPageSize {
+		opts.PageSize = s.maxListPageSize
+	}
+
+	if req.GetOrderBy() == pb.ListOrder_LIST_ORDER_UPDATED {
+		opts.OrderBy = storage.ListOrderUpdated
+	}
+
+	if req.UpdatedAfter != nil {
+		ts := req.UpdatedAfter.AsTime()
+		opts.UpdatedAfter = &ts
+	}
+
+	return opts
+}
+
+func (s *StorageService) Stat(ctx context.Context, res *pb.Resource) (*pb.Resource, error) {
 	if res.Id == nil {
-		return status.Errorf(codes.InvalidArgument, "resource id is empty")
+		return nil, status.Errorf(codes.InvalidArgument, "resource id is empty")
 	}
 
 	id, err := uuid.Parse(*res.Id)
 	if err != nil {
-		return err
+		return nil, status.Errorf(codes.InvalidArgument, "bad resource id: %v", err)
+	}
+
+	userAuth, ok := ctx.Value(_userAuthKey).(*app.AuthData)
+	if !ok || userAuth == nil {
+		return nil, status.Error(codes.Unauthenticated, "auth token missed")
+	}
+
+	userID, err := storage.NewUserIDFromString(userAuth.ID)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "bad user id")
+	}
+
+	resID := storage.ResourceID(id)
+	info, err := s.wh.Stat(ctx, userID, &resID)
+	if err != nil {
+		return nil, err
+	}
+
+	return resourceInfoToProto(info), nil
+}
+
+func (s *StorageService) Get(req *pb.GetRequest, stream pb.Storage_GetServer) error {
+	if req.Id == nil {
+		return status.Errorf(codes.InvalidArgument, "resource id is empty")
+	}
+
+	id, err := uuid.Parse(*req.Id)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "bad resource id: %v", err)
 	}
 
 	ctx := stream.Context()
@@ -156,7 +313,9 @@ func (s *StorageService) Get(res *pb.Resource, stream pb.Storage_GetServer) erro
 		return err
 	}
 	defer func() {
-		_ = resource.Close()
+		if err := resource.Close(); err != nil {
+			logging.FromContext(ctx).Warn("failed to close resource", zap.Error(err))
+		}
 	}()
 
 	salt, err := resource.Salt()
@@ -164,10 +323,36 @@ func (s *StorageService) Get(res *pb.Resource, stream pb.Storage_GetServer) erro
 		return err
 	}
 
+	digest, err := resource.Digest()
+	if err != nil {
+		return err
+	}
+
+	size, err := resource.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+
+	offset := req.GetOffset()
+	if offset > uint64(size) {
+		return status.Errorf(codes.OutOfRange, "offset %d is beyond the resource size %d", offset, size)
+	}
+	if _, err := resource.Seek(int64(offset), io.SeekStart); err != nil {
+		return err
+	}
+
+	remaining := uint64(size) - offset
+	if req.GetLength() != 0 && req.GetLength() < remaining {
+		remaining = req.GetLength()
+	}
+
+	resourceSize := uint64(size)
 	err = stream.Send(&pb.ResourceOperationData{
 		Data: &pb.ResourceOperationData_Meta{
 			Meta: &pb.ResourceOperationData_ResourceMeta{
-				Salt: salt,
+				Salt:             salt,
+				ResourceByteSize: &resourceSize,
+				Sha256:           digest,
 			},
 		},
 	})
@@ -175,51 +360,36 @@ func (s *StorageService) Get(res *pb.Resource, stream pb.Storage_GetServer) erro
 		return err
 	}
 
-	readSize := uint64(0)
 	buffer := make([]byte, s.sendBufferSize)
-	for {
-		readBytes, err := resource.Read(buffer)
-		if err == io.EOF {
-			if readBytes != 0 {
-				readSize += uint64(readBytes)
-				err = stream.Send(&pb.ResourceOperationData{
-					Data: &pb.ResourceOperationData_Chunk{
-						Chunk: &pb.ResourceOperationData_DataChunk{
-							Data: buffer[:readBytes],
-						},
+	for remaining != 0 {
+		readSize := uint64(len(buffer))
+		if readSize > remaining {
+			readSize = remaining
+		}
+
+		readBytes, err := resource.Read(buffer[:readSize])
+		if readBytes != 0 {
+			remaining -= uint64(readBytes)
+			sendErr := stream.Send(&pb.ResourceOperationData{
+				Data: &pb.ResourceOperationData_Chunk{
+					Chunk: &pb.ResourceOperationData_DataChunk{
+						Data: buffer[:readBytes],
 					},
-				})
-				if err != nil {
-					return err
-				}
+				},
+			})
+			if sendErr != nil {
+				return sendErr
 			}
-			break
 		}
-		if err != nil {
-			return err
+		if err == io.EOF {
+			break
 		}
-
-		readSize += uint64(readBytes)
-		err = stream.Send(&pb.ResourceOperationData{
-			Data: &pb.ResourceOperationData_Chunk{
-				Chunk: &pb.ResourceOperationData_DataChunk{
-					Data: buffer[:readBytes],
-				},
-			},
-		})
 		if err != nil {
 			return err
 		}
 	}
 
-	err = stream.Send(&pb.ResourceOperationData{
-		Data: &pb.ResourceOperationData_Meta{
-			Meta: &pb.ResourceOperationData_ResourceMeta{
-				ResourceByteSize: &readSize,
-			},
-		},
-	})
-	return err
+	return nil
 }
 
 func (s *StorageService) Delete(ctx context.Context, res *pb.Resource) (*pb.ResourceOperationResponse, error) {
@@ -229,7 +399,7 @@ func (s *StorageService) Delete(ctx context.Context, res *pb.Resource) (*pb.Reso
 
 	id, err := uuid.Parse(*res.Id)
 	if err != nil {
-		return nil, err
+		return nil, status.Errorf(codes.InvalidArgument, "bad resource id: %v", err)
 	}
 
 	userAuth, ok := ctx.Value(_userAuthKey).(*app.AuthData)
@@ -253,3 +423,40 @@ func (s *StorageService) Delete(ctx context.Context, res *pb.Resource) (*pb.Reso
 		},
 	}, nil
 }
+
+func resourceInfoToProto(info *storage.ResourceInfo) *pb.Resource {
+	id := info.ID.String()
+	size := info.ByteSize
+	version := info.Version
+	isDeleted := info.IsDeleted
+	kind := info.Kind
+	return &pb.Resource{
+		Id:        &id,
+		IsDeleted: &isDeleted,
+		CreatedAt: timestamppb.New(info.CreatedAt),
+		UpdatedAt: timestamppb.New(info.UpdatedAt),
+		ByteSize:  &size,
+		Version:   &version,
+		Metadata:  info.Metadata,
+		Salt:      info.Salt,
+		Kind:      &kind,
+		Sha256:    info.Digest,
+	}
+}
+
+func resourceMetaFromProto(m *pb.ResourceOperationData_ResourceMeta) (*storage.ResourceMeta, error) {
+	if m == nil || m.ResourceByteSize == nil {
+		return nil, status.Error(codes.InvalidArgument, "resource byte size must be specified")
+	}
+	if len(m.Sha256) != 0 && len(m.Sha256) != sha256.Size {
+		return nil, status.Errorf(codes.InvalidArgument, "bad sha256 digest length %d", len(m.Sha256))
+	}
+
+	return &storage.ResourceMeta{
+		Salt:     m.Salt,
+		Metadata: m.Metadata,
+		Kind:     m.GetKind(),
+		ByteSize: *m.ResourceByteSize,
+		Digest:   m.Sha256,
+	}, nil
+}
@@ -3,15 +3,21 @@ package grpc
 import (
 	"context"
 	"crypto/rand"
-	"fmt"
+	"crypto/sha256"
+	"errors"
 	"io"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	grpc_auth "github.com/grpc-ecosystem/go-grpc-middleware/auth"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	pb "github.com/r4start/goph-keeper/pkg/grpc/proto"
 
@@ -27,122 +33,81 @@ func generateRandom(size int) ([]byte, error) {
 	return buf, nil
 }
 
-func authGenerator(id string) grpc_auth.AuthFunc {
-	return func(ctx context.Context) (context.Context, error) {
-		auth := &app.AuthData{
-			ID: id,
-		}
-		return context.WithValue(ctx, _userAuthKey, auth), nil
+func authData(id string) *app.AuthData {
+	return &app.AuthData{
+		ID: id,
 	}
 }
 
-type mockWhStorage struct {
-	Resources map[uuid.UUID]*mockResource
-}
-
-func newMockWhStorage() *mockWhStorage {
-	return &mockWhStorage{
-		Resources: make(map[uuid.UUID]*mockResource),
-	}
-}
-
-func (m *mockWhStorage) Create(ctx context.Context, _ *storage.UserID, salt []byte) (storage.Resource, error) {
-	resID, err := uuid.NewRandom()
-	if err != nil {
-		return nil, err
-	}
-	res := &mockResource{
-		ID:         resID,
-		Buffer:     make([]byte, 0),
-		ReadOffset: 0,
-		SaltData:   salt,
+func authGenerator(id string) grpc_auth.AuthFunc {
+	return func(ctx context.Context) (context.Context, error) {
+		return context.WithValue(ctx, _userAuthKey, authData(id)), nil
 	}
-
-	m.Resources[resID] = res
-
-	return res, nil
 }
 
-func (m *mockWhStorage) Open(ctx context.Context, user *storage.UserID, id *storage.ResourceID) (storage.Resource, error) {
-	res, ok := m.Resources[uuid.UUID(*id)]
-	if !ok {
-		return nil, fmt.Errorf("not found")
-	}
-	return res, nil
+// testStorage keeps resources of a registered user in memory, tests check
+// them through the storage API.
+type testStorage struct {
+	storage.Storage
+	blobs storage.BlobStore
+	user  *storage.UserID
 }
 
-func (m *mockWhStorage) Delete(ctx context.Context, user *storage.UserID, id *storage.ResourceID) error {
-	if _, ok := m.Resources[uuid.UUID(*id)]; !ok {
-		return fmt.Errorf("not found")
-	}
-	delete(m.Resources, uuid.UUID(*id))
-	return nil
-}
+func newTestStorage(t *testing.T) *testStorage {
+	users := storage.NewMemoryUserService()
+	user, err := users.Add(context.Background(), uuid.NewString(), []byte("key salt"), []byte("salt"), []byte("secret"))
+	require.NoError(t, err)
 
-func (m *mockWhStorage) List(ctx context.Context, user *storage.UserID) ([]storage.Resource, error) {
-	result := make([]storage.Resource, 0, len(m.Resources))
-	for _, v := range m.Resources {
-		result = append(result, v)
+	blobs := storage.NewMemoryBlobStore()
+	return &testStorage{
+		Storage: storage.NewBlobStorage(users, blobs),
+		blobs:   blobs,
+		user:    user,
 	}
-	return result, nil
 }
 
-type mockResource struct {
-	ID         uuid.UUID
-	Buffer     []byte
-	ReadOffset int
-	SaltData   []byte
+// resources lists stored resources of the user.
+func (s *testStorage) resources(t *testing.T) []storage.ResourceInfo {
+	resources, _, err := s.List(context.Background(), s.user, &storage.ListOptions{PageSize: 1000})
+	require.NoError(t, err)
+	return resources
 }
 
-func (mr *mockResource) Close() error {
-	mr.ReadOffset = 0
-	return nil
+// blobCount returns a number of stored blobs, so tests see blobs leaked by
+// aborted resources and uploads.
+func (s *testStorage) blobCount(t *testing.T) int {
+	count := 0
+	require.NoError(t, s.blobs.Walk(context.Background(), func(storage.BlobID, time.Time) error {
+		count++
+		return nil
+	}))
+	return count
 }
 
-func (mr *mockResource) Write(p []byte) (n int, err error) {
-	mr.Buffer = append(mr.Buffer, p...)
-	return len(p), nil
+// failingStorage fails resource calls with err.
+type failingStorage struct {
+	storage.Storage
+	err error
 }
 
-func (mr *mockResource) Read(p []byte) (n int, err error) {
-	bufSize := len(mr.Buffer)
-	if mr.ReadOffset == bufSize {
-		return 0, io.EOF
-	}
-
-	bufLen := len(p)
-	if mr.ReadOffset+bufLen > bufSize {
-		bufLen = len(mr.Buffer) - mr.ReadOffset
-	}
-	copy(p, mr.Buffer[mr.ReadOffset:mr.ReadOffset+bufLen])
-	mr.ReadOffset += bufLen
-	return bufLen, nil
+func (f *failingStorage) Create(context.Context, *storage.UserID, *storage.ResourceMeta) (storage.Resource, error) {
+	return nil, f.err
 }
 
-func (mr *mockResource) GetId() *storage.ResourceID {
-	uid := storage.ResourceID(mr.ID)
-	return &uid
-}
-
-func (mr *mockResource) IsDeleted() bool {
-	return false
-}
-
-func (mr *mockResource) Salt() ([]byte, error) {
-	return mr.SaltData, nil
+func (f *failingStorage) Stat(context.Context, *storage.UserID, *storage.ResourceID) (*storage.ResourceInfo, error) {
+	return nil, f.err
 }
 
 func TestStorageService_Add(t *testing.T) {
-	userID, err := uuid.NewRandom()
-	assert.NoError(t, err)
-	s, err := NewStorageService(newMockWhStorage(), 1024)
+	wh := newTestStorage(t)
+	s, err := NewStorageService(wh, 1024)
 	assert.NoError(t, err)
 
 	reg := func(srv *grpc.Server) {
 		pb.RegisterStorageServer(srv, s)
 	}
 
-	authFunc := authGenerator(userID.String())
+	authFunc := authGenerator(wh.user.String())
 
 	ctx := context.Background()
 	srv, conn := prepareTestEnv(t, reg,
@@ -195,16 +160,15 @@ func TestStorageService_Add(t *testing.T) {
 }
 
 func TestStorageService_Add_WrongStart(t *testing.T) {
-	userID, err := uuid.NewRandom()
-	assert.NoError(t, err)
-	s, err := NewStorageService(newMockWhStorage(), 1024)
+	wh := newTestStorage(t)
+	s, err := NewStorageService(wh, 1024)
 	assert.NoError(t, err)
 
 	reg := func(srv *grpc.Server) {
 		pb.RegisterStorageServer(srv, s)
 	}
 
-	authFunc := authGenerator(userID.String())
+	authFunc := authGenerator(wh.user.String())
 
 	ctx := context.Background()
 	srv, conn := prepareTestEnv(t, reg,
@@ -237,16 +201,15 @@ func TestStorageService_Add_WrongStart(t *testing.T) {
 }
 
 func TestStorageService_List(t *testing.T) {
-	userID, err := uuid.NewRandom()
-	assert.NoError(t, err)
-	s, err := NewStorageService(newMockWhStorage(), 1024)
+	wh := newTestStorage(t)
+	s, err := NewStorageService(wh, 1024)
 	assert.NoError(t, err)
 
 	reg := func(srv *grpc.Server) {
 		pb.RegisterStorageServer(srv, s)
 	}
 
-	authFunc := authGenerator(userID.String())
+	authFunc := authGenerator(wh.user.String())
 
 	ctx := context.Background()
 	srv, conn := prepareTestEnv(t, reg,
@@ -310,7 +273,7 @@ func TestStorageService_List(t *testing.T) {
 			break
 		}
 		assert.NoError(t, err)
-		remoteResources = append(remoteResources, *rr.Id)
+		remoteResources = append(remoteResources, rr.GetId())
 	}
 
 	sort.Strings(ids)
@@ -319,18 +282,15 @@ func TestStorageService_List(t *testing.T) {
 }
 
 func TestStorageService_Get(t *testing.T) {
-	storage := newMockWhStorage()
-
-	userID, err := uuid.NewRandom()
-	assert.NoError(t, err)
-	s, err := NewStorageService(storage, 1024)
+	wh := newTestStorage(t)
+	s, err := NewStorageService(wh, 1024)
 	assert.NoError(t, err)
 
 	reg := func(srv *grpc.Server) {
 		pb.RegisterStorageServer(srv, s)
 	}
 
-	authFunc := authGenerator(userID.String())
+	authFunc := authGenerator(wh.user.String())
 
 	ctx := context.Background()
 	srv, conn := prepareTestEnv(t, reg,
@@ -381,7 +341,7 @@ func TestStorageService_Get(t *testing.T) {
 	assert.NotNil(t, m.GetResource().Id)
 	assert.NotZero(t, len(*m.GetResource().Id))
 
-	getC, err := client.Get(ctx, &pb.Resource{
+	getC, err := client.Get(ctx, &pb.GetRequest{
 		Id: m.GetResource().Id,
 	})
 	assert.NoError(t, err)
@@ -429,11 +389,8 @@ func TestStorageService_Get(t *testing.T) {
 	rndRes, err := uuid.NewRandom()
 	assert.NoError(t, err)
 
-	_, ok := storage.Resources[rndRes]
-	assert.False(t, ok)
-
 	testRes := rndRes.String()
-	getC, err = client.Get(ctx, &pb.Resource{
+	getC, err = client.Get(ctx, &pb.GetRequest{
 		Id: &testRes,
 	})
 	assert.NoError(t, err)
@@ -442,17 +399,15 @@ func TestStorageService_Get(t *testing.T) {
 }
 
 func TestStorageService_Delete(t *testing.T) {
-	storage := newMockWhStorage()
-	userID, err := uuid.NewRandom()
-	assert.NoError(t, err)
-	s, err := NewStorageService(storage, 1024)
+	wh := newTestStorage(t)
+	s, err := NewStorageService(wh, 1024)
 	assert.NoError(t, err)
 
 	reg := func(srv *grpc.Server) {
 		pb.RegisterStorageServer(srv, s)
 	}
 
-	authFunc := authGenerator(userID.String())
+	authFunc := authGenerator(wh.user.String())
 
 	ctx := context.Background()
 	srv, conn := prepareTestEnv(t, reg,
@@ -507,7 +462,7 @@ func TestStorageService_Delete(t *testing.T) {
 		ids[i] = *m.GetResource().Id
 	}
 
-	assert.Equal(t, len(ids), len(storage.Resources))
+	assert.Len(t, wh.resources(t), len(ids))
 
 	for i := 0; i < len(ids); i++ {
 		_, err := client.Delete(ctx, &pb.Resource{
@@ -516,10 +471,11 @@ func TestStorageService_Delete(t *testing.T) {
 		assert.NoError(t, err)
 		id, err := uuid.Parse(ids[i])
 		assert.NoError(t, err)
-		_, ok := storage.Resources[id]
-		assert.False(t, ok)
+		resID := storage.ResourceID(id)
+		_, err = wh.Stat(ctx, wh.user, &resID)
+		assert.ErrorIs(t, err, storage.ErrResourceNotFound)
 	}
-	assert.Zero(t, len(storage.Resources))
+	assert.Empty(t, wh.resources(t))
 
 	rndRes, err := uuid.NewRandom()
 	assert.NoError(t, err)
@@ -529,3 +485,574 @@ func TestStorageService_Delete(t *testing.T) {
 	})
 	assert.Error(t, err)
 }
+
+func TestStorageService_Stat(t *testing.T) {
+	wh := newTestStorage(t)
+	s, err := NewStorageService(wh, 1024)
+	assert.NoError(t, err)
+
+	reg := func(srv *grpc.Server) {
+		pb.RegisterStorageServer(srv, s)
+	}
+
+	authFunc := authGenerator(wh.user.String())
+
+	ctx := context.Background()
+	srv, conn := prepareTestEnv(t, reg,
+		grpc.StreamInterceptor(grpc_auth.StreamServerInterceptor(authFunc)),
+		grpc.UnaryInterceptor(grpc_auth.UnaryServerInterceptor(authFunc)))
+	defer srv.Stop()
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	client := pb.NewStorageClient(conn)
+
+	streamC, err := client.Add(ctx)
+	assert.NoError(t, err)
+
+	salt, err := generateRandom(64)
+	assert.NoError(t, err)
+
+	metadata, err := generateRandom(128)
+	assert.NoError(t, err)
+
+	data, err := generateRandom(64 * 1024)
+	assert.NoError(t, err)
+
+	size := uint64(len(data))
+	err = streamC.Send(&pb.ResourceOperationData{
+		Data: &pb.ResourceOperationData_Meta{Meta: &pb.ResourceOperationData_ResourceMeta{
+			Salt:             salt,
+			ResourceByteSize: &size,
+			Metadata:         metadata,
+		}},
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, streamC.Send(&pb.ResourceOperationData{
+		Data: &pb.ResourceOperationData_Chunk{
+			Chunk: &pb.ResourceOperationData_DataChunk{
+				Data: data,
+			},
+		},
+	}))
+
+	m, err := streamC.CloseAndRecv()
+	assert.NoError(t, err)
+	assert.NotNil(t, m.GetResource().Id)
+
+	info, err := client.Stat(ctx, &pb.Resource{
+		Id: m.GetResource().Id,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, *m.GetResource().Id, info.GetId())
+	assert.Equal(t, size, info.GetByteSize())
+	assert.Equal(t, uint64(1), info.GetVersion())
+	assert.Equal(t, metadata, info.GetMetadata())
+	assert.Equal(t, salt, info.GetSalt())
+	assert.NotNil(t, info.GetCreatedAt())
+	assert.NotNil(t, info.GetUpdatedAt())
+	assert.False(t, info.GetIsDeleted())
+
+	listC, err := client.List(ctx, &pb.ListRequest{})
+	assert.NoError(t, err)
+	listed, err := listC.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, metadata, listed.GetMetadata())
+	assert.Equal(t, size, listed.GetByteSize())
+
+	rndRes, err := uuid.NewRandom()
+	assert.NoError(t, err)
+	rndStr := rndRes.String()
+	_, err = client.Stat(ctx, &pb.Resource{
+		Id: &rndStr,
+	})
+	assert.Error(t, err)
+}
+
+func TestStorageService_ListPages(t *testing.T) {
+	wh := newTestStorage(t)
+	s, err := NewStorageService(wh, 1024, WithMaxListPageSize(5))
+	assert.NoError(t, err)
+
+	reg := func(srv *grpc.Server) {
+		pb.RegisterStorageServer(srv, s)
+	}
+
+	authFunc := authGenerator(wh.user.String())
+
+	ctx := context.Background()
+	srv, conn := prepareTestEnv(t, reg,
+		grpc.StreamInterceptor(grpc_auth.StreamServerInterceptor(authFunc)),
+		grpc.UnaryInterceptor(grpc_auth.UnaryServerInterceptor(authFunc)))
+	defer srv.Stop()
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	client := pb.NewStorageClient(conn)
+
+	ids := make([]string, 12)
+	for i := 0; i < len(ids); i++ {
+		streamC, err := client.Add(ctx)
+		assert.NoError(t, err)
+
+		salt, err := generateRandom(64)
+		assert.NoError(t, err)
+
+		data, err := generateRandom(1024)
+		assert.NoError(t, err)
+
+		kind := "card"
+		if i%3 == 0 {
+			kind = "file"
+		}
+
+		size := uint64(len(data))
+		assert.NoError(t, streamC.Send(&pb.ResourceOperationData{
+			Data: &pb.ResourceOperationData_Meta{Meta: &pb.ResourceOperationData_ResourceMeta{
+				Salt:             salt,
+				ResourceByteSize: &size,
+				Kind:             &kind,
+			}},
+		}))
+		assert.NoError(t, streamC.Send(&pb.ResourceOperationData{
+			Data: &pb.ResourceOperationData_Chunk{
+				Chunk: &pb.ResourceOperationData_DataChunk{
+					Data: data,
+				},
+			},
+		}))
+
+		m, err := streamC.CloseAndRecv()
+		assert.NoError(t, err)
+		ids[i] = m.GetResource().GetId()
+	}
+
+	listPage := func(req *pb.ListRequest) ([]string, string) {
+		listC, err := client.ListPage(ctx, req)
+		assert.NoError(t, err)
+
+		var (
+			page      = make([]string, 0)
+			nextToken string
+		)
+		for {
+			rr, err := listC.Recv()
+			if err == io.EOF {
+				break
+			}
+			assert.NoError(t, err)
+			if r := rr.GetResource(); r != nil {
+				page = append(page, r.GetId())
+			} else {
+				nextToken = rr.GetNextPageToken()
+			}
+		}
+		return page, nextToken
+	}
+
+	pageSize := uint32(100)
+	listed := make([]string, 0, len(ids))
+	req := &pb.ListRequest{PageSize: &pageSize}
+	pages := 0
+	for {
+		page, nextToken := listPage(req)
+		assert.LessOrEqual(t, len(page), 5)
+		listed = append(listed, page...)
+		pages++
+		if len(nextToken) == 0 {
+			break
+		}
+		req.PageToken = &nextToken
+	}
+	assert.Equal(t, 3, pages)
+	assert.Equal(t, ids, listed)
+
+	kind := "file"
+	page, nextToken := listPage(&pb.ListRequest{Kind: &kind})
+	assert.Empty(t, nextToken)
+	assert.Equal(t, []string{ids[0], ids[3], ids[6], ids[9]}, page)
+
+	listErr := func(req *pb.ListRequest) error {
+		listC, err := client.ListPage(ctx, req)
+		require.NoError(t, err)
+		for {
+			if _, err := listC.Recv(); err != nil {
+				if err == io.EOF {
+					return nil
+				}
+				return err
+			}
+		}
+	}
+
+	badToken := "bad token"
+	assert.Equal(t, codes.InvalidArgument, status.Code(listErr(&pb.ListRequest{PageToken: &badToken})))
+
+	// A page token is valid with the order and filters of its page only.
+	kind = "card"
+	page, nextToken = listPage(&pb.ListRequest{Kind: &kind})
+	assert.Len(t, page, 5)
+	require.NotEmpty(t, nextToken)
+
+	otherKind := "file"
+	updated := pb.ListOrder_LIST_ORDER_UPDATED
+	includeDeleted := true
+	for _, req := range []*pb.ListRequest{
+		{PageToken: &nextToken},
+		{PageToken: &nextToken, Kind: &otherKind},
+		{PageToken: &nextToken, Kind: &kind, OrderBy: &updated},
+		{PageToken: &nextToken, Kind: &kind, IncludeDeleted: &includeDeleted},
+		{PageToken: &nextToken, Kind: &kind, UpdatedAfter: timestamppb.New(time.Unix(1, 0))},
+	} {
+		assert.Equal(t, codes.InvalidArgument, status.Code(listErr(req)))
+	}
+	page, nextToken = listPage(&pb.ListRequest{Kind: &kind, PageToken: &nextToken})
+	assert.Len(t, page, 3)
+	assert.Empty(t, nextToken)
+
+	// List streams all resources regardless of the page size.
+	listC, err := client.List(ctx, &pb.ListRequest{PageSize: &pageSize})
+	require.NoError(t, err)
+	listed = listed[:0]
+	for {
+		r, err := listC.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		listed = append(listed, r.GetId())
+	}
+	assert.Equal(t, ids, listed)
+}
+
+func TestStorageService_GetRange(t *testing.T) {
+	wh := newTestStorage(t)
+	s, err := NewStorageService(wh, 1024)
+	assert.NoError(t, err)
+
+	reg := func(srv *grpc.Server) {
+		pb.RegisterStorageServer(srv, s)
+	}
+
+	authFunc := authGenerator(wh.user.String())
+
+	ctx := context.Background()
+	srv, conn := prepareTestEnv(t, reg,
+		grpc.StreamInterceptor(grpc_auth.StreamServerInterceptor(authFunc)),
+		grpc.UnaryInterceptor(grpc_auth.UnaryServerInterceptor(authFunc)))
+	defer srv.Stop()
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	client := pb.NewStorageClient(conn)
+
+	salt, err := generateRandom(64)
+	assert.NoError(t, err)
+
+	data, err := generateRandom(100 * 1024)
+	assert.NoError(t, err)
+
+	res, err := wh.Create(ctx, wh.user, &storage.ResourceMeta{Salt: salt, ByteSize: uint64(len(data))})
+	assert.NoError(t, err)
+	_, err = res.Write(data)
+	assert.NoError(t, err)
+	assert.NoError(t, res.Close())
+
+	resID := res.GetId().String()
+	size := uint64(len(data))
+
+	tests := []struct {
+		name   string
+		offset uint64
+		length uint64
+		want   []byte
+	}{
+		{name: "whole resource", want: data},
+		{name: "tail", offset: 70000, want: data[70000:]},
+		{name: "range", offset: 1000, length: 5000, want: data[1000:6000]},
+		{name: "length beyond the end", offset: size - 10, length: 100, want: data[size-10:]},
+		{name: "offset at the end", offset: size, want: []byte{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			offset, length := tt.offset, tt.length
+			getC, err := client.Get(ctx, &pb.GetRequest{
+				Id:     &resID,
+				Offset: &offset,
+				Length: &length,
+			})
+			assert.NoError(t, err)
+
+			first, err := getC.Recv()
+			assert.NoError(t, err)
+			assert.Equal(t, salt, first.GetMeta().GetSalt())
+			assert.Equal(t, size, first.GetMeta().GetResourceByteSize())
+
+			remoteData := make([]byte, 0, len(tt.want))
+			for {
+				chunk, err := getC.Recv()
+				if err == io.EOF {
+					break
+				}
+				assert.NoError(t, err)
+				assert.Nil(t, chunk.GetMeta())
+				remoteData = append(remoteData, chunk.GetChunk().GetData()...)
+			}
+			assert.Equal(t, tt.want, remoteData)
+		})
+	}
+
+	offset := size + 1
+	getC, err := client.Get(ctx, &pb.GetRequest{
+		Id:     &resID,
+		Offset: &offset,
+	})
+	assert.NoError(t, err)
+	_, err = getC.Recv()
+	assert.Equal(t, codes.OutOfRange, status.Code(err))
+}
+
+func TestStorageService_AddVerification(t *testing.T) {
+	wh := newTestStorage(t)
+	s, err := NewStorageService(wh, 1024)
+	assert.NoError(t, err)
+
+	reg := func(srv *grpc.Server) {
+		pb.RegisterStorageServer(srv, s)
+	}
+
+	authFunc := authGenerator(wh.user.String())
+
+	ctx := context.Background()
+	srv, conn := prepareTestEnv(t, reg,
+		grpc.StreamInterceptor(grpc_auth.StreamServerInterceptor(authFunc)),
+		grpc.UnaryInterceptor(grpc_auth.UnaryServerInterceptor(authFunc)))
+	defer srv.Stop()
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	client := pb.NewStorageClient(conn)
+
+	salt, err := generateRandom(64)
+	assert.NoError(t, err)
+
+	data, err := generateRandom(64 * 1024)
+	assert.NoError(t, err)
+	digest := sha256.Sum256(data)
+
+	badDigest := digest
+	badDigest[0] ^= 0xff
+
+	tests := []struct {
+		name   string
+		size   uint64
+		digest []byte
+		data   []byte
+		code   codes.Code
+	}{
+		{name: "valid", size: uint64(len(data)), digest: digest[:], data: data, code: codes.OK},
+		{name: "no digest", size: uint64(len(data)), data: data, code: codes.OK},
+		{name: "truncated", size: uint64(len(data)), digest: digest[:], data: data[:len(data)/2], code: codes.InvalidArgument},
+		{name: "corrupted", size: uint64(len(data)), digest: badDigest[:], data: data, code: codes.DataLoss},
+		{name: "bad digest length", size: uint64(len(data)), digest: digest[:10], data: data, code: codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := len(wh.resources(t))
+
+			streamC, err := client.Add(ctx)
+			assert.NoError(t, err)
+
+			size := tt.size
+			err = streamC.Send(&pb.ResourceOperationData{
+				Data: &pb.ResourceOperationData_Meta{Meta: &pb.ResourceOperationData_ResourceMeta{
+					Salt:             salt,
+					ResourceByteSize: &size,
+					Sha256:           tt.digest,
+				}},
+			})
+			assert.NoError(t, err)
+
+			_ = streamC.Send(&pb.ResourceOperationData{
+				Data: &pb.ResourceOperationData_Chunk{
+					Chunk: &pb.ResourceOperationData_DataChunk{Data: tt.data},
+				},
+			})
+
+			m, err := streamC.CloseAndRecv()
+			assert.Equal(t, tt.code, status.Code(err))
+			if tt.code != codes.OK {
+				assert.Len(t, wh.resources(t), before)
+				assert.Equal(t, before, wh.blobCount(t))
+				return
+			}
+
+			stat, err := client.Stat(ctx, &pb.Resource{Id: m.GetResource().Id})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.digest, stat.GetSha256())
+
+			getC, err := client.Get(ctx, &pb.GetRequest{Id: m.GetResource().Id})
+			assert.NoError(t, err)
+			first, err := getC.Recv()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.digest, first.GetMeta().GetSha256())
+		})
+	}
+}
+
+// mockAddStream replays messages to the Add handler and then fails with err
+// the way a broken client connection does.
+type mockAddStream struct {
+	grpc.ServerStream
+
+	ctx      context.Context
+	messages []*pb.ResourceOperationData
+	err      error
+	response *pb.ResourceOperationResponse
+}
+
+func (m *mockAddStream) Context() context.Context {
+	return m.ctx
+}
+
+func (m *mockAddStream) Recv() (*pb.ResourceOperationData, error) {
+	if len(m.messages) == 0 {
+		return nil, m.err
+	}
+	msg := m.messages[0]
+	m.messages = m.messages[1:]
+	return msg, nil
+}
+
+func (m *mockAddStream) SendAndClose(r *pb.ResourceOperationResponse) error {
+	m.response = r
+	return nil
+}
+
+func TestStorageService_AddAbort(t *testing.T) {
+	ctx := context.Background()
+
+	data, err := generateRandom(4 * 1024)
+	assert.NoError(t, err)
+	digest := sha256.Sum256(data)
+
+	metaMsg := func(size uint64) *pb.ResourceOperationData {
+		return &pb.ResourceOperationData{
+			Data: &pb.ResourceOperationData_Meta{Meta: &pb.ResourceOperationData_ResourceMeta{
+				ResourceByteSize: &size,
+				Sha256:           digest[:],
+			}},
+		}
+	}
+	chunkMsg := func(d []byte) *pb.ResourceOperationData {
+		return &pb.ResourceOperationData{
+			Data: &pb.ResourceOperationData_Chunk{
+				Chunk: &pb.ResourceOperationData_DataChunk{Data: d},
+			},
+		}
+	}
+	size := uint64(len(data))
+	disconnect := status.Error(codes.Canceled, "context canceled")
+
+	tests := []struct {
+		name     string
+		messages []*pb.ResourceOperationData
+		err      error
+		stored   int
+	}{
+		{
+			name:     "committed",
+			messages: []*pb.ResourceOperationData{metaMsg(size), chunkMsg(data)},
+			err:      io.EOF,
+			stored:   1,
+		},
+		{
+			name:     "disconnect after meta",
+			messages: []*pb.ResourceOperationData{metaMsg(size)},
+			err:      disconnect,
+		},
+		{
+			name:     "disconnect in the middle",
+			messages: []*pb.ResourceOperationData{metaMsg(size), chunkMsg(data[:1024])},
+			err:      disconnect,
+		},
+		{
+			name:     "oversized data",
+			messages: []*pb.ResourceOperationData{metaMsg(size - 1), chunkMsg(data)},
+			err:      io.EOF,
+		},
+		{
+			name:     "truncated data",
+			messages: []*pb.ResourceOperationData{metaMsg(size), chunkMsg(data[:1024])},
+			err:      io.EOF,
+		},
+		{
+			name:     "duplicated meta",
+			messages: []*pb.ResourceOperationData{metaMsg(size), metaMsg(size), chunkMsg(data)},
+			err:      io.EOF,
+		},
+		{
+			name:     "bad meta",
+			messages: []*pb.ResourceOperationData{{Data: &pb.ResourceOperationData_Meta{}}},
+			err:      io.EOF,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wh := newTestStorage(t)
+			s, err := NewStorageService(wh, 1024)
+			assert.NoError(t, err)
+
+			stream := &mockAddStream{
+				ctx:      context.WithValue(ctx, _userAuthKey, authData(wh.user.String())),
+				messages: tt.messages,
+				err:      tt.err,
+			}
+			err = s.Add(stream)
+			if tt.stored == 0 {
+				assert.Error(t, err)
+				assert.Nil(t, stream.response)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, stream.response)
+			}
+
+			assert.Len(t, wh.resources(t), tt.stored)
+			assert.Equal(t, tt.stored, wh.blobCount(t))
+		})
+	}
+}
+
+func TestStorageService_StorageError(t *testing.T) {
+	wh := newTestStorage(t)
+	s, err := NewStorageService(&failingStorage{Storage: wh, err: errors.New("connection reset")}, 1024)
+	assert.NoError(t, err)
+	ctx := context.WithValue(context.Background(), _userAuthKey, authData(wh.user.String()))
+
+	// Failures of a storage are internal, their details aren't sent.
+	id := uuid.NewString()
+	_, err = s.Stat(ctx, &pb.Resource{Id: &id})
+	st := status.Convert(statusError(err))
+	assert.Equal(t, codes.Internal, st.Code())
+	assert.NotContains(t, st.Message(), "connection reset")
+
+	size := uint64(16)
+	stream := &mockAddStream{
+		ctx: ctx,
+		messages: []*pb.ResourceOperationData{{
+			Data: &pb.ResourceOperationData_Meta{Meta: &pb.ResourceOperationData_ResourceMeta{ResourceByteSize: &size}},
+		}},
+		err: io.EOF,
+	}
+	assert.Equal(t, codes.Internal, status.Code(statusError(s.Add(stream))))
+	assert.Nil(t, stream.response)
+	assert.Zero(t, wh.blobCount(t))
+}
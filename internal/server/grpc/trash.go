@@ -0,0 +1,95 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/r4start/goph-keeper/internal/server/storage"
+	pb "github.com/r4start/goph-keeper/pkg/grpc/proto"
+)
+
+const _defaultRetention = 30 * 24 * time.Hour
+
+// WithRetention sets a period during which a deleted resource can be restored.
+// It should match the retention period of garbage collection.
+func WithRetention(retention time.Duration) StorageServiceOption {
+	return func(s *StorageService) {
+		if retention > 0 {
+			s.retention = retention
+		}
+	}
+}
+
+func (s *StorageService) ListDeleted(req *pb.ListRequest, stream pb.Storage_ListDeletedServer) error {
+	ctx := stream.Context()
+	userID, err := authorizedUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	opts := s.listOptions(req)
+	opts.DeletedOnly = true
+	if cutoff := s.restorableSince(); opts.UpdatedAfter == nil || opts.UpdatedAfter.Before(cutoff) {
+		opts.UpdatedAfter = &cutoff
+	}
+
+	resources, nextToken, err := s.wh.List(ctx, userID, opts)
+	if err != nil {
+		return err
+	}
+
+	return sendList(stream, resources, nextToken, func(info *storage.ResourceInfo) *pb.Resource {
+		res := resourceInfoToProto(info)
+		res.PurgeAt = timestamppb.New(info.UpdatedAt.Add(s.retention))
+		return res
+	})
+}
+
+func (s *StorageService) Restore(ctx context.Context, r *pb.Resource) (*pb.Resource, error) {
+	userID, err := authorizedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.Id == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "resource id is empty")
+	}
+
+	id, err := uuid.Parse(*r.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "bad resource id: %v", err)
+	}
+
+	resID := storage.ResourceID(id)
+	info, err := s.wh.Restore(ctx, userID, &resID, s.restorableSince())
+	if err != nil {
+		return nil, err
+	}
+
+	return resourceInfoToProto(info), nil
+}
+
+func (s *StorageService) EmptyTrash(ctx context.Context, _ *pb.EmptyTrashRequest) (*pb.EmptyTrashResponse, error) {
+	userID, err := authorizedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	purged, err := s.wh.EmptyTrash(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	count := uint64(purged)
+	return &pb.EmptyTrashResponse{Purged: &count}, nil
+}
+
+// restorableSince returns the earliest deletion time of a resource which can still be restored.
+func (s *StorageService) restorableSince() time.Time {
+	return time.Now().UTC().Add(-s.retention)
+}
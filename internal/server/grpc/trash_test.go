@@ -0,0 +1,102 @@
+package grpc
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	grpc_auth "github.com/grpc-ecosystem/go-grpc-middleware/auth"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/r4start/goph-keeper/internal/server/storage"
+	pb "github.com/r4start/goph-keeper/pkg/grpc/proto"
+)
+
+func TestStorageService_Trash(t *testing.T) {
+	// Resources are purged soon, so the test doesn't wait long for one.
+	retention := 500 * time.Millisecond
+	wh := newTestStorage(t)
+	s, err := NewStorageService(wh, 1024, WithRetention(retention))
+	assert.NoError(t, err)
+
+	reg := func(srv *grpc.Server) {
+		pb.RegisterStorageServer(srv, s)
+	}
+
+	authFunc := authGenerator(wh.user.String())
+
+	ctx := context.Background()
+	srv, conn := prepareTestEnv(t, reg,
+		grpc.StreamInterceptor(grpc_auth.StreamServerInterceptor(authFunc)),
+		grpc.UnaryInterceptor(grpc_auth.UnaryServerInterceptor(authFunc)))
+	defer srv.Stop()
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	client := pb.NewStorageClient(conn)
+
+	ids := make([]string, 3)
+	for i := range ids {
+		res, err := wh.Create(ctx, wh.user, &storage.ResourceMeta{})
+		assert.NoError(t, err)
+		assert.NoError(t, res.Close())
+		ids[i] = res.GetId().String()
+	}
+
+	// The last resource is deleted before the retention period.
+	for _, i := range []int{2, 0, 1} {
+		_, err = client.Delete(ctx, &pb.Resource{Id: &ids[i]})
+		assert.NoError(t, err)
+		if i == 2 {
+			time.Sleep(retention + 100*time.Millisecond)
+		}
+	}
+
+	listDeleted := func() map[string]*pb.Resource {
+		listC, err := client.ListDeleted(ctx, &pb.ListRequest{})
+		assert.NoError(t, err)
+
+		result := make(map[string]*pb.Resource)
+		for {
+			m, err := listC.Recv()
+			if err == io.EOF {
+				return result
+			}
+			assert.NoError(t, err)
+			result[m.GetResource().GetId()] = m.GetResource()
+		}
+	}
+
+	deleted := listDeleted()
+	assert.Len(t, deleted, 2)
+	assert.True(t, deleted[ids[0]].GetIsDeleted())
+	assert.WithinDuration(t, time.Now().Add(retention), deleted[ids[0]].GetPurgeAt().AsTime(), retention)
+
+	restored, err := client.Restore(ctx, &pb.Resource{Id: &ids[0]})
+	assert.NoError(t, err)
+	assert.Equal(t, ids[0], restored.GetId())
+	assert.False(t, restored.GetIsDeleted())
+
+	info, err := client.Stat(ctx, &pb.Resource{Id: &ids[0]})
+	assert.NoError(t, err)
+	assert.Equal(t, ids[0], info.GetId())
+
+	for _, id := range []string{ids[0], ids[2]} {
+		_, err = client.Restore(ctx, &pb.Resource{Id: &id})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	}
+
+	bad := "not an id"
+	_, err = client.Restore(ctx, &pb.Resource{Id: &bad})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	empty, err := client.EmptyTrash(ctx, &pb.EmptyTrashRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), empty.GetPurged())
+	assert.Empty(t, listDeleted())
+}
@@ -0,0 +1,171 @@
+package grpc
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/r4start/goph-keeper/internal/server/app"
+	"github.com/r4start/goph-keeper/internal/server/storage"
+	pb "github.com/r4start/goph-keeper/pkg/grpc/proto"
+)
+
+const _defaultUploadTTL = 24 * time.Hour
+
+// WithUploadTTL sets a period after which an idle upload session expires.
+func WithUploadTTL(ttl time.Duration) StorageServiceOption {
+	return func(s *StorageService) {
+		if ttl > 0 {
+			s.uploadTTL = ttl
+		}
+	}
+}
+
+func (s *StorageService) CreateUpload(ctx context.Context, r *pb.CreateUploadRequest) (*pb.UploadSession, error) {
+	userID, err := authorizedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	meta, err := resourceMetaFromProto(r.Meta)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkQuota(ctx, userID, meta.ByteSize, 1); err != nil {
+		return nil, err
+	}
+
+	session, err := s.wh.CreateUpload(ctx, userID, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.uploadSessionToProto(session), nil
+}
+
+func (s *StorageService) GetUpload(ctx context.Context, r *pb.UploadSessionRequest) (*pb.UploadSession, error) {
+	userID, err := authorizedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	uploadID, err := parseUploadID(r.UploadId)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.wh.GetUpload(ctx, userID, uploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.uploadSessionToProto(session), nil
+}
+
+func (s *StorageService) AppendUpload(stream pb.Storage_AppendUploadServer) error {
+	ctx := stream.Context()
+	userID, err := authorizedUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	var (
+		uploadID *storage.UploadID
+		session  *storage.UploadSession
+	)
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			if session == nil {
+				return status.Error(codes.FailedPrecondition, "no data received")
+			}
+			return stream.SendAndClose(s.uploadSessionToProto(session))
+		}
+		if err != nil {
+			return err
+		}
+
+		if uploadID == nil {
+			if uploadID, err = parseUploadID(chunk.UploadId); err != nil {
+				return err
+			}
+		} else if chunk.UploadId != nil && *chunk.UploadId != uploadID.String() {
+			return status.Error(codes.InvalidArgument, "upload id must be the same for all chunks")
+		}
+
+		session, err = s.wh.AppendUpload(ctx, userID, uploadID, chunk.GetOffset(), chunk.Data)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (s *StorageService) FinalizeUpload(ctx context.Context, r *pb.UploadSessionRequest) (*pb.ResourceOperationResponse, error) {
+	userID, err := authorizedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	uploadID, err := parseUploadID(r.UploadId)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := s.wh.FinalizeUpload(ctx, userID, uploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ResourceOperationResponse{
+		Result: &pb.ResourceOperationResponse_Resource{
+			Resource: resourceInfoToProto(info),
+		},
+	}, nil
+}
+
+// ExpireUploads removes upload sessions which have been idle longer than the upload TTL.
+func (s *StorageService) ExpireUploads(ctx context.Context) (int, error) {
+	return s.wh.ExpireUploads(ctx, time.Now().UTC().Add(-s.uploadTTL))
+}
+
+func (s *StorageService) uploadSessionToProto(session *storage.UploadSession) *pb.UploadSession {
+	id := session.ID.String()
+	offset := session.Offset
+	size := session.ByteSize
+	return &pb.UploadSession{
+		UploadId:         &id,
+		CommittedOffset:  &offset,
+		ResourceByteSize: &size,
+		ExpiresAt:        timestamppb.New(session.UpdatedAt.Add(s.uploadTTL)),
+	}
+}
+
+func parseUploadID(id *string) (*storage.UploadID, error) {
+	if id == nil {
+		return nil, status.Error(codes.InvalidArgument, "upload id is empty")
+	}
+
+	uploadID, err := storage.NewUploadIDFromString(*id)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "bad upload id")
+	}
+	return uploadID, nil
+}
+
+func authorizedUser(ctx context.Context) (*storage.UserID, error) {
+	userAuth, ok := ctx.Value(_userAuthKey).(*app.AuthData)
+	if !ok || userAuth == nil {
+		return nil, status.Error(codes.Unauthenticated, "auth token missed")
+	}
+
+	userID, err := storage.NewUserIDFromString(userAuth.ID)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "bad user id")
+	}
+	return userID, nil
+}
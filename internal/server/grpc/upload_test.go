@@ -0,0 +1,184 @@
+package grpc
+
+import (
+	"context"
+	"crypto/sha256"
+	"io"
+	"testing"
+	"time"
+
+	grpc_auth "github.com/grpc-ecosystem/go-grpc-middleware/auth"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/r4start/goph-keeper/internal/server/storage"
+	pb "github.com/r4start/goph-keeper/pkg/grpc/proto"
+)
+
+func TestStorageService_ResumableUpload(t *testing.T) {
+	wh := newTestStorage(t)
+	s, err := NewStorageService(wh, 1024, WithUploadTTL(time.Hour))
+	assert.NoError(t, err)
+
+	reg := func(srv *grpc.Server) {
+		pb.RegisterStorageServer(srv, s)
+	}
+
+	authFunc := authGenerator(wh.user.String())
+
+	ctx := context.Background()
+	srv, conn := prepareTestEnv(t, reg,
+		grpc.StreamInterceptor(grpc_auth.StreamServerInterceptor(authFunc)),
+		grpc.UnaryInterceptor(grpc_auth.UnaryServerInterceptor(authFunc)))
+	defer srv.Stop()
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	client := pb.NewStorageClient(conn)
+
+	salt, err := generateRandom(64)
+	assert.NoError(t, err)
+
+	data, err := generateRandom(256 * 1024)
+	assert.NoError(t, err)
+
+	size := uint64(len(data))
+	digest := sha256.Sum256(data)
+	session, err := client.CreateUpload(ctx, &pb.CreateUploadRequest{
+		Meta: &pb.ResourceOperationData_ResourceMeta{
+			Salt:             salt,
+			ResourceByteSize: &size,
+			Sha256:           digest[:],
+		},
+	})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, session.GetUploadId())
+	assert.Zero(t, session.GetCommittedOffset())
+	assert.NotNil(t, session.GetExpiresAt())
+
+	appendChunks := func(from, to uint64) (*pb.UploadSession, error) {
+		appendC, err := client.AppendUpload(ctx)
+		assert.NoError(t, err)
+
+		chunkSize := uint64(16 * 1024)
+		for offset := from; offset < to; offset += chunkSize {
+			end := offset + chunkSize
+			if end > to {
+				end = to
+			}
+			o := offset
+			err := appendC.Send(&pb.UploadChunk{
+				UploadId: session.UploadId,
+				Offset:   &o,
+				Data:     data[offset:end],
+			})
+			if err != nil {
+				break
+			}
+		}
+		return appendC.CloseAndRecv()
+	}
+
+	// The first connection is interrupted in the middle of the upload.
+	half := size / 2
+	partial, err := appendChunks(0, half)
+	assert.NoError(t, err)
+	assert.Equal(t, half, partial.GetCommittedOffset())
+
+	_, err = client.FinalizeUpload(ctx, &pb.UploadSessionRequest{UploadId: session.UploadId})
+	assert.Error(t, err)
+
+	// Sending data from a wrong offset is rejected.
+	_, err = appendChunks(0, half)
+	assert.Error(t, err)
+
+	current, err := client.GetUpload(ctx, &pb.UploadSessionRequest{UploadId: session.UploadId})
+	assert.NoError(t, err)
+	assert.Equal(t, half, current.GetCommittedOffset())
+
+	completed, err := appendChunks(current.GetCommittedOffset(), size)
+	assert.NoError(t, err)
+	assert.Equal(t, size, completed.GetCommittedOffset())
+
+	m, err := client.FinalizeUpload(ctx, &pb.UploadSessionRequest{UploadId: session.UploadId})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, m.GetResource().GetId())
+	assert.Equal(t, size, m.GetResource().GetByteSize())
+	assert.Equal(t, digest[:], m.GetResource().GetSha256())
+	uploadID, err := parseUploadID(session.UploadId)
+	assert.NoError(t, err)
+	_, err = wh.GetUpload(ctx, wh.user, uploadID)
+	assert.ErrorIs(t, err, storage.ErrUploadNotFound)
+
+	getC, err := client.Get(ctx, &pb.GetRequest{Id: m.GetResource().Id})
+	assert.NoError(t, err)
+
+	remoteData := make([]byte, 0, len(data))
+	for {
+		chunk, err := getC.Recv()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		remoteData = append(remoteData, chunk.GetChunk().GetData()...)
+	}
+	assert.Equal(t, data, remoteData)
+}
+
+func TestStorageService_FinalizeCorruptedUpload(t *testing.T) {
+	wh := newTestStorage(t)
+	s, err := NewStorageService(wh, 1024)
+	assert.NoError(t, err)
+	ctx := context.WithValue(context.Background(), _userAuthKey, authData(wh.user.String()))
+
+	data, err := generateRandom(1024)
+	assert.NoError(t, err)
+	digest := sha256.Sum256(data)
+	data[0] ^= 0xff
+
+	size := uint64(len(data))
+	session, err := s.CreateUpload(ctx, &pb.CreateUploadRequest{
+		Meta: &pb.ResourceOperationData_ResourceMeta{
+			ResourceByteSize: &size,
+			Sha256:           digest[:],
+		},
+	})
+	assert.NoError(t, err)
+
+	uploadID, err := parseUploadID(session.UploadId)
+	assert.NoError(t, err)
+	_, err = wh.AppendUpload(ctx, wh.user, uploadID, 0, data)
+	assert.NoError(t, err)
+
+	_, err = s.FinalizeUpload(ctx, &pb.UploadSessionRequest{UploadId: session.UploadId})
+	assert.Equal(t, codes.DataLoss, status.Code(statusError(err)))
+}
+
+func TestStorageService_ExpireUploads(t *testing.T) {
+	// Uploads expire soon, so the test doesn't wait long for one.
+	ttl := 200 * time.Millisecond
+	wh := newTestStorage(t)
+	s, err := NewStorageService(wh, 1024, WithUploadTTL(ttl))
+	assert.NoError(t, err)
+	ctx := context.WithValue(context.Background(), _userAuthKey, authData(wh.user.String()))
+
+	size := uint64(1024)
+	_, err = s.CreateUpload(ctx, &pb.CreateUploadRequest{
+		Meta: &pb.ResourceOperationData_ResourceMeta{ResourceByteSize: &size},
+	})
+	assert.NoError(t, err)
+
+	expired, err := s.ExpireUploads(ctx)
+	assert.NoError(t, err)
+	assert.Zero(t, expired)
+
+	time.Sleep(ttl + 100*time.Millisecond)
+	expired, err = s.ExpireUploads(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, expired)
+	assert.Empty(t, wh.resources(t))
+	assert.Zero(t, wh.blobCount(t))
+}
@@ -0,0 +1,176 @@
+// Package keyring keeps key encryption keys which wrap data keys of stored
+// blobs. Keys are read from a local file, so they never reach a database.
+package keyring
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+// KeySize is a size of a key encryption key, keys are AES-256 keys.
+const KeySize = 32
+
+var (
+	ErrUnknownKey = errors.New("unknown key encryption key")
+	ErrKeyInUse   = errors.New("key encryption key is in use")
+)
+
+// KeysInUse returns ids of keys which wrap stored data keys.
+type KeysInUse func() ([]string, error)
+
+type Option func(k *Keyring)
+
+// WithKeysInUse rejects key files which lack keys reported by inUse, so data
+// keys wrapped by a key removed too early don't become unreadable.
+func WithKeysInUse(inUse KeysInUse) Option {
+	return func(k *Keyring) {
+		k.inUse = inUse
+	}
+}
+
+// Keyring is a set of key encryption keys loaded from a file which is replaced
+// on Reload. Every non-empty line of the file, except for # comments, is a key
+// id and a base64 encoded key separated by spaces. The first key is primary,
+// it wraps new data keys. Other keys only unwrap data keys wrapped before,
+// so a key is rotated by adding a new key on top and removing the old one once
+// data keys are re-wrapped.
+type Keyring struct {
+	path  string
+	inUse KeysInUse
+	keys  atomic.Pointer[keySet]
+}
+
+type keySet struct {
+	primary string
+	aeads   map[string]cipher.AEAD
+}
+
+func Load(path string, opts ...Option) (*Keyring, error) {
+	k := &Keyring{path: path}
+	for _, o := range opts {
+		o(k)
+	}
+	if err := k.Reload(); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// Reload reads the key file again. An invalid file or a file which lacks keys
+// in use is not applied, the current keys stay in use.
+func (k *Keyring) Reload() error {
+	data, err := os.ReadFile(k.path)
+	if err != nil {
+		return err
+	}
+	keys, err := parseKeys(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", k.path, err)
+	}
+	if k.inUse != nil {
+		ids, err := k.inUse()
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			if _, ok := keys.aeads[id]; !ok {
+				return fmt.Errorf("%s: %w: %s wraps data keys", k.path, ErrKeyInUse, id)
+			}
+		}
+	}
+	k.keys.Store(keys)
+	return nil
+}
+
+// Files returns a path of the key file.
+func (k *Keyring) Files() []string {
+	return []string{k.path}
+}
+
+// PrimaryID returns an id of the key which wraps new data keys.
+func (k *Keyring) PrimaryID() string {
+	return k.keys.Load().primary
+}
+
+// Wrap encrypts a data key with the primary key. Additional data binds the
+// wrapped key to its owner, it must be the same on Unwrap.
+func (k *Keyring) Wrap(key, additionalData []byte) (string, []byte, error) {
+	keys := k.keys.Load()
+	aead := keys.aeads[keys.primary]
+
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(key)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", nil, err
+	}
+	return keys.primary, aead.Seal(nonce, nonce, key, additionalData), nil
+}
+
+// Unwrap decrypts a data key wrapped by the key with id.
+func (k *Keyring) Unwrap(id string, wrapped, additionalData []byte) ([]byte, error) {
+	aead, ok := k.keys.Load().aeads[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, id)
+	}
+	if len(wrapped) < aead.NonceSize() {
+		return nil, errors.New("wrapped key is too short")
+	}
+	nonce, sealed := wrapped[:aead.NonceSize()], wrapped[aead.NonceSize():]
+	return aead.Open(nil, nonce, sealed, additionalData)
+}
+
+func parseKeys(data []byte) (*keySet, error) {
+	keys := &keySet{aeads: make(map[string]cipher.AEAD)}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if len(text) == 0 || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: want a key id and a key", line)
+		}
+		id := fields[0]
+		if _, ok := keys.aeads[id]; ok {
+			return nil, fmt.Errorf("line %d: duplicate key id %s", line, id)
+		}
+		key, err := base64.StdEncoding.DecodeString(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if len(key) != KeySize {
+			return nil, fmt.Errorf("line %d: key %s is %d bytes, want %d", line, id, len(key), KeySize)
+		}
+
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		keys.aeads[id] = aead
+		if len(keys.primary) == 0 {
+			keys.primary = id
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(keys.primary) == 0 {
+		return nil, errors.New("no keys")
+	}
+	return keys, nil
+}
@@ -0,0 +1,106 @@
+package keyring
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testKey(b byte) string {
+	return base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{b}, KeySize))
+}
+
+func TestKeyring_Rotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kek")
+	require.NoError(t, os.WriteFile(path, []byte("# keys\nold "+testKey(1)+"\n"), 0600))
+
+	k, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, "old", k.PrimaryID())
+	assert.Equal(t, []string{path}, k.Files())
+
+	dataKey := []byte("data key")
+	id, wrapped, err := k.Wrap(dataKey, []byte("blob"))
+	require.NoError(t, err)
+	assert.Equal(t, "old", id)
+	assert.NotContains(t, string(wrapped), string(dataKey))
+
+	// A new key wraps new data keys, the old one still unwraps.
+	require.NoError(t, os.WriteFile(path, []byte("new "+testKey(2)+"\n\nold "+testKey(1)+"\n"), 0600))
+	require.NoError(t, k.Reload())
+	assert.Equal(t, "new", k.PrimaryID())
+
+	unwrapped, err := k.Unwrap(id, wrapped, []byte("blob"))
+	require.NoError(t, err)
+	assert.Equal(t, dataKey, unwrapped)
+
+	// A wrapped key belongs to its blob only.
+	_, err = k.Unwrap(id, wrapped, []byte("other blob"))
+	assert.Error(t, err)
+
+	// An invalid file is rejected and the keys are kept.
+	require.NoError(t, os.WriteFile(path, []byte("new short\n"), 0600))
+	assert.Error(t, k.Reload())
+	assert.Equal(t, "new", k.PrimaryID())
+
+	// Data keys of a removed key can't be unwrapped.
+	require.NoError(t, os.WriteFile(path, []byte("new "+testKey(2)+"\n"), 0600))
+	require.NoError(t, k.Reload())
+	_, err = k.Unwrap(id, wrapped, []byte("blob"))
+	assert.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func TestParseKeys(t *testing.T) {
+	for name, data := range map[string]string{
+		"empty":     "# no keys\n",
+		"no key":    "primary\n",
+		"extra":     "primary " + testKey(1) + " extra\n",
+		"base64":    "primary !!!\n",
+		"size":      "primary " + base64.StdEncoding.EncodeToString([]byte("short")) + "\n",
+		"duplicate": "primary " + testKey(1) + "\nprimary " + testKey(2) + "\n",
+	} {
+		_, err := parseKeys([]byte(data))
+		assert.Error(t, err, name)
+	}
+
+	keys, err := parseKeys([]byte("  first " + testKey(1) + "  \nsecond\t" + testKey(2)))
+	require.NoError(t, err)
+	assert.Equal(t, "first", keys.primary)
+	assert.Len(t, keys.aeads, 2)
+}
+
+func TestKeyring_KeysInUse(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kek")
+	require.NoError(t, os.WriteFile(path, []byte("new "+testKey(2)+"\nold "+testKey(1)+"\n"), 0600))
+
+	inUse := []string{"old"}
+	k, err := Load(path, WithKeysInUse(func() ([]string, error) {
+		return inUse, nil
+	}))
+	require.NoError(t, err)
+
+	// A key which wraps data keys can't be removed.
+	require.NoError(t, os.WriteFile(path, []byte("new "+testKey(2)+"\n"), 0600))
+	assert.ErrorIs(t, k.Reload(), ErrKeyInUse)
+	id, wrapped, err := k.Wrap([]byte("data key"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "new", id)
+	_, err = k.Unwrap("old", wrapped, nil)
+	assert.NotErrorIs(t, err, ErrUnknownKey)
+
+	inUse = []string{"new"}
+	require.NoError(t, k.Reload())
+	_, err = k.Unwrap("old", wrapped, nil)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+
+	// A file can't be loaded without keys in use either.
+	_, err = Load(path, WithKeysInUse(func() ([]string, error) {
+		return []string{"old"}, nil
+	}))
+	assert.ErrorIs(t, err, ErrKeyInUse)
+}
@@ -0,0 +1,125 @@
+// Package listener parses listener specs of the server, e.g.
+// tcp://0.0.0.0:8090, tcp://127.0.0.1:8091?tls=false or
+// unix:///run/gophkeeper.sock, and opens them.
+package listener
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Networks of listeners.
+const (
+	NetworkTCP  = "tcp"
+	NetworkUnix = "unix"
+)
+
+const _staleSocketDialTimeout = time.Second
+
+// Spec describes a listener.
+type Spec struct {
+	Network string
+	// Address is host:port for TCP and a socket path for Unix sockets.
+	Address string
+	// TLS tells whether connections are encrypted.
+	TLS bool
+}
+
+// Parse parses a spec. TCP listeners use TLS when defaultTLS is set, Unix
+// sockets are plaintext by default. A tls query parameter overrides the
+// default.
+func Parse(spec string, defaultTLS bool) (*Spec, error) {
+	u, err := url.Parse(spec)
+	if err != nil {
+		return nil, fmt.Errorf("bad listener %q: %w", spec, err)
+	}
+
+	s := &Spec{Network: u.Scheme}
+	switch u.Scheme {
+	case NetworkTCP:
+		if len(u.Host) == 0 || len(u.Port()) == 0 {
+			return nil, fmt.Errorf("bad listener %q: host:port is expected", spec)
+		}
+		s.Address = u.Host
+		s.TLS = defaultTLS
+	case NetworkUnix:
+		s.Address = u.Path
+		if len(u.Host) != 0 {
+			// unix://relative/path
+			s.Address = u.Host + u.Path
+		}
+		if len(s.Address) == 0 {
+			return nil, fmt.Errorf("bad listener %q: socket path is expected", spec)
+		}
+	default:
+		return nil, fmt.Errorf("bad listener %q: unsupported network %q", spec, u.Scheme)
+	}
+
+	if v := u.Query().Get("tls"); len(v) != 0 {
+		s.TLS, err = strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("bad listener %q: tls must be true or false", spec)
+		}
+	}
+	return s, nil
+}
+
+// ParseList parses comma separated specs.
+func ParseList(specs string, defaultTLS bool) ([]*Spec, error) {
+	result := make([]*Spec, 0)
+	for _, spec := range strings.Split(specs, ",") {
+		spec = strings.TrimSpace(spec)
+		if len(spec) == 0 {
+			continue
+		}
+
+		s, err := Parse(spec, defaultTLS)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, s)
+	}
+	return result, nil
+}
+
+func (s *Spec) String() string {
+	return s.Network + "://" + s.Address
+}
+
+// Listen opens the listener. A socket file left by a crashed server is
+// removed, a socket of a running server is not.
+func (s *Spec) Listen() (net.Listener, error) {
+	if s.Network == NetworkUnix {
+		if err := removeStaleSocket(s.Address); err != nil {
+			return nil, err
+		}
+	}
+	return net.Listen(s.Network, s.Address)
+}
+
+func removeStaleSocket(path string) error {
+	info, err := os.Lstat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.Mode()&fs.ModeSocket == 0 {
+		return fmt.Errorf("%s exists and is not a socket", path)
+	}
+
+	conn, err := net.DialTimeout(NetworkUnix, path, _staleSocketDialTimeout)
+	if err == nil {
+		_ = conn.Close()
+		return fmt.Errorf("%s is used by another server", path)
+	}
+	return os.Remove(path)
+}
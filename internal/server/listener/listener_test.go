@@ -0,0 +1,80 @@
+package listener
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		spec       string
+		defaultTLS bool
+		want       *Spec
+	}{
+		{"tcp://0.0.0.0:8090", true, &Spec{Network: NetworkTCP, Address: "0.0.0.0:8090", TLS: true}},
+		{"tcp://127.0.0.1:8091?tls=false", true, &Spec{Network: NetworkTCP, Address: "127.0.0.1:8091"}},
+		{"tcp://[::1]:8090", false, &Spec{Network: NetworkTCP, Address: "[::1]:8090"}},
+		{"unix:///run/gophkeeper.sock", true, &Spec{Network: NetworkUnix, Address: "/run/gophkeeper.sock"}},
+		{"unix://gophkeeper.sock?tls=true", false, &Spec{Network: NetworkUnix, Address: "gophkeeper.sock", TLS: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := Parse(tt.spec, tt.defaultTLS)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	for _, spec := range []string{"udp://0.0.0.0:53", "tcp://0.0.0.0", "unix://", "tcp://:8090?tls=maybe", "0.0.0.0:8090"} {
+		_, err := Parse(spec, false)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestParseList(t *testing.T) {
+	specs, err := ParseList("tcp://0.0.0.0:8090, unix:///run/gophkeeper.sock,", false)
+	require.NoError(t, err)
+	require.Len(t, specs, 2)
+	assert.Equal(t, "tcp://0.0.0.0:8090", specs[0].String())
+	assert.Equal(t, "unix:///run/gophkeeper.sock", specs[1].String())
+
+	specs, err = ParseList("", false)
+	require.NoError(t, err)
+	assert.Empty(t, specs)
+
+	_, err = ParseList("tcp://0.0.0.0:8090,bad", false)
+	assert.Error(t, err)
+}
+
+func TestSpec_ListenUnix(t *testing.T) {
+	dir, err := os.MkdirTemp("", "gk")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = os.RemoveAll(dir)
+	})
+	path := filepath.Join(dir, "s.sock")
+	spec := &Spec{Network: NetworkUnix, Address: path}
+
+	l, err := spec.Listen()
+	require.NoError(t, err)
+
+	// A socket of a running server is kept.
+	_, err = spec.Listen()
+	assert.Error(t, err)
+
+	// A stale socket is replaced.
+	l.(*net.UnixListener).SetUnlinkOnClose(false)
+	require.NoError(t, l.Close())
+	l, err = spec.Listen()
+	require.NoError(t, err)
+	require.NoError(t, l.Close())
+
+	require.NoError(t, os.WriteFile(path, []byte("data"), 0600))
+	_, err = spec.Listen()
+	assert.Error(t, err)
+}
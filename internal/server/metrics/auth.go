@@ -0,0 +1,70 @@
+package metrics
+
+import (
+	"context"
+	"strings"
+
+	"github.com/r4start/goph-keeper/internal/server/app"
+	"github.com/r4start/goph-keeper/internal/server/storage"
+)
+
+var _ app.Authorizer = (*authorizer)(nil)
+
+// Authorization operations.
+const (
+	AuthRegister  = "register"
+	AuthAuthorize = "authorize"
+	AuthRefresh   = "refresh"
+	AuthToken     = "token"
+)
+
+// Authorizer counts outcomes of authorization attempts made with a.
+func (m *Metrics) Authorizer(a app.Authorizer) app.Authorizer {
+	return &authorizer{Authorizer: a, metrics: m}
+}
+
+type authorizer struct {
+	app.Authorizer
+	metrics *Metrics
+}
+
+func (a *authorizer) Register(ctx context.Context, login, password string, salt []byte) (*app.AuthData, error) {
+	data, err := a.Authorizer.Register(ctx, login, password, salt)
+	a.observe(AuthRegister, err)
+	return data, err
+}
+
+func (a *authorizer) Authorize(ctx context.Context, login, password string) (*app.AuthData, error) {
+	data, err := a.Authorizer.Authorize(ctx, login, password)
+	a.observe(AuthAuthorize, err)
+	return data, err
+}
+
+func (a *authorizer) RefreshToken(ctx context.Context, refreshToken string) (*app.AuthData, error) {
+	data, err := a.Authorizer.RefreshToken(ctx, refreshToken)
+	a.observe(AuthRefresh, err)
+	return data, err
+}
+
+func (a *authorizer) AuthorizeWithToken(ctx context.Context, token string) (*app.AuthData, error) {
+	data, err := a.Authorizer.AuthorizeWithToken(ctx, token)
+	a.observe(AuthToken, err)
+	return data, err
+}
+
+func (a *authorizer) observe(operation string, err error) {
+	a.metrics.authAttempts.WithLabelValues(operation, authResult(err)).Inc()
+}
+
+// authResult names a failure by its code, e.g. invalid_credentials.
+func authResult(err error) string {
+	if err == nil {
+		return ResultSuccess
+	}
+
+	code := storage.CodeOf(err)
+	if code == storage.CodeInternal {
+		return ResultError
+	}
+	return strings.ReplaceAll(code.String(), " ", "_")
+}
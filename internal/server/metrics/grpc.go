@@ -0,0 +1,104 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	grpc_ratelimit "github.com/grpc-ecosystem/go-grpc-middleware/ratelimit"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// UnaryServerInterceptor counts calls and their traffic. It goes first in a
+// chain, so rejected calls are counted too.
+func (m *Metrics) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		service, method := splitMethod(info.FullMethod)
+		start := time.Now()
+
+		m.grpcReceivedBytes.WithLabelValues(service, method).Add(messageSize(req))
+		resp, err := handler(ctx, req)
+		if err == nil {
+			m.grpcSentBytes.WithLabelValues(service, method).Add(messageSize(resp))
+		}
+
+		m.observeCall(service, method, start, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor counts calls and sizes of streamed messages.
+func (m *Metrics) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		service, method := splitMethod(info.FullMethod)
+		start := time.Now()
+
+		err := handler(srv, &monitoredStream{
+			ServerStream: ss,
+			received:     m.grpcReceivedBytes.WithLabelValues(service, method),
+			sent:         m.grpcSentBytes.WithLabelValues(service, method),
+		})
+
+		m.observeCall(service, method, start, err)
+		return err
+	}
+}
+
+func (m *Metrics) observeCall(service, method string, start time.Time, err error) {
+	m.grpcDuration.WithLabelValues(service, method).Observe(time.Since(start).Seconds())
+	m.grpcRequests.WithLabelValues(service, method, status.Code(err).String()).Inc()
+}
+
+type counter interface {
+	Add(float64)
+}
+
+type monitoredStream struct {
+	grpc.ServerStream
+	received counter
+	sent     counter
+}
+
+func (s *monitoredStream) RecvMsg(msg interface{}) error {
+	if err := s.ServerStream.RecvMsg(msg); err != nil {
+		return err
+	}
+	s.received.Add(messageSize(msg))
+	return nil
+}
+
+func (s *monitoredStream) SendMsg(msg interface{}) error {
+	if err := s.ServerStream.SendMsg(msg); err != nil {
+		return err
+	}
+	s.sent.Add(messageSize(msg))
+	return nil
+}
+
+func messageSize(msg interface{}) float64 {
+	if m, ok := msg.(proto.Message); ok {
+		return float64(proto.Size(m))
+	}
+	return 0
+}
+
+// Limiter reports delays and rejections of l.
+func (m *Metrics) Limiter(l grpc_ratelimit.Limiter) grpc_ratelimit.Limiter {
+	return &limiter{Limiter: l, metrics: m}
+}
+
+type limiter struct {
+	grpc_ratelimit.Limiter
+	metrics *Metrics
+}
+
+func (l *limiter) Limit() bool {
+	start := time.Now()
+	rejected := l.Limiter.Limit()
+	l.metrics.rateLimitWait.Observe(time.Since(start).Seconds())
+	if rejected {
+		l.metrics.rateLimitRejections.Inc()
+	}
+	return rejected
+}
@@ -0,0 +1,144 @@
+// Package metrics exposes server metrics in Prometheus format. Names of the
+// metrics are a part of the server interface and must not change.
+package metrics
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const _namespace = "gophkeeper"
+
+// Label values of outcomes.
+const (
+	ResultSuccess = "success"
+	ResultError   = "error"
+)
+
+type Metrics struct {
+	registry *prometheus.Registry
+
+	grpcRequests      *prometheus.CounterVec
+	grpcDuration      *prometheus.HistogramVec
+	grpcReceivedBytes *prometheus.CounterVec
+	grpcSentBytes     *prometheus.CounterVec
+
+	rateLimitWait       prometheus.Histogram
+	rateLimitRejections prometheus.Counter
+
+	authAttempts *prometheus.CounterVec
+
+	dbQueries *prometheus.HistogramVec
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		grpcRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: _namespace,
+			Subsystem: "grpc",
+			Name:      "requests_total",
+			Help:      "Number of finished gRPC calls by status code.",
+		}, []string{"service", "method", "code"}),
+		grpcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: _namespace,
+			Subsystem: "grpc",
+			Name:      "request_duration_seconds",
+			Help:      "Duration of gRPC calls.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"service", "method"}),
+		grpcReceivedBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: _namespace,
+			Subsystem: "grpc",
+			Name:      "received_bytes_total",
+			Help:      "Size of messages received from clients.",
+		}, []string{"service", "method"}),
+		grpcSentBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: _namespace,
+			Subsystem: "grpc",
+			Name:      "sent_bytes_total",
+			Help:      "Size of messages sent to clients.",
+		}, []string{"service", "method"}),
+		rateLimitWait: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: _namespace,
+			Subsystem: "ratelimit",
+			Name:      "wait_seconds",
+			Help:      "Time calls are delayed by the rate limiter.",
+			Buckets:   prometheus.ExponentialBuckets(0.001, 4, 8),
+		}),
+		rateLimitRejections: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: _namespace,
+			Subsystem: "ratelimit",
+			Name:      "rejections_total",
+			Help:      "Number of calls rejected by the rate limiter.",
+		}),
+		authAttempts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: _namespace,
+			Subsystem: "auth",
+			Name:      "attempts_total",
+			Help:      "Number of authorization attempts by operation and result.",
+		}, []string{"operation", "result"}),
+		dbQueries: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: _namespace,
+			Subsystem: "db",
+			Name:      "query_duration_seconds",
+			Help:      "Duration of database queries.",
+			Buckets:   prometheus.ExponentialBuckets(0.0005, 2, 14),
+		}, []string{"operation", "result"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.grpcRequests,
+		m.grpcDuration,
+		m.grpcReceivedBytes,
+		m.grpcSentBytes,
+		m.rateLimitWait,
+		m.rateLimitRejections,
+		m.authAttempts,
+		m.dbQueries,
+	)
+	return m
+}
+
+// Handler serves the metrics to scrapers.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// Register adds collectors of other subsystems.
+func (m *Metrics) Register(cs ...prometheus.Collector) error {
+	for _, c := range cs {
+		if err := m.registry.Register(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ObserveQuery records a database query. It fits storage.QueryObserver.
+func (m *Metrics) ObserveQuery(operation string, duration time.Duration, err error) {
+	m.dbQueries.WithLabelValues(operation, result(err)).Observe(duration.Seconds())
+}
+
+func result(err error) string {
+	if err != nil {
+		return ResultError
+	}
+	return ResultSuccess
+}
+
+// splitMethod splits a full gRPC method name /package.Service/Method.
+func splitMethod(fullMethod string) (string, string) {
+	service, method, ok := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	if !ok {
+		return "unknown", "unknown"
+	}
+	return service, method
+}
@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/r4start/goph-keeper/internal/server/app"
+	pb "github.com/r4start/goph-keeper/pkg/grpc/proto"
+)
+
+type mockAuth struct {
+	app.Authorizer
+}
+
+func (mockAuth) Authorize(_ context.Context, login, _ string) (*app.AuthData, error) {
+	if login != "user" {
+		return nil, app.ErrInvalidCredentials
+	}
+	return &app.AuthData{}, nil
+}
+
+type mockLimiter bool
+
+func (l mockLimiter) Limit() bool {
+	return bool(l)
+}
+
+func TestMetrics_UnaryServerInterceptor(t *testing.T) {
+	m := New()
+	interceptor := m.UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/gophkeeper.Storage/Stat"}
+
+	id := "resource"
+	_, err := interceptor(context.Background(), &pb.Resource{Id: &id}, info,
+		func(context.Context, interface{}) (interface{}, error) {
+			return &pb.Resource{Id: &id}, nil
+		})
+	require.NoError(t, err)
+
+	_, err = interceptor(context.Background(), &pb.Resource{}, info,
+		func(context.Context, interface{}) (interface{}, error) {
+			return nil, status.Error(codes.NotFound, "not found")
+		})
+	require.Error(t, err)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.grpcRequests.WithLabelValues("gophkeeper.Storage", "Stat", "OK")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.grpcRequests.WithLabelValues("gophkeeper.Storage", "Stat", "NotFound")))
+	assert.Equal(t, float64(len(id)+2), testutil.ToFloat64(m.grpcReceivedBytes.WithLabelValues("gophkeeper.Storage", "Stat")))
+	assert.Equal(t, float64(len(id)+2), testutil.ToFloat64(m.grpcSentBytes.WithLabelValues("gophkeeper.Storage", "Stat")))
+}
+
+func TestMetrics_Authorizer(t *testing.T) {
+	m := New()
+	a := m.Authorizer(mockAuth{})
+
+	_, err := a.Authorize(context.Background(), "user", "pwd")
+	require.NoError(t, err)
+	_, err = a.Authorize(context.Background(), "unknown", "pwd")
+	require.ErrorIs(t, err, app.ErrInvalidCredentials)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.authAttempts.WithLabelValues(AuthAuthorize, ResultSuccess)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.authAttempts.WithLabelValues(AuthAuthorize, "invalid_credentials")))
+}
+
+func TestMetrics_Limiter(t *testing.T) {
+	m := New()
+
+	assert.False(t, m.Limiter(mockLimiter(false)).Limit())
+	assert.True(t, m.Limiter(mockLimiter(true)).Limit())
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.rateLimitRejections))
+}
+
+func TestMetrics_Handler(t *testing.T) {
+	m := New()
+	m.ObserveQuery("select users", time.Millisecond, nil)
+
+	srv := httptest.NewServer(m.Handler())
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	require.NoError(t, err)
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(body),
+		`gophkeeper_db_query_duration_seconds_count{operation="select users",result="success"} 1`)
+	assert.Contains(t, string(body), "go_goroutines")
+}
@@ -0,0 +1,56 @@
+// Package reload applies changes of configuration files to a running server
+// without dropping its connections.
+package reload
+
+import (
+	"crypto/tls"
+	"sync/atomic"
+)
+
+// Certificate is a TLS certificate which is replaced on Reload. Listeners
+// get it on every handshake, so established connections keep an old
+// certificate and new ones get a new one.
+type Certificate struct {
+	certFile string
+	keyFile  string
+	cert     atomic.Pointer[tls.Certificate]
+}
+
+func NewCertificate(certFile, keyFile string) (*Certificate, error) {
+	c := &Certificate{
+		certFile: certFile,
+		keyFile:  keyFile,
+	}
+	if err := c.Reload(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Reload reads the certificate files again. An invalid certificate is not
+// applied, the current one stays in use.
+func (c *Certificate) Reload() error {
+	cert, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+	if err != nil {
+		return err
+	}
+	c.cert.Store(&cert)
+	return nil
+}
+
+// Files returns paths of the certificate and its key.
+func (c *Certificate) Files() []string {
+	return []string{c.certFile, c.keyFile}
+}
+
+func (c *Certificate) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return c.cert.Load(), nil
+}
+
+// TLSConfig returns a server configuration serving the certificate.
+func (c *Certificate) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: c.GetCertificate,
+	}
+}
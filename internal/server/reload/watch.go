@@ -0,0 +1,89 @@
+package reload
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/zap"
+
+	"github.com/r4start/goph-keeper/internal/logging"
+)
+
+// Watch calls fn after any of files changes, until ctx is done. Directories
+// of the files are watched, so files replaced by renames, e.g. by editors or
+// certificate managers, are noticed too. Events coming within delay are
+// merged into a single call.
+func Watch(ctx context.Context, files []string, delay time.Duration, fn func()) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+
+	watched := make(map[string]bool, len(files))
+	dirs := make(map[string]bool)
+	for _, f := range files {
+		path, err := filepath.Abs(f)
+		if err != nil {
+			_ = watcher.Close()
+			return err
+		}
+		watched[path] = true
+
+		dir := filepath.Dir(path)
+		if dirs[dir] {
+			continue
+		}
+		if err := watcher.Add(dir); err != nil {
+			_ = watcher.Close()
+			return err
+		}
+		dirs[dir] = true
+	}
+
+	go watch(ctx, watcher, watched, delay, fn)
+	return nil
+}
+
+func watch(ctx context.Context, watcher *fsnotify.Watcher, watched map[string]bool, delay time.Duration, fn func()) {
+	defer func() {
+		_ = watcher.Close()
+	}()
+
+	timer := time.NewTimer(delay)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if changes(e, watched) {
+				timer.Reset(delay)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			logging.FromContext(ctx).Warn("failed to watch configuration files", zap.Error(err))
+		case <-timer.C:
+			fn()
+		}
+	}
+}
+
+// changes tells whether an event touches a watched file. Kubernetes updates
+// mounted secrets by swapping a ..data symlink, so such swaps change every
+// file of a directory.
+func changes(e fsnotify.Event, watched map[string]bool) bool {
+	if e.Op == fsnotify.Chmod {
+		return false
+	}
+	return watched[filepath.Clean(e.Name)] || strings.HasPrefix(filepath.Base(e.Name), "..")
+}
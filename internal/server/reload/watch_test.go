@@ -0,0 +1,57 @@
+package reload
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatch(t *testing.T) {
+	dir := t.TempDir()
+	config := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(config, []byte("rps_limit: 10"), 0600))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	calls := make(chan struct{}, 10)
+	require.NoError(t, Watch(ctx, []string{config}, 10*time.Millisecond, func() {
+		calls <- struct{}{}
+	}))
+
+	// Bursts of writes are merged.
+	for i := 0; i < 3; i++ {
+		require.NoError(t, os.WriteFile(config, []byte("rps_limit: 20"), 0600))
+	}
+	select {
+	case <-calls:
+	case <-time.After(5 * time.Second):
+		t.Fatal("change is not noticed")
+	}
+
+	// Files replaced by renames are noticed too.
+	tmp := filepath.Join(dir, "config.tmp")
+	require.NoError(t, os.WriteFile(tmp, []byte("rps_limit: 30"), 0600))
+	require.NoError(t, os.Rename(tmp, config))
+	select {
+	case <-calls:
+	case <-time.After(5 * time.Second):
+		t.Fatal("rename is not noticed")
+	}
+
+	// Other files of a directory are ignored.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "other"), []byte("data"), 0600))
+	select {
+	case <-calls:
+		t.Fatal("unrelated change is reported")
+	case <-time.After(100 * time.Millisecond):
+	}
+	assert.Empty(t, calls)
+
+	assert.Error(t, Watch(ctx, []string{filepath.Join(dir, "missing", "config.yaml")}, time.Millisecond, func() {}))
+}
@@ -0,0 +1,40 @@
+package storage
+
+import (
+	"context"
+	"time"
+)
+
+// ErrBatchAborted is a result of items of an atomic batch rolled back
+// because of a failure of another item.
+var ErrBatchAborted = NewError(CodeConflict, "batch is aborted")
+
+type BatchOp int
+
+const (
+	BatchStat BatchOp = iota
+	BatchDelete
+	BatchRestore
+)
+
+type BatchItem struct {
+	Op BatchOp
+	ID ResourceID
+}
+
+type BatchResult struct {
+	Info *ResourceInfo
+	Err  error
+}
+
+type BatchOptions struct {
+	// Atomic applies either all of the items or none of them.
+	Atomic bool
+	// RestorableSince is the earliest deletion time of a resource BatchRestore
+	// can restore.
+	RestorableSince time.Time
+}
+
+func (b *blobStorage) Batch(ctx context.Context, user *UserID, items []BatchItem, opts *BatchOptions) ([]BatchResult, error) {
+	return b.meta.Batch(ctx, user, items, opts)
+}
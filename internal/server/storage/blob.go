@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+const (
+	// BlobStorePostgresLO keeps blobs in Postgres large objects.
+	BlobStorePostgresLO = "postgres-lo"
+	// BlobStoreFS keeps blobs in files of a local directory.
+	BlobStoreFS = "fs"
+	// BlobStoreS3 keeps blobs in an S3 compatible object storage.
+	BlobStoreS3 = "s3"
+	// BlobStoreSQLite keeps blobs in the SQLite database of metadata.
+	BlobStoreSQLite = "sqlite"
+	// BlobStoreMemory keeps blobs in memory until the server stops.
+	BlobStoreMemory = "memory"
+)
+
+var ErrBlobNotFound = errors.New("blob not found")
+
+// Pinger is implemented by stores which can tell whether they are available.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// BlobID is an opaque identifier of a blob within a BlobStore.
+type BlobID string
+
+// BlobStore keeps resource data. A blob is created uncommitted, filled with
+// writers and then committed. Uncommitted blobs survive restarts, so they are
+// used by upload sessions as well.
+type BlobStore interface {
+	// Create allocates a new empty uncommitted blob.
+	Create(ctx context.Context) (BlobID, error)
+	// NewWriter writes into an uncommitted blob starting from offset.
+	// The written data is durable once the writer is closed.
+	NewWriter(ctx context.Context, id BlobID, offset uint64) (io.WriteCloser, error)
+	// Commit makes a blob permanent. Committing a committed blob does nothing.
+	Commit(ctx context.Context, id BlobID) error
+	// Open opens a committed or an uncommitted blob for reading.
+	Open(ctx context.Context, id BlobID) (io.ReadSeekCloser, error)
+	Delete(ctx context.Context, id BlobID) error
+	// Walk calls fn for every committed and uncommitted blob of the store.
+	// The modification time is zero when a store doesn't track it, then
+	// garbage collection never removes the blob as an orphan.
+	Walk(ctx context.Context, fn func(id BlobID, modTime time.Time) error) error
+}
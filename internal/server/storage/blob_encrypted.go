@@ -0,0 +1,326 @@
+package storage
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"sort"
+	"time"
+)
+
+var (
+	_ BlobStore    = (*encryptedBlobStore)(nil)
+	_ Pinger       = (*encryptedBlobStore)(nil)
+	_ KeyRewrapper = (*encryptedBlobStore)(nil)
+)
+
+const (
+	_dataKeySize      = 32
+	_nonceSize        = 8
+	_rewrapBatchSize  = 1000
+	_encryptedBufSize = 32 * 1024
+)
+
+// encryptedBlobStore encrypts blobs of another store. Every blob has its own
+// AES-256 data key wrapped by a key encryption key and kept in a BlobKeyStore.
+// Data is encrypted in CTR mode, so an offset in a blob is the same as in its
+// plain data and blobs are appended and read at any offset as before.
+// A key stream must never encrypt different data, so a writer which rewrites
+// data, e.g. of a resumed upload, starts a new segment with a random nonce.
+// Integrity is checked by digests of resources, which are verified on upload.
+//
+// Blobs without a data key, i.e. stored before encryption was enabled,
+// are read and written as is.
+type encryptedBlobStore struct {
+	blobs BlobStore
+	keys  BlobKeyStore
+	kek   KeyWrapper
+}
+
+func NewEncryptedBlobStore(blobs BlobStore, keys BlobKeyStore, kek KeyWrapper) *encryptedBlobStore {
+	return &encryptedBlobStore{
+		blobs: blobs,
+		keys:  keys,
+		kek:   kek,
+	}
+}
+
+// Ping checks the underlying store.
+func (e *encryptedBlobStore) Ping(ctx context.Context) error {
+	if p, ok := e.blobs.(Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+func (e *encryptedBlobStore) Create(ctx context.Context) (BlobID, error) {
+	key := make([]byte, _dataKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+
+	id, err := e.blobs.Create(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	kekID, wrapped, err := e.kek.Wrap(key, []byte(id))
+	if err == nil {
+		err = e.keys.AddBlobKey(ctx, &BlobKey{BlobID: id, KEKID: kekID, Wrapped: wrapped})
+	}
+	if err != nil {
+		cleanupCtx, cancel := context.WithTimeout(context.Background(), _cleanupTimeout)
+		defer cancel()
+		_ = e.blobs.Delete(cleanupCtx, id)
+		return "", err
+	}
+	return id, nil
+}
+
+func (e *encryptedBlobStore) NewWriter(ctx context.Context, id BlobID, offset uint64) (io.WriteCloser, error) {
+	c, err := e.blobCipher(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if c != nil {
+		if err := e.startSegment(ctx, id, c, offset); err != nil {
+			return nil, err
+		}
+	}
+
+	w, err := e.blobs.NewWriter(ctx, id, offset)
+	if err != nil || c == nil {
+		return w, err
+	}
+	stream, _ := c.stream(offset)
+	return &encryptedBlobWriter{w: w, stream: stream}, nil
+}
+
+func (e *encryptedBlobStore) Commit(ctx context.Context, id BlobID) error {
+	return e.blobs.Commit(ctx, id)
+}
+
+func (e *encryptedBlobStore) Open(ctx context.Context, id BlobID) (io.ReadSeekCloser, error) {
+	c, err := e.blobCipher(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := e.blobs.Open(ctx, id)
+	if err != nil || c == nil {
+		return r, err
+	}
+	stream, end := c.stream(0)
+	return &encryptedBlobReader{r: r, cipher: c, stream: stream, end: end}, nil
+}
+
+// Delete removes a data key even if a blob is already missing, so keys
+// of blobs removed by a failed call don't pile up.
+func (e *encryptedBlobStore) Delete(ctx context.Context, id BlobID) error {
+	err := e.blobs.Delete(ctx, id)
+	if err != nil && !errors.Is(err, ErrBlobNotFound) {
+		return err
+	}
+	if keyErr := e.keys.DeleteBlobKey(ctx, id); keyErr != nil {
+		return keyErr
+	}
+	return err
+}
+
+func (e *encryptedBlobStore) Walk(ctx context.Context, fn func(id BlobID, modTime time.Time) error) error {
+	return e.blobs.Walk(ctx, fn)
+}
+
+// RewrapKeys wraps data keys wrapped by old key encryption keys with the
+// primary one. It returns a number of re-wrapped keys. A key which can't be
+// unwrapped, e.g. because its key encryption key was removed too early,
+// stops the run.
+func (e *encryptedBlobStore) RewrapKeys(ctx context.Context) (int, error) {
+	rewrapped := 0
+	for {
+		keys, err := e.keys.StaleBlobKeys(ctx, e.kek.PrimaryID(), _rewrapBatchSize)
+		if err != nil || len(keys) == 0 {
+			return rewrapped, err
+		}
+
+		for i := range keys {
+			key, err := e.kek.Unwrap(keys[i].KEKID, keys[i].Wrapped, []byte(keys[i].BlobID))
+			if err != nil {
+				return rewrapped, err
+			}
+			keys[i].KEKID, keys[i].Wrapped, err = e.kek.Wrap(key, []byte(keys[i].BlobID))
+			if err != nil {
+				return rewrapped, err
+			}
+			if err := e.keys.UpdateBlobKey(ctx, &keys[i]); err != nil {
+				return rewrapped, err
+			}
+			rewrapped++
+		}
+	}
+}
+
+// blobCipher returns a cipher of a blob or nil when the blob isn't encrypted.
+func (e *encryptedBlobStore) blobCipher(ctx context.Context, id BlobID) (*blobCipher, error) {
+	wrapped, err := e.keys.BlobKey(ctx, id)
+	if errors.Is(err, ErrBlobKeyNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	key, err := e.kek.Unwrap(wrapped.KEKID, wrapped.Wrapped, []byte(id))
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	segments, err := e.keys.BlobSegments(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &blobCipher{block: block, segments: segments}, nil
+}
+
+// startSegment starts a new segment at offset unless data is only appended,
+// i.e. the key stream past offset has never been used.
+func (e *encryptedBlobStore) startSegment(ctx context.Context, id BlobID, c *blobCipher, offset uint64) error {
+	size, err := e.blobSize(ctx, id)
+	if err != nil {
+		return err
+	}
+	if offset >= size && (len(c.segments) == 0 || c.segments[len(c.segments)-1].Offset <= offset) {
+		return nil
+	}
+
+	segment := BlobSegment{Offset: offset, Nonce: make([]byte, _nonceSize)}
+	if _, err := rand.Read(segment.Nonce); err != nil {
+		return err
+	}
+	if err := e.keys.AddBlobSegment(ctx, id, &segment); err != nil {
+		return err
+	}
+	n := sort.Search(len(c.segments), func(i int) bool {
+		return c.segments[i].Offset >= offset
+	})
+	c.segments = append(c.segments[:n], segment)
+	return nil
+}
+
+func (e *encryptedBlobStore) blobSize(ctx context.Context, id BlobID) (uint64, error) {
+	r, err := e.blobs.Open(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = r.Close()
+	}()
+
+	size, err := r.Seek(0, io.SeekEnd)
+	return uint64(size), err
+}
+
+// blobCipher makes key streams of a blob. Data keys are unique, so counters
+// start from zero in every blob, segments are told apart by nonces.
+type blobCipher struct {
+	block    cipher.Block
+	segments []BlobSegment
+}
+
+// stream returns a key stream starting at offset and the end of its segment.
+func (c *blobCipher) stream(offset uint64) (cipher.Stream, uint64) {
+	iv := make([]byte, aes.BlockSize)
+	end := uint64(math.MaxUint64)
+	for _, s := range c.segments {
+		if s.Offset > offset {
+			end = s.Offset
+			break
+		}
+		copy(iv[:_nonceSize], s.Nonce)
+	}
+	binary.BigEndian.PutUint64(iv[_nonceSize:], offset/aes.BlockSize)
+	stream := cipher.NewCTR(c.block, iv)
+
+	skip := make([]byte, offset%aes.BlockSize)
+	stream.XORKeyStream(skip, skip)
+	return stream, end
+}
+
+type encryptedBlobWriter struct {
+	w      io.WriteCloser
+	stream cipher.Stream
+	buf    []byte
+}
+
+// Write encrypts a copy of p, callers may reuse it.
+func (w *encryptedBlobWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) != 0 {
+		n := len(p)
+		if n > _encryptedBufSize {
+			n = _encryptedBufSize
+		}
+		if len(w.buf) < n {
+			w.buf = make([]byte, n)
+		}
+
+		w.stream.XORKeyStream(w.buf[:n], p[:n])
+		m, err := w.w.Write(w.buf[:n])
+		written += m
+		if err != nil {
+			return written, err
+		}
+		p = p[n:]
+	}
+	return written, nil
+}
+
+func (w *encryptedBlobWriter) Close() error {
+	return w.w.Close()
+}
+
+type encryptedBlobReader struct {
+	r      io.ReadSeekCloser
+	cipher *blobCipher
+	stream cipher.Stream
+	pos    uint64
+	// end is the end of the segment of stream.
+	end uint64
+}
+
+// Read stops at the end of a segment, the next one has another key stream.
+func (r *encryptedBlobReader) Read(p []byte) (int, error) {
+	if r.pos >= r.end {
+		r.stream, r.end = r.cipher.stream(r.pos)
+	}
+	if left := r.end - r.pos; uint64(len(p)) > left {
+		p = p[:left]
+	}
+
+	n, err := r.r.Read(p)
+	r.stream.XORKeyStream(p[:n], p[:n])
+	r.pos += uint64(n)
+	return n, err
+}
+
+func (r *encryptedBlobReader) Seek(offset int64, whence int) (int64, error) {
+	pos, err := r.r.Seek(offset, whence)
+	if err != nil {
+		return pos, err
+	}
+	r.pos = uint64(pos)
+	r.stream, r.end = r.cipher.stream(r.pos)
+	return pos, nil
+}
+
+func (r *encryptedBlobReader) Close() error {
+	return r.r.Close()
+}
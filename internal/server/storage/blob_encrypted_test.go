@@ -0,0 +1,187 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/r4start/goph-keeper/internal/server/keyring"
+)
+
+// newTestKeyring returns a keyring of ids, the first one is primary.
+func newTestKeyring(t *testing.T, ids ...string) *keyring.Keyring {
+	path := filepath.Join(t.TempDir(), "kek")
+	writeTestKeyring(t, path, ids...)
+	k, err := keyring.Load(path)
+	require.NoError(t, err)
+	return k
+}
+
+// writeTestKeyring writes a key file, a key is derived from its id.
+func writeTestKeyring(t *testing.T, path string, ids ...string) {
+	lines := make([]string, 0, len(ids))
+	for _, id := range ids {
+		key := sha256.Sum256([]byte(id))
+		lines = append(lines, id+" "+base64.StdEncoding.EncodeToString(key[:]))
+	}
+	require.NoError(t, os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0600))
+}
+
+func writeTestBlob(t *testing.T, store BlobStore, id BlobID, offset uint64, data []byte) {
+	w, err := store.NewWriter(context.Background(), id, offset)
+	require.NoError(t, err)
+	_, err = w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+}
+
+func TestEncryptedBlobStore(t *testing.T) {
+	ctx := context.Background()
+	blobs, keys := NewMemoryBlobStore(), NewMemoryUserService()
+	store := NewEncryptedBlobStore(blobs, keys, newTestKeyring(t, "primary"))
+
+	data := make([]byte, 100)
+	_, err := rand.Read(data)
+	require.NoError(t, err)
+
+	id, err := store.Create(ctx)
+	require.NoError(t, err)
+	// Appends start in the middle of cipher blocks.
+	writeTestBlob(t, store, id, 0, data[:10])
+	writeTestBlob(t, store, id, 10, data[10:37])
+	writeTestBlob(t, store, id, 37, data[37:])
+
+	assert.Equal(t, string(data), readBlob(t, store, id))
+	stored := readBlob(t, blobs, id)
+	assert.Len(t, stored, len(data))
+	assert.NotEqual(t, string(data), stored)
+
+	r, err := store.Open(ctx, id)
+	require.NoError(t, err)
+	_, err = r.Seek(23, io.SeekStart)
+	require.NoError(t, err)
+	remote, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, data[23:], remote)
+	require.NoError(t, r.Close())
+
+	// Data keys differ, so equal data is stored differently.
+	other, err := store.Create(ctx)
+	require.NoError(t, err)
+	writeTestBlob(t, store, other, 0, data)
+	assert.NotEqual(t, stored, readBlob(t, blobs, other))
+
+	// Blobs stored before encryption was enabled are kept as is.
+	plain, err := blobs.Create(ctx)
+	require.NoError(t, err)
+	writeTestBlob(t, blobs, plain, 0, []byte("plain"))
+	writeTestBlob(t, store, plain, 5, []byte(" text"))
+	assert.Equal(t, "plain text", readBlob(t, store, plain))
+	assert.Equal(t, "plain text", readBlob(t, blobs, plain))
+
+	require.NoError(t, store.Delete(ctx, id))
+	_, err = keys.BlobKey(ctx, id)
+	assert.ErrorIs(t, err, ErrBlobKeyNotFound)
+	assert.ErrorIs(t, store.Delete(ctx, id), ErrBlobNotFound)
+}
+
+func TestEncryptedBlobStore_Rewrite(t *testing.T) {
+	ctx := context.Background()
+	blobs, keys := NewMemoryBlobStore(), NewMemoryUserService()
+	store := NewEncryptedBlobStore(blobs, keys, newTestKeyring(t, "primary"))
+
+	data := make([]byte, 100)
+	_, err := rand.Read(data)
+	require.NoError(t, err)
+
+	id, err := store.Create(ctx)
+	require.NoError(t, err)
+	writeTestBlob(t, store, id, 0, data[:50])
+	writeTestBlob(t, store, id, 50, data[50:])
+	stored := readBlob(t, blobs, id)
+	segments, err := keys.BlobSegments(ctx, id)
+	require.NoError(t, err)
+	assert.Empty(t, segments, "appends keep the key stream")
+
+	// The same data rewritten at an offset is encrypted with another key stream.
+	writeTestBlob(t, store, id, 30, data[30:70])
+	writeTestBlob(t, store, id, 70, data[70:])
+	rewritten := readBlob(t, blobs, id)
+	assert.Equal(t, stored[:30], rewritten[:30])
+	assert.NotEqual(t, stored[30:], rewritten[30:])
+	assert.Equal(t, string(data), readBlob(t, store, id))
+
+	writeTestBlob(t, store, id, 10, data[10:20])
+	writeTestBlob(t, store, id, 20, data[20:])
+	assert.NotEqual(t, rewritten[10:], readBlob(t, blobs, id)[10:])
+	segments, err = keys.BlobSegments(ctx, id)
+	require.NoError(t, err)
+	require.Len(t, segments, 1)
+	assert.Equal(t, uint64(10), segments[0].Offset)
+
+	// Reads cross segments at any offset.
+	writeTestBlob(t, store, id, 60, data[60:])
+	assert.Equal(t, string(data), readBlob(t, store, id))
+	r, err := store.Open(ctx, id)
+	require.NoError(t, err)
+	_, err = r.Seek(5, io.SeekStart)
+	require.NoError(t, err)
+	remote, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, data[5:], remote)
+	require.NoError(t, r.Close())
+
+	require.NoError(t, store.Delete(ctx, id))
+	segments, err = keys.BlobSegments(ctx, id)
+	require.NoError(t, err)
+	assert.Empty(t, segments)
+}
+
+func TestEncryptedBlobStore_Rewrap(t *testing.T) {
+	ctx := context.Background()
+	keys := NewMemoryUserService()
+	kek := newTestKeyring(t, "old")
+	store := NewEncryptedBlobStore(NewMemoryBlobStore(), keys, kek)
+
+	ids := make([]BlobID, 3)
+	for i := range ids {
+		id, err := store.Create(ctx)
+		require.NoError(t, err)
+		writeTestBlob(t, store, id, 0, []byte("data"))
+		ids[i] = id
+	}
+
+	rewrapped, err := store.RewrapKeys(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, rewrapped)
+
+	// A new key is added on top and the old one is removed after re-wrapping.
+	writeTestKeyring(t, kek.Files()[0], "new", "old")
+	require.NoError(t, kek.Reload())
+	rewrapped, err = store.RewrapKeys(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, len(ids), rewrapped)
+
+	writeTestKeyring(t, kek.Files()[0], "new")
+	require.NoError(t, kek.Reload())
+	for _, id := range ids {
+		key, err := keys.BlobKey(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, "new", key.KEKID)
+		assert.Equal(t, "data", readBlob(t, store, id))
+	}
+
+	// Keys wrapped by a removed key encryption key can't be re-wrapped.
+	require.NoError(t, keys.AddBlobKey(ctx, &BlobKey{BlobID: "lost", KEKID: "old", Wrapped: []byte("key")}))
+	_, err = store.RewrapKeys(ctx)
+	assert.ErrorIs(t, err, keyring.ErrUnknownKey)
+}
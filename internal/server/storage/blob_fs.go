@@ -0,0 +1,237 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+var (
+	_ BlobStore = (*fsBlobStore)(nil)
+	_ Pinger    = (*fsBlobStore)(nil)
+)
+
+const (
+	_fsBlobIDSize     = 16
+	_fsStagingDirName = "tmp"
+	_fsDirPermissions = 0700
+)
+
+// fsBlobStore keeps every blob in a separate file. Uncommitted blobs are written
+// into a staging directory and moved into a sharded directory tree on commit.
+type fsBlobStore struct {
+	root string
+}
+
+func NewFileBlobStore(root string) (*fsBlobStore, error) {
+	if root == "" {
+		return nil, errors.New("blob store directory must be specified")
+	}
+
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Join(root, _fsStagingDirName), _fsDirPermissions); err != nil {
+		return nil, err
+	}
+
+	return &fsBlobStore{root: root}, nil
+}
+
+// Ping checks that the staging directory is still there, e.g. a volume is
+// mounted.
+func (f *fsBlobStore) Ping(_ context.Context) error {
+	info, err := os.Stat(filepath.Join(f.root, _fsStagingDirName))
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", info.Name())
+	}
+	return nil
+}
+
+func (f *fsBlobStore) Create(_ context.Context) (BlobID, error) {
+	raw := make([]byte, _fsBlobIDSize)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+
+	id := BlobID(hex.EncodeToString(raw))
+	file, err := os.OpenFile(f.stagingPath(id), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		return "", err
+	}
+
+	return id, syncDir(filepath.Join(f.root, _fsStagingDirName))
+}
+
+func (f *fsBlobStore) NewWriter(_ context.Context, id BlobID, offset uint64) (io.WriteCloser, error) {
+	if err := validateFileBlobID(id); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(f.stagingPath(id), os.O_WRONLY, 0)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrBlobNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := file.Seek(int64(offset), io.SeekStart); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	return &fsBlobWriter{file: file}, nil
+}
+
+func (f *fsBlobStore) Commit(_ context.Context, id BlobID) error {
+	if err := validateFileBlobID(id); err != nil {
+		return err
+	}
+
+	blobPath := f.blobPath(id)
+	if _, err := os.Stat(blobPath); err == nil {
+		return nil
+	}
+
+	dir := filepath.Dir(blobPath)
+	if err := os.MkdirAll(dir, _fsDirPermissions); err != nil {
+		return err
+	}
+
+	err := os.Rename(f.stagingPath(id), blobPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrBlobNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := syncDir(dir); err != nil {
+		return err
+	}
+	return syncDir(filepath.Join(f.root, _fsStagingDirName))
+}
+
+func (f *fsBlobStore) Open(_ context.Context, id BlobID) (io.ReadSeekCloser, error) {
+	if err := validateFileBlobID(id); err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(f.blobPath(id))
+	if errors.Is(err, fs.ErrNotExist) {
+		file, err = os.Open(f.stagingPath(id))
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrBlobNotFound
+	}
+	return file, err
+}
+
+func (f *fsBlobStore) Delete(_ context.Context, id BlobID) error {
+	if err := validateFileBlobID(id); err != nil {
+		return err
+	}
+
+	removed := false
+	for _, p := range []string{f.blobPath(id), f.stagingPath(id)} {
+		err := os.Remove(p)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		removed = true
+	}
+
+	if !removed {
+		return ErrBlobNotFound
+	}
+	return nil
+}
+
+func (f *fsBlobStore) Walk(ctx context.Context, fn func(id BlobID, modTime time.Time) error) error {
+	return filepath.WalkDir(f.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		id := BlobID(d.Name())
+		if d.IsDir() || validateFileBlobID(id) != nil {
+			return nil
+		}
+
+		info, err := d.Info()
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return fn(id, info.ModTime())
+	})
+}
+
+// blobPath spreads blobs over two levels of directories named after
+// the first bytes of a blob id, so no directory grows too large.
+func (f *fsBlobStore) blobPath(id BlobID) string {
+	return filepath.Join(f.root, string(id[0:2]), string(id[2:4]), string(id))
+}
+
+func (f *fsBlobStore) stagingPath(id BlobID) string {
+	return filepath.Join(f.root, _fsStagingDirName, string(id))
+}
+
+func validateFileBlobID(id BlobID) error {
+	raw, err := hex.DecodeString(string(id))
+	if err != nil || len(raw) != _fsBlobIDSize {
+		return fmt.Errorf("bad blob id %q", id)
+	}
+	return nil
+}
+
+func syncDir(path string) error {
+	dir, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = dir.Close()
+	}()
+	return dir.Sync()
+}
+
+type fsBlobWriter struct {
+	file *os.File
+}
+
+func (w *fsBlobWriter) Write(p []byte) (int, error) {
+	return w.file.Write(p)
+}
+
+func (w *fsBlobWriter) Close() error {
+	if err := w.file.Sync(); err != nil {
+		_ = w.file.Close()
+		return err
+	}
+	return w.file.Close()
+}
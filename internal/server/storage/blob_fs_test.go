@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileBlobStore(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	store, err := NewFileBlobStore(root)
+	assert.NoError(t, err)
+
+	id, err := store.Create(ctx)
+	assert.NoError(t, err)
+	assert.FileExists(t, filepath.Join(root, _fsStagingDirName, string(id)))
+
+	w, err := store.NewWriter(ctx, id, 0)
+	assert.NoError(t, err)
+	_, err = w.Write([]byte("hello, "))
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+
+	w, err = store.NewWriter(ctx, id, 7)
+	assert.NoError(t, err)
+	_, err = w.Write([]byte("world"))
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+
+	// Uncommitted blobs are readable, e.g. to verify them before a commit.
+	assert.Equal(t, "hello, world", readBlob(t, store, id))
+
+	assert.NoError(t, store.Commit(ctx, id))
+	assert.NoError(t, store.Commit(ctx, id))
+
+	blobPath := filepath.Join(root, string(id[0:2]), string(id[2:4]), string(id))
+	assert.FileExists(t, blobPath)
+	assert.NoFileExists(t, filepath.Join(root, _fsStagingDirName, string(id)))
+	assert.Equal(t, "hello, world", readBlob(t, store, id))
+
+	r, err := store.Open(ctx, id)
+	assert.NoError(t, err)
+	_, err = r.Seek(7, io.SeekStart)
+	assert.NoError(t, err)
+	data, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "world", string(data))
+	assert.NoError(t, r.Close())
+
+	assert.NoError(t, store.Delete(ctx, id))
+	assert.NoFileExists(t, blobPath)
+	assert.ErrorIs(t, store.Delete(ctx, id), ErrBlobNotFound)
+
+	_, err = store.Open(ctx, id)
+	assert.ErrorIs(t, err, ErrBlobNotFound)
+	_, err = store.NewWriter(ctx, id, 0)
+	assert.ErrorIs(t, err, ErrBlobNotFound)
+}
+
+func TestFileBlobStore_BadID(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	store, err := NewFileBlobStore(filepath.Join(root, "blobs"))
+	assert.NoError(t, err)
+
+	secret := filepath.Join(root, "secret")
+	assert.NoError(t, os.WriteFile(secret, []byte("secret"), 0600))
+
+	for _, id := range []BlobID{"", "../../secret", "0123", "zz112233445566778899aabbccddeeff"} {
+		_, err := store.Open(ctx, id)
+		assert.Error(t, err)
+		assert.Error(t, store.Delete(ctx, id))
+		assert.Error(t, store.Commit(ctx, id))
+	}
+	assert.FileExists(t, secret)
+}
+
+func readBlob(t *testing.T, store BlobStore, id BlobID) string {
+	r, err := store.Open(context.Background(), id)
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, r.Close())
+	}()
+
+	data, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	return string(data)
+}
+
+func TestFileBlobStore_Ping(t *testing.T) {
+	root := t.TempDir()
+	store, err := NewFileBlobStore(root)
+	assert.NoError(t, err)
+	assert.NoError(t, store.Ping(context.Background()))
+
+	assert.NoError(t, os.RemoveAll(root))
+	assert.Error(t, store.Ping(context.Background()))
+}
@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrBlobKeyNotFound = errors.New("blob key not found")
+
+// BlobKey is a data key of a blob wrapped by a key encryption key.
+type BlobKey struct {
+	BlobID BlobID
+	// KEKID is an id of the key encryption key which wrapped the data key.
+	KEKID   string
+	Wrapped []byte
+}
+
+// BlobSegment starts a new key stream of a blob at Offset, so data rewritten
+// at an offset is never encrypted with a key stream used before. Data at an
+// offset belongs to the last segment starting at or before it, data before
+// the first segment uses a zero nonce.
+type BlobSegment struct {
+	Offset uint64
+	Nonce  []byte
+}
+
+// BlobKeyStore keeps wrapped data keys of encrypted blobs.
+type BlobKeyStore interface {
+	AddBlobKey(ctx context.Context, key *BlobKey) error
+	// BlobKey fails with ErrBlobKeyNotFound when a blob has no data key,
+	// e.g. it was stored before encryption was enabled.
+	BlobKey(ctx context.Context, id BlobID) (*BlobKey, error)
+	// UpdateBlobKey replaces a wrapped data key of a blob. A missing key is
+	// not an error, the blob may have been deleted in the meantime.
+	UpdateBlobKey(ctx context.Context, key *BlobKey) error
+	// DeleteBlobKey removes segments of a blob too.
+	DeleteBlobKey(ctx context.Context, id BlobID) error
+	// AddBlobSegment replaces segments of a blob which start at or after
+	// the offset of segment.
+	AddBlobSegment(ctx context.Context, id BlobID, segment *BlobSegment) error
+	// BlobSegments returns segments of a blob ordered by offsets.
+	BlobSegments(ctx context.Context, id BlobID) ([]BlobSegment, error)
+	// KEKIDs returns ids of key encryption keys which wrap data keys.
+	KEKIDs(ctx context.Context) ([]string, error)
+	// StaleBlobKeys returns up to limit data keys wrapped by key encryption
+	// keys other than kekID.
+	StaleBlobKeys(ctx context.Context, kekID string, limit int) ([]BlobKey, error)
+}
+
+// KeyWrapper wraps data keys with key encryption keys.
+type KeyWrapper interface {
+	// PrimaryID returns an id of the key which wraps new data keys.
+	PrimaryID() string
+	// Wrap encrypts a key with the primary key and returns the id of it.
+	Wrap(key, additionalData []byte) (string, []byte, error)
+	Unwrap(id string, wrapped, additionalData []byte) ([]byte, error)
+}
+
+// KeyRewrapper re-wraps data keys after key encryption keys are rotated.
+type KeyRewrapper interface {
+	// RewrapKeys wraps data keys with the primary key encryption key and
+	// returns a number of re-wrapped keys.
+	RewrapKeys(ctx context.Context) (int, error)
+}
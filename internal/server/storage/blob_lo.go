@@ -0,0 +1,208 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	_ BlobStore = (*loBlobStore)(nil)
+	_ Pinger    = (*loBlobStore)(nil)
+)
+
+const (
+	_trackLargeObject   = `insert into lo_blobs (oid) values ($1);`
+	_touchLargeObject   = `update lo_blobs set last_update=now() where oid=$1;`
+	_untrackLargeObject = `delete from lo_blobs where oid=$1;`
+	_listLargeObjects   = `select oid, last_update from lo_blobs;`
+
+	_pgUndefinedObject = "42704"
+)
+
+// loBlobStore keeps blobs in Postgres large objects. Large objects have no
+// commit state, so a blob is complete as soon as its writer is closed.
+// Postgres doesn't keep their modification time and lists large objects
+// of every user of a database, so the store tracks its large objects itself.
+type loBlobStore struct {
+	dbConn *pgxpool.Pool
+}
+
+// NewLargeObjectBlobStore creates a blob store sharing a connection pool with d.
+func NewLargeObjectBlobStore(d *dbStorage) *loBlobStore {
+	return &loBlobStore{dbConn: d.dbConn}
+}
+
+// Ping checks the database keeping large objects.
+func (l *loBlobStore) Ping(ctx context.Context) error {
+	return l.dbConn.Ping(ctx)
+}
+
+func (l *loBlobStore) Create(ctx context.Context) (BlobID, error) {
+	var oid uint32
+	err := pgx.BeginFunc(ctx, l.dbConn, func(tx pgx.Tx) error {
+		lo := tx.LargeObjects()
+		var err error
+		if oid, err = lo.Create(ctx, _emptyOID); err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, _trackLargeObject, oid)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return loBlobID(oid), nil
+}
+
+func (l *loBlobStore) NewWriter(ctx context.Context, id BlobID, offset uint64) (io.WriteCloser, error) {
+	oid, err := parseLoBlobID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err := l.open(ctx, id, pgx.LargeObjectModeWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := obj.Seek(int64(offset), io.SeekStart); err != nil {
+		return nil, obj.rollback(err)
+	}
+	// The time is committed with written data when the writer is closed.
+	if _, err := obj.tx.Exec(ctx, _touchLargeObject, oid); err != nil {
+		return nil, obj.rollback(err)
+	}
+	return obj, nil
+}
+
+func (l *loBlobStore) Commit(context.Context, BlobID) error {
+	return nil
+}
+
+func (l *loBlobStore) Open(ctx context.Context, id BlobID) (io.ReadSeekCloser, error) {
+	return l.open(ctx, id, pgx.LargeObjectModeRead)
+}
+
+func (l *loBlobStore) Delete(ctx context.Context, id BlobID) error {
+	oid, err := parseLoBlobID(id)
+	if err != nil {
+		return err
+	}
+
+	err = pgx.BeginFunc(ctx, l.dbConn, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, _untrackLargeObject, oid); err != nil {
+			return err
+		}
+		lo := tx.LargeObjects()
+		return lo.Unlink(ctx, oid)
+	})
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == _pgUndefinedObject {
+		// A large object removed behind the store is not tracked anymore.
+		if _, err := l.dbConn.Exec(ctx, _untrackLargeObject, oid); err != nil {
+			return err
+		}
+		return ErrBlobNotFound
+	}
+	return err
+}
+
+// Walk lists large objects created by the store.
+func (l *loBlobStore) Walk(ctx context.Context, fn func(id BlobID, modTime time.Time) error) error {
+	rows, err := l.dbConn.Query(ctx, _listLargeObjects)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	modTimes := make(map[uint32]time.Time)
+	for rows.Next() {
+		var (
+			oid     uint32
+			modTime time.Time
+		)
+		if err := rows.Scan(&oid, &modTime); err != nil {
+			return err
+		}
+		modTimes[oid] = modTime
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	rows.Close()
+
+	for oid, modTime := range modTimes {
+		if err := fn(loBlobID(oid), modTime); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *loBlobStore) open(ctx context.Context, id BlobID, mode pgx.LargeObjectMode) (*loBlob, error) {
+	oid, err := parseLoBlobID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := l.dbConn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	lo := tx.LargeObjects()
+	obj, err := lo.Open(ctx, oid, mode)
+	if err != nil {
+		if e := tx.Rollback(ctx); e != nil {
+			err = multierror.Append(err, e)
+		}
+		return nil, err
+	}
+
+	return &loBlob{
+		LargeObject: obj,
+		ctx:         ctx,
+		tx:          tx,
+	}, nil
+}
+
+// loBlob is a large object opened within its own transaction.
+type loBlob struct {
+	*pgx.LargeObject
+
+	ctx context.Context
+	tx  pgx.Tx
+}
+
+func (b *loBlob) Close() error {
+	return b.tx.Commit(b.ctx)
+}
+
+func (b *loBlob) rollback(err error) error {
+	if e := b.tx.Rollback(b.ctx); e != nil {
+		err = multierror.Append(err, e)
+	}
+	return err
+}
+
+func loBlobID(oid uint32) BlobID {
+	return BlobID(strconv.FormatUint(uint64(oid), 10))
+}
+
+func parseLoBlobID(id BlobID) (uint32, error) {
+	oid, err := strconv.ParseUint(string(id), 10, 32)
+	if err != nil || oid == uint64(_emptyOID) {
+		return 0, fmt.Errorf("bad blob id %q", id)
+	}
+	return uint32(oid), nil
+}
@@ -0,0 +1,682 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+var (
+	_ BlobStore = (*s3BlobStore)(nil)
+	_ Pinger    = (*s3BlobStore)(nil)
+)
+
+const (
+	_s3DefaultRegion   = "us-east-1"
+	_s3MinPartSize     = 5 * 1024 * 1024
+	_s3DefaultPartSize = 8 * 1024 * 1024
+	_s3MaxCopySize     = 5 * 1024 * 1024 * 1024
+	_s3MaxDeleteKeys   = 1000
+	_s3BlobsDir        = "blobs/"
+	_s3StagingDir      = "staging/"
+	_s3StagingMarker   = "created"
+	_s3SegmentFormat   = "%020d"
+)
+
+// S3Config describes a bucket of an S3 compatible object storage.
+type S3Config struct {
+	// Endpoint overrides the AWS endpoint, e.g. for MinIO or Ceph.
+	Endpoint string
+	Region   string
+	Bucket   string
+	// Prefix is prepended to every object key, so a bucket can be shared.
+	Prefix string
+	// AccessKeyID and SecretAccessKey are static credentials. If they are
+	// empty, the default AWS credentials chain is used.
+	AccessKeyID     string
+	SecretAccessKey string
+	// PathStyle puts a bucket name into a path instead of a host name,
+	// as most self-hosted S3 servers expect.
+	PathStyle bool
+	// PartSize is a size of multipart upload parts. Zero means a default size.
+	PartSize int
+}
+
+// s3BlobStore keeps blobs in objects of an S3 bucket. Objects can not be
+// appended to, so an uncommitted blob is a set of staging segments, one per
+// writer, named after an offset they start from. A commit concatenates the
+// segments into a single object. Large segments are sent as multipart
+// uploads; uploads interrupted by a crash are left to a bucket lifecycle rule.
+type s3BlobStore struct {
+	client   *s3.S3
+	bucket   string
+	prefix   string
+	partSize int
+}
+
+func NewS3BlobStore(cfg S3Config) (*s3BlobStore, error) {
+	if cfg.Bucket == "" {
+		return nil, errors.New("s3 bucket must be specified")
+	}
+
+	partSize := cfg.PartSize
+	if partSize == 0 {
+		partSize = _s3DefaultPartSize
+	}
+	if partSize < _s3MinPartSize {
+		return nil, fmt.Errorf("s3 part size must be at least %d bytes", _s3MinPartSize)
+	}
+
+	region := cfg.Region
+	if region == "" {
+		region = _s3DefaultRegion
+	}
+
+	awsCfg := aws.NewConfig().
+		WithRegion(region).
+		WithS3ForcePathStyle(cfg.PathStyle)
+	if cfg.Endpoint != "" {
+		awsCfg = awsCfg.WithEndpoint(cfg.Endpoint)
+	}
+	if cfg.AccessKeyID != "" {
+		awsCfg = awsCfg.WithCredentials(credentials.NewStaticCredentials(cfg.AccessKeyID, cfg.SecretAccessKey, ""))
+	}
+
+	sess, err := session.NewSession(awsCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := cfg.Prefix
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	return &s3BlobStore{
+		client:   s3.New(sess),
+		bucket:   cfg.Bucket,
+		prefix:   prefix,
+		partSize: partSize,
+	}, nil
+}
+
+// Ping checks that the bucket exists and is accessible.
+func (s *s3BlobStore) Ping(ctx context.Context) error {
+	_, err := s.client.HeadBucketWithContext(ctx, &s3.HeadBucketInput{Bucket: aws.String(s.bucket)})
+	return err
+}
+
+func (s *s3BlobStore) Create(ctx context.Context) (BlobID, error) {
+	raw := make([]byte, _fsBlobIDSize)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+
+	id := BlobID(hex.EncodeToString(raw))
+	_, err := s.client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.stagingKey(id) + _s3StagingMarker),
+		Body:   bytes.NewReader(nil),
+	})
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (s *s3BlobStore) NewWriter(ctx context.Context, id BlobID, offset uint64) (io.WriteCloser, error) {
+	if err := validateFileBlobID(id); err != nil {
+		return nil, err
+	}
+
+	staged, _, err := s.listStaging(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !staged {
+		return nil, ErrBlobNotFound
+	}
+
+	return &s3BlobWriter{
+		store:  s,
+		ctx:    ctx,
+		id:     id,
+		offset: offset,
+		object: s.newObjectWriter(ctx, s.segmentKey(id, offset)),
+	}, nil
+}
+
+func (s *s3BlobStore) Commit(ctx context.Context, id BlobID) error {
+	if err := validateFileBlobID(id); err != nil {
+		return err
+	}
+
+	staged, segments, err := s.listStaging(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	_, committed, err := s.stat(ctx, s.blobKey(id))
+	if err != nil {
+		return err
+	}
+
+	if !staged {
+		if committed {
+			return nil
+		}
+		return ErrBlobNotFound
+	}
+
+	// The staging segments outlive the blob object only if a previous commit
+	// was interrupted, and then they just have to be removed.
+	if !committed {
+		if err := s.concat(ctx, s.blobKey(id), chainSegments(segments)); err != nil {
+			return err
+		}
+	}
+
+	keys := []string{s.stagingKey(id) + _s3StagingMarker}
+	for _, seg := range segments {
+		keys = append(keys, seg.key)
+	}
+	return s.deleteKeys(ctx, keys)
+}
+
+func (s *s3BlobStore) Open(ctx context.Context, id BlobID) (io.ReadSeekCloser, error) {
+	if err := validateFileBlobID(id); err != nil {
+		return nil, err
+	}
+
+	size, committed, err := s.stat(ctx, s.blobKey(id))
+	if err != nil {
+		return nil, err
+	}
+	if committed {
+		return newS3Reader(ctx, s, []s3Segment{{key: s.blobKey(id), size: size}}), nil
+	}
+
+	staged, segments, err := s.listStaging(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !staged {
+		return nil, ErrBlobNotFound
+	}
+	return newS3Reader(ctx, s, chainSegments(segments)), nil
+}
+
+func (s *s3BlobStore) Delete(ctx context.Context, id BlobID) error {
+	if err := validateFileBlobID(id); err != nil {
+		return err
+	}
+
+	staged, segments, err := s.listStaging(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	_, committed, err := s.stat(ctx, s.blobKey(id))
+	if err != nil {
+		return err
+	}
+
+	if !staged && !committed {
+		return ErrBlobNotFound
+	}
+
+	keys := []string{s.blobKey(id), s.stagingKey(id) + _s3StagingMarker}
+	for _, seg := range segments {
+		keys = append(keys, seg.key)
+	}
+	return s.deleteKeys(ctx, keys)
+}
+
+func (s *s3BlobStore) Walk(ctx context.Context, fn func(id BlobID, modTime time.Time) error) error {
+	var walkErr error
+	visit := func(prefix, suffix string) error {
+		err := s.client.ListObjectsV2PagesWithContext(ctx, &s3.ListObjectsV2Input{
+			Bucket: aws.String(s.bucket),
+			Prefix: aws.String(prefix),
+		}, func(page *s3.ListObjectsV2Output, _ bool) bool {
+			for _, obj := range page.Contents {
+				name := strings.TrimPrefix(aws.StringValue(obj.Key), prefix)
+				if !strings.HasSuffix(name, suffix) {
+					continue
+				}
+
+				id := BlobID(strings.TrimSuffix(name, suffix))
+				if validateFileBlobID(id) != nil {
+					continue
+				}
+				if walkErr = fn(id, aws.TimeValue(obj.LastModified)); walkErr != nil {
+					return false
+				}
+			}
+			return true
+		})
+		if err != nil {
+			return err
+		}
+		return walkErr
+	}
+
+	// An uncommitted blob is reported once by its staging marker.
+	if err := visit(s.prefix+_s3BlobsDir, ""); err != nil {
+		return err
+	}
+	return visit(s.prefix+_s3StagingDir, "/"+_s3StagingMarker)
+}
+
+func (s *s3BlobStore) blobKey(id BlobID) string {
+	return s.prefix + _s3BlobsDir + string(id)
+}
+
+func (s *s3BlobStore) stagingKey(id BlobID) string {
+	return s.prefix + _s3StagingDir + string(id) + "/"
+}
+
+func (s *s3BlobStore) segmentKey(id BlobID, offset uint64) string {
+	return s.stagingKey(id) + fmt.Sprintf(_s3SegmentFormat, offset)
+}
+
+// stat returns a size of an object and whether it exists.
+func (s *s3BlobStore) stat(ctx context.Context, key string) (int64, bool, error) {
+	head, err := s.client.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if isS3NotFound(err) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return aws.Int64Value(head.ContentLength), true, nil
+}
+
+// listStaging returns staging segments of a blob and whether the blob
+// has been created and not committed yet.
+func (s *s3BlobStore) listStaging(ctx context.Context, id BlobID) (bool, []s3Segment, error) {
+	var (
+		staged   bool
+		segments []s3Segment
+	)
+
+	prefix := s.stagingKey(id)
+	err := s.client.ListObjectsV2PagesWithContext(ctx, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(prefix),
+	}, func(page *s3.ListObjectsV2Output, _ bool) bool {
+		for _, obj := range page.Contents {
+			name := strings.TrimPrefix(aws.StringValue(obj.Key), prefix)
+			if name == _s3StagingMarker {
+				staged = true
+				continue
+			}
+
+			offset, err := strconv.ParseUint(name, 10, 64)
+			if err != nil {
+				continue
+			}
+			segments = append(segments, s3Segment{
+				key:    aws.StringValue(obj.Key),
+				offset: int64(offset),
+				size:   aws.Int64Value(obj.Size),
+			})
+		}
+		return true
+	})
+	if err != nil {
+		return false, nil, err
+	}
+
+	return staged, segments, nil
+}
+
+// concat writes segments into a single object. A single segment is copied
+// on the server side without downloading it.
+func (s *s3BlobStore) concat(ctx context.Context, key string, segments []s3Segment) error {
+	if len(segments) == 1 && segments[0].size <= _s3MaxCopySize {
+		_, err := s.client.CopyObjectWithContext(ctx, &s3.CopyObjectInput{
+			Bucket:     aws.String(s.bucket),
+			Key:        aws.String(key),
+			CopySource: aws.String(s.bucket + "/" + segments[0].key),
+		})
+		return err
+	}
+
+	w := s.newObjectWriter(ctx, key)
+	if _, err := io.Copy(w, newS3Reader(ctx, s, segments)); err != nil {
+		w.abort()
+		return err
+	}
+	return w.Close()
+}
+
+func (s *s3BlobStore) deleteKeys(ctx context.Context, keys []string) error {
+	for len(keys) > 0 {
+		batch := keys
+		if len(batch) > _s3MaxDeleteKeys {
+			batch = batch[:_s3MaxDeleteKeys]
+		}
+		keys = keys[len(batch):]
+
+		objects := make([]*s3.ObjectIdentifier, 0, len(batch))
+		for _, key := range batch {
+			objects = append(objects, &s3.ObjectIdentifier{Key: aws.String(key)})
+		}
+
+		out, err := s.client.DeleteObjectsWithContext(ctx, &s3.DeleteObjectsInput{
+			Bucket: aws.String(s.bucket),
+			Delete: &s3.Delete{Objects: objects, Quiet: aws.Bool(true)},
+		})
+		if err != nil {
+			return err
+		}
+		if len(out.Errors) != 0 {
+			return fmt.Errorf("failed to delete %s: %s",
+				aws.StringValue(out.Errors[0].Key), aws.StringValue(out.Errors[0].Message))
+		}
+	}
+	return nil
+}
+
+func isS3NotFound(err error) bool {
+	var reqErr awserr.RequestFailure
+	return errors.As(err, &reqErr) && reqErr.StatusCode() == 404
+}
+
+type s3Segment struct {
+	key    string
+	offset int64
+	size   int64
+}
+
+// chainSegments orders segments into a blob. Every writer starts where
+// the previous one has finished, so segments that do not continue
+// the chain are leftovers of failed writes.
+func chainSegments(segments []s3Segment) []s3Segment {
+	byOffset := make(map[int64]s3Segment, len(segments))
+	for _, seg := range segments {
+		byOffset[seg.offset] = seg
+	}
+
+	chain := make([]s3Segment, 0, len(segments))
+	var pos int64
+	for {
+		seg, ok := byOffset[pos]
+		if !ok || seg.size == 0 {
+			return chain
+		}
+		chain = append(chain, seg)
+		pos += seg.size
+	}
+}
+
+// s3BlobWriter writes a staging segment of a blob. Segments starting past
+// the writer offset are dropped once the segment is stored, because
+// the blob is rewritten from there.
+type s3BlobWriter struct {
+	store   *s3BlobStore
+	ctx     context.Context
+	id      BlobID
+	offset  uint64
+	written uint64
+	object  *s3ObjectWriter
+}
+
+func (w *s3BlobWriter) Write(p []byte) (int, error) {
+	n, err := w.object.Write(p)
+	w.written += uint64(n)
+	return n, err
+}
+
+func (w *s3BlobWriter) Close() error {
+	if w.written == 0 {
+		w.object.abort()
+	} else if err := w.object.Close(); err != nil {
+		return err
+	}
+
+	_, segments, err := w.store.listStaging(w.ctx, w.id)
+	if err != nil {
+		return err
+	}
+
+	stale := make([]string, 0)
+	for _, seg := range segments {
+		if seg.offset > int64(w.offset) || (seg.offset == int64(w.offset) && w.written == 0) {
+			stale = append(stale, seg.key)
+		}
+	}
+	return w.store.deleteKeys(w.ctx, stale)
+}
+
+// s3ObjectWriter buffers data into parts of a multipart upload.
+// Data smaller than a single part is put as a regular object.
+type s3ObjectWriter struct {
+	store    *s3BlobStore
+	ctx      context.Context
+	key      string
+	buf      []byte
+	uploadID *string
+	parts    []*s3.CompletedPart
+	err      error
+}
+
+func (s *s3BlobStore) newObjectWriter(ctx context.Context, key string) *s3ObjectWriter {
+	return &s3ObjectWriter{store: s, ctx: ctx, key: key}
+}
+
+func (w *s3ObjectWriter) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+
+	w.buf = append(w.buf, p...)
+	for len(w.buf) >= w.store.partSize {
+		if w.err = w.uploadPart(w.buf[:w.store.partSize]); w.err != nil {
+			return 0, w.err
+		}
+		w.buf = append(w.buf[:0], w.buf[w.store.partSize:]...)
+	}
+	return len(p), nil
+}
+
+func (w *s3ObjectWriter) Close() error {
+	if w.err != nil {
+		w.abort()
+		return w.err
+	}
+
+	client := w.store.client
+	if w.uploadID == nil {
+		_, err := client.PutObjectWithContext(w.ctx, &s3.PutObjectInput{
+			Bucket: aws.String(w.store.bucket),
+			Key:    aws.String(w.key),
+			Body:   bytes.NewReader(w.buf),
+		})
+		return err
+	}
+
+	if len(w.buf) != 0 {
+		if err := w.uploadPart(w.buf); err != nil {
+			w.abort()
+			return err
+		}
+	}
+
+	_, err := client.CompleteMultipartUploadWithContext(w.ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(w.store.bucket),
+		Key:             aws.String(w.key),
+		UploadId:        w.uploadID,
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: w.parts},
+	})
+	if err != nil {
+		w.abort()
+	}
+	return err
+}
+
+func (w *s3ObjectWriter) uploadPart(data []byte) error {
+	client := w.store.client
+	if w.uploadID == nil {
+		out, err := client.CreateMultipartUploadWithContext(w.ctx, &s3.CreateMultipartUploadInput{
+			Bucket: aws.String(w.store.bucket),
+			Key:    aws.String(w.key),
+		})
+		if err != nil {
+			return err
+		}
+		w.uploadID = out.UploadId
+	}
+
+	partNumber := aws.Int64(int64(len(w.parts) + 1))
+	out, err := client.UploadPartWithContext(w.ctx, &s3.UploadPartInput{
+		Bucket:     aws.String(w.store.bucket),
+		Key:        aws.String(w.key),
+		UploadId:   w.uploadID,
+		PartNumber: partNumber,
+		Body:       bytes.NewReader(data),
+	})
+	if err != nil {
+		return err
+	}
+
+	w.parts = append(w.parts, &s3.CompletedPart{ETag: out.ETag, PartNumber: partNumber})
+	return nil
+}
+
+// abort drops a multipart upload. It uses a fresh context,
+// since it is usually called when the original one is cancelled.
+func (w *s3ObjectWriter) abort() {
+	if w.uploadID == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), _cleanupTimeout)
+	defer cancel()
+
+	_, _ = w.store.client.AbortMultipartUploadWithContext(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(w.store.bucket),
+		Key:      aws.String(w.key),
+		UploadId: w.uploadID,
+	})
+	w.uploadID = nil
+}
+
+// s3Reader reads a sequence of objects as a single stream. Every object
+// is fetched with a ranged GET starting from a current position, so seeking
+// does not download skipped data.
+type s3Reader struct {
+	store    *s3BlobStore
+	ctx      context.Context
+	segments []s3Segment
+	size     int64
+	pos      int64
+	body     io.ReadCloser
+}
+
+func newS3Reader(ctx context.Context, s *s3BlobStore, segments []s3Segment) *s3Reader {
+	r := &s3Reader{store: s, ctx: ctx}
+	for _, seg := range segments {
+		seg.offset = r.size
+		r.segments = append(r.segments, seg)
+		r.size += seg.size
+	}
+	return r
+}
+
+func (r *s3Reader) Read(p []byte) (int, error) {
+	for {
+		if r.pos >= r.size {
+			return 0, io.EOF
+		}
+
+		if r.body == nil {
+			if err := r.openBody(); err != nil {
+				return 0, err
+			}
+		}
+
+		n, err := r.body.Read(p)
+		r.pos += int64(n)
+		if err == io.EOF {
+			_ = r.body.Close()
+			r.body = nil
+			err = nil
+		}
+		if n != 0 || err != nil {
+			return n, err
+		}
+	}
+}
+
+func (r *s3Reader) openBody() error {
+	for _, seg := range r.segments {
+		if r.pos >= seg.offset+seg.size {
+			continue
+		}
+
+		out, err := r.store.client.GetObjectWithContext(r.ctx, &s3.GetObjectInput{
+			Bucket: aws.String(r.store.bucket),
+			Key:    aws.String(seg.key),
+			Range:  aws.String(fmt.Sprintf("bytes=%d-%d", r.pos-seg.offset, seg.size-1)),
+		})
+		if isS3NotFound(err) {
+			return ErrBlobNotFound
+		}
+		if err != nil {
+			return err
+		}
+		r.body = out.Body
+		return nil
+	}
+	return io.ErrUnexpectedEOF
+}
+
+func (r *s3Reader) Seek(offset int64, whence int) (int64, error) {
+	pos := offset
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		pos += r.pos
+	case io.SeekEnd:
+		pos += r.size
+	default:
+		return 0, errors.New("invalid whence")
+	}
+	if pos < 0 {
+		return 0, errors.New("negative position")
+	}
+
+	if pos != r.pos && r.body != nil {
+		_ = r.body.Close()
+		r.body = nil
+	}
+	r.pos = pos
+	return pos, nil
+}
+
+func (r *s3Reader) Close() error {
+	if r.body == nil {
+		return nil
+	}
+	err := r.body.Close()
+	r.body = nil
+	return err
+}
@@ -0,0 +1,190 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/johannesboyne/gofakes3"
+	"github.com/johannesboyne/gofakes3/backend/s3mem"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestS3BlobStore(t *testing.T) *s3BlobStore {
+	srv := httptest.NewServer(gofakes3.New(s3mem.New()).Server())
+	t.Cleanup(srv.Close)
+
+	store, err := NewS3BlobStore(S3Config{
+		Endpoint:        srv.URL,
+		Bucket:          "blobs",
+		Prefix:          "keeper",
+		AccessKeyID:     "key",
+		SecretAccessKey: "secret",
+		PathStyle:       true,
+		PartSize:        _s3MinPartSize,
+	})
+	assert.NoError(t, err)
+
+	_, err = store.client.CreateBucket(&s3.CreateBucketInput{Bucket: aws.String("blobs")})
+	assert.NoError(t, err)
+	return store
+}
+
+func TestS3BlobStore(t *testing.T) {
+	ctx := context.Background()
+	store := newTestS3BlobStore(t)
+
+	large := make([]byte, _s3MinPartSize+1024)
+	_, err := rand.Read(large)
+	assert.NoError(t, err)
+
+	id, err := store.Create(ctx)
+	assert.NoError(t, err)
+
+	w, err := store.NewWriter(ctx, id, 0)
+	assert.NoError(t, err)
+	_, err = w.Write([]byte("hello, "))
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+
+	// A failed write left data which is rewritten by the next writer.
+	w, err = store.NewWriter(ctx, id, 7)
+	assert.NoError(t, err)
+	_, err = w.Write([]byte("stale data"))
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+
+	w, err = store.NewWriter(ctx, id, 7)
+	assert.NoError(t, err)
+	_, err = w.Write([]byte("world"))
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+
+	// The segment is larger than a part, so it is sent as a multipart upload.
+	w, err = store.NewWriter(ctx, id, 12)
+	assert.NoError(t, err)
+	_, err = w.Write(large)
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+
+	expected := append([]byte("hello, world"), large...)
+	assert.Equal(t, expected, readAllBlob(t, store, id))
+
+	assert.NoError(t, store.Commit(ctx, id))
+	assert.NoError(t, store.Commit(ctx, id))
+	assert.Equal(t, []string{"keeper/blobs/" + string(id)}, listKeys(t, store))
+	assert.Equal(t, expected, readAllBlob(t, store, id))
+
+	_, err = store.NewWriter(ctx, id, 0)
+	assert.ErrorIs(t, err, ErrBlobNotFound)
+
+	r, err := store.Open(ctx, id)
+	assert.NoError(t, err)
+	_, err = r.Seek(7, io.SeekStart)
+	assert.NoError(t, err)
+	head := make([]byte, 5)
+	_, err = io.ReadFull(r, head)
+	assert.NoError(t, err)
+	assert.Equal(t, "world", string(head))
+	size, err := r.Seek(0, io.SeekEnd)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(expected)), size)
+	assert.NoError(t, r.Close())
+
+	assert.NoError(t, store.Delete(ctx, id))
+	assert.Empty(t, listKeys(t, store))
+	assert.ErrorIs(t, store.Delete(ctx, id), ErrBlobNotFound)
+
+	_, err = store.Open(ctx, id)
+	assert.ErrorIs(t, err, ErrBlobNotFound)
+	assert.ErrorIs(t, store.Commit(ctx, id), ErrBlobNotFound)
+}
+
+func TestS3BlobStore_SeekAcrossSegments(t *testing.T) {
+	ctx := context.Background()
+	store := newTestS3BlobStore(t)
+
+	id, err := store.Create(ctx)
+	assert.NoError(t, err)
+
+	var offset uint64
+	for _, part := range []string{"abc", "def", "ghi"} {
+		w, err := store.NewWriter(ctx, id, offset)
+		assert.NoError(t, err)
+		_, err = w.Write([]byte(part))
+		assert.NoError(t, err)
+		assert.NoError(t, w.Close())
+		offset += uint64(len(part))
+	}
+
+	r, err := store.Open(ctx, id)
+	assert.NoError(t, err)
+	_, err = r.Seek(2, io.SeekStart)
+	assert.NoError(t, err)
+	data, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "cdefghi", string(data))
+	assert.NoError(t, r.Close())
+
+	assert.NoError(t, store.Commit(ctx, id))
+	assert.Equal(t, "abcdefghi", string(readAllBlob(t, store, id)))
+	assert.NoError(t, store.Delete(ctx, id))
+}
+
+func TestS3BlobStore_BadID(t *testing.T) {
+	ctx := context.Background()
+	store := newTestS3BlobStore(t)
+
+	for _, id := range []BlobID{"", "../secret", "0123"} {
+		_, err := store.Open(ctx, id)
+		assert.Error(t, err)
+		_, err = store.NewWriter(ctx, id, 0)
+		assert.Error(t, err)
+		assert.Error(t, store.Delete(ctx, id))
+		assert.Error(t, store.Commit(ctx, id))
+	}
+
+	_, err := NewS3BlobStore(S3Config{})
+	assert.Error(t, err)
+	_, err = NewS3BlobStore(S3Config{Bucket: "blobs", PartSize: 1024})
+	assert.Error(t, err)
+}
+
+func readAllBlob(t *testing.T, store BlobStore, id BlobID) []byte {
+	r, err := store.Open(context.Background(), id)
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, r.Close())
+	}()
+
+	buf := bytes.Buffer{}
+	_, err = io.Copy(&buf, r)
+	assert.NoError(t, err)
+	return buf.Bytes()
+}
+
+func listKeys(t *testing.T, store *s3BlobStore) []string {
+	out, err := store.client.ListObjectsV2(&s3.ListObjectsV2Input{Bucket: aws.String(store.bucket)})
+	assert.NoError(t, err)
+
+	keys := make([]string, 0, len(out.Contents))
+	for _, obj := range out.Contents {
+		assert.True(t, strings.HasPrefix(aws.StringValue(obj.Key), "keeper/"))
+		keys = append(keys, aws.StringValue(obj.Key))
+	}
+	return keys
+}
+
+func TestS3BlobStore_Ping(t *testing.T) {
+	store := newTestS3BlobStore(t)
+	assert.NoError(t, store.Ping(context.Background()))
+
+	store.bucket = "missing"
+	assert.Error(t, store.Ping(context.Background()))
+}
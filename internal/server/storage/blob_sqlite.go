@@ -0,0 +1,293 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+var (
+	_ BlobStore = (*sqliteBlobStore)(nil)
+	_ Pinger    = (*sqliteBlobStore)(nil)
+)
+
+const (
+	_sqliteBlobIDSize   = 16
+	_sqliteBlobPartSize = 1024 * 1024
+
+	_sqliteCreateBlob   = `insert into blobs (id, last_update) values (?, ?);`
+	_sqliteTouchBlob    = `update blobs set last_update=? where id=?;`
+	_sqliteBlobSize     = `select coalesce((select sum(length(data)) from blob_parts where blob_id=b.id), 0) from blobs b where b.id=?;`
+	_sqliteTruncateBlob = `delete from blob_parts where blob_id=? and pos>=?;`
+	_sqliteTrimBlobPart = `update blob_parts set data=substr(data, 1, ?1-pos) where blob_id=?2 and pos<?1 and pos+length(data)>?1;`
+	_sqliteAddBlobPart  = `insert into blob_parts (blob_id, pos, data) values (?, ?, ?);`
+	_sqliteGetBlobPart  = `select pos, data from blob_parts where blob_id=? and pos<=? order by pos desc limit 1;`
+	_sqliteDeleteBlob   = `delete from blobs where id=?;`
+	_sqliteListBlobs    = `select id, last_update from blobs;`
+)
+
+// sqliteBlobStore keeps blobs in the database of a SQLite storage. A blob is
+// split into parts, so appending to it doesn't rewrite the stored data.
+// Like large objects, blobs have no commit state.
+type sqliteBlobStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteBlobStore creates a blob store sharing a database with s.
+func NewSQLiteBlobStore(s *sqliteStorage) *sqliteBlobStore {
+	return &sqliteBlobStore{db: s.db}
+}
+
+// Ping checks the database keeping blobs.
+func (b *sqliteBlobStore) Ping(ctx context.Context) error {
+	return b.db.PingContext(ctx)
+}
+
+func (b *sqliteBlobStore) Create(ctx context.Context) (BlobID, error) {
+	raw := make([]byte, _sqliteBlobIDSize)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+
+	id := BlobID(hex.EncodeToString(raw))
+	if _, err := b.db.ExecContext(ctx, _sqliteCreateBlob, string(id), time.Now().UnixMicro()); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// NewWriter drops data of a blob past offset, so the writer appends to it.
+func (b *sqliteBlobStore) NewWriter(ctx context.Context, id BlobID, offset uint64) (io.WriteCloser, error) {
+	tx, err := b.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	size, err := blobSize(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if offset > size {
+		return nil, fmt.Errorf("offset %d is past the end of blob %s", offset, id)
+	}
+
+	if _, err := tx.ExecContext(ctx, _sqliteTruncateBlob, string(id), int64(offset)); err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx, _sqliteTrimBlobPart, int64(offset), string(id)); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &sqliteBlobWriter{
+		ctx: ctx,
+		db:  b.db,
+		id:  id,
+		pos: offset,
+	}, nil
+}
+
+func (b *sqliteBlobStore) Commit(context.Context, BlobID) error {
+	return nil
+}
+
+func (b *sqliteBlobStore) Open(ctx context.Context, id BlobID) (io.ReadSeekCloser, error) {
+	size, err := blobSize(ctx, b.db, id)
+	if err != nil {
+		return nil, err
+	}
+	return &sqliteBlobReader{
+		ctx:  ctx,
+		db:   b.db,
+		id:   id,
+		size: int64(size),
+	}, nil
+}
+
+func (b *sqliteBlobStore) Delete(ctx context.Context, id BlobID) error {
+	res, err := b.db.ExecContext(ctx, _sqliteDeleteBlob, string(id))
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrBlobNotFound
+	}
+	return nil
+}
+
+// Walk reads the whole list of blobs first, as fn may query the same database.
+func (b *sqliteBlobStore) Walk(ctx context.Context, fn func(id BlobID, modTime time.Time) error) error {
+	rows, err := b.db.QueryContext(ctx, _sqliteListBlobs)
+	if err != nil {
+		return err
+	}
+
+	type blob struct {
+		id      BlobID
+		modTime time.Time
+	}
+	blobs := make([]blob, 0)
+	for rows.Next() {
+		var (
+			id      string
+			updated int64
+		)
+		if err := rows.Scan(&id, &updated); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		blobs = append(blobs, blob{id: BlobID(id), modTime: time.UnixMicro(updated)})
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, b := range blobs {
+		if err := fn(b.id, b.modTime); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func blobSize(ctx context.Context, q sqliteQuerier, id BlobID) (uint64, error) {
+	var size int64
+	if err := q.QueryRowContext(ctx, _sqliteBlobSize, string(id)).Scan(&size); err != nil {
+		return 0, noRows(err, ErrBlobNotFound)
+	}
+	return uint64(size), nil
+}
+
+// sqliteBlobWriter buffers data up to a part size. Every full part is stored
+// right away, the rest is stored on Close.
+type sqliteBlobWriter struct {
+	ctx context.Context
+	db  *sql.DB
+	id  BlobID
+	pos uint64
+	buf []byte
+}
+
+func (w *sqliteBlobWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) != 0 {
+		n := _sqliteBlobPartSize - len(w.buf)
+		if n > len(p) {
+			n = len(p)
+		}
+		w.buf = append(w.buf, p[:n]...)
+		p = p[n:]
+		written += n
+
+		if len(w.buf) == _sqliteBlobPartSize {
+			if err := w.flush(); err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, nil
+}
+
+func (w *sqliteBlobWriter) Close() error {
+	return w.flush()
+}
+
+func (w *sqliteBlobWriter) flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+
+	tx, err := w.db.BeginTx(w.ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if _, err := tx.ExecContext(w.ctx, _sqliteAddBlobPart, string(w.id), int64(w.pos), w.buf); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(w.ctx, _sqliteTouchBlob, time.Now().UnixMicro(), string(w.id)); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	w.pos += uint64(len(w.buf))
+	w.buf = w.buf[:0]
+	return nil
+}
+
+// sqliteBlobReader reads a blob a part at a time. It keeps no query open
+// between reads, so the database stays available to others.
+type sqliteBlobReader struct {
+	ctx  context.Context
+	db   *sql.DB
+	id   BlobID
+	size int64
+	pos  int64
+
+	partPos int64
+	part    []byte
+}
+
+func (r *sqliteBlobReader) Read(p []byte) (int, error) {
+	if r.pos >= r.size {
+		return 0, io.EOF
+	}
+
+	if r.pos < r.partPos || r.pos >= r.partPos+int64(len(r.part)) {
+		err := r.db.QueryRowContext(r.ctx, _sqliteGetBlobPart, string(r.id), r.pos).Scan(&r.partPos, &r.part)
+		if err != nil {
+			r.part = nil
+			return 0, noRows(err, ErrBlobNotFound)
+		}
+		if r.pos >= r.partPos+int64(len(r.part)) {
+			r.part = nil
+			return 0, io.ErrUnexpectedEOF
+		}
+	}
+
+	n := copy(p, r.part[r.pos-r.partPos:])
+	r.pos += int64(n)
+	return n, nil
+}
+
+func (r *sqliteBlobReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += r.pos
+	case io.SeekEnd:
+		offset += r.size
+	default:
+		return 0, errors.New("invalid whence")
+	}
+	if offset < 0 {
+		return 0, errors.New("negative position")
+	}
+	r.pos = offset
+	return offset, nil
+}
+
+func (r *sqliteBlobReader) Close() error {
+	r.part = nil
+	return nil
+}
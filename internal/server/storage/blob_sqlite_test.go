@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSQLiteBlobStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewSQLiteBlobStore(newTestSQLiteStorage(t))
+
+	id, err := store.Create(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "", readBlob(t, store, id))
+
+	w, err := store.NewWriter(ctx, id, 0)
+	assert.NoError(t, err)
+	_, err = w.Write([]byte("hello, "))
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+
+	w, err = store.NewWriter(ctx, id, 7)
+	assert.NoError(t, err)
+	_, err = w.Write([]byte("world"))
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+	assert.Equal(t, "hello, world", readBlob(t, store, id))
+
+	// Writing at an offset drops data written past it, e.g. by a failed append.
+	w, err = store.NewWriter(ctx, id, 5)
+	assert.NoError(t, err)
+	_, err = w.Write([]byte("!"))
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+	assert.Equal(t, "hello!", readBlob(t, store, id))
+
+	_, err = store.NewWriter(ctx, id, 7)
+	assert.Error(t, err)
+
+	assert.NoError(t, store.Commit(ctx, id))
+
+	walked := make([]BlobID, 0)
+	assert.NoError(t, store.Walk(ctx, func(id BlobID, modTime time.Time) error {
+		walked = append(walked, id)
+		assert.WithinDuration(t, time.Now(), modTime, time.Minute)
+		return nil
+	}))
+	assert.Equal(t, []BlobID{id}, walked)
+
+	assert.NoError(t, store.Delete(ctx, id))
+	assert.ErrorIs(t, store.Delete(ctx, id), ErrBlobNotFound)
+
+	_, err = store.Open(ctx, id)
+	assert.ErrorIs(t, err, ErrBlobNotFound)
+	_, err = store.NewWriter(ctx, id, 0)
+	assert.ErrorIs(t, err, ErrBlobNotFound)
+}
+
+func TestSQLiteBlobStore_Parts(t *testing.T) {
+	ctx := context.Background()
+	store := NewSQLiteBlobStore(newTestSQLiteStorage(t))
+
+	data := bytes.Repeat([]byte("0123456789"), _sqliteBlobPartSize/4)
+	id, err := store.Create(ctx)
+	assert.NoError(t, err)
+	w, err := store.NewWriter(ctx, id, 0)
+	assert.NoError(t, err)
+	_, err = w.Write(data)
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+
+	// Append in the middle of the last part.
+	w, err = store.NewWriter(ctx, id, uint64(len(data)-3))
+	assert.NoError(t, err)
+	_, err = w.Write([]byte("abc"))
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+	copy(data[len(data)-3:], "abc")
+
+	r, err := store.Open(ctx, id)
+	assert.NoError(t, err)
+	remote, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, data, remote)
+
+	// Seeking moves between parts.
+	_, err = r.Seek(_sqliteBlobPartSize-5, io.SeekStart)
+	assert.NoError(t, err)
+	remote, err = io.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, data[_sqliteBlobPartSize-5:], remote)
+	size, err := r.Seek(0, io.SeekEnd)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(data)), size)
+	assert.NoError(t, r.Close())
+}
+
+func TestSQLiteBlobStore_Ping(t *testing.T) {
+	s := newTestSQLiteStorage(t)
+	store := NewSQLiteBlobStore(s)
+	assert.NoError(t, store.Ping(context.Background()))
+
+	assert.NoError(t, s.Close())
+	assert.Error(t, store.Ping(context.Background()))
+}
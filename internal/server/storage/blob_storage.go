@@ -0,0 +1,439 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"hash"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/go-multierror"
+)
+
+var (
+	_ Storage          = (*blobStorage)(nil)
+	_ GarbageCollector = (*blobStorage)(nil)
+	_ Resource         = (*blobResource)(nil)
+)
+
+const (
+	_cleanupTimeout   = 5 * time.Second
+	_digestBufferSize = 1024 * 1024
+)
+
+// blobStorage implements Storage keeping resource data in a BlobStore and
+// everything else in a MetadataStore. A blob is committed before its resource
+// is added, so a failure in between may leave an unreferenced blob but never
+// a resource without data.
+type blobStorage struct {
+	meta  MetadataStore
+	blobs BlobStore
+
+	// orphans are blobs found unreferenced by the last garbage collection.
+	orphans map[BlobID]bool
+	gcMutex sync.Mutex
+}
+
+func NewBlobStorage(meta MetadataStore, blobs BlobStore) *blobStorage {
+	return &blobStorage{
+		meta:  meta,
+		blobs: blobs,
+	}
+}
+
+func (b *blobStorage) Create(ctx context.Context, user *UserID, meta *ResourceMeta) (Resource, error) {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return nil, err
+	}
+
+	blob, err := b.blobs.Create(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	writer, err := b.blobs.NewWriter(ctx, blob, 0)
+	if err != nil {
+		return nil, b.deleteBlob(blob, err)
+	}
+
+	return &blobResource{
+		ctx:     ctx,
+		storage: b,
+		user:    user,
+		id:      ResourceID(id),
+		meta:    meta,
+		blob:    blob,
+		writer:  writer,
+		hash:    sha256.New(),
+	}, nil
+}
+
+func (b *blobStorage) Open(ctx context.Context, user *UserID, id *ResourceID) (Resource, error) {
+	info, blob, err := b.meta.ResourceBlob(ctx, user, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var reader io.ReadSeekCloser
+	if blob == "" {
+		chunks, err := b.meta.ResourceChunks(ctx, user, id)
+		if err != nil {
+			return nil, err
+		}
+		reader = newChunkReader(ctx, b.blobs, chunks)
+	} else if reader, err = b.blobs.Open(ctx, blob); err != nil {
+		return nil, err
+	}
+
+	return &blobResource{
+		ctx:     ctx,
+		storage: b,
+		user:    user,
+		id:      *id,
+		meta: &ResourceMeta{
+			Salt:     info.Salt,
+			Metadata: info.Metadata,
+			Kind:     info.Kind,
+			ByteSize: info.ByteSize,
+			Digest:   info.Digest,
+		},
+		blob:   blob,
+		reader: reader,
+	}, nil
+}
+
+func (b *blobStorage) Delete(ctx context.Context, user *UserID, id *ResourceID) error {
+	return b.meta.Delete(ctx, user, id)
+}
+
+func (b *blobStorage) List(ctx context.Context, user *UserID, opts *ListOptions) ([]ResourceInfo, string, error) {
+	return b.meta.List(ctx, user, opts)
+}
+
+func (b *blobStorage) Stat(ctx context.Context, user *UserID, id *ResourceID) (*ResourceInfo, error) {
+	return b.meta.Stat(ctx, user, id)
+}
+
+func (b *blobStorage) CreateUpload(ctx context.Context, user *UserID, meta *ResourceMeta) (*UploadSession, error) {
+	blob, err := b.blobs.Create(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := b.meta.AddUpload(ctx, user, blob, meta)
+	if err != nil {
+		return nil, b.deleteBlob(blob, err)
+	}
+	return session, nil
+}
+
+func (b *blobStorage) GetUpload(ctx context.Context, user *UserID, id *UploadID) (*UploadSession, error) {
+	return b.meta.GetUpload(ctx, user, id)
+}
+
+// AppendUpload writes data before moving the committed offset, so the offset never
+// points past durable data. Concurrent appends to one session are not supported.
+func (b *blobStorage) AppendUpload(ctx context.Context, user *UserID, id *UploadID, offset uint64, data []byte) (*UploadSession, error) {
+	session, err := b.meta.GetUpload(ctx, user, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.Offset != offset {
+		return nil, ErrUploadOffsetMismatch
+	}
+
+	if offset+uint64(len(data)) > session.ByteSize {
+		return nil, ErrUploadTooLarge
+	}
+
+	writer, err := b.blobs.NewWriter(ctx, session.blob, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := writer.Write(data); err != nil {
+		if e := writer.Close(); e != nil {
+			err = multierror.Append(err, e)
+		}
+		return nil, err
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return b.meta.AdvanceUpload(ctx, user, id, offset, uint64(len(data)))
+}
+
+func (b *blobStorage) FinalizeUpload(ctx context.Context, user *UserID, id *UploadID) (*ResourceInfo, error) {
+	session, err := b.meta.GetUpload(ctx, user, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.Offset != session.ByteSize {
+		return nil, ErrUploadIncomplete
+	}
+
+	if err := b.verifyBlob(ctx, session.blob, &session.Meta); err != nil {
+		return nil, err
+	}
+
+	if err := b.blobs.Commit(ctx, session.blob); err != nil {
+		return nil, err
+	}
+
+	resourceID, err := uuid.NewRandom()
+	if err != nil {
+		return nil, err
+	}
+
+	rid := ResourceID(resourceID)
+	return b.meta.FinalizeUpload(ctx, user, id, &rid)
+}
+
+func (b *blobStorage) ExpireUploads(ctx context.Context, idleSince time.Time) (int, error) {
+	blobs, err := b.meta.ExpireUploads(ctx, idleSince)
+	if err != nil {
+		return 0, err
+	}
+
+	var result error
+	for _, blob := range blobs {
+		if err := b.blobs.Delete(ctx, blob); err != nil && !errors.Is(err, ErrBlobNotFound) {
+			result = multierror.Append(result, err)
+		}
+	}
+	return len(blobs), result
+}
+
+func (b *blobStorage) MissingChunks(ctx context.Context, user *UserID, digests [][]byte) ([][]byte, error) {
+	return b.meta.MissingChunks(ctx, user, digests)
+}
+
+// PutChunk writes a chunk into its own blob. Two concurrent uploads of one chunk
+// are resolved by the metadata store, and the blob of the loser is removed.
+func (b *blobStorage) PutChunk(ctx context.Context, user *UserID, digest, data []byte) error {
+	if len(data) > MaxChunkSize {
+		return ErrChunkTooLarge
+	}
+
+	sum := sha256.Sum256(data)
+	if !bytes.Equal(sum[:], digest) {
+		return ErrDigestMismatch
+	}
+
+	missing, err := b.meta.MissingChunks(ctx, user, [][]byte{digest})
+	if err != nil {
+		return err
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	blob, err := b.blobs.Create(ctx)
+	if err != nil {
+		return err
+	}
+
+	writer, err := b.blobs.NewWriter(ctx, blob, 0)
+	if err != nil {
+		return b.deleteBlob(blob, err)
+	}
+
+	if _, err := writer.Write(data); err != nil {
+		if e := writer.Close(); e != nil {
+			err = multierror.Append(err, e)
+		}
+		return b.deleteBlob(blob, err)
+	}
+
+	if err := writer.Close(); err != nil {
+		return b.deleteBlob(blob, err)
+	}
+
+	if err := b.blobs.Commit(ctx, blob); err != nil {
+		return b.deleteBlob(blob, err)
+	}
+
+	added, err := b.meta.AddChunk(ctx, user, &Chunk{Digest: digest, Blob: blob, ByteSize: uint64(len(data))})
+	if err != nil {
+		return b.deleteBlob(blob, err)
+	}
+	if !added {
+		return b.deleteBlob(blob, nil)
+	}
+	return nil
+}
+
+func (b *blobStorage) CreateChunked(ctx context.Context, user *UserID, meta *ResourceMeta, digests [][]byte) (*ResourceInfo, error) {
+	chunks, err := b.meta.Chunks(ctx, user, digests)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifyData(newChunkReader(ctx, b.blobs, chunks), meta); err != nil {
+		return nil, err
+	}
+
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return nil, err
+	}
+
+	rid := ResourceID(id)
+	return b.meta.AddChunkedResource(ctx, user, &rid, meta, digests)
+}
+
+func (b *blobStorage) verifyBlob(ctx context.Context, blob BlobID, meta *ResourceMeta) error {
+	reader, err := b.blobs.Open(ctx, blob)
+	if err != nil {
+		return err
+	}
+	return verifyData(reader, meta)
+}
+
+// verifyData checks data of reader against meta and closes the reader.
+func verifyData(reader io.ReadCloser, meta *ResourceMeta) error {
+	defer func() {
+		_ = reader.Close()
+	}()
+
+	digest := sha256.New()
+	size, err := io.CopyBuffer(digest, reader, make([]byte, _digestBufferSize))
+	if err != nil {
+		return err
+	}
+
+	return meta.Verify(uint64(size), digest.Sum(nil))
+}
+
+// deleteBlob removes a blob of a failed operation. It doesn't use ctx of the
+// operation because it is usually canceled at this point.
+func (b *blobStorage) deleteBlob(blob BlobID, err error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), _cleanupTimeout)
+	defer cancel()
+
+	if e := b.blobs.Delete(ctx, blob); e != nil {
+		err = multierror.Append(err, e)
+	}
+	return err
+}
+
+// blobResource is either a new resource being written or an existing one being read.
+type blobResource struct {
+	ctx     context.Context
+	storage *blobStorage
+	user    *UserID
+	id      ResourceID
+	meta    *ResourceMeta
+	blob    BlobID
+
+	writer  io.WriteCloser
+	hash    hash.Hash
+	written uint64
+
+	reader io.ReadSeekCloser
+
+	done bool
+}
+
+func (r *blobResource) Close() error {
+	if r.done {
+		return errors.New("resource is already closed")
+	}
+	r.done = true
+
+	if r.reader != nil {
+		return r.reader.Close()
+	}
+
+	if err := r.writer.Close(); err != nil {
+		return r.storage.deleteBlob(r.blob, err)
+	}
+
+	if err := r.meta.Verify(r.written, r.hash.Sum(nil)); err != nil {
+		return r.storage.deleteBlob(r.blob, err)
+	}
+
+	if err := r.storage.blobs.Commit(r.ctx, r.blob); err != nil {
+		return r.storage.deleteBlob(r.blob, err)
+	}
+
+	if _, err := r.storage.meta.AddResource(r.ctx, r.user, &r.id, r.blob, r.meta); err != nil {
+		return r.storage.deleteBlob(r.blob, err)
+	}
+	return nil
+}
+
+// Abort discards a new resource with its blob. It does nothing when the resource
+// has been already closed.
+func (r *blobResource) Abort() error {
+	if r.done {
+		return nil
+	}
+	r.done = true
+
+	if r.reader != nil {
+		return r.reader.Close()
+	}
+
+	var err error
+	if e := r.writer.Close(); e != nil {
+		err = multierror.Append(err, e)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), _cleanupTimeout)
+	defer cancel()
+	if e := r.storage.blobs.Delete(ctx, r.blob); e != nil {
+		err = multierror.Append(err, e)
+	}
+	return err
+}
+
+func (r *blobResource) Write(p []byte) (int, error) {
+	if r.writer == nil {
+		return 0, errors.New("resource is opened for reading")
+	}
+
+	n, err := r.writer.Write(p)
+	r.written += uint64(n)
+	r.hash.Write(p[:n])
+	return n, err
+}
+
+func (r *blobResource) Read(p []byte) (int, error) {
+	if r.reader == nil {
+		return 0, errors.New("resource is opened for writing")
+	}
+	return r.reader.Read(p)
+}
+
+func (r *blobResource) Seek(offset int64, whence int) (int64, error) {
+	if r.reader == nil {
+		return 0, errors.New("resource is opened for writing")
+	}
+	return r.reader.Seek(offset, whence)
+}
+
+func (r *blobResource) GetId() *ResourceID {
+	return &r.id
+}
+
+func (r *blobResource) IsDeleted() bool {
+	return false
+}
+
+func (r *blobResource) Salt() ([]byte, error) {
+	return r.meta.Salt, nil
+}
+
+func (r *blobResource) Digest() ([]byte, error) {
+	return r.meta.Digest, nil
+}
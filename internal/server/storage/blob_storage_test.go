@@ -0,0 +1,686 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockMetadataStore struct {
+	resources map[ResourceID]*ResourceInfo
+	blobs     map[ResourceID]BlobID
+	uploads   map[UploadID]*UploadSession
+	chunks    map[string]mockChunk
+	manifests map[ResourceID][][]byte
+	quotas    map[UserID]Quota
+}
+
+type mockChunk struct {
+	Chunk
+	refs int
+}
+
+func newMockMetadataStore() *mockMetadataStore {
+	return &mockMetadataStore{
+		resources: make(map[ResourceID]*ResourceInfo),
+		blobs:     make(map[ResourceID]BlobID),
+		uploads:   make(map[UploadID]*UploadSession),
+		chunks:    make(map[string]mockChunk),
+		manifests: make(map[ResourceID][][]byte),
+		quotas:    make(map[UserID]Quota),
+	}
+}
+
+func (m *mockMetadataStore) AddResource(_ context.Context, _ *UserID, id *ResourceID, blob BlobID, meta *ResourceMeta) (*ResourceInfo, error) {
+	info := &ResourceInfo{
+		ID:       *id,
+		Salt:     meta.Salt,
+		Digest:   meta.Digest,
+		ByteSize: meta.ByteSize,
+	}
+	m.resources[*id] = info
+	m.blobs[*id] = blob
+	return info, nil
+}
+
+func (m *mockMetadataStore) ResourceBlob(_ context.Context, _ *UserID, id *ResourceID) (*ResourceInfo, BlobID, error) {
+	info, ok := m.resources[*id]
+	if !ok || info.IsDeleted {
+		return nil, "", errors.New("not found")
+	}
+	return info, m.blobs[*id], nil
+}
+
+func (m *mockMetadataStore) Delete(_ context.Context, _ *UserID, id *ResourceID) error {
+	if info, ok := m.resources[*id]; ok {
+		info.IsDeleted = true
+		info.UpdatedAt = time.Now()
+	}
+	return nil
+}
+
+func (m *mockMetadataStore) List(context.Context, *UserID, *ListOptions) ([]ResourceInfo, string, error) {
+	return nil, "", nil
+}
+
+func (m *mockMetadataStore) Stat(_ context.Context, _ *UserID, id *ResourceID) (*ResourceInfo, error) {
+	return m.resources[*id], nil
+}
+
+func (m *mockMetadataStore) AddUpload(_ context.Context, _ *UserID, blob BlobID, meta *ResourceMeta) (*UploadSession, error) {
+	session := &UploadSession{
+		ID:        UploadID(uuid.New()),
+		Meta:      *meta,
+		ByteSize:  meta.ByteSize,
+		UpdatedAt: time.Now(),
+		blob:      blob,
+	}
+	m.uploads[session.ID] = session
+	s := *session
+	return &s, nil
+}
+
+func (m *mockMetadataStore) GetUpload(_ context.Context, _ *UserID, id *UploadID) (*UploadSession, error) {
+	session, ok := m.uploads[*id]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	s := *session
+	return &s, nil
+}
+
+func (m *mockMetadataStore) AdvanceUpload(_ context.Context, _ *UserID, id *UploadID, offset, size uint64) (*UploadSession, error) {
+	session := m.uploads[*id]
+	if session.Offset != offset {
+		return nil, ErrUploadOffsetMismatch
+	}
+	session.Offset += size
+	s := *session
+	return &s, nil
+}
+
+func (m *mockMetadataStore) FinalizeUpload(ctx context.Context, user *UserID, id *UploadID, resourceID *ResourceID) (*ResourceInfo, error) {
+	session := m.uploads[*id]
+	delete(m.uploads, *id)
+	return m.AddResource(ctx, user, resourceID, session.blob, &session.Meta)
+}
+
+func (m *mockMetadataStore) ExpireUploads(_ context.Context, idleSince time.Time) ([]BlobID, error) {
+	blobs := make([]BlobID, 0)
+	for id, session := range m.uploads {
+		if session.UpdatedAt.Before(idleSince) {
+			blobs = append(blobs, session.blob)
+			delete(m.uploads, id)
+		}
+	}
+	return blobs, nil
+}
+
+func (m *mockMetadataStore) MissingChunks(_ context.Context, _ *UserID, digests [][]byte) ([][]byte, error) {
+	missing := make([][]byte, 0)
+	for _, d := range digests {
+		if _, ok := m.chunks[string(d)]; !ok {
+			missing = append(missing, d)
+		}
+	}
+	return missing, nil
+}
+
+func (m *mockMetadataStore) Chunks(_ context.Context, _ *UserID, digests [][]byte) ([]Chunk, error) {
+	chunks := make([]Chunk, 0, len(digests))
+	for _, d := range digests {
+		chunk, ok := m.chunks[string(d)]
+		if !ok {
+			return nil, ErrChunkMissing
+		}
+		chunks = append(chunks, chunk.Chunk)
+	}
+	return chunks, nil
+}
+
+func (m *mockMetadataStore) AddChunk(_ context.Context, _ *UserID, chunk *Chunk) (bool, error) {
+	if _, ok := m.chunks[string(chunk.Digest)]; ok {
+		return false, nil
+	}
+	m.chunks[string(chunk.Digest)] = mockChunk{Chunk: *chunk}
+	return true, nil
+}
+
+func (m *mockMetadataStore) AddChunkedResource(ctx context.Context, user *UserID, id *ResourceID, meta *ResourceMeta, digests [][]byte) (*ResourceInfo, error) {
+	m.manifests[*id] = digests
+	for _, d := range digests {
+		chunk := m.chunks[string(d)]
+		chunk.refs++
+		m.chunks[string(d)] = chunk
+	}
+	return m.AddResource(ctx, user, id, "", meta)
+}
+
+func (m *mockMetadataStore) ResourceChunks(ctx context.Context, user *UserID, id *ResourceID) ([]Chunk, error) {
+	return m.Chunks(ctx, user, m.manifests[*id])
+}
+
+func (m *mockMetadataStore) Restore(_ context.Context, _ *UserID, id *ResourceID, deletedAfter time.Time) (*ResourceInfo, error) {
+	info, ok := m.resources[*id]
+	if !ok || !info.IsDeleted || info.UpdatedAt.Before(deletedAfter) {
+		return nil, ErrNotInTrash
+	}
+	info.IsDeleted = false
+	info.UpdatedAt = time.Now()
+	return info, nil
+}
+
+func (m *mockMetadataStore) PurgeUserDeleted(ctx context.Context, _ *UserID, limit int) (int, []BlobID, error) {
+	return m.PurgeDeleted(ctx, time.Now().Add(time.Hour), limit)
+}
+
+func (m *mockMetadataStore) PurgeDeleted(_ context.Context, deletedBefore time.Time, limit int) (int, []BlobID, error) {
+	purged := 0
+	blobs := make([]BlobID, 0)
+	for id, info := range m.resources {
+		if purged == limit {
+			break
+		}
+		if !info.IsDeleted || !info.UpdatedAt.Before(deletedBefore) {
+			continue
+		}
+
+		for _, d := range m.manifests[id] {
+			chunk := m.chunks[string(d)]
+			chunk.refs--
+			m.chunks[string(d)] = chunk
+		}
+		if m.blobs[id] != "" {
+			blobs = append(blobs, m.blobs[id])
+		}
+		delete(m.resources, id)
+		delete(m.blobs, id)
+		delete(m.manifests, id)
+		purged++
+	}
+	return purged, blobs, nil
+}
+
+func (m *mockMetadataStore) PurgeChunks(_ context.Context, _ time.Time, limit int) ([]BlobID, error) {
+	blobs := make([]BlobID, 0)
+	for d, chunk := range m.chunks {
+		if len(blobs) == limit {
+			break
+		}
+		if chunk.refs <= 0 {
+			blobs = append(blobs, chunk.Blob)
+			delete(m.chunks, d)
+		}
+	}
+	return blobs, nil
+}
+
+func (m *mockMetadataStore) UnreferencedBlobs(_ context.Context, blobs []BlobID) ([]BlobID, error) {
+	referenced := make(map[BlobID]struct{})
+	for _, b := range m.blobs {
+		referenced[b] = struct{}{}
+	}
+	for _, c := range m.chunks {
+		referenced[c.Blob] = struct{}{}
+	}
+	for _, u := range m.uploads {
+		referenced[u.blob] = struct{}{}
+	}
+
+	result := make([]BlobID, 0)
+	for _, b := range blobs {
+		if _, ok := referenced[b]; !ok {
+			result = append(result, b)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockMetadataStore) Batch(ctx context.Context, user *UserID, items []BatchItem, opts *BatchOptions) ([]BatchResult, error) {
+	saved := make(map[ResourceID]ResourceInfo, len(m.resources))
+	for id, info := range m.resources {
+		saved[id] = *info
+	}
+
+	results := make([]BatchResult, len(items))
+	for i, item := range items {
+		info, ok := m.resources[item.ID]
+		switch {
+		case item.Op == BatchRestore:
+			info, results[i].Err = m.Restore(ctx, user, &item.ID, opts.RestorableSince)
+		case !ok || info.IsDeleted:
+			results[i].Err = ErrResourceNotFound
+		case item.Op == BatchDelete:
+			results[i].Err = m.Delete(ctx, user, &item.ID)
+		}
+		if results[i].Err == nil {
+			copied := *info
+			results[i].Info = &copied
+			continue
+		}
+
+		if opts.Atomic {
+			for id, info := range saved {
+				*m.resources[id] = info
+			}
+			for j := range results {
+				if j != i {
+					results[j] = BatchResult{Err: ErrBatchAborted}
+				}
+			}
+			break
+		}
+	}
+	return results, nil
+}
+
+func (m *mockMetadataStore) Usage(context.Context, *UserID) (*Usage, error) {
+	usage := &Usage{}
+	for _, info := range m.resources {
+		usage.Bytes += info.ByteSize
+		usage.Items++
+	}
+	for _, session := range m.uploads {
+		usage.Bytes += session.ByteSize
+		usage.Items++
+	}
+	return usage, nil
+}
+
+func (m *mockMetadataStore) Quota(_ context.Context, user *UserID) (*Quota, error) {
+	quota, ok := m.quotas[*user]
+	if !ok {
+		return nil, ErrQuotaNotSet
+	}
+	return &quota, nil
+}
+
+func (m *mockMetadataStore) SetQuota(_ context.Context, user *UserID, quota *Quota) error {
+	m.quotas[*user] = *quota
+	return nil
+}
+
+func (m *mockMetadataStore) DeleteQuota(_ context.Context, user *UserID) error {
+	delete(m.quotas, *user)
+	return nil
+}
+
+func TestBlobStorage_Resource(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	blobs, err := NewFileBlobStore(root)
+	assert.NoError(t, err)
+
+	meta := newMockMetadataStore()
+	s := NewBlobStorage(meta, blobs)
+	user := UserID(uuid.New())
+
+	data := []byte("resource data")
+	digest := sha256.Sum256(data)
+
+	res, err := s.Create(ctx, &user, &ResourceMeta{ByteSize: uint64(len(data)), Digest: digest[:]})
+	assert.NoError(t, err)
+	_, err = res.Write(data)
+	assert.NoError(t, err)
+	assert.Empty(t, meta.resources)
+	assert.NoError(t, res.Close())
+	assert.NoError(t, res.Abort())
+	assert.Equal(t, 1, len(meta.resources))
+
+	res, err = s.Open(ctx, &user, res.GetId())
+	assert.NoError(t, err)
+	remote, err := io.ReadAll(res)
+	assert.NoError(t, err)
+	assert.Equal(t, data, remote)
+	assert.NoError(t, res.Close())
+
+	// Aborted and corrupted resources leave neither rows nor blobs.
+	res, err = s.Create(ctx, &user, &ResourceMeta{ByteSize: uint64(len(data))})
+	assert.NoError(t, err)
+	_, err = res.Write(data[:3])
+	assert.NoError(t, err)
+	assert.NoError(t, res.Abort())
+
+	res, err = s.Create(ctx, &user, &ResourceMeta{ByteSize: uint64(len(data)), Digest: digest[:]})
+	assert.NoError(t, err)
+	_, err = res.Write([]byte("corrupted dat"))
+	assert.NoError(t, err)
+	assert.ErrorIs(t, res.Close(), ErrDigestMismatch)
+
+	assert.Equal(t, 1, len(meta.resources))
+	assert.Equal(t, 1, countFiles(t, root))
+}
+
+func TestBlobStorage_Upload(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	blobs, err := NewFileBlobStore(root)
+	assert.NoError(t, err)
+
+	meta := newMockMetadataStore()
+	s := NewBlobStorage(meta, blobs)
+	user := UserID(uuid.New())
+
+	data := []byte("resource data")
+	digest := sha256.Sum256(data)
+
+	session, err := s.CreateUpload(ctx, &user, &ResourceMeta{ByteSize: uint64(len(data)), Digest: digest[:]})
+	assert.NoError(t, err)
+
+	_, err = s.AppendUpload(ctx, &user, &session.ID, 0, data[:5])
+	assert.NoError(t, err)
+	_, err = s.AppendUpload(ctx, &user, &session.ID, 0, data[:5])
+	assert.ErrorIs(t, err, ErrUploadOffsetMismatch)
+	_, err = s.AppendUpload(ctx, &user, &session.ID, 5, data)
+	assert.ErrorIs(t, err, ErrUploadTooLarge)
+
+	_, err = s.FinalizeUpload(ctx, &user, &session.ID)
+	assert.ErrorIs(t, err, ErrUploadIncomplete)
+
+	session, err = s.AppendUpload(ctx, &user, &session.ID, 5, data[5:])
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(len(data)), session.Offset)
+
+	info, err := s.FinalizeUpload(ctx, &user, &session.ID)
+	assert.NoError(t, err)
+
+	res, err := s.Open(ctx, &user, &info.ID)
+	assert.NoError(t, err)
+	remote, err := io.ReadAll(res)
+	assert.NoError(t, err)
+	assert.Equal(t, data, remote)
+	assert.NoError(t, res.Close())
+
+	_, err = s.CreateUpload(ctx, &user, &ResourceMeta{ByteSize: uint64(len(data))})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, countFiles(t, root))
+
+	expired, err := s.ExpireUploads(ctx, time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, expired)
+	assert.Equal(t, 1, countFiles(t, root))
+}
+
+func TestBlobStorage_Chunked(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	blobs, err := NewFileBlobStore(root)
+	assert.NoError(t, err)
+
+	meta := newMockMetadataStore()
+	s := NewBlobStorage(meta, blobs)
+	user := UserID(uuid.New())
+
+	chunks := [][]byte{[]byte("first chunk, "), []byte("second chunk, "), []byte("first chunk, ")}
+	digests := make([][]byte, 0, len(chunks))
+	data := make([]byte, 0)
+	for _, c := range chunks {
+		digest := sha256.Sum256(c)
+		digests = append(digests, digest[:])
+		data = append(data, c...)
+	}
+
+	missing, err := s.MissingChunks(ctx, &user, digests)
+	assert.NoError(t, err)
+	assert.Equal(t, digests, missing)
+
+	bad := append([]byte{}, chunks[0]...)
+	bad[0] = 'F'
+	assert.ErrorIs(t, s.PutChunk(ctx, &user, digests[0], bad), ErrDigestMismatch)
+	assert.ErrorIs(t, s.PutChunk(ctx, &user, digests[0], make([]byte, MaxChunkSize+1)), ErrChunkTooLarge)
+
+	for i, c := range chunks {
+		assert.NoError(t, s.PutChunk(ctx, &user, digests[i], c))
+	}
+	// A repeated chunk is stored once.
+	assert.Equal(t, 2, countFiles(t, root))
+
+	missing, err = s.MissingChunks(ctx, &user, digests)
+	assert.NoError(t, err)
+	assert.Empty(t, missing)
+
+	digest := sha256.Sum256(data)
+	_, err = s.CreateChunked(ctx, &user, &ResourceMeta{ByteSize: uint64(len(data)), Digest: digest[:]}, digests[:2])
+	assert.ErrorIs(t, err, ErrSizeMismatch)
+
+	unknown := sha256.Sum256([]byte("unknown"))
+	_, err = s.CreateChunked(ctx, &user, &ResourceMeta{ByteSize: uint64(len(data))}, [][]byte{unknown[:]})
+	assert.ErrorIs(t, err, ErrChunkMissing)
+
+	info, err := s.CreateChunked(ctx, &user, &ResourceMeta{ByteSize: uint64(len(data)), Digest: digest[:]}, digests)
+	assert.NoError(t, err)
+
+	res, err := s.Open(ctx, &user, &info.ID)
+	assert.NoError(t, err)
+	remote, err := io.ReadAll(res)
+	assert.NoError(t, err)
+	assert.Equal(t, data, remote)
+
+	// Seeking moves between chunks.
+	_, err = res.Seek(int64(len(chunks[0])+7), io.SeekStart)
+	assert.NoError(t, err)
+	remote, err = io.ReadAll(res)
+	assert.NoError(t, err)
+	assert.Equal(t, data[len(chunks[0])+7:], remote)
+	size, err := res.Seek(0, io.SeekEnd)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(data)), size)
+	assert.NoError(t, res.Close())
+}
+
+func TestBlobStorage_ChunkedCorrupt(t *testing.T) {
+	ctx := context.Background()
+	blobs, err := NewFileBlobStore(t.TempDir())
+	require.NoError(t, err)
+
+	meta := newMockMetadataStore()
+	s := NewBlobStorage(meta, blobs)
+	user := UserID(uuid.New())
+
+	chunks := [][]byte{[]byte("first chunk, "), []byte("second chunk")}
+	digests := make([][]byte, 0, len(chunks))
+	for _, c := range chunks {
+		digest := sha256.Sum256(c)
+		digests = append(digests, digest[:])
+		require.NoError(t, s.PutChunk(ctx, &user, digest[:], c))
+	}
+	info, err := s.CreateChunked(ctx, &user, &ResourceMeta{ByteSize: uint64(len(chunks[0]) + len(chunks[1]))}, digests)
+	require.NoError(t, err)
+	stored, err := meta.Chunks(ctx, &user, digests)
+	require.NoError(t, err)
+
+	readAll := func() ([]byte, error) {
+		res, err := s.Open(ctx, &user, &info.ID)
+		require.NoError(t, err)
+		defer func() {
+			_ = res.Close()
+		}()
+		return io.ReadAll(res)
+	}
+
+	// Extra data of a blob doesn't leak into the next chunk.
+	first := blobs.blobPath(stored[0].Blob)
+	require.NoError(t, os.WriteFile(first, append(append([]byte{}, chunks[0]...), "extra"...), 0600))
+	remote, err := readAll()
+	require.NoError(t, err)
+	assert.Equal(t, string(chunks[0])+string(chunks[1]), string(remote))
+
+	// A truncated blob fails reads instead of being read again forever.
+	require.NoError(t, os.WriteFile(first, chunks[0][:5], 0600))
+	done := make(chan error, 1)
+	go func() {
+		_, err := readAll()
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	case <-time.After(5 * time.Second):
+		t.Fatal("reading a truncated chunk doesn't stop")
+	}
+}
+
+func countFiles(t *testing.T, root string) int {
+	count := 0
+	err := filepath.Walk(root, func(_ string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			count++
+		}
+		return err
+	})
+	assert.NoError(t, err)
+	return count
+}
+
+func TestBlobStorage_CollectGarbage(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	blobs, err := NewFileBlobStore(root)
+	assert.NoError(t, err)
+
+	meta := newMockMetadataStore()
+	s := NewBlobStorage(meta, blobs)
+	user := UserID(uuid.New())
+
+	data := []byte("some data")
+	digest := sha256.Sum256(data)
+	created := make([]ResourceID, 0, 2)
+	for i := 0; i < 2; i++ {
+		res, err := s.Create(ctx, &user, &ResourceMeta{ByteSize: uint64(len(data)), Digest: digest[:]})
+		assert.NoError(t, err)
+		_, err = res.Write(data)
+		assert.NoError(t, err)
+		assert.NoError(t, res.Close())
+		created = append(created, *res.GetId())
+	}
+
+	assert.NoError(t, s.PutChunk(ctx, &user, digest[:], data))
+	info, err := s.CreateChunked(ctx, &user, &ResourceMeta{ByteSize: uint64(len(data)), Digest: digest[:]}, [][]byte{digest[:]})
+	assert.NoError(t, err)
+
+	orphan, err := blobs.Create(ctx)
+	assert.NoError(t, err)
+	w, err := blobs.NewWriter(ctx, orphan, 0)
+	assert.NoError(t, err)
+	_, err = w.Write(data)
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+	assert.NoError(t, blobs.Commit(ctx, orphan))
+	assert.Equal(t, 4, countFiles(t, root))
+
+	assert.NoError(t, s.Delete(ctx, &user, &created[0]))
+	assert.NoError(t, s.Delete(ctx, &user, &info.ID))
+
+	// Nothing is old enough yet, but the orphan is remembered.
+	stats, err := s.CollectGarbage(ctx, time.Now().Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, stats.Tombstones)
+	assert.Equal(t, 1, stats.Orphans)
+	assert.Equal(t, 0, stats.OrphansDeleted)
+	assert.Equal(t, 4, countFiles(t, root))
+
+	stats, err = s.CollectGarbage(ctx, time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, stats.Tombstones)
+	assert.Equal(t, 1, stats.Chunks)
+	assert.Equal(t, 2, stats.Blobs)
+	assert.Equal(t, 1, stats.Orphans)
+	assert.Equal(t, 1, stats.OrphansDeleted)
+	assert.Equal(t, 1, countFiles(t, root))
+
+	res, err := s.Open(ctx, &user, &created[1])
+	assert.NoError(t, err)
+	remote, err := io.ReadAll(res)
+	assert.NoError(t, err)
+	assert.Equal(t, data, remote)
+	assert.NoError(t, res.Close())
+}
+
+// untimedBlobStore is a blob store which doesn't track modification time.
+type untimedBlobStore struct {
+	*memoryBlobStore
+}
+
+func (u untimedBlobStore) Walk(ctx context.Context, fn func(id BlobID, modTime time.Time) error) error {
+	return u.memoryBlobStore.Walk(ctx, func(id BlobID, _ time.Time) error {
+		return fn(id, time.Time{})
+	})
+}
+
+func TestBlobStorage_CollectGarbageUntimedBlobs(t *testing.T) {
+	ctx := context.Background()
+	blobs := untimedBlobStore{NewMemoryBlobStore()}
+	s := NewBlobStorage(NewMemoryUserService(), blobs)
+
+	// A blob being written has no resource yet.
+	pending, err := blobs.Create(ctx)
+	assert.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		stats, err := s.CollectGarbage(ctx, time.Now().Add(time.Hour))
+		assert.NoError(t, err)
+		assert.Equal(t, 1, stats.Orphans)
+		assert.Equal(t, 0, stats.OrphansDeleted)
+	}
+
+	_, err = blobs.Open(ctx, pending)
+	assert.NoError(t, err)
+}
+
+func TestBlobStorage_Trash(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	blobs, err := NewFileBlobStore(root)
+	assert.NoError(t, err)
+
+	meta := newMockMetadataStore()
+	s := NewBlobStorage(meta, blobs)
+	user := UserID(uuid.New())
+
+	data := []byte("some data")
+	digest := sha256.Sum256(data)
+	ids := make([]ResourceID, 0, 2)
+	for i := 0; i < 2; i++ {
+		res, err := s.Create(ctx, &user, &ResourceMeta{ByteSize: uint64(len(data)), Digest: digest[:]})
+		assert.NoError(t, err)
+		_, err = res.Write(data)
+		assert.NoError(t, err)
+		assert.NoError(t, res.Close())
+		ids = append(ids, *res.GetId())
+		assert.NoError(t, s.Delete(ctx, &user, res.GetId()))
+	}
+
+	_, err = s.Restore(ctx, &user, &ids[0], time.Now().Add(time.Hour))
+	assert.ErrorIs(t, err, ErrNotInTrash)
+
+	info, err := s.Restore(ctx, &user, &ids[0], time.Now().Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.False(t, info.IsDeleted)
+
+	_, err = s.Restore(ctx, &user, &ids[0], time.Now().Add(-time.Hour))
+	assert.ErrorIs(t, err, ErrNotInTrash)
+
+	purged, err := s.EmptyTrash(ctx, &user)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, purged)
+	assert.Equal(t, 1, countFiles(t, root))
+
+	_, err = s.Restore(ctx, &user, &ids[1], time.Now().Add(-time.Hour))
+	assert.ErrorIs(t, err, ErrNotInTrash)
+
+	res, err := s.Open(ctx, &user, &ids[0])
+	assert.NoError(t, err)
+	remote, err := io.ReadAll(res)
+	assert.NoError(t, err)
+	assert.Equal(t, data, remote)
+	assert.NoError(t, res.Close())
+}
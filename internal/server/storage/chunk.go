@@ -0,0 +1,159 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// MaxChunkSize limits a size of a single chunk of a chunked resource.
+const MaxChunkSize = 8 * 1024 * 1024
+
+var (
+	ErrChunkMissing  = NewError(CodeFailedPrecondition, "chunk is missing")
+	ErrChunkTooLarge = NewError(CodeOutOfRange, "chunk is too large")
+)
+
+// Chunk is a piece of resource data addressed by its SHA-256 digest. Chunks
+// belong to a user and are shared by all resources of the user referring them.
+type Chunk struct {
+	Digest   []byte
+	Blob     BlobID
+	ByteSize uint64
+}
+
+// ChunkStorage stores resources as manifests of chunks, so data a user has
+// already uploaded isn't sent and stored again.
+type ChunkStorage interface {
+	// MissingChunks returns those of digests which a user has no chunks for.
+	MissingChunks(ctx context.Context, user *UserID, digests [][]byte) ([][]byte, error)
+	// PutChunk stores a chunk of a user. Storing an existing chunk does nothing.
+	PutChunk(ctx context.Context, user *UserID, digest, data []byte) error
+	// CreateChunked adds a resource which data is a concatenation of stored chunks.
+	CreateChunked(ctx context.Context, user *UserID, meta *ResourceMeta, digests [][]byte) (*ResourceInfo, error)
+}
+
+// chunkReader reads chunks of a resource as a single stream.
+// A chunk blob is opened only when reading reaches it.
+type chunkReader struct {
+	ctx     context.Context
+	blobs   BlobStore
+	chunks  []Chunk
+	offsets []int64
+	size    int64
+	pos     int64
+	current io.ReadSeekCloser
+	// end is the end of the current chunk.
+	end int64
+}
+
+func newChunkReader(ctx context.Context, blobs BlobStore, chunks []Chunk) *chunkReader {
+	r := &chunkReader{
+		ctx:     ctx,
+		blobs:   blobs,
+		chunks:  chunks,
+		offsets: make([]int64, len(chunks)),
+	}
+	for i, c := range chunks {
+		r.offsets[i] = r.size
+		r.size += int64(c.ByteSize)
+	}
+	return r
+}
+
+// Read stops at the end of a chunk, so a blob larger than its chunk never
+// leaks into the range of the next one, and a shorter blob is reported as
+// corrupt data instead of being read again.
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for {
+		if r.pos >= r.size {
+			return 0, io.EOF
+		}
+		if len(p) == 0 {
+			return 0, nil
+		}
+
+		if r.current == nil {
+			if err := r.openChunk(); err != nil {
+				return 0, err
+			}
+		}
+
+		if left := r.end - r.pos; int64(len(p)) > left {
+			p = p[:left]
+		}
+		n, err := r.current.Read(p)
+		r.pos += int64(n)
+		switch {
+		case r.pos == r.end:
+			if closeErr := r.closeChunk(); err == nil || err == io.EOF {
+				err = closeErr
+			}
+		case err == io.EOF:
+			_ = r.closeChunk()
+			err = fmt.Errorf("chunk ends at %d of %d bytes: %w", r.pos, r.end, io.ErrUnexpectedEOF)
+		}
+		if n != 0 || err != nil {
+			return n, err
+		}
+	}
+}
+
+func (r *chunkReader) openChunk() error {
+	for i, c := range r.chunks {
+		end := r.offsets[i] + int64(c.ByteSize)
+		if r.pos >= end {
+			continue
+		}
+
+		blob, err := r.blobs.Open(r.ctx, c.Blob)
+		if err != nil {
+			return err
+		}
+		if _, err := blob.Seek(r.pos-r.offsets[i], io.SeekStart); err != nil {
+			_ = blob.Close()
+			return err
+		}
+		r.current, r.end = blob, end
+		return nil
+	}
+	return io.ErrUnexpectedEOF
+}
+
+func (r *chunkReader) closeChunk() error {
+	if r.current == nil {
+		return nil
+	}
+	err := r.current.Close()
+	r.current = nil
+	return err
+}
+
+func (r *chunkReader) Seek(offset int64, whence int) (int64, error) {
+	pos := offset
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		pos += r.pos
+	case io.SeekEnd:
+		pos += r.size
+	default:
+		return 0, errors.New("invalid whence")
+	}
+	if pos < 0 {
+		return 0, errors.New("negative position")
+	}
+
+	if pos != r.pos {
+		if err := r.closeChunk(); err != nil {
+			return 0, err
+		}
+	}
+	r.pos = pos
+	return pos, nil
+}
+
+func (r *chunkReader) Close() error {
+	return r.closeChunk()
+}
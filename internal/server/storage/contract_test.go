@@ -0,0 +1,685 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// _testDatabaseDSN names a variable with a DSN of a migrated Postgres database.
+// Contract tests run against Postgres only when it is set.
+const _testDatabaseDSN = "TEST_DATABASE_DSN"
+
+// metadataBackend is a database implementing both UserService and MetadataStore.
+type metadataBackend interface {
+	UserService
+	MetadataStore
+	BlobKeyStore
+}
+
+// metadataBackends returns constructors of every backend the contract tests
+// run against. A backend may be shared by tests, so tests use their own users.
+func metadataBackends() map[string]func(t *testing.T) metadataBackend {
+	backends := map[string]func(t *testing.T) metadataBackend{
+		"memory": func(*testing.T) metadataBackend {
+			return NewMemoryUserService()
+		},
+		"sqlite": func(t *testing.T) metadataBackend {
+			return newTestSQLiteStorage(t)
+		},
+	}
+
+	if dsn := os.Getenv(_testDatabaseDSN); len(dsn) != 0 {
+		backends["postgres"] = func(t *testing.T) metadataBackend {
+			d, err := NewDatabaseUserService(context.Background(), dsn, time.Second)
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				_ = d.Close()
+			})
+			return d
+		}
+	}
+	return backends
+}
+
+func newTestSQLiteStorage(t *testing.T) *sqliteStorage {
+	s, err := NewSQLiteUserService(context.Background(),
+		SQLiteScheme+filepath.Join(t.TempDir(), "keeper.db"), time.Second)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = s.Close()
+	})
+	return s
+}
+
+// runContract runs test against every metadata backend.
+func runContract(t *testing.T, test func(t *testing.T, ctx context.Context, b metadataBackend)) {
+	for name, newBackend := range metadataBackends() {
+		newBackend := newBackend
+		t.Run(name, func(t *testing.T) {
+			test(t, context.Background(), newBackend(t))
+		})
+	}
+}
+
+func addTestUser(t *testing.T, ctx context.Context, users UserService) *UserID {
+	id, err := users.Add(ctx, uuid.NewString(), []byte("key salt"), []byte("salt"), []byte("secret"))
+	require.NoError(t, err)
+	return id
+}
+
+func addTestResource(t *testing.T, ctx context.Context, meta MetadataStore, user *UserID, kind string) *ResourceInfo {
+	id := ResourceID(uuid.New())
+	info, err := meta.AddResource(ctx, user, &id, BlobID(uuid.NewString()), &ResourceMeta{
+		Salt:     []byte("salt"),
+		Metadata: []byte("metadata"),
+		Kind:     kind,
+		ByteSize: 4,
+	})
+	require.NoError(t, err)
+	return info
+}
+
+func TestContract_Users(t *testing.T) {
+	runContract(t, func(t *testing.T, ctx context.Context, b metadataBackend) {
+		login := uuid.NewString()
+		id, err := b.Add(ctx, login, []byte("key salt"), []byte("salt"), []byte("secret"))
+		require.NoError(t, err)
+
+		_, err = b.Add(ctx, login, []byte("key salt"), []byte("salt"), []byte("secret"))
+		assert.ErrorIs(t, err, ErrUserExists)
+
+		user, err := b.GetByLogin(ctx, login)
+		require.NoError(t, err)
+		assert.Equal(t, *id, user.ID)
+		assert.Equal(t, login, user.Login)
+		assert.Equal(t, []byte("salt"), user.Salt)
+		assert.Equal(t, []byte("secret"), user.Secret)
+
+		user, err = b.GetByID(ctx, id.String())
+		require.NoError(t, err)
+		assert.Equal(t, login, user.Login)
+
+		_, err = b.GetByLogin(ctx, uuid.NewString())
+		assert.ErrorIs(t, err, ErrUserNotFound)
+		_, err = b.GetByID(ctx, uuid.NewString())
+		assert.ErrorIs(t, err, ErrUserNotFound)
+	})
+}
+
+func TestContract_Resources(t *testing.T) {
+	runContract(t, func(t *testing.T, ctx context.Context, b metadataBackend) {
+		user, other := addTestUser(t, ctx, b), addTestUser(t, ctx, b)
+		id := ResourceID(uuid.New())
+		digest := sha256.Sum256([]byte("data"))
+
+		info, err := b.AddResource(ctx, user, &id, "blob-"+BlobID(id.String()), &ResourceMeta{
+			Salt:     []byte("salt"),
+			Metadata: []byte("metadata"),
+			Kind:     "note",
+			ByteSize: 4,
+			Digest:   digest[:],
+		})
+		require.NoError(t, err)
+		assert.Equal(t, id, info.ID)
+		assert.Equal(t, []byte("metadata"), info.Metadata)
+		assert.Equal(t, "note", info.Kind)
+		assert.Equal(t, digest[:], info.Digest)
+		assert.Equal(t, uint64(4), info.ByteSize)
+		assert.Equal(t, uint64(1), info.Version)
+		assert.False(t, info.IsDeleted)
+		assert.WithinDuration(t, time.Now(), info.CreatedAt, time.Minute)
+
+		stat, blob, err := b.ResourceBlob(ctx, user, &id)
+		require.NoError(t, err)
+		assert.Equal(t, "blob-"+BlobID(id.String()), blob)
+		assert.Equal(t, info.UpdatedAt.UnixMicro(), stat.UpdatedAt.UnixMicro())
+
+		_, err = b.Stat(ctx, other, &id)
+		assert.ErrorIs(t, err, ErrResourceNotFound)
+		assert.ErrorIs(t, b.Delete(ctx, other, &id), ErrResourceNotFound)
+
+		deletedAfter := time.Now().Add(-time.Minute)
+		require.NoError(t, b.Delete(ctx, user, &id))
+		_, err = b.Stat(ctx, user, &id)
+		assert.ErrorIs(t, err, ErrResourceNotFound)
+		_, _, err = b.ResourceBlob(ctx, user, &id)
+		assert.ErrorIs(t, err, ErrResourceNotFound)
+
+		_, err = b.Restore(ctx, user, &id, time.Now().Add(time.Minute))
+		assert.ErrorIs(t, err, ErrNotInTrash)
+		restored, err := b.Restore(ctx, user, &id, deletedAfter)
+		require.NoError(t, err)
+		assert.False(t, restored.IsDeleted)
+		_, err = b.Restore(ctx, user, &id, deletedAfter)
+		assert.ErrorIs(t, err, ErrNotInTrash)
+
+		_, err = b.Stat(ctx, user, &id)
+		assert.NoError(t, err)
+	})
+}
+
+func TestContract_List(t *testing.T) {
+	runContract(t, func(t *testing.T, ctx context.Context, b metadataBackend) {
+		user := addTestUser(t, ctx, b)
+		ids := make([]ResourceID, 0, 5)
+		for i := 0; i < 5; i++ {
+			kind := "note"
+			if i%2 == 0 {
+				kind = "card"
+			}
+			ids = append(ids, addTestResource(t, ctx, b, user, kind).ID)
+		}
+		require.NoError(t, b.Delete(ctx, user, &ids[4]))
+
+		listAll := func(opts ListOptions) []ResourceID {
+			listed := make([]ResourceID, 0)
+			for {
+				page, next, err := b.List(ctx, user, &opts)
+				require.NoError(t, err)
+				assert.LessOrEqual(t, len(page), opts.PageSize)
+				for _, info := range page {
+					listed = append(listed, info.ID)
+				}
+				if next == "" {
+					return listed
+				}
+				opts.PageToken = next
+			}
+		}
+
+		assert.Equal(t, ids[:4], listAll(ListOptions{PageSize: 2}))
+		assert.Equal(t, ids, listAll(ListOptions{PageSize: 2, IncludeDeleted: true}))
+		assert.Equal(t, ids[4:], listAll(ListOptions{PageSize: 2, DeletedOnly: true}))
+
+		kind := "card"
+		assert.Equal(t, []ResourceID{ids[0], ids[2]}, listAll(ListOptions{PageSize: 1, Kind: &kind}))
+
+		// The deleted resource is the last updated one.
+		assert.Equal(t, ids, listAll(ListOptions{PageSize: 3, OrderBy: ListOrderUpdated, IncludeDeleted: true}))
+
+		deleted, _, err := b.List(ctx, user, &ListOptions{PageSize: 1, DeletedOnly: true})
+		require.NoError(t, err)
+		updatedAfter := deleted[0].UpdatedAt.Add(-time.Microsecond)
+		assert.Equal(t, ids[4:], listAll(ListOptions{PageSize: 10, UpdatedAfter: &updatedAfter, IncludeDeleted: true}))
+
+		_, _, err = b.List(ctx, user, &ListOptions{PageSize: 1, PageToken: "bad"})
+		assert.ErrorIs(t, err, ErrInvalidPageToken)
+
+		// A page token can't be used with other filters.
+		_, next, err := b.List(ctx, user, &ListOptions{PageSize: 1, Kind: &kind})
+		require.NoError(t, err)
+		require.NotEmpty(t, next)
+		other := "note"
+		for _, opts := range []ListOptions{
+			{PageSize: 1, PageToken: next},
+			{PageSize: 1, PageToken: next, Kind: &other},
+			{PageSize: 1, PageToken: next, Kind: &kind, OrderBy: ListOrderUpdated},
+			{PageSize: 1, PageToken: next, Kind: &kind, IncludeDeleted: true},
+			{PageSize: 1, PageToken: next, Kind: &kind, UpdatedAfter: &updatedAfter},
+		} {
+			opts := opts
+			_, _, err = b.List(ctx, user, &opts)
+			assert.ErrorIs(t, err, ErrPageTokenMismatch)
+		}
+		_, _, err = b.List(ctx, user, &ListOptions{PageSize: 1, PageToken: next, Kind: &kind})
+		assert.NoError(t, err)
+	})
+}
+
+func TestContract_Uploads(t *testing.T) {
+	runContract(t, func(t *testing.T, ctx context.Context, b metadataBackend) {
+		user, other := addTestUser(t, ctx, b), addTestUser(t, ctx, b)
+		blob := BlobID(uuid.NewString())
+
+		session, err := b.AddUpload(ctx, user, blob, &ResourceMeta{Salt: []byte("salt"), Kind: "file", ByteSize: 10})
+		require.NoError(t, err)
+		assert.Equal(t, uint64(10), session.ByteSize)
+		assert.Equal(t, uint64(0), session.Offset)
+
+		_, err = b.GetUpload(ctx, other, &session.ID)
+		assert.ErrorIs(t, err, ErrUploadNotFound)
+
+		session, err = b.AdvanceUpload(ctx, user, &session.ID, 0, 4)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(4), session.Offset)
+		_, err = b.AdvanceUpload(ctx, user, &session.ID, 0, 4)
+		assert.ErrorIs(t, err, ErrUploadOffsetMismatch)
+
+		id := ResourceID(uuid.New())
+		_, err = b.FinalizeUpload(ctx, user, &session.ID, &id)
+		assert.ErrorIs(t, err, ErrUploadIncomplete)
+
+		_, err = b.AdvanceUpload(ctx, user, &session.ID, 4, 6)
+		require.NoError(t, err)
+		info, err := b.FinalizeUpload(ctx, user, &session.ID, &id)
+		require.NoError(t, err)
+		assert.Equal(t, "file", info.Kind)
+
+		_, found, err := b.ResourceBlob(ctx, user, &id)
+		require.NoError(t, err)
+		assert.Equal(t, blob, found)
+		_, err = b.GetUpload(ctx, user, &session.ID)
+		assert.ErrorIs(t, err, ErrUploadNotFound)
+
+		idle := BlobID(uuid.NewString())
+		session, err = b.AddUpload(ctx, user, idle, &ResourceMeta{Salt: []byte("salt"), ByteSize: 10})
+		require.NoError(t, err)
+		expired, err := b.ExpireUploads(ctx, time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+		assert.NotContains(t, expired, idle)
+		expired, err = b.ExpireUploads(ctx, time.Now().Add(time.Second))
+		require.NoError(t, err)
+		assert.Contains(t, expired, idle)
+		_, err = b.GetUpload(ctx, user, &session.ID)
+		assert.ErrorIs(t, err, ErrUploadNotFound)
+	})
+}
+
+func TestContract_Chunks(t *testing.T) {
+	runContract(t, func(t *testing.T, ctx context.Context, b metadataBackend) {
+		user := addTestUser(t, ctx, b)
+		first, second := sha256.Sum256([]byte("first")), sha256.Sum256([]byte("second"))
+		digests := [][]byte{first[:], second[:], first[:]}
+
+		missing, err := b.MissingChunks(ctx, user, digests)
+		require.NoError(t, err)
+		assert.Equal(t, digests, missing)
+
+		firstBlob := BlobID(uuid.NewString())
+		added, err := b.AddChunk(ctx, user, &Chunk{Digest: first[:], Blob: firstBlob, ByteSize: 5})
+		require.NoError(t, err)
+		assert.True(t, added)
+		added, err = b.AddChunk(ctx, user, &Chunk{Digest: first[:], Blob: BlobID(uuid.NewString()), ByteSize: 5})
+		require.NoError(t, err)
+		assert.False(t, added)
+
+		missing, err = b.MissingChunks(ctx, user, digests)
+		require.NoError(t, err)
+		assert.Equal(t, [][]byte{second[:]}, missing)
+		_, err = b.Chunks(ctx, user, digests)
+		assert.ErrorIs(t, err, ErrChunkMissing)
+
+		// A failed resource adds neither itself nor chunk references.
+		id := ResourceID(uuid.New())
+		meta := &ResourceMeta{Salt: []byte("salt"), ByteSize: 16}
+		_, err = b.AddChunkedResource(ctx, user, &id, meta, digests)
+		assert.ErrorIs(t, err, ErrChunkMissing)
+		_, err = b.Stat(ctx, user, &id)
+		assert.ErrorIs(t, err, ErrResourceNotFound)
+
+		secondBlob := BlobID(uuid.NewString())
+		_, err = b.AddChunk(ctx, user, &Chunk{Digest: second[:], Blob: secondBlob, ByteSize: 6})
+		require.NoError(t, err)
+
+		chunks, err := b.Chunks(ctx, user, digests)
+		require.NoError(t, err)
+		assert.Equal(t, []BlobID{firstBlob, secondBlob, firstBlob},
+			[]BlobID{chunks[0].Blob, chunks[1].Blob, chunks[2].Blob})
+
+		_, err = b.AddChunkedResource(ctx, user, &id, meta, digests)
+		require.NoError(t, err)
+		_, blob, err := b.ResourceBlob(ctx, user, &id)
+		require.NoError(t, err)
+		assert.Empty(t, blob)
+
+		chunks, err = b.ResourceChunks(ctx, user, &id)
+		require.NoError(t, err)
+		require.Len(t, chunks, 3)
+		assert.Equal(t, first[:], chunks[0].Digest)
+		assert.Equal(t, second[:], chunks[1].Digest)
+		assert.Equal(t, uint64(5), chunks[2].ByteSize)
+
+		// Chunks of other users are not shared.
+		missing, err = b.MissingChunks(ctx, addTestUser(t, ctx, b), digests)
+		require.NoError(t, err)
+		assert.Len(t, missing, 3)
+	})
+}
+
+func TestContract_Purge(t *testing.T) {
+	runContract(t, func(t *testing.T, ctx context.Context, b metadataBackend) {
+		user := addTestUser(t, ctx, b)
+
+		digest := sha256.Sum256([]byte(uuid.NewString()))
+		chunkBlob := BlobID(uuid.NewString())
+		_, err := b.AddChunk(ctx, user, &Chunk{Digest: digest[:], Blob: chunkBlob, ByteSize: 4})
+		require.NoError(t, err)
+		chunked := ResourceID(uuid.New())
+		_, err = b.AddChunkedResource(ctx, user, &chunked, &ResourceMeta{Salt: []byte("salt"), ByteSize: 8},
+			[][]byte{digest[:], digest[:]})
+		require.NoError(t, err)
+
+		kept := addTestResource(t, ctx, b, user, "note")
+		deleted := addTestResource(t, ctx, b, user, "note")
+		_, deletedBlob, err := b.ResourceBlob(ctx, user, &deleted.ID)
+		require.NoError(t, err)
+		_, keptBlob, err := b.ResourceBlob(ctx, user, &kept.ID)
+		require.NoError(t, err)
+
+		unreferenced, err := b.UnreferencedBlobs(ctx, []BlobID{deletedBlob, keptBlob, chunkBlob, "unknown"})
+		require.NoError(t, err)
+		assert.Equal(t, []BlobID{"unknown"}, unreferenced)
+
+		require.NoError(t, b.Delete(ctx, user, &deleted.ID))
+		require.NoError(t, b.Delete(ctx, user, &chunked))
+
+		// Referenced chunks are never purged.
+		purgedChunks, err := b.PurgeChunks(ctx, time.Now().Add(time.Second), 1000)
+		require.NoError(t, err)
+		assert.NotContains(t, purgedChunks, chunkBlob)
+
+		_, blobs, err := b.PurgeDeleted(ctx, time.Now().Add(-time.Hour), 1000)
+		require.NoError(t, err)
+		assert.NotContains(t, blobs, deletedBlob)
+
+		purged, blobs, err := b.PurgeUserDeleted(ctx, user, 1000)
+		require.NoError(t, err)
+		assert.Equal(t, 2, purged)
+		assert.Equal(t, []BlobID{deletedBlob}, blobs)
+
+		_, err = b.Restore(ctx, user, &deleted.ID, time.Time{})
+		assert.ErrorIs(t, err, ErrNotInTrash)
+		_, err = b.Stat(ctx, user, &kept.ID)
+		assert.NoError(t, err)
+
+		purgedChunks, err = b.PurgeChunks(ctx, time.Now().Add(-time.Hour), 1000)
+		require.NoError(t, err)
+		assert.NotContains(t, purgedChunks, chunkBlob)
+		purgedChunks, err = b.PurgeChunks(ctx, time.Now().Add(time.Second), 1000)
+		require.NoError(t, err)
+		assert.Contains(t, purgedChunks, chunkBlob)
+
+		missing, err := b.MissingChunks(ctx, user, [][]byte{digest[:]})
+		require.NoError(t, err)
+		assert.Len(t, missing, 1)
+	})
+}
+
+func TestContract_Quota(t *testing.T) {
+	runContract(t, func(t *testing.T, ctx context.Context, b metadataBackend) {
+		user := addTestUser(t, ctx, b)
+
+		usage, err := b.Usage(ctx, user)
+		require.NoError(t, err)
+		assert.Equal(t, &Usage{}, usage)
+
+		addTestResource(t, ctx, b, user, "note")
+		deleted := addTestResource(t, ctx, b, user, "note")
+		require.NoError(t, b.Delete(ctx, user, &deleted.ID))
+		_, err = b.AddUpload(ctx, user, BlobID(uuid.NewString()), &ResourceMeta{Salt: []byte("salt"), ByteSize: 10})
+		require.NoError(t, err)
+
+		usage, err = b.Usage(ctx, user)
+		require.NoError(t, err)
+		assert.Equal(t, &Usage{Bytes: 18, Items: 3}, usage)
+
+		// Chunks count once, whether resources refer them or not.
+		digest := sha256.Sum256([]byte("usage chunk"))
+		_, err = b.AddChunk(ctx, user, &Chunk{Digest: digest[:], Blob: BlobID(uuid.NewString()), ByteSize: 5})
+		require.NoError(t, err)
+		usage, err = b.Usage(ctx, user)
+		require.NoError(t, err)
+		assert.Equal(t, &Usage{Bytes: 23, Items: 3}, usage)
+		for i := 0; i < 2; i++ {
+			id := ResourceID(uuid.New())
+			_, err = b.AddChunkedResource(ctx, user, &id, &ResourceMeta{Salt: []byte("salt"), ByteSize: 5}, [][]byte{digest[:]})
+			require.NoError(t, err)
+		}
+		usage, err = b.Usage(ctx, user)
+		require.NoError(t, err)
+		assert.Equal(t, &Usage{Bytes: 23, Items: 5}, usage)
+
+		_, err = b.Quota(ctx, user)
+		assert.ErrorIs(t, err, ErrQuotaNotSet)
+
+		require.NoError(t, b.SetQuota(ctx, user, &Quota{MaxBytes: 100, MaxItems: 10}))
+		require.NoError(t, b.SetQuota(ctx, user, &Quota{MaxBytes: 200, MaxItems: 20}))
+		quota, err := b.Quota(ctx, user)
+		require.NoError(t, err)
+		assert.Equal(t, &Quota{MaxBytes: 200, MaxItems: 20}, quota)
+
+		require.NoError(t, b.DeleteQuota(ctx, user))
+		require.NoError(t, b.DeleteQuota(ctx, user))
+		_, err = b.Quota(ctx, user)
+		assert.ErrorIs(t, err, ErrQuotaNotSet)
+	})
+}
+
+func TestContract_Batch(t *testing.T) {
+	runContract(t, func(t *testing.T, ctx context.Context, b metadataBackend) {
+		user := addTestUser(t, ctx, b)
+		first, second := addTestResource(t, ctx, b, user, "note"), addTestResource(t, ctx, b, user, "note")
+		unknown := ResourceID(uuid.New())
+
+		items := []BatchItem{
+			{Op: BatchDelete, ID: first.ID},
+			{Op: BatchDelete, ID: unknown},
+			{Op: BatchStat, ID: second.ID},
+		}
+		results, err := b.Batch(ctx, user, items, &BatchOptions{Atomic: true})
+		require.NoError(t, err)
+		assert.ErrorIs(t, results[0].Err, ErrBatchAborted)
+		assert.ErrorIs(t, results[1].Err, ErrResourceNotFound)
+		assert.ErrorIs(t, results[2].Err, ErrBatchAborted)
+		_, err = b.Stat(ctx, user, &first.ID)
+		assert.NoError(t, err)
+
+		results, err = b.Batch(ctx, user, items, &BatchOptions{})
+		require.NoError(t, err)
+		require.NoError(t, results[0].Err)
+		assert.True(t, results[0].Info.IsDeleted)
+		assert.ErrorIs(t, results[1].Err, ErrResourceNotFound)
+		require.NoError(t, results[2].Err)
+		assert.Equal(t, second.ID, results[2].Info.ID)
+		_, err = b.Stat(ctx, user, &first.ID)
+		assert.ErrorIs(t, err, ErrResourceNotFound)
+
+		results, err = b.Batch(ctx, user, []BatchItem{
+			{Op: BatchRestore, ID: first.ID},
+			{Op: BatchRestore, ID: second.ID},
+		}, &BatchOptions{RestorableSince: time.Now().Add(-time.Minute)})
+		require.NoError(t, err)
+		require.NoError(t, results[0].Err)
+		assert.False(t, results[0].Info.IsDeleted)
+		assert.ErrorIs(t, results[1].Err, ErrNotInTrash)
+	})
+}
+
+func TestContract_BlobKeys(t *testing.T) {
+	runContract(t, func(t *testing.T, ctx context.Context, b metadataBackend) {
+		_, err := b.BlobKey(ctx, BlobID(uuid.NewString()))
+		assert.ErrorIs(t, err, ErrBlobKeyNotFound)
+
+		keys := make([]BlobKey, 3)
+		for i := range keys {
+			keys[i] = BlobKey{BlobID: BlobID(uuid.NewString()), KEKID: "old", Wrapped: []byte{byte(i)}}
+			require.NoError(t, b.AddBlobKey(ctx, &keys[i]))
+		}
+		assert.Error(t, b.AddBlobKey(ctx, &keys[0]))
+
+		key, err := b.BlobKey(ctx, keys[1].BlobID)
+		require.NoError(t, err)
+		assert.Equal(t, keys[1], *key)
+
+		keys[1].KEKID, keys[1].Wrapped = "new", []byte("rewrapped")
+		require.NoError(t, b.UpdateBlobKey(ctx, &keys[1]))
+		key, err = b.BlobKey(ctx, keys[1].BlobID)
+		require.NoError(t, err)
+		assert.Equal(t, keys[1], *key)
+
+		stale, err := b.StaleBlobKeys(ctx, "new", 10)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []BlobKey{keys[0], keys[2]}, stale)
+		stale, err = b.StaleBlobKeys(ctx, "new", 1)
+		require.NoError(t, err)
+		assert.Len(t, stale, 1)
+		kekIDs, err := b.KEKIDs(ctx)
+		require.NoError(t, err)
+		assert.Subset(t, kekIDs, []string{"old", "new"})
+		seen := make(map[string]bool)
+		for _, id := range kekIDs {
+			assert.False(t, seen[id], "duplicate kek id %s", id)
+			seen[id] = true
+		}
+
+		segments, err := b.BlobSegments(ctx, keys[0].BlobID)
+		require.NoError(t, err)
+		assert.Empty(t, segments)
+		for _, offset := range []uint64{30, 10, 20, 20} {
+			nonce := []byte{byte(offset)}
+			require.NoError(t, b.AddBlobSegment(ctx, keys[0].BlobID, &BlobSegment{Offset: offset, Nonce: nonce}))
+		}
+		// Segments past a new one are replaced by it.
+		segments, err = b.BlobSegments(ctx, keys[0].BlobID)
+		require.NoError(t, err)
+		assert.Equal(t, []BlobSegment{{Offset: 10, Nonce: []byte{10}}, {Offset: 20, Nonce: []byte{20}}}, segments)
+		assert.Error(t, b.AddBlobSegment(ctx, BlobID(uuid.NewString()), &BlobSegment{Nonce: []byte{0}}))
+
+		require.NoError(t, b.DeleteBlobKey(ctx, keys[0].BlobID))
+		require.NoError(t, b.DeleteBlobKey(ctx, keys[0].BlobID))
+		segments, err = b.BlobSegments(ctx, keys[0].BlobID)
+		require.NoError(t, err)
+		assert.Empty(t, segments)
+		_, err = b.BlobKey(ctx, keys[0].BlobID)
+		assert.ErrorIs(t, err, ErrBlobKeyNotFound)
+
+		// Updating a deleted key does nothing.
+		require.NoError(t, b.UpdateBlobKey(ctx, &keys[0]))
+		_, err = b.BlobKey(ctx, keys[0].BlobID)
+		assert.ErrorIs(t, err, ErrBlobKeyNotFound)
+	})
+}
+
+// blobBackends returns constructors of every blob store the contract tests
+// run against.
+func blobBackends() map[string]func(t *testing.T) BlobStore {
+	return map[string]func(t *testing.T) BlobStore{
+		"fs": func(t *testing.T) BlobStore {
+			store, err := NewFileBlobStore(t.TempDir())
+			require.NoError(t, err)
+			return store
+		},
+		"encrypted": func(t *testing.T) BlobStore {
+			return NewEncryptedBlobStore(NewMemoryBlobStore(), NewMemoryUserService(), newTestKeyring(t, "primary"))
+		},
+		"memory": func(*testing.T) BlobStore {
+			return NewMemoryBlobStore()
+		},
+		"s3": func(t *testing.T) BlobStore {
+			return newTestS3BlobStore(t)
+		},
+		"sqlite": func(t *testing.T) BlobStore {
+			return NewSQLiteBlobStore(newTestSQLiteStorage(t))
+		},
+	}
+}
+
+func TestContract_BlobStore(t *testing.T) {
+	for name, newStore := range blobBackends() {
+		newStore := newStore
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			store := newStore(t)
+
+			id, err := store.Create(ctx)
+			require.NoError(t, err)
+			for _, part := range []struct {
+				offset uint64
+				data   string
+			}{{0, "hello, "}, {7, "world"}} {
+				w, err := store.NewWriter(ctx, id, part.offset)
+				require.NoError(t, err)
+				_, err = w.Write([]byte(part.data))
+				require.NoError(t, err)
+				require.NoError(t, w.Close())
+			}
+			// Uncommitted blobs are readable, e.g. to verify them before a commit.
+			assert.Equal(t, "hello, world", readBlob(t, store, id))
+
+			require.NoError(t, store.Commit(ctx, id))
+			require.NoError(t, store.Commit(ctx, id))
+
+			r, err := store.Open(ctx, id)
+			require.NoError(t, err)
+			_, err = r.Seek(7, io.SeekStart)
+			require.NoError(t, err)
+			data, err := io.ReadAll(r)
+			require.NoError(t, err)
+			assert.Equal(t, "world", string(data))
+			size, err := r.Seek(0, io.SeekEnd)
+			require.NoError(t, err)
+			assert.Equal(t, int64(12), size)
+			require.NoError(t, r.Close())
+
+			walked := make([]BlobID, 0)
+			require.NoError(t, store.Walk(ctx, func(id BlobID, _ time.Time) error {
+				walked = append(walked, id)
+				return nil
+			}))
+			assert.Equal(t, []BlobID{id}, walked)
+
+			require.NoError(t, store.Delete(ctx, id))
+			assert.ErrorIs(t, store.Delete(ctx, id), ErrBlobNotFound)
+			_, err = store.Open(ctx, id)
+			assert.ErrorIs(t, err, ErrBlobNotFound)
+		})
+	}
+}
+
+// TestContract_Storage runs Storage made of every metadata backend.
+func TestContract_Storage(t *testing.T) {
+	runContract(t, func(t *testing.T, ctx context.Context, b metadataBackend) {
+		s := NewBlobStorage(b, NewMemoryBlobStore())
+		user := addTestUser(t, ctx, b)
+
+		data := []byte("resource data")
+		digest := sha256.Sum256(data)
+		meta := &ResourceMeta{Salt: []byte("salt"), ByteSize: uint64(len(data)), Digest: digest[:]}
+
+		res, err := s.Create(ctx, user, meta)
+		require.NoError(t, err)
+		_, err = res.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, res.Close())
+		created := *res.GetId()
+
+		session, err := s.CreateUpload(ctx, user, meta)
+		require.NoError(t, err)
+		_, err = s.AppendUpload(ctx, user, &session.ID, 0, data)
+		require.NoError(t, err)
+		uploaded, err := s.FinalizeUpload(ctx, user, &session.ID)
+		require.NoError(t, err)
+
+		require.NoError(t, s.PutChunk(ctx, user, digest[:], data))
+		chunked, err := s.CreateChunked(ctx, user, meta, [][]byte{digest[:]})
+		require.NoError(t, err)
+
+		for _, id := range []ResourceID{created, uploaded.ID, chunked.ID} {
+			res, err := s.Open(ctx, user, &id)
+			require.NoError(t, err)
+			remote, err := io.ReadAll(res)
+			require.NoError(t, err)
+			assert.Equal(t, data, remote)
+			require.NoError(t, res.Close())
+
+			require.NoError(t, s.Delete(ctx, user, &id))
+		}
+
+		purged, err := s.EmptyTrash(ctx, user)
+		require.NoError(t, err)
+		assert.Equal(t, 3, purged)
+
+		page, _, err := s.List(ctx, user, &ListOptions{PageSize: 10, IncludeDeleted: true})
+		require.NoError(t, err)
+		assert.Empty(t, page)
+	})
+}
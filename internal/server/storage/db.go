@@ -2,35 +2,45 @@ package storage
 
 import (
 	"context"
-	"encoding/hex"
+	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/hashicorp/go-multierror"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 var (
-	_ UserService = (*dbStorage)(nil)
-	_ Storage     = (*dbStorage)(nil)
-
-	_ Resource = (*dbResource)(nil)
+	_ UserService   = (*dbStorage)(nil)
+	_ MetadataStore = (*dbStorage)(nil)
+	_ Pinger        = (*dbStorage)(nil)
 )
 
 const (
 	_emptyOID = uint32(0)
 
-	_addUser = `insert into users (id, login, key_salt, salt, secret) VALUES ('%s', '%s', '\x%s', '\x%s', '\x%s');`
+	_pgUniqueViolation = "23505"
+
+	_addUser = `insert into users (id, login, key_salt, salt, secret) values ($1, $2, $3, $4, $5);`
 
 	_getUserByLogin = `select id, login, salt, secret from users where is_deleted='false' and login=$1;`
 	_getUserByID    = `select id, login, salt, secret from users where is_deleted='false' and id=$1;`
 
-	_addNewResource = `insert into user_data (user_id, resource_id, data_id, salt) values('%s', '%s', '%d', '\x%s');`
-	_getResource    = `select data_id, salt from user_data where resource_id=$1 and user_id=$2 and is_deleted='false';`
-	_listResources  = `select resource_id, salt from user_data where user_id=$1 and is_deleted='false';`
-	_deleteResource = `update user_data set is_deleted='true' where user_id=$1 and resource_id=$2;`
+	_addNewResource = `insert into user_data (user_id, resource_id, blob_id, salt, metadata, kind, sha256, byte_size)
+					values($1, $2, $3, $4, $5, $6, $7, $8)
+					returning resource_id, salt, metadata, kind, sha256, byte_size, version, created, last_update, is_deleted;`
+	_getResource    = `select resource_id, salt, metadata, kind, sha256, byte_size, version, created, last_update, is_deleted, coalesce(blob_id, '') from user_data where resource_id=$1 and user_id=$2 and is_deleted='false';`
+	_statResource   = `select resource_id, salt, metadata, kind, sha256, byte_size, version, created, last_update, is_deleted from user_data where resource_id=$1 and user_id=$2 and is_deleted='false';`
+	_listResources  = `select resource_id, salt, metadata, kind, sha256, byte_size, version, created, last_update, is_deleted, id from user_data where user_id=$1`
+	_deleteResource = `update user_data set is_deleted='true', last_update=now() where user_id=$1 and resource_id=$2;`
+
+	_restoreResource = `update user_data set is_deleted='false', last_update=now()
+					where user_id=$1 and resource_id=$2 and is_deleted='true' and last_update>=$3
+					returning resource_id, salt, metadata, kind, sha256, byte_size, version, created, last_update, is_deleted;`
 )
 
 type dbStorage struct {
@@ -38,8 +48,18 @@ type dbStorage struct {
 	operationTimeout time.Duration
 }
 
-func NewDatabaseUserService(ctx context.Context, dsn string, operationTimeout time.Duration) (*dbStorage, error) {
-	conn, err := pgxpool.New(ctx, dsn)
+func NewDatabaseUserService(ctx context.Context, dsn string, operationTimeout time.Duration, opts ...DatabaseOption) (*dbStorage, error) {
+	cfg, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, err
+	}
+	tracer := &queryTracer{}
+	for _, o := range opts {
+		o(tracer)
+	}
+	cfg.ConnConfig.Tracer = tracer
+
+	conn, err := pgxpool.NewWithConfig(ctx, cfg)
 	if err != nil {
 		return nil, err
 	}
@@ -67,9 +87,11 @@ func (d *dbStorage) Add(ctx context.Context, login string, keySalt, salt, secret
 		_ = tx.Rollback(c)
 	}()
 
-	insertQuery := fmt.Sprintf(_addUser, id.String(), login,
-		hex.EncodeToString(keySalt), hex.EncodeToString(salt), hex.EncodeToString(secret))
-	if _, err := tx.Exec(c, insertQuery); err != nil {
+	if _, err := tx.Exec(c, _addUser, id.String(), login, keySalt, salt, secret); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == _pgUniqueViolation {
+			return nil, ErrUserExists
+		}
 		return nil, err
 	}
 
@@ -87,7 +109,7 @@ func (d *dbStorage) GetByLogin(ctx context.Context, login string) (*User, error)
 	)
 	row := d.dbConn.QueryRow(c, _getUserByLogin, login)
 	if err := row.Scan(&id, &user.Login, &user.Salt, &user.Secret); err != nil {
-		return nil, err
+		return nil, noRows(err, ErrUserNotFound)
 	}
 
 	user.ID = UserID(id)
@@ -105,7 +127,7 @@ func (d *dbStorage) GetByID(ctx context.Context, id string) (*User, error) {
 	)
 	row := d.dbConn.QueryRow(c, _getUserByID, id)
 	if err := row.Scan(&userID, &user.Login, &user.Salt, &user.Secret); err != nil {
-		return nil, err
+		return nil, noRows(err, ErrUserNotFound)
 	}
 
 	user.ID = UserID(userID)
@@ -113,149 +135,180 @@ func (d *dbStorage) GetByID(ctx context.Context, id string) (*User, error) {
 	return user, nil
 }
 
+// PoolStat returns statistics of the connection pool.
+func (d *dbStorage) PoolStat() *pgxpool.Stat {
+	return d.dbConn.Stat()
+}
+
+// Ping checks that the database accepts queries.
+func (d *dbStorage) Ping(ctx context.Context) error {
+	c, cancel := context.WithTimeout(ctx, d.operationTimeout)
+	defer cancel()
+	return d.dbConn.Ping(c)
+}
+
 func (d *dbStorage) Close() error {
 	d.dbConn.Close()
 	return nil
 }
 
-func (d *dbStorage) Create(ctx context.Context, user *UserID, salt []byte) (Resource, error) {
-	resourceId, err := uuid.NewRandom()
-	if err != nil {
-		return nil, err
-	}
+func (d *dbStorage) AddResource(ctx context.Context, user *UserID, id *ResourceID, blob BlobID, meta *ResourceMeta) (*ResourceInfo, error) {
+	return scanResourceInfo(d.dbConn.QueryRow(ctx, _addNewResource, user.String(), id.String(), string(blob),
+		meta.Salt, meta.Metadata, meta.Kind, meta.Digest, int64(meta.ByteSize)))
+}
 
-	tx, err := d.dbConn.Begin(ctx)
+func (d *dbStorage) ResourceBlob(ctx context.Context, user *UserID, id *ResourceID) (*ResourceInfo, BlobID, error) {
+	var blob string
+	info, err := scanResourceInfo(d.dbConn.QueryRow(ctx, _getResource, id, user), &blob)
 	if err != nil {
-		return nil, err
+		return nil, "", noRows(err, ErrResourceNotFound)
 	}
+	return info, BlobID(blob), nil
+}
 
-	lo := tx.LargeObjects()
-	oid, err := lo.Create(ctx, _emptyOID)
+func (d *dbStorage) Delete(ctx context.Context, user *UserID, id *ResourceID) error {
+	tag, err := d.dbConn.Exec(ctx, _deleteResource, user, id)
 	if err != nil {
-		if e := tx.Rollback(ctx); e != nil {
-			err = multierror.Append(err, e)
-		}
-		return nil, err
+		return err
 	}
-
-	addQuery := fmt.Sprintf(_addNewResource, user.String(), resourceId.String(), oid, hex.EncodeToString(salt))
-	if _, err := tx.Exec(ctx, addQuery); err != nil {
-		if e := tx.Rollback(ctx); e != nil {
-			err = multierror.Append(err, e)
-		}
-		return nil, err
+	if tag.RowsAffected() == 0 {
+		return ErrResourceNotFound
 	}
+	return nil
+}
 
-	obj, err := lo.Open(ctx, oid, pgx.LargeObjectModeRead|pgx.LargeObjectModeWrite)
+func (d *dbStorage) Restore(ctx context.Context, user *UserID, id *ResourceID, deletedAfter time.Time) (*ResourceInfo, error) {
+	info, err := scanResourceInfo(d.dbConn.QueryRow(ctx, _restoreResource, user, id, deletedAfter))
 	if err != nil {
-		if e := tx.Rollback(ctx); e != nil {
-			err = multierror.Append(err, e)
-		}
-		return nil, err
+		return nil, noRows(err, ErrNotInTrash)
 	}
-
-	return &dbResource{
-		ctx:  ctx,
-		tx:   tx,
-		lo:   obj,
-		id:   ResourceID(resourceId),
-		salt: salt,
-	}, nil
+	return info, nil
 }
 
-func (d *dbStorage) Open(ctx context.Context, user *UserID, id *ResourceID) (Resource, error) {
-	tx, err := d.dbConn.Begin(ctx)
+func (d *dbStorage) List(ctx context.Context, userId *UserID, opts *ListOptions) ([]ResourceInfo, string, error) {
+	cursor, err := ParsePageToken(opts)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
+	query, args := buildListQuery(userId, opts, cursor)
+	rows, err := d.dbConn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
 	var (
-		oid  = _emptyOID
-		salt []byte
+		resources = make([]ResourceInfo, 0, opts.PageSize)
+		last      *ListCursor
+		nextToken string
 	)
-	if err := tx.QueryRow(ctx, _getResource, id, user).Scan(&oid, &salt); err != nil {
-		if e := tx.Rollback(ctx); e != nil {
-			err = multierror.Append(err, e)
+
+	for rows.Next() {
+		if len(resources) == opts.PageSize {
+			nextToken = last.Token(opts)
+			break
 		}
-		return nil, err
-	}
 
-	lo := tx.LargeObjects()
-	obj, err := lo.Open(ctx, oid, pgx.LargeObjectModeRead|pgx.LargeObjectModeWrite)
-	if err != nil {
-		return nil, err
+		var rowID int64
+		info, err := scanResourceInfo(rows, &rowID)
+		if err != nil {
+			return nil, "", err
+		}
+		resources = append(resources, *info)
+		last = &ListCursor{
+			OrderBy: opts.OrderBy,
+			RowID:   rowID,
+			Updated: info.UpdatedAt.UnixMicro(),
+		}
 	}
 
-	return &dbResource{
-		ctx:  ctx,
-		tx:   tx,
-		lo:   obj,
-		id:   *id,
-		salt: salt,
-	}, nil
-}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
 
-func (d *dbStorage) Delete(ctx context.Context, user *UserID, id *ResourceID) error {
-	_, err := d.dbConn.Exec(ctx, _deleteResource, user, id)
-	return err
+	return resources, nextToken, nil
 }
 
-func (d *dbStorage) List(ctx context.Context, userId *UserID) ([]Resource, error) {
-	rows, err := d.dbConn.Query(ctx, _listResources, userId.String())
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
+// buildListQuery prepares a keyset pagination query. One extra row is requested
+// to find out whether there is a next page.
+func buildListQuery(userId *UserID, opts *ListOptions, cursor *ListCursor) (string, []any) {
+	var (
+		query strings.Builder
+		args  = []any{userId.String()}
+	)
 
-	resources := make([]Resource, 0)
+	query.WriteString(_listResources)
 
-	for rows.Next() {
-		var (
-			id   uuid.UUID
-			salt []byte
-		)
-		if err := rows.Scan(&id, &salt); err != nil {
-			return nil, err
-		}
-		resources = append(resources, &dbResource{id: ResourceID(id), salt: salt})
+	if opts.DeletedOnly {
+		query.WriteString(` and is_deleted='true'`)
+	} else if !opts.IncludeDeleted {
+		query.WriteString(` and is_deleted='false'`)
 	}
 
-	if err := rows.Err(); err != nil {
-		return nil, err
+	if opts.UpdatedAfter != nil {
+		args = append(args, *opts.UpdatedAfter)
+		query.WriteString(fmt.Sprintf(` and last_update>$%d`, len(args)))
 	}
 
-	return resources, nil
-}
+	if opts.Kind != nil {
+		args = append(args, *opts.Kind)
+		query.WriteString(fmt.Sprintf(` and kind=$%d`, len(args)))
+	}
 
-type dbResource struct {
-	ctx       context.Context
-	tx        pgx.Tx
-	lo        *pgx.LargeObject
-	id        ResourceID
-	salt      []byte
-	isDeleted bool
-}
+	switch opts.OrderBy {
+	case ListOrderUpdated:
+		if cursor != nil {
+			args = append(args, time.UnixMicro(cursor.Updated), cursor.RowID)
+			query.WriteString(fmt.Sprintf(` and (last_update, id)>($%d, $%d)`, len(args)-1, len(args)))
+		}
+		query.WriteString(` order by last_update, id`)
+	default:
+		if cursor != nil {
+			args = append(args, cursor.RowID)
+			query.WriteString(fmt.Sprintf(` and id>$%d`, len(args)))
+		}
+		query.WriteString(` order by id`)
+	}
 
-func (d *dbResource) Close() error {
-	return d.tx.Commit(d.ctx)
-}
+	args = append(args, opts.PageSize+1)
+	query.WriteString(fmt.Sprintf(` limit $%d;`, len(args)))
 
-func (d *dbResource) Write(p []byte) (n int, err error) {
-	return d.lo.Write(p)
+	return query.String(), args
 }
 
-func (d *dbResource) Read(p []byte) (n int, err error) {
-	return d.lo.Read(p)
+func (d *dbStorage) Stat(ctx context.Context, user *UserID, id *ResourceID) (*ResourceInfo, error) {
+	info, err := scanResourceInfo(d.dbConn.QueryRow(ctx, _statResource, id, user))
+	if err != nil {
+		return nil, noRows(err, ErrResourceNotFound)
+	}
+	return info, nil
 }
 
-func (d *dbResource) GetId() *ResourceID {
-	return &d.id
+// noRows replaces pgx.ErrNoRows and sql.ErrNoRows with a domain error, so
+// a caller doesn't depend on the driver.
+func noRows(err, replacement error) error {
+	if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows) {
+		return replacement
+	}
+	return err
 }
 
-func (d *dbResource) IsDeleted() bool {
-	return d.isDeleted
-}
+func scanResourceInfo(row pgx.Row, extra ...any) (*ResourceInfo, error) {
+	var (
+		info     = &ResourceInfo{}
+		id       uuid.UUID
+		byteSize int64
+		version  int64
+	)
+	dest := []any{&id, &info.Salt, &info.Metadata, &info.Kind, &info.Digest, &byteSize, &version,
+		&info.CreatedAt, &info.UpdatedAt, &info.IsDeleted}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
 
-func (d *dbResource) Salt() ([]byte, error) {
-	return d.salt, nil
+	info.ID = ResourceID(id)
+	info.ByteSize = uint64(byteSize)
+	info.Version = uint64(version)
+	return info, nil
 }
@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const _batchDeleteResource = `update user_data set is_deleted='true', last_update=now()
+					where user_id=$1 and resource_id=$2 and is_deleted='false'
+					returning resource_id, salt, metadata, kind, sha256, byte_size, version, created, last_update, is_deleted;`
+
+var errBatchRollback = errors.New("rollback batch")
+
+// Batch runs all items in a single transaction. Every item of a non atomic batch
+// runs in its own savepoint, so a failed item doesn't affect the rest.
+func (d *dbStorage) Batch(ctx context.Context, user *UserID, items []BatchItem, opts *BatchOptions) ([]BatchResult, error) {
+	results := make([]BatchResult, len(items))
+	err := pgx.BeginFunc(ctx, d.dbConn, func(tx pgx.Tx) error {
+		for i := range items {
+			if opts.Atomic {
+				results[i].Info, results[i].Err = runBatchItem(ctx, tx, user, &items[i], opts)
+				if results[i].Err != nil {
+					abortBatch(results, i)
+					return errBatchRollback
+				}
+				continue
+			}
+
+			err := pgx.BeginFunc(ctx, tx, func(sp pgx.Tx) error {
+				results[i].Info, results[i].Err = runBatchItem(ctx, sp, user, &items[i], opts)
+				return results[i].Err
+			})
+			if err != nil && results[i].Err == nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errBatchRollback) {
+		return nil, err
+	}
+	return results, nil
+}
+
+func runBatchItem(ctx context.Context, tx pgx.Tx, user *UserID, item *BatchItem, opts *BatchOptions) (*ResourceInfo, error) {
+	var (
+		info *ResourceInfo
+		err  error
+	)
+	switch item.Op {
+	case BatchStat:
+		info, err = scanResourceInfo(tx.QueryRow(ctx, _statResource, item.ID, user))
+	case BatchDelete:
+		info, err = scanResourceInfo(tx.QueryRow(ctx, _batchDeleteResource, user, item.ID))
+	case BatchRestore:
+		info, err = scanResourceInfo(tx.QueryRow(ctx, _restoreResource, user, item.ID, opts.RestorableSince))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotInTrash
+		}
+	default:
+		return nil, fmt.Errorf("unknown batch operation %d", item.Op)
+	}
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrResourceNotFound
+	}
+	return info, err
+}
+
+// abortBatch marks all items of a batch except the failed one as aborted.
+func abortBatch(results []BatchResult, failed int) {
+	for i := range results {
+		if i != failed {
+			results[i] = BatchResult{Err: ErrBatchAborted}
+		}
+	}
+}
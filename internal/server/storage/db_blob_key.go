@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"context"
+)
+
+var _ BlobKeyStore = (*dbStorage)(nil)
+
+const (
+	_addBlobKey    = `insert into blob_keys (blob_id, kek_id, wrapped_key) values ($1, $2, $3);`
+	_getBlobKey    = `select kek_id, wrapped_key from blob_keys where blob_id=$1;`
+	_updateBlobKey = `update blob_keys set kek_id=$2, wrapped_key=$3, last_update=now() where blob_id=$1;`
+	_deleteBlobKey = `delete from blob_keys where blob_id=$1;`
+	_kekIDs        = `select distinct kek_id from blob_keys;`
+	_staleBlobKeys = `select blob_id, kek_id, wrapped_key from blob_keys where kek_id<>$1 limit $2;`
+
+	_addBlobSegment = `with dropped as (delete from blob_segments where blob_id=$1 and pos>$2)
+					insert into blob_segments (blob_id, pos, nonce) values ($1, $2, $3)
+					on conflict (blob_id, pos) do update set nonce=excluded.nonce;`
+	_blobSegments = `select pos, nonce from blob_segments where blob_id=$1 order by pos;`
+)
+
+func (d *dbStorage) AddBlobKey(ctx context.Context, key *BlobKey) error {
+	_, err := d.dbConn.Exec(ctx, _addBlobKey, string(key.BlobID), key.KEKID, key.Wrapped)
+	return err
+}
+
+func (d *dbStorage) BlobKey(ctx context.Context, id BlobID) (*BlobKey, error) {
+	key := &BlobKey{BlobID: id}
+	if err := d.dbConn.QueryRow(ctx, _getBlobKey, string(id)).Scan(&key.KEKID, &key.Wrapped); err != nil {
+		return nil, noRows(err, ErrBlobKeyNotFound)
+	}
+	return key, nil
+}
+
+func (d *dbStorage) UpdateBlobKey(ctx context.Context, key *BlobKey) error {
+	_, err := d.dbConn.Exec(ctx, _updateBlobKey, string(key.BlobID), key.KEKID, key.Wrapped)
+	return err
+}
+
+func (d *dbStorage) DeleteBlobKey(ctx context.Context, id BlobID) error {
+	_, err := d.dbConn.Exec(ctx, _deleteBlobKey, string(id))
+	return err
+}
+
+func (d *dbStorage) KEKIDs(ctx context.Context) ([]string, error) {
+	rows, err := d.dbConn.Query(ctx, _kekIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make([]string, 0)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (d *dbStorage) StaleBlobKeys(ctx context.Context, kekID string, limit int) ([]BlobKey, error) {
+	rows, err := d.dbConn.Query(ctx, _staleBlobKeys, kekID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := make([]BlobKey, 0)
+	for rows.Next() {
+		var (
+			key BlobKey
+			id  string
+		)
+		if err := rows.Scan(&id, &key.KEKID, &key.Wrapped); err != nil {
+			return nil, err
+		}
+		key.BlobID = BlobID(id)
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+func (d *dbStorage) AddBlobSegment(ctx context.Context, id BlobID, segment *BlobSegment) error {
+	_, err := d.dbConn.Exec(ctx, _addBlobSegment, string(id), int64(segment.Offset), segment.Nonce)
+	return err
+}
+
+func (d *dbStorage) BlobSegments(ctx context.Context, id BlobID) ([]BlobSegment, error) {
+	rows, err := d.dbConn.Query(ctx, _blobSegments, string(id))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	segments := make([]BlobSegment, 0)
+	for rows.Next() {
+		var (
+			segment BlobSegment
+			pos     int64
+		)
+		if err := rows.Scan(&pos, &segment.Nonce); err != nil {
+			return nil, err
+		}
+		segment.Offset = uint64(pos)
+		segments = append(segments, segment)
+	}
+	return segments, rows.Err()
+}
@@ -0,0 +1,146 @@
+package storage
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	_getChunks = `select sha256, blob_id, byte_size from user_chunks where user_id=$1 and sha256=any($2);`
+	_addChunk  = `insert into user_chunks (user_id, sha256, blob_id, byte_size) values ($1, $2, $3, $4)
+					on conflict (user_id, sha256) do nothing;`
+	_addResourceChunk = `insert into resource_chunks (resource_id, seq, user_id, sha256) values ($1, $2, $3, $4);`
+	_referenceChunk   = `update user_chunks set ref_count=ref_count+1 where user_id=$1 and sha256=$2;`
+	_resourceChunks   = `select c.sha256, c.blob_id, c.byte_size from resource_chunks r
+					join user_chunks c on c.user_id=r.user_id and c.sha256=r.sha256
+					where r.resource_id=$1 and r.user_id=$2 order by r.seq;`
+)
+
+func (d *dbStorage) MissingChunks(ctx context.Context, user *UserID, digests [][]byte) ([][]byte, error) {
+	existing, err := d.chunksByDigest(ctx, user, digests)
+	if err != nil {
+		return nil, err
+	}
+
+	missing := make([][]byte, 0)
+	for _, digest := range digests {
+		if _, ok := existing[hex.EncodeToString(digest)]; !ok {
+			missing = append(missing, digest)
+		}
+	}
+	return missing, nil
+}
+
+func (d *dbStorage) Chunks(ctx context.Context, user *UserID, digests [][]byte) ([]Chunk, error) {
+	existing, err := d.chunksByDigest(ctx, user, digests)
+	if err != nil {
+		return nil, err
+	}
+
+	chunks := make([]Chunk, 0, len(digests))
+	for _, digest := range digests {
+		chunk, ok := existing[hex.EncodeToString(digest)]
+		if !ok {
+			return nil, fmt.Errorf("%w: %x", ErrChunkMissing, digest)
+		}
+		chunks = append(chunks, chunk)
+	}
+	return chunks, nil
+}
+
+func (d *dbStorage) AddChunk(ctx context.Context, user *UserID, chunk *Chunk) (bool, error) {
+	tag, err := d.dbConn.Exec(ctx, _addChunk, user.String(), chunk.Digest, string(chunk.Blob), int64(chunk.ByteSize))
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() != 0, nil
+}
+
+// AddChunkedResource adds a resource without a blob of its own. References are
+// counted per manifest entry, so a chunk repeated in a resource is counted twice.
+func (d *dbStorage) AddChunkedResource(ctx context.Context, user *UserID, id *ResourceID, meta *ResourceMeta, digests [][]byte) (*ResourceInfo, error) {
+	tx, err := d.dbConn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	info, err := scanResourceInfo(tx.QueryRow(ctx, _addNewResource, user.String(), id.String(), nil,
+		meta.Salt, meta.Metadata, meta.Kind, meta.Digest, int64(meta.ByteSize)))
+	if err != nil {
+		return nil, err
+	}
+
+	for i, digest := range digests {
+		tag, err := tx.Exec(ctx, _referenceChunk, user.String(), digest)
+		if err != nil {
+			return nil, err
+		}
+		if tag.RowsAffected() == 0 {
+			return nil, fmt.Errorf("%w: %x", ErrChunkMissing, digest)
+		}
+
+		if _, err := tx.Exec(ctx, _addResourceChunk, id.String(), i, user.String(), digest); err != nil {
+			return nil, err
+		}
+	}
+
+	return info, tx.Commit(ctx)
+}
+
+func (d *dbStorage) ResourceChunks(ctx context.Context, user *UserID, id *ResourceID) ([]Chunk, error) {
+	rows, err := d.dbConn.Query(ctx, _resourceChunks, id.String(), user.String())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	chunks := make([]Chunk, 0)
+	for rows.Next() {
+		chunk, err := scanChunk(rows)
+		if err != nil {
+			return nil, err
+		}
+		chunks = append(chunks, *chunk)
+	}
+	return chunks, rows.Err()
+}
+
+// chunksByDigest returns existing chunks of a user keyed by hex encoded digests.
+func (d *dbStorage) chunksByDigest(ctx context.Context, user *UserID, digests [][]byte) (map[string]Chunk, error) {
+	rows, err := d.dbConn.Query(ctx, _getChunks, user.String(), digests)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	chunks := make(map[string]Chunk, len(digests))
+	for rows.Next() {
+		chunk, err := scanChunk(rows)
+		if err != nil {
+			return nil, err
+		}
+		chunks[hex.EncodeToString(chunk.Digest)] = *chunk
+	}
+	return chunks, rows.Err()
+}
+
+func scanChunk(row pgx.Row) (*Chunk, error) {
+	var (
+		chunk    = &Chunk{}
+		blob     string
+		byteSize int64
+	)
+	if err := row.Scan(&chunk.Digest, &blob, &byteSize); err != nil {
+		return nil, err
+	}
+
+	chunk.Blob = BlobID(blob)
+	chunk.ByteSize = uint64(byteSize)
+	return chunk, nil
+}
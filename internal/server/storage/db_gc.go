@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	_lockDeletedResources = `select resource_id, coalesce(blob_id, '') from user_data
+					where is_deleted='true' and last_update<$1 limit $2 for update skip locked;`
+	_lockUserDeletedResources = `select resource_id, coalesce(blob_id, '') from user_data
+					where user_id=$1 and is_deleted='true' limit $2 for update skip locked;`
+	_releaseResourceChunks = `update user_chunks c set ref_count=c.ref_count-r.refs
+					from (select user_id, sha256, count(*) as refs from resource_chunks
+						where resource_id=any($1) group by user_id, sha256) r
+					where c.user_id=r.user_id and c.sha256=r.sha256;`
+	_deleteResourceChunks = `delete from resource_chunks where resource_id=any($1);`
+	_purgeResources       = `delete from user_data where resource_id=any($1);`
+	_purgeChunks          = `delete from user_chunks where (user_id, sha256) in
+					(select user_id, sha256 from user_chunks where ref_count<=0 and created<$1 limit $2 for update skip locked)
+					returning blob_id;`
+	_unreferencedBlobs = `select b.id from unnest($1::varchar[]) as b(id)
+					where not exists (select 1 from user_data where blob_id=b.id)
+					and not exists (select 1 from upload_sessions where blob_id=b.id)
+					and not exists (select 1 from user_chunks where blob_id=b.id);`
+)
+
+func (d *dbStorage) PurgeDeleted(ctx context.Context, deletedBefore time.Time, limit int) (int, []BlobID, error) {
+	return d.purgeResources(ctx, limit, _lockDeletedResources, deletedBefore, limit)
+}
+
+func (d *dbStorage) PurgeUserDeleted(ctx context.Context, user *UserID, limit int) (int, []BlobID, error) {
+	return d.purgeResources(ctx, limit, _lockUserDeletedResources, user, limit)
+}
+
+// purgeResources removes resources selected and locked by lockQuery.
+func (d *dbStorage) purgeResources(ctx context.Context, limit int, lockQuery string, args ...any) (int, []BlobID, error) {
+	var (
+		ids   = make([]uuid.UUID, 0, limit)
+		blobs = make([]BlobID, 0, limit)
+	)
+
+	err := pgx.BeginFunc(ctx, d.dbConn, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, lockQuery, args...)
+		if err != nil {
+			return err
+		}
+
+		for rows.Next() {
+			var (
+				id   uuid.UUID
+				blob string
+			)
+			if err := rows.Scan(&id, &blob); err != nil {
+				rows.Close()
+				return err
+			}
+			ids = append(ids, id)
+			if blob != "" {
+				blobs = append(blobs, BlobID(blob))
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		if len(ids) == 0 {
+			return nil
+		}
+
+		for _, query := range []string{_releaseResourceChunks, _deleteResourceChunks, _purgeResources} {
+			if _, err := tx.Exec(ctx, query, ids); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return len(ids), blobs, nil
+}
+
+func (d *dbStorage) PurgeChunks(ctx context.Context, createdBefore time.Time, limit int) ([]BlobID, error) {
+	return d.queryBlobs(ctx, _purgeChunks, createdBefore, limit)
+}
+
+func (d *dbStorage) UnreferencedBlobs(ctx context.Context, blobs []BlobID) ([]BlobID, error) {
+	ids := make([]string, 0, len(blobs))
+	for _, b := range blobs {
+		ids = append(ids, string(b))
+	}
+	return d.queryBlobs(ctx, _unreferencedBlobs, ids)
+}
+
+func (d *dbStorage) queryBlobs(ctx context.Context, query string, args ...any) ([]BlobID, error) {
+	rows, err := d.dbConn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	blobs := make([]BlobID, 0)
+	for rows.Next() {
+		var blob string
+		if err := rows.Scan(&blob); err != nil {
+			return nil, err
+		}
+		blobs = append(blobs, BlobID(blob))
+	}
+	return blobs, rows.Err()
+}
@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	_getUsage = `select
+					(select coalesce(sum(byte_size), 0) from user_data where user_id=$1 and blob_id is not null) +
+					(select coalesce(sum(byte_size), 0) from upload_sessions where user_id=$1) +
+					(select coalesce(sum(byte_size), 0) from user_chunks where user_id=$1),
+					(select count(*) from user_data where user_id=$1) +
+					(select count(*) from upload_sessions where user_id=$1);`
+	_getQuota = `select max_bytes, max_items from user_quotas where user_id=$1;`
+	_setQuota = `insert into user_quotas (user_id, max_bytes, max_items) values ($1, $2, $3)
+					on conflict (user_id) do update set max_bytes=excluded.max_bytes, max_items=excluded.max_items, last_update=now();`
+	_deleteQuota = `delete from user_quotas where user_id=$1;`
+)
+
+func (d *dbStorage) Usage(ctx context.Context, user *UserID) (*Usage, error) {
+	var bytes, items int64
+	if err := d.dbConn.QueryRow(ctx, _getUsage, user.String()).Scan(&bytes, &items); err != nil {
+		return nil, err
+	}
+	return &Usage{Bytes: uint64(bytes), Items: uint64(items)}, nil
+}
+
+func (d *dbStorage) Quota(ctx context.Context, user *UserID) (*Quota, error) {
+	var maxBytes, maxItems int64
+	err := d.dbConn.QueryRow(ctx, _getQuota, user.String()).Scan(&maxBytes, &maxItems)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrQuotaNotSet
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Quota{MaxBytes: uint64(maxBytes), MaxItems: uint64(maxItems)}, nil
+}
+
+func (d *dbStorage) SetQuota(ctx context.Context, user *UserID, quota *Quota) error {
+	_, err := d.dbConn.Exec(ctx, _setQuota, user.String(), int64(quota.MaxBytes), int64(quota.MaxItems))
+	return err
+}
+
+func (d *dbStorage) DeleteQuota(ctx context.Context, user *UserID) error {
+	_, err := d.dbConn.Exec(ctx, _deleteQuota, user.String())
+	return err
+}
@@ -0,0 +1,109 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"github.com/r4start/goph-keeper/internal/logging"
+	"github.com/r4start/goph-keeper/internal/tracing"
+)
+
+var _ pgx.QueryTracer = (*queryTracer)(nil)
+
+// QueryObserver receives timings of database queries. An operation is a verb
+// and a table of a query, e.g. "select user_data".
+type QueryObserver func(operation string, duration time.Duration, err error)
+
+type DatabaseOption func(t *queryTracer)
+
+// WithQueryObserver passes timings of all queries to observe.
+func WithQueryObserver(observe QueryObserver) DatabaseOption {
+	return func(t *queryTracer) {
+		t.observe = observe
+	}
+}
+
+type queryStartKey struct{}
+
+type queryStart struct {
+	operation string
+	at        time.Time
+	span      trace.Span
+}
+
+// queryTracer records a span of every query, large object I/O included,
+// reports query timings to an observer and logs failed queries with a logger
+// of a request. Spans name an operation only, neither statements nor
+// arguments are exported, as they may hold user data.
+type queryTracer struct {
+	observe QueryObserver
+}
+
+func (t *queryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	operation := queryOperation(data.SQL)
+	ctx, span := tracing.Start(ctx, "db "+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "postgresql"),
+			attribute.String("db.operation", operation),
+		))
+
+	return context.WithValue(ctx, queryStartKey{}, &queryStart{
+		operation: operation,
+		at:        time.Now(),
+		span:      span,
+	})
+}
+
+func (t *queryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	start, ok := ctx.Value(queryStartKey{}).(*queryStart)
+	if !ok {
+		return
+	}
+
+	duration := time.Since(start.at)
+	tracing.End(start.span, data.Err)
+	if t.observe != nil {
+		t.observe(start.operation, duration, data.Err)
+	}
+
+	// Query arguments are never logged, they hold user data.
+	logger := logging.FromContext(ctx)
+	fields := []zap.Field{zap.String("operation", start.operation), zap.Duration("duration", duration)}
+	if data.Err != nil && !errors.Is(data.Err, pgx.ErrNoRows) {
+		logger.Warn("query failed", append(fields, zap.Error(data.Err))...)
+		return
+	}
+	logger.Debug("query", fields...)
+}
+
+// queryOperation names a query by its verb and the first table it touches, so
+// the names are few and don't depend on arguments. Selects of functions, like
+// large object calls, are named by the function.
+func queryOperation(sql string) string {
+	words := strings.Fields(strings.ToLower(sql))
+	if len(words) == 0 {
+		return "unknown"
+	}
+
+	verb := words[0]
+	for i, w := range words[:len(words)-1] {
+		if w == "from" || w == "into" || (w == "update" && i == 0) {
+			return verb + " " + strings.Trim(words[i+1], "();,")
+		}
+	}
+
+	if verb == "select" && len(words) > 1 {
+		if name, _, ok := strings.Cut(words[1], "("); ok {
+			return verb + " " + name
+		}
+	}
+	return verb
+}
@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestQueryOperation(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want string
+	}{
+		{_getUserByLogin, "select users"},
+		{_addNewResource, "insert user_data"},
+		{_deleteResource, "update user_data"},
+		{"delete from chunks where user_id=$1", "delete chunks"},
+		{"select lo_create(0)", "select lo_create"},
+		{"select loread($1, $2)", "select loread"},
+		{"with deleted as (select id from user_data) select 1", "with user_data"},
+		{"  ", "unknown"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, queryOperation(tt.sql), tt.sql)
+	}
+}
+
+func TestQueryTracer_NoArguments(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(trace.NewNoopTracerProvider())
+
+	args := []any{"c0ffee00-0000-0000-0000-000000000000", "secret-login", []byte("key salt"), []byte("salt"), []byte("secret")}
+	tracer := &queryTracer{}
+	ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: _addUser, Args: args})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{})
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "db insert users", spans[0].Name())
+	for _, attr := range spans[0].Attributes() {
+		assert.NotEqual(t, "db.statement", string(attr.Key))
+		for _, arg := range []string{"c0ffee00", "secret-login", "key salt", "salt", "secret"} {
+			assert.NotContains(t, attr.Value.Emit(), arg, attr.Key)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	_addUpload = `insert into upload_sessions (id, user_id, blob_id, salt, metadata, kind, byte_size, sha256)
+					values ($1, $2, $3, $4, $5, $6, $7, $8)
+					returning id, blob_id, salt, metadata, kind, sha256, byte_size, committed_offset, created, last_update;`
+	_getUpload = `select id, blob_id, salt, metadata, kind, sha256, byte_size, committed_offset, created, last_update
+					from upload_sessions where id=$1 and user_id=$2;`
+	_advanceUpload = `update upload_sessions set committed_offset=$1, last_update=now()
+					where id=$2 and user_id=$3 and committed_offset=$4
+					returning id, blob_id, salt, metadata, kind, sha256, byte_size, committed_offset, created, last_update;`
+	_lockUpload = `select id, blob_id, salt, metadata, kind, sha256, byte_size, committed_offset, created, last_update
+					from upload_sessions where id=$1 and user_id=$2 for update;`
+	_deleteUpload  = `delete from upload_sessions where id=$1;`
+	_expireUploads = `delete from upload_sessions where last_update<$1 returning blob_id;`
+)
+
+func (d *dbStorage) AddUpload(ctx context.Context, user *UserID, blob BlobID, meta *ResourceMeta) (*UploadSession, error) {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return nil, err
+	}
+
+	return scanUploadSession(d.dbConn.QueryRow(ctx, _addUpload, id.String(), user.String(), string(blob),
+		meta.Salt, meta.Metadata, meta.Kind, int64(meta.ByteSize), meta.Digest))
+}
+
+func (d *dbStorage) GetUpload(ctx context.Context, user *UserID, id *UploadID) (*UploadSession, error) {
+	session, err := scanUploadSession(d.dbConn.QueryRow(ctx, _getUpload, id.String(), user.String()))
+	if err != nil {
+		return nil, noRows(err, ErrUploadNotFound)
+	}
+	return session, nil
+}
+
+func (d *dbStorage) AdvanceUpload(ctx context.Context, user *UserID, id *UploadID, offset, size uint64) (*UploadSession, error) {
+	session, err := scanUploadSession(d.dbConn.QueryRow(ctx, _advanceUpload,
+		int64(offset+size), id.String(), user.String(), int64(offset)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrUploadOffsetMismatch
+	}
+	return session, err
+}
+
+func (d *dbStorage) FinalizeUpload(ctx context.Context, user *UserID, id *UploadID, resourceID *ResourceID) (*ResourceInfo, error) {
+	tx, err := d.dbConn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	session, err := scanUploadSession(tx.QueryRow(ctx, _lockUpload, id.String(), user.String()))
+	if err != nil {
+		return nil, noRows(err, ErrUploadNotFound)
+	}
+
+	if session.Offset != session.ByteSize {
+		return nil, ErrUploadIncomplete
+	}
+
+	meta := session.Meta
+	info, err := scanResourceInfo(tx.QueryRow(ctx, _addNewResource, user.String(), resourceID.String(),
+		string(session.blob), meta.Salt, meta.Metadata, meta.Kind, meta.Digest, int64(meta.ByteSize)))
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec(ctx, _deleteUpload, id.String()); err != nil {
+		return nil, err
+	}
+
+	return info, tx.Commit(ctx)
+}
+
+func (d *dbStorage) ExpireUploads(ctx context.Context, idleSince time.Time) ([]BlobID, error) {
+	rows, err := d.dbConn.Query(ctx, _expireUploads, idleSince)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	blobs := make([]BlobID, 0)
+	for rows.Next() {
+		var blob string
+		if err := rows.Scan(&blob); err != nil {
+			return nil, err
+		}
+		blobs = append(blobs, BlobID(blob))
+	}
+
+	return blobs, rows.Err()
+}
+
+func scanUploadSession(row pgx.Row) (*UploadSession, error) {
+	var (
+		session  = &UploadSession{}
+		id       uuid.UUID
+		blob     string
+		byteSize int64
+		offset   int64
+	)
+	err := row.Scan(&id, &blob, &session.Meta.Salt, &session.Meta.Metadata, &session.Meta.Kind,
+		&session.Meta.Digest, &byteSize, &offset, &session.CreatedAt, &session.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	session.ID = UploadID(id)
+	session.blob = BlobID(blob)
+	session.ByteSize = uint64(byteSize)
+	session.Meta.ByteSize = session.ByteSize
+	session.Offset = uint64(offset)
+	return session, nil
+}
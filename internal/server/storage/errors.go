@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"errors"
+)
+
+// Code classifies domain errors, so a transport maps a few codes instead of
+// every particular error. Errors without a code are internal and their text
+// must not reach clients.
+type Code int
+
+const (
+	CodeInternal Code = iota
+	CodeNotFound
+	CodeAlreadyExists
+	CodeInvalidArgument
+	CodeInvalidCredentials
+	CodeUnauthenticated
+	CodeQuotaExceeded
+	// CodeConflict is a failure caused by a concurrent change which may succeed
+	// on retry.
+	CodeConflict
+	// CodeFailedPrecondition is a request which can't succeed until the state
+	// it depends on is fixed.
+	CodeFailedPrecondition
+	CodeOutOfRange
+	CodeDataLoss
+)
+
+var _codeNames = map[Code]string{
+	CodeInternal:           "internal",
+	CodeNotFound:           "not found",
+	CodeAlreadyExists:      "already exists",
+	CodeInvalidArgument:    "invalid argument",
+	CodeInvalidCredentials: "invalid credentials",
+	CodeUnauthenticated:    "unauthenticated",
+	CodeQuotaExceeded:      "quota exceeded",
+	CodeConflict:           "conflict",
+	CodeFailedPrecondition: "failed precondition",
+	CodeOutOfRange:         "out of range",
+	CodeDataLoss:           "data loss",
+}
+
+func (c Code) String() string {
+	if name, ok := _codeNames[c]; ok {
+		return name
+	}
+	return _codeNames[CodeInternal]
+}
+
+// Error is a domain error with a code. Errors are compared by identity, so the
+// exported sentinels work with errors.Is even when wrapped.
+type Error struct {
+	code Code
+	msg  string
+}
+
+func NewError(code Code, msg string) *Error {
+	return &Error{code: code, msg: msg}
+}
+
+func (e *Error) Error() string {
+	return e.msg
+}
+
+func (e *Error) Code() Code {
+	return e.code
+}
+
+// CodeOf returns a code of the first domain error in the chain of err.
+func CodeOf(err error) Code {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.code
+	}
+	return CodeInternal
+}
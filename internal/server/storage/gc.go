@@ -0,0 +1,148 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+const _gcBatchSize = 1000
+
+// GCStats describes a single garbage collection run.
+type GCStats struct {
+	StartedAt  time.Time
+	FinishedAt time.Time
+	// Tombstones is a number of deleted resources removed for good.
+	Tombstones int
+	// Chunks is a number of removed chunks no resource referred to.
+	Chunks int
+	// Blobs is a number of removed blobs of tombstones and chunks.
+	Blobs int
+	// Orphans is a number of found blobs nothing refers to.
+	Orphans int
+	// OrphansDeleted is a number of orphans removed during the run.
+	OrphansDeleted int
+}
+
+type GarbageCollector interface {
+	// CollectGarbage removes resources deleted before deletedBefore with their data,
+	// unreferenced chunks and orphaned blobs.
+	CollectGarbage(ctx context.Context, deletedBefore time.Time) (*GCStats, error)
+}
+
+// CollectGarbage frees space in three steps. Tombstones are removed first,
+// which releases their chunks, and then unreferenced chunks are removed.
+// Finally blobs are checked for references. A blob is unreferenced for a while
+// when it is being written, so an orphan is removed only if it has been found
+// by a previous run too and it is older than deletedBefore.
+func (b *blobStorage) CollectGarbage(ctx context.Context, deletedBefore time.Time) (*GCStats, error) {
+	stats := &GCStats{StartedAt: time.Now().UTC()}
+	defer func() {
+		stats.FinishedAt = time.Now().UTC()
+	}()
+
+	var result error
+	for {
+		purged, blobs, err := b.meta.PurgeDeleted(ctx, deletedBefore, _gcBatchSize)
+		if err != nil {
+			return stats, err
+		}
+		stats.Tombstones += purged
+		stats.Blobs += b.deleteBlobs(ctx, blobs, &result)
+		if purged < _gcBatchSize {
+			break
+		}
+	}
+
+	for {
+		blobs, err := b.meta.PurgeChunks(ctx, deletedBefore, _gcBatchSize)
+		if err != nil {
+			return stats, multierror.Append(result, err)
+		}
+		stats.Chunks += len(blobs)
+		stats.Blobs += b.deleteBlobs(ctx, blobs, &result)
+		if len(blobs) < _gcBatchSize {
+			break
+		}
+	}
+
+	orphans, err := b.findOrphans(ctx, deletedBefore)
+	if err != nil {
+		return stats, multierror.Append(result, err)
+	}
+
+	b.gcMutex.Lock()
+	seen := b.orphans
+	b.orphans = orphans
+	b.gcMutex.Unlock()
+
+	stats.Orphans = len(orphans)
+	expired := make([]BlobID, 0)
+	for id, old := range orphans {
+		if _, ok := seen[id]; ok && old {
+			expired = append(expired, id)
+		}
+	}
+	stats.OrphansDeleted = b.deleteBlobs(ctx, expired, &result)
+
+	return stats, result
+}
+
+// findOrphans returns unreferenced blobs and whether they are older than olderThan.
+// A blob without a modification time is never old, it may be being written.
+func (b *blobStorage) findOrphans(ctx context.Context, olderThan time.Time) (map[BlobID]bool, error) {
+	orphans := make(map[BlobID]bool)
+	batch := make(map[BlobID]bool, _gcBatchSize)
+
+	check := func() error {
+		ids := make([]BlobID, 0, len(batch))
+		for id := range batch {
+			ids = append(ids, id)
+		}
+
+		unreferenced, err := b.meta.UnreferencedBlobs(ctx, ids)
+		if err != nil {
+			return err
+		}
+		for _, id := range unreferenced {
+			orphans[id] = batch[id]
+		}
+		batch = make(map[BlobID]bool, _gcBatchSize)
+		return nil
+	}
+
+	err := b.blobs.Walk(ctx, func(id BlobID, modTime time.Time) error {
+		batch[id] = !modTime.IsZero() && modTime.Before(olderThan)
+		if len(batch) < _gcBatchSize {
+			return nil
+		}
+		return check()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(batch) != 0 {
+		if err := check(); err != nil {
+			return nil, err
+		}
+	}
+	return orphans, nil
+}
+
+// deleteBlobs removes blobs collecting errors into result. It returns
+// a number of removed blobs.
+func (b *blobStorage) deleteBlobs(ctx context.Context, blobs []BlobID, result *error) int {
+	deleted := 0
+	for _, blob := range blobs {
+		err := b.blobs.Delete(ctx, blob)
+		if err != nil && !errors.Is(err, ErrBlobNotFound) {
+			*result = multierror.Append(*result, err)
+			continue
+		}
+		deleted++
+	}
+	return deleted
+}
@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+)
+
+type ListOrder int
+
+const (
+	// ListOrderID orders resources by their creation sequence.
+	ListOrderID ListOrder = iota
+	// ListOrderUpdated orders resources by the last update time.
+	ListOrderUpdated
+)
+
+var (
+	ErrInvalidPageToken  = NewError(CodeInvalidArgument, "invalid page token")
+	ErrPageTokenMismatch = NewError(CodeInvalidArgument, "page token doesn't match the order or filters of the request")
+)
+
+type ListOptions struct {
+	PageSize       int
+	PageToken      string
+	OrderBy        ListOrder
+	UpdatedAfter   *time.Time
+	IncludeDeleted bool
+	// DeletedOnly selects deleted resources only. IncludeDeleted is ignored then.
+	DeletedOnly bool
+	Kind        *string
+}
+
+// ListCursor is a position of the last returned row of a page.
+// It is handed to clients as an opaque page token together with the filters
+// the page was listed with, so a token can't be used with other filters.
+type ListCursor struct {
+	OrderBy ListOrder  `json:"o"`
+	RowID   int64      `json:"i"`
+	Updated int64      `json:"u,omitempty"`
+	Filter  listFilter `json:"f"`
+}
+
+// listFilter is a part of ListOptions a page token is bound to.
+type listFilter struct {
+	UpdatedAfter   *int64  `json:"a,omitempty"`
+	IncludeDeleted bool    `json:"d,omitempty"`
+	DeletedOnly    bool    `json:"x,omitempty"`
+	Kind           *string `json:"k,omitempty"`
+}
+
+func newListFilter(opts *ListOptions) listFilter {
+	f := listFilter{
+		IncludeDeleted: opts.IncludeDeleted,
+		DeletedOnly:    opts.DeletedOnly,
+		Kind:           opts.Kind,
+	}
+	if opts.UpdatedAfter != nil {
+		after := opts.UpdatedAfter.UnixMicro()
+		f.UpdatedAfter = &after
+	}
+	return f
+}
+
+func (f *listFilter) equal(other *listFilter) bool {
+	return equalPtr(f.UpdatedAfter, other.UpdatedAfter) &&
+		f.IncludeDeleted == other.IncludeDeleted &&
+		f.DeletedOnly == other.DeletedOnly &&
+		equalPtr(f.Kind, other.Kind)
+}
+
+func equalPtr[T comparable](lhs, rhs *T) bool {
+	if lhs == nil || rhs == nil {
+		return lhs == rhs
+	}
+	return *lhs == *rhs
+}
+
+// Token encodes a cursor and the filters of opts into a page token.
+func (c *ListCursor) Token(opts *ListOptions) string {
+	token := *c
+	token.Filter = newListFilter(opts)
+	data, _ := json.Marshal(&token)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// ParsePageToken restores a cursor from the page token of opts. An empty token
+// yields a nil cursor. A token of a page listed with another order or other
+// filters is rejected.
+func ParsePageToken(opts *ListOptions) (*ListCursor, error) {
+	if len(opts.PageToken) == 0 {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(opts.PageToken)
+	if err != nil {
+		return nil, ErrInvalidPageToken
+	}
+
+	c := &ListCursor{}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, ErrInvalidPageToken
+	}
+
+	filter := newListFilter(opts)
+	if c.OrderBy != opts.OrderBy || !c.Filter.equal(&filter) {
+		return nil, ErrPageTokenMismatch
+	}
+
+	return c, nil
+}
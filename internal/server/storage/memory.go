@@ -0,0 +1,817 @@
+package storage
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	_ UserService   = (*memoryStorage)(nil)
+	_ MetadataStore = (*memoryStorage)(nil)
+	_ Pinger        = (*memoryStorage)(nil)
+	_ BlobKeyStore  = (*memoryStorage)(nil)
+)
+
+// memoryStorage keeps users and resource metadata in memory. It behaves like
+// the databases, e.g. in tests or in a development server, but loses
+// everything on exit.
+type memoryStorage struct {
+	mu        sync.Mutex
+	users     map[UserID]*User
+	logins    map[string]UserID
+	resources map[ResourceID]*memoryResource
+	uploads   map[UploadID]*memoryUpload
+	chunks    map[memoryChunkKey]*memoryChunk
+	quotas    map[UserID]Quota
+	blobKeys  map[BlobID]BlobKey
+	segments  map[BlobID][]BlobSegment
+	rowID     int64
+}
+
+type memoryResource struct {
+	info  ResourceInfo
+	user  UserID
+	blob  BlobID
+	rowID int64
+	// digests is a manifest of a chunked resource.
+	digests [][]byte
+}
+
+type memoryUpload struct {
+	session UploadSession
+	user    UserID
+}
+
+type memoryChunkKey struct {
+	user   UserID
+	digest string
+}
+
+type memoryChunk struct {
+	Chunk
+	refs    int
+	created time.Time
+}
+
+func NewMemoryUserService() *memoryStorage {
+	return &memoryStorage{
+		users:     make(map[UserID]*User),
+		logins:    make(map[string]UserID),
+		resources: make(map[ResourceID]*memoryResource),
+		uploads:   make(map[UploadID]*memoryUpload),
+		chunks:    make(map[memoryChunkKey]*memoryChunk),
+		quotas:    make(map[UserID]Quota),
+		blobKeys:  make(map[BlobID]BlobKey),
+		segments:  make(map[BlobID][]BlobSegment),
+	}
+}
+
+// memoryNow returns the current time at the precision of database timestamps.
+func memoryNow() time.Time {
+	return time.Now().Truncate(time.Microsecond)
+}
+
+func (m *memoryStorage) Add(_ context.Context, login string, keySalt, salt, secret []byte) (*UserID, error) {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.logins[login]; ok {
+		return nil, ErrUserExists
+	}
+
+	uid := UserID(id)
+	m.users[uid] = &User{
+		ID:      uid,
+		Login:   login,
+		KeySalt: cloneBytes(keySalt),
+		Salt:    cloneBytes(salt),
+		Secret:  cloneBytes(secret),
+	}
+	m.logins[login] = uid
+	return &uid, nil
+}
+
+func (m *memoryStorage) GetByLogin(_ context.Context, login string) (*User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	id, ok := m.logins[login]
+	if !ok {
+		return nil, ErrUserNotFound
+	}
+	return m.user(id)
+}
+
+func (m *memoryStorage) GetByID(_ context.Context, id string) (*User, error) {
+	uid, err := NewUserIDFromString(id)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.user(*uid)
+}
+
+// user returns a copy of a user, so callers can't change the stored one.
+func (m *memoryStorage) user(id UserID) (*User, error) {
+	user, ok := m.users[id]
+	if !ok || user.IsDeleted {
+		return nil, ErrUserNotFound
+	}
+	return &User{
+		ID:     user.ID,
+		Login:  user.Login,
+		Salt:   cloneBytes(user.Salt),
+		Secret: cloneBytes(user.Secret),
+	}, nil
+}
+
+// Ping always succeeds, memory is always available.
+func (m *memoryStorage) Ping(context.Context) error {
+	return nil
+}
+
+func (m *memoryStorage) Close() error {
+	return nil
+}
+
+func (m *memoryStorage) AddResource(_ context.Context, user *UserID, id *ResourceID, blob BlobID, meta *ResourceMeta) (*ResourceInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.addResource(user, id, blob, meta, nil)
+}
+
+func (m *memoryStorage) addResource(user *UserID, id *ResourceID, blob BlobID, meta *ResourceMeta, digests [][]byte) (*ResourceInfo, error) {
+	if _, ok := m.users[*user]; !ok {
+		return nil, ErrUserNotFound
+	}
+	if _, ok := m.resources[*id]; ok {
+		return nil, fmt.Errorf("resource %s already exists", id)
+	}
+
+	m.rowID++
+	now := memoryNow()
+	res := &memoryResource{
+		info: ResourceInfo{
+			ID:        *id,
+			Salt:      cloneBytes(meta.Salt),
+			Metadata:  cloneBytes(meta.Metadata),
+			Kind:      meta.Kind,
+			Digest:    cloneBytes(meta.Digest),
+			ByteSize:  meta.ByteSize,
+			Version:   1,
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+		user:    *user,
+		blob:    blob,
+		rowID:   m.rowID,
+		digests: digests,
+	}
+	m.resources[*id] = res
+	return res.infoCopy(), nil
+}
+
+func (m *memoryStorage) ResourceBlob(_ context.Context, user *UserID, id *ResourceID) (*ResourceInfo, BlobID, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	res, err := m.resource(user, id)
+	if err != nil {
+		return nil, "", err
+	}
+	return res.infoCopy(), res.blob, nil
+}
+
+// resource returns an existing resource of a user.
+func (m *memoryStorage) resource(user *UserID, id *ResourceID) (*memoryResource, error) {
+	res, ok := m.resources[*id]
+	if !ok || res.user != *user || res.info.IsDeleted {
+		return nil, ErrResourceNotFound
+	}
+	return res, nil
+}
+
+// Delete marks a resource deleted. Like the databases, deleting a deleted
+// resource only moves its deletion time.
+func (m *memoryStorage) Delete(_ context.Context, user *UserID, id *ResourceID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	res, ok := m.resources[*id]
+	if !ok || res.user != *user {
+		return ErrResourceNotFound
+	}
+	res.info.IsDeleted = true
+	res.info.UpdatedAt = memoryNow()
+	return nil
+}
+
+func (m *memoryStorage) Restore(_ context.Context, user *UserID, id *ResourceID, deletedAfter time.Time) (*ResourceInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.restore(user, id, deletedAfter)
+}
+
+func (m *memoryStorage) restore(user *UserID, id *ResourceID, deletedAfter time.Time) (*ResourceInfo, error) {
+	res, ok := m.resources[*id]
+	if !ok || res.user != *user || !res.info.IsDeleted || res.info.UpdatedAt.Before(deletedAfter) {
+		return nil, ErrNotInTrash
+	}
+	res.info.IsDeleted = false
+	res.info.UpdatedAt = memoryNow()
+	return res.infoCopy(), nil
+}
+
+func (m *memoryStorage) List(_ context.Context, user *UserID, opts *ListOptions) ([]ResourceInfo, string, error) {
+	cursor, err := ParsePageToken(opts)
+	if err != nil {
+		return nil, "", err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	selected := make([]*memoryResource, 0)
+	for _, res := range m.resources {
+		if res.user != *user || !res.matches(opts) || (cursor != nil && !res.after(cursor)) {
+			continue
+		}
+		selected = append(selected, res)
+	}
+	sort.Slice(selected, func(i, j int) bool {
+		return selected[j].after(selected[i].cursor(opts.OrderBy))
+	})
+
+	var (
+		resources = make([]ResourceInfo, 0, opts.PageSize)
+		nextToken string
+	)
+	for i, res := range selected {
+		if i == opts.PageSize {
+			nextToken = selected[i-1].cursor(opts.OrderBy).Token(opts)
+			break
+		}
+		resources = append(resources, *res.infoCopy())
+	}
+	return resources, nextToken, nil
+}
+
+func (r *memoryResource) matches(opts *ListOptions) bool {
+	if opts.DeletedOnly {
+		if !r.info.IsDeleted {
+			return false
+		}
+	} else if !opts.IncludeDeleted && r.info.IsDeleted {
+		return false
+	}
+	if opts.UpdatedAfter != nil && !r.info.UpdatedAt.After(*opts.UpdatedAfter) {
+		return false
+	}
+	return opts.Kind == nil || *opts.Kind == r.info.Kind
+}
+
+func (r *memoryResource) cursor(order ListOrder) *ListCursor {
+	return &ListCursor{
+		OrderBy: order,
+		RowID:   r.rowID,
+		Updated: r.info.UpdatedAt.UnixMicro(),
+	}
+}
+
+// after reports whether a resource follows a cursor in the order of the cursor.
+func (r *memoryResource) after(c *ListCursor) bool {
+	if c.OrderBy == ListOrderUpdated {
+		updated := r.info.UpdatedAt.UnixMicro()
+		if updated != c.Updated {
+			return updated > c.Updated
+		}
+	}
+	return r.rowID > c.RowID
+}
+
+func (r *memoryResource) infoCopy() *ResourceInfo {
+	info := r.info
+	info.Salt = cloneBytes(info.Salt)
+	info.Metadata = cloneBytes(info.Metadata)
+	info.Digest = cloneBytes(info.Digest)
+	return &info
+}
+
+func (m *memoryStorage) Stat(_ context.Context, user *UserID, id *ResourceID) (*ResourceInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	res, err := m.resource(user, id)
+	if err != nil {
+		return nil, err
+	}
+	return res.infoCopy(), nil
+}
+
+// Batch holds the lock for the whole batch. An atomic batch saves states of
+// resources it changes and brings them back on a failure.
+func (m *memoryStorage) Batch(_ context.Context, user *UserID, items []BatchItem, opts *BatchOptions) ([]BatchResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var (
+		results = make([]BatchResult, len(items))
+		saved   = make(map[ResourceID]ResourceInfo)
+	)
+	for i := range items {
+		if res, ok := m.resources[items[i].ID]; ok {
+			if _, ok := saved[items[i].ID]; !ok {
+				saved[items[i].ID] = res.info
+			}
+		}
+
+		results[i].Info, results[i].Err = m.runBatchItem(user, &items[i], opts)
+		if results[i].Err != nil && opts.Atomic {
+			for id, info := range saved {
+				m.resources[id].info = info
+			}
+			abortBatch(results, i)
+			break
+		}
+	}
+	return results, nil
+}
+
+func (m *memoryStorage) runBatchItem(user *UserID, item *BatchItem, opts *BatchOptions) (*ResourceInfo, error) {
+	switch item.Op {
+	case BatchStat:
+		res, err := m.resource(user, &item.ID)
+		if err != nil {
+			return nil, err
+		}
+		return res.infoCopy(), nil
+	case BatchDelete:
+		res, err := m.resource(user, &item.ID)
+		if err != nil {
+			return nil, err
+		}
+		res.info.IsDeleted = true
+		res.info.UpdatedAt = memoryNow()
+		return res.infoCopy(), nil
+	case BatchRestore:
+		return m.restore(user, &item.ID, opts.RestorableSince)
+	default:
+		return nil, fmt.Errorf("unknown batch operation %d", item.Op)
+	}
+}
+
+func (m *memoryStorage) AddUpload(_ context.Context, user *UserID, blob BlobID, meta *ResourceMeta) (*UploadSession, error) {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.users[*user]; !ok {
+		return nil, ErrUserNotFound
+	}
+
+	now := memoryNow()
+	upload := &memoryUpload{
+		session: UploadSession{
+			ID: UploadID(id),
+			Meta: ResourceMeta{
+				Salt:     cloneBytes(meta.Salt),
+				Metadata: cloneBytes(meta.Metadata),
+				Kind:     meta.Kind,
+				ByteSize: meta.ByteSize,
+				Digest:   cloneBytes(meta.Digest),
+			},
+			ByteSize:  meta.ByteSize,
+			CreatedAt: now,
+			UpdatedAt: now,
+			blob:      blob,
+		},
+		user: *user,
+	}
+	m.uploads[upload.session.ID] = upload
+
+	session := upload.session
+	return &session, nil
+}
+
+func (m *memoryStorage) GetUpload(_ context.Context, user *UserID, id *UploadID) (*UploadSession, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	upload, ok := m.uploads[*id]
+	if !ok || upload.user != *user {
+		return nil, ErrUploadNotFound
+	}
+	session := upload.session
+	return &session, nil
+}
+
+func (m *memoryStorage) AdvanceUpload(_ context.Context, user *UserID, id *UploadID, offset, size uint64) (*UploadSession, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	upload, ok := m.uploads[*id]
+	if !ok || upload.user != *user || upload.session.Offset != offset {
+		return nil, ErrUploadOffsetMismatch
+	}
+	upload.session.Offset = offset + size
+	upload.session.UpdatedAt = memoryNow()
+
+	session := upload.session
+	return &session, nil
+}
+
+func (m *memoryStorage) FinalizeUpload(_ context.Context, user *UserID, id *UploadID, resourceID *ResourceID) (*ResourceInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	upload, ok := m.uploads[*id]
+	if !ok || upload.user != *user {
+		return nil, ErrUploadNotFound
+	}
+	if upload.session.Offset != upload.session.ByteSize {
+		return nil, ErrUploadIncomplete
+	}
+
+	info, err := m.addResource(user, resourceID, upload.session.blob, &upload.session.Meta, nil)
+	if err != nil {
+		return nil, err
+	}
+	delete(m.uploads, *id)
+	return info, nil
+}
+
+func (m *memoryStorage) ExpireUploads(_ context.Context, idleSince time.Time) ([]BlobID, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	blobs := make([]BlobID, 0)
+	for id, upload := range m.uploads {
+		if upload.session.UpdatedAt.Before(idleSince) {
+			blobs = append(blobs, upload.session.blob)
+			delete(m.uploads, id)
+		}
+	}
+	return blobs, nil
+}
+
+func (m *memoryStorage) MissingChunks(_ context.Context, user *UserID, digests [][]byte) ([][]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	missing := make([][]byte, 0)
+	for _, digest := range digests {
+		if _, ok := m.chunks[chunkKey(user, digest)]; !ok {
+			missing = append(missing, digest)
+		}
+	}
+	return missing, nil
+}
+
+func (m *memoryStorage) Chunks(_ context.Context, user *UserID, digests [][]byte) ([]Chunk, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.chunkList(user, digests)
+}
+
+func (m *memoryStorage) chunkList(user *UserID, digests [][]byte) ([]Chunk, error) {
+	chunks := make([]Chunk, 0, len(digests))
+	for _, digest := range digests {
+		chunk, ok := m.chunks[chunkKey(user, digest)]
+		if !ok {
+			return nil, fmt.Errorf("%w: %x", ErrChunkMissing, digest)
+		}
+		chunks = append(chunks, chunk.Chunk)
+	}
+	return chunks, nil
+}
+
+func (m *memoryStorage) AddChunk(_ context.Context, user *UserID, chunk *Chunk) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.users[*user]; !ok {
+		return false, ErrUserNotFound
+	}
+
+	key := chunkKey(user, chunk.Digest)
+	if _, ok := m.chunks[key]; ok {
+		return false, nil
+	}
+	m.chunks[key] = &memoryChunk{
+		Chunk: Chunk{
+			Digest:   cloneBytes(chunk.Digest),
+			Blob:     chunk.Blob,
+			ByteSize: chunk.ByteSize,
+		},
+		created: memoryNow(),
+	}
+	return true, nil
+}
+
+// AddChunkedResource adds a resource without a blob of its own. References are
+// counted per manifest entry, so a chunk repeated in a resource is counted twice.
+func (m *memoryStorage) AddChunkedResource(_ context.Context, user *UserID, id *ResourceID, meta *ResourceMeta, digests [][]byte) (*ResourceInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, err := m.chunkList(user, digests); err != nil {
+		return nil, err
+	}
+
+	manifest := make([][]byte, 0, len(digests))
+	for _, digest := range digests {
+		manifest = append(manifest, cloneBytes(digest))
+	}
+	info, err := m.addResource(user, id, "", meta, manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, digest := range digests {
+		m.chunks[chunkKey(user, digest)].refs++
+	}
+	return info, nil
+}
+
+func (m *memoryStorage) ResourceChunks(_ context.Context, user *UserID, id *ResourceID) ([]Chunk, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	res, ok := m.resources[*id]
+	if !ok || res.user != *user {
+		return []Chunk{}, nil
+	}
+	return m.chunkList(user, res.digests)
+}
+
+func (m *memoryStorage) PurgeDeleted(_ context.Context, deletedBefore time.Time, limit int) (int, []BlobID, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.purgeResources(limit, func(res *memoryResource) bool {
+		return res.info.UpdatedAt.Before(deletedBefore)
+	})
+}
+
+func (m *memoryStorage) PurgeUserDeleted(_ context.Context, user *UserID, limit int) (int, []BlobID, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.purgeResources(limit, func(res *memoryResource) bool {
+		return res.user == *user
+	})
+}
+
+// purgeResources removes up to limit deleted resources selected by fn.
+func (m *memoryStorage) purgeResources(limit int, fn func(res *memoryResource) bool) (int, []BlobID, error) {
+	var (
+		purged int
+		blobs  = make([]BlobID, 0)
+	)
+	for id, res := range m.resources {
+		if purged == limit {
+			break
+		}
+		if !res.info.IsDeleted || !fn(res) {
+			continue
+		}
+
+		for _, digest := range res.digests {
+			if chunk, ok := m.chunks[chunkKey(&res.user, digest)]; ok {
+				chunk.refs--
+			}
+		}
+		if res.blob != "" {
+			blobs = append(blobs, res.blob)
+		}
+		delete(m.resources, id)
+		purged++
+	}
+	return purged, blobs, nil
+}
+
+func (m *memoryStorage) PurgeChunks(_ context.Context, createdBefore time.Time, limit int) ([]BlobID, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	blobs := make([]BlobID, 0)
+	for key, chunk := range m.chunks {
+		if len(blobs) == limit {
+			break
+		}
+		if chunk.refs <= 0 && chunk.created.Before(createdBefore) {
+			blobs = append(blobs, chunk.Blob)
+			delete(m.chunks, key)
+		}
+	}
+	return blobs, nil
+}
+
+func (m *memoryStorage) UnreferencedBlobs(_ context.Context, blobs []BlobID) ([]BlobID, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	referenced := make(map[BlobID]bool, len(m.resources)+len(m.uploads)+len(m.chunks))
+	for _, res := range m.resources {
+		referenced[res.blob] = true
+	}
+	for _, upload := range m.uploads {
+		referenced[upload.session.blob] = true
+	}
+	for _, chunk := range m.chunks {
+		referenced[chunk.Blob] = true
+	}
+
+	unreferenced := make([]BlobID, 0)
+	for _, blob := range blobs {
+		if !referenced[blob] {
+			unreferenced = append(unreferenced, blob)
+		}
+	}
+	return unreferenced, nil
+}
+
+func (m *memoryStorage) Usage(_ context.Context, user *UserID) (*Usage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	usage := &Usage{}
+	for _, res := range m.resources {
+		if res.user == *user {
+			if len(res.blob) != 0 {
+				usage.Bytes += res.info.ByteSize
+			}
+			usage.Items++
+		}
+	}
+	for key, chunk := range m.chunks {
+		if key.user == *user {
+			usage.Bytes += chunk.ByteSize
+		}
+	}
+	for _, upload := range m.uploads {
+		if upload.user == *user {
+			usage.Bytes += upload.session.ByteSize
+			usage.Items++
+		}
+	}
+	return usage, nil
+}
+
+func (m *memoryStorage) Quota(_ context.Context, user *UserID) (*Quota, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	quota, ok := m.quotas[*user]
+	if !ok {
+		return nil, ErrQuotaNotSet
+	}
+	return &quota, nil
+}
+
+func (m *memoryStorage) SetQuota(_ context.Context, user *UserID, quota *Quota) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.users[*user]; !ok {
+		return ErrUserNotFound
+	}
+	m.quotas[*user] = *quota
+	return nil
+}
+
+func (m *memoryStorage) DeleteQuota(_ context.Context, user *UserID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.quotas, *user)
+	return nil
+}
+
+func (m *memoryStorage) AddBlobKey(_ context.Context, key *BlobKey) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.blobKeys[key.BlobID]; ok {
+		return fmt.Errorf("blob %s already has a key", key.BlobID)
+	}
+	m.blobKeys[key.BlobID] = BlobKey{BlobID: key.BlobID, KEKID: key.KEKID, Wrapped: cloneBytes(key.Wrapped)}
+	return nil
+}
+
+func (m *memoryStorage) BlobKey(_ context.Context, id BlobID) (*BlobKey, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key, ok := m.blobKeys[id]
+	if !ok {
+		return nil, ErrBlobKeyNotFound
+	}
+	key.Wrapped = cloneBytes(key.Wrapped)
+	return &key, nil
+}
+
+func (m *memoryStorage) UpdateBlobKey(_ context.Context, key *BlobKey) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.blobKeys[key.BlobID]; ok {
+		m.blobKeys[key.BlobID] = BlobKey{BlobID: key.BlobID, KEKID: key.KEKID, Wrapped: cloneBytes(key.Wrapped)}
+	}
+	return nil
+}
+
+func (m *memoryStorage) DeleteBlobKey(_ context.Context, id BlobID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.blobKeys, id)
+	delete(m.segments, id)
+	return nil
+}
+
+func (m *memoryStorage) AddBlobSegment(_ context.Context, id BlobID, segment *BlobSegment) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.blobKeys[id]; !ok {
+		return ErrBlobKeyNotFound
+	}
+	segments := m.segments[id]
+	n := sort.Search(len(segments), func(i int) bool {
+		return segments[i].Offset >= segment.Offset
+	})
+	m.segments[id] = append(segments[:n:n], BlobSegment{Offset: segment.Offset, Nonce: cloneBytes(segment.Nonce)})
+	return nil
+}
+
+func (m *memoryStorage) BlobSegments(_ context.Context, id BlobID) ([]BlobSegment, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	segments := make([]BlobSegment, 0, len(m.segments[id]))
+	for _, s := range m.segments[id] {
+		segments = append(segments, BlobSegment{Offset: s.Offset, Nonce: cloneBytes(s.Nonce)})
+	}
+	return segments, nil
+}
+
+func (m *memoryStorage) KEKIDs(context.Context) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	seen := make(map[string]bool)
+	ids := make([]string, 0)
+	for _, key := range m.blobKeys {
+		if !seen[key.KEKID] {
+			seen[key.KEKID] = true
+			ids = append(ids, key.KEKID)
+		}
+	}
+	return ids, nil
+}
+
+func (m *memoryStorage) StaleBlobKeys(_ context.Context, kekID string, limit int) ([]BlobKey, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	keys := make([]BlobKey, 0)
+	for _, key := range m.blobKeys {
+		if len(keys) == limit {
+			break
+		}
+		if key.KEKID != kekID {
+			key.Wrapped = cloneBytes(key.Wrapped)
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+func chunkKey(user *UserID, digest []byte) memoryChunkKey {
+	return memoryChunkKey{user: *user, digest: hex.EncodeToString(digest)}
+}
+
+func cloneBytes(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+	return append([]byte{}, b...)
+}
@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"context"
+	"time"
+)
+
+// MetadataStore keeps resources and upload sessions while their data
+// is kept by a BlobStore.
+type MetadataStore interface {
+	// AddResource stores a resource which data is in a committed blob.
+	AddResource(ctx context.Context, user *UserID, id *ResourceID, blob BlobID, meta *ResourceMeta) (*ResourceInfo, error)
+	// ResourceBlob returns an existing resource and a blob with its data.
+	// The blob is empty for chunked resources.
+	ResourceBlob(ctx context.Context, user *UserID, id *ResourceID) (*ResourceInfo, BlobID, error)
+	Delete(ctx context.Context, user *UserID, id *ResourceID) error
+	// Restore undeletes a resource deleted after deletedAfter.
+	// It fails with ErrNotInTrash when there is no such resource.
+	Restore(ctx context.Context, user *UserID, id *ResourceID, deletedAfter time.Time) (*ResourceInfo, error)
+	List(ctx context.Context, user *UserID, opts *ListOptions) ([]ResourceInfo, string, error)
+	Stat(ctx context.Context, user *UserID, id *ResourceID) (*ResourceInfo, error)
+	// Batch runs items in a single transaction. It returns a result for every item.
+	Batch(ctx context.Context, user *UserID, items []BatchItem, opts *BatchOptions) ([]BatchResult, error)
+
+	AddUpload(ctx context.Context, user *UserID, blob BlobID, meta *ResourceMeta) (*UploadSession, error)
+	GetUpload(ctx context.Context, user *UserID, id *UploadID) (*UploadSession, error)
+	// AdvanceUpload moves the committed offset of a session from offset by size bytes.
+	// It fails with ErrUploadOffsetMismatch when offset isn't the committed one.
+	AdvanceUpload(ctx context.Context, user *UserID, id *UploadID, offset, size uint64) (*UploadSession, error)
+	// FinalizeUpload replaces a session with a resource.
+	FinalizeUpload(ctx context.Context, user *UserID, id *UploadID, resourceID *ResourceID) (*ResourceInfo, error)
+	// ExpireUploads removes sessions which were not updated since idleSince
+	// and returns their blobs.
+	ExpireUploads(ctx context.Context, idleSince time.Time) ([]BlobID, error)
+
+	// MissingChunks returns those of digests which a user has no chunks for.
+	MissingChunks(ctx context.Context, user *UserID, digests [][]byte) ([][]byte, error)
+	// Chunks returns chunks of a user in the order of digests.
+	// It fails with ErrChunkMissing when any of them doesn't exist.
+	Chunks(ctx context.Context, user *UserID, digests [][]byte) ([]Chunk, error)
+	// AddChunk stores a chunk which data is in a committed blob. It returns false
+	// when the user already has the chunk, then the blob isn't referenced.
+	AddChunk(ctx context.Context, user *UserID, chunk *Chunk) (bool, error)
+	// AddChunkedResource stores a resource made of chunks and adds
+	// a reference to every chunk of it.
+	AddChunkedResource(ctx context.Context, user *UserID, id *ResourceID, meta *ResourceMeta, digests [][]byte) (*ResourceInfo, error)
+	// ResourceChunks returns chunks of a chunked resource in order.
+	ResourceChunks(ctx context.Context, user *UserID, id *ResourceID) ([]Chunk, error)
+
+	// PurgeDeleted removes up to limit resources deleted before deletedBefore
+	// and releases their chunks. It returns a number of removed resources
+	// and blobs which are not referenced anymore.
+	PurgeDeleted(ctx context.Context, deletedBefore time.Time, limit int) (int, []BlobID, error)
+	// PurgeChunks removes up to limit chunks which no resource refers to
+	// and which were created before createdBefore. It returns their blobs.
+	PurgeChunks(ctx context.Context, createdBefore time.Time, limit int) ([]BlobID, error)
+	// PurgeUserDeleted is PurgeDeleted limited to resources of a single user
+	// regardless of their deletion time.
+	PurgeUserDeleted(ctx context.Context, user *UserID, limit int) (int, []BlobID, error)
+	// UnreferencedBlobs returns those of blobs which neither a resource,
+	// a chunk nor an upload session refers to.
+	UnreferencedBlobs(ctx context.Context, blobs []BlobID) ([]BlobID, error)
+
+	QuotaStorage
+}
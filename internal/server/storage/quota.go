@@ -0,0 +1,63 @@
+package storage
+
+import "context"
+
+var ErrQuotaNotSet = NewError(CodeNotFound, "quota is not set")
+
+// Usage is an amount of storage a user consumes. Deleted resources and upload
+// sessions count until they are removed. Bytes of chunked resources are those
+// of their chunks, which count once however many resources refer them and
+// even when no resource does, until they are collected.
+type Usage struct {
+	Bytes uint64
+	Items uint64
+}
+
+// Quota limits Usage of a user. Zero limits are unlimited.
+type Quota struct {
+	MaxBytes uint64
+	MaxItems uint64
+}
+
+// Allows reports whether usage stays within the quota after adding bytes and items.
+// Sizes come from clients, so sums are never computed to avoid overflows.
+func (q *Quota) Allows(usage *Usage, bytes, items uint64) bool {
+	if q.MaxBytes != 0 && !fits(usage.Bytes, bytes, q.MaxBytes) {
+		return false
+	}
+	if q.MaxItems != 0 && !fits(usage.Items, items, q.MaxItems) {
+		return false
+	}
+	return true
+}
+
+// fits reports whether used+added is at most limit.
+func fits(used, added, limit uint64) bool {
+	return used <= limit && added <= limit-used
+}
+
+type QuotaStorage interface {
+	Usage(ctx context.Context, user *UserID) (*Usage, error)
+	// Quota returns a quota set for a user. It fails with ErrQuotaNotSet
+	// when there is none.
+	Quota(ctx context.Context, user *UserID) (*Quota, error)
+	SetQuota(ctx context.Context, user *UserID, quota *Quota) error
+	// DeleteQuota removes a quota of a user. It does nothing when there is none.
+	DeleteQuota(ctx context.Context, user *UserID) error
+}
+
+func (b *blobStorage) Usage(ctx context.Context, user *UserID) (*Usage, error) {
+	return b.meta.Usage(ctx, user)
+}
+
+func (b *blobStorage) Quota(ctx context.Context, user *UserID) (*Quota, error) {
+	return b.meta.Quota(ctx, user)
+}
+
+func (b *blobStorage) SetQuota(ctx context.Context, user *UserID, quota *Quota) error {
+	return b.meta.SetQuota(ctx, user, quota)
+}
+
+func (b *blobStorage) DeleteQuota(ctx context.Context, user *UserID) error {
+	return b.meta.DeleteQuota(ctx, user)
+}
@@ -0,0 +1,34 @@
+package storage
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuota_Allows(t *testing.T) {
+	quota := &Quota{MaxBytes: 100, MaxItems: 10}
+	tests := []struct {
+		name   string
+		usage  Usage
+		bytes  uint64
+		items  uint64
+		allows bool
+	}{
+		{name: "within", usage: Usage{Bytes: 50, Items: 5}, bytes: 50, items: 5, allows: true},
+		{name: "too many bytes", usage: Usage{Bytes: 50, Items: 5}, bytes: 51, items: 1},
+		{name: "too many items", usage: Usage{Bytes: 50, Items: 5}, bytes: 1, items: 6},
+		{name: "bytes overflow", usage: Usage{Bytes: 50, Items: 5}, bytes: math.MaxUint64 - 10, items: 1},
+		{name: "items overflow", usage: Usage{Bytes: 50, Items: 5}, bytes: 1, items: math.MaxUint64 - 2},
+		{name: "over quota already", usage: Usage{Bytes: 200, Items: 5}, bytes: 0, items: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.allows, quota.Allows(&tt.usage, tt.bytes, tt.items))
+		})
+	}
+
+	unlimited := &Quota{}
+	assert.True(t, unlimited.Allows(&Usage{Bytes: 50}, math.MaxUint64, math.MaxUint64))
+}
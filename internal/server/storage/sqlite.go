@@ -0,0 +1,385 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mattn/go-sqlite3"
+)
+
+var (
+	_ UserService   = (*sqliteStorage)(nil)
+	_ MetadataStore = (*sqliteStorage)(nil)
+	_ Pinger        = (*sqliteStorage)(nil)
+)
+
+// SQLiteScheme prefixes DSNs of an embedded SQLite database,
+// e.g. sqlite:///var/lib/gophkeeper/keeper.db.
+const SQLiteScheme = "sqlite://"
+
+const (
+	_sqliteAddUser = `insert into users (id, login, key_salt, salt, secret, created, last_update) values (?, ?, ?, ?, ?, ?, ?);`
+
+	_sqliteGetUserByLogin = `select id, login, salt, secret from users where is_deleted=0 and login=?;`
+	_sqliteGetUserByID    = `select id, login, salt, secret from users where is_deleted=0 and id=?;`
+
+	_sqliteAddNewResource = `insert into user_data (user_id, resource_id, blob_id, salt, metadata, kind, sha256, byte_size, created, last_update)
+					values(?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+					returning resource_id, salt, metadata, kind, sha256, byte_size, version, created, last_update, is_deleted;`
+	_sqliteGetResource    = `select resource_id, salt, metadata, kind, sha256, byte_size, version, created, last_update, is_deleted, coalesce(blob_id, '') from user_data where resource_id=? and user_id=? and is_deleted=0;`
+	_sqliteStatResource   = `select resource_id, salt, metadata, kind, sha256, byte_size, version, created, last_update, is_deleted from user_data where resource_id=? and user_id=? and is_deleted=0;`
+	_sqliteListResources  = `select resource_id, salt, metadata, kind, sha256, byte_size, version, created, last_update, is_deleted, id from user_data where user_id=?`
+	_sqliteDeleteResource = `update user_data set is_deleted=1, last_update=? where user_id=? and resource_id=?;`
+
+	_sqliteRestoreResource = `update user_data set is_deleted=0, last_update=?
+					where user_id=? and resource_id=? and is_deleted=1 and last_update>=?
+					returning resource_id, salt, metadata, kind, sha256, byte_size, version, created, last_update, is_deleted;`
+)
+
+// _sqliteDefaultParams are driver parameters a DSN may override.
+var _sqliteDefaultParams = map[string]string{
+	"_foreign_keys": "1",
+	"_busy_timeout": "5000",
+	"_journal_mode": "WAL",
+}
+
+// sqliteStorage keeps users and resource metadata in a SQLite database, so
+// a server runs without an external database.
+type sqliteStorage struct {
+	db               *sql.DB
+	operationTimeout time.Duration
+}
+
+// IsSQLiteDSN reports whether dsn selects an embedded SQLite database.
+func IsSQLiteDSN(dsn string) bool {
+	return strings.HasPrefix(dsn, SQLiteScheme)
+}
+
+// NewSQLiteUserService opens a database at the path of a sqlite:// DSN and
+// creates its tables. Parameters of the DSN are passed to the driver.
+func NewSQLiteUserService(ctx context.Context, dsn string, operationTimeout time.Duration) (*sqliteStorage, error) {
+	driverDSN, err := sqliteDriverDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open("sqlite3", driverDSN)
+	if err != nil {
+		return nil, err
+	}
+	// SQLite has a single writer anyway. A single connection saves transactions
+	// from busy errors and keeps an in-memory database alive.
+	db.SetMaxOpenConns(1)
+
+	s := &sqliteStorage{
+		db:               db,
+		operationTimeout: operationTimeout,
+	}
+	if err := s.prepareDB(ctx); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+func sqliteDriverDSN(dsn string) (string, error) {
+	if !IsSQLiteDSN(dsn) {
+		return "", fmt.Errorf("sqlite dsn must start with %s", SQLiteScheme)
+	}
+
+	path, query, _ := strings.Cut(strings.TrimPrefix(dsn, SQLiteScheme), "?")
+	if len(path) == 0 {
+		return "", errors.New("sqlite database path must be specified")
+	}
+
+	params, err := url.ParseQuery(query)
+	if err != nil {
+		return "", err
+	}
+	for k, v := range _sqliteDefaultParams {
+		if !params.Has(k) {
+			params.Set(k, v)
+		}
+	}
+	return "file:" + path + "?" + params.Encode(), nil
+}
+
+func (s *sqliteStorage) prepareDB(ctx context.Context) error {
+	for _, t := range _sqliteSchema {
+		if _, err := s.db.ExecContext(ctx, t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *sqliteStorage) Add(ctx context.Context, login string, keySalt, salt, secret []byte) (*UserID, error) {
+	c, cancel := context.WithTimeout(ctx, s.operationTimeout)
+	defer cancel()
+
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UnixMicro()
+	if _, err := s.db.ExecContext(c, _sqliteAddUser, id.String(), login, keySalt, salt, secret, now, now); err != nil {
+		var sqliteErr sqlite3.Error
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			return nil, ErrUserExists
+		}
+		return nil, err
+	}
+
+	uid := UserID(id)
+	return &uid, nil
+}
+
+func (s *sqliteStorage) GetByLogin(ctx context.Context, login string) (*User, error) {
+	c, cancel := context.WithTimeout(ctx, s.operationTimeout)
+	defer cancel()
+
+	return scanSQLiteUser(s.db.QueryRowContext(c, _sqliteGetUserByLogin, login))
+}
+
+func (s *sqliteStorage) GetByID(ctx context.Context, id string) (*User, error) {
+	c, cancel := context.WithTimeout(ctx, s.operationTimeout)
+	defer cancel()
+
+	return scanSQLiteUser(s.db.QueryRowContext(c, _sqliteGetUserByID, id))
+}
+
+func scanSQLiteUser(row *sql.Row) (*User, error) {
+	var (
+		user = &User{}
+		id   string
+	)
+	if err := row.Scan(&id, &user.Login, &user.Salt, &user.Secret); err != nil {
+		return nil, noRows(err, ErrUserNotFound)
+	}
+
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	user.ID = UserID(userID)
+	return user, nil
+}
+
+// Ping checks that the database accepts queries.
+func (s *sqliteStorage) Ping(ctx context.Context) error {
+	c, cancel := context.WithTimeout(ctx, s.operationTimeout)
+	defer cancel()
+	return s.db.PingContext(c)
+}
+
+func (s *sqliteStorage) Close() error {
+	return s.db.Close()
+}
+
+func (s *sqliteStorage) AddResource(ctx context.Context, user *UserID, id *ResourceID, blob BlobID, meta *ResourceMeta) (*ResourceInfo, error) {
+	return s.addResource(ctx, s.db, user, id, sql.NullString{String: string(blob), Valid: true}, meta)
+}
+
+func (s *sqliteStorage) addResource(ctx context.Context, q sqliteQuerier, user *UserID, id *ResourceID, blob sql.NullString, meta *ResourceMeta) (*ResourceInfo, error) {
+	now := time.Now().UnixMicro()
+	return scanSQLiteResourceInfo(q.QueryRowContext(ctx, _sqliteAddNewResource, user.String(), id.String(), blob,
+		meta.Salt, meta.Metadata, meta.Kind, meta.Digest, int64(meta.ByteSize), now, now))
+}
+
+func (s *sqliteStorage) ResourceBlob(ctx context.Context, user *UserID, id *ResourceID) (*ResourceInfo, BlobID, error) {
+	var blob string
+	info, err := scanSQLiteResourceInfo(s.db.QueryRowContext(ctx, _sqliteGetResource, id.String(), user.String()), &blob)
+	if err != nil {
+		return nil, "", noRows(err, ErrResourceNotFound)
+	}
+	return info, BlobID(blob), nil
+}
+
+func (s *sqliteStorage) Delete(ctx context.Context, user *UserID, id *ResourceID) error {
+	res, err := s.db.ExecContext(ctx, _sqliteDeleteResource, time.Now().UnixMicro(), user.String(), id.String())
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrResourceNotFound
+	}
+	return nil
+}
+
+func (s *sqliteStorage) Restore(ctx context.Context, user *UserID, id *ResourceID, deletedAfter time.Time) (*ResourceInfo, error) {
+	info, err := scanSQLiteResourceInfo(s.db.QueryRowContext(ctx, _sqliteRestoreResource,
+		time.Now().UnixMicro(), user.String(), id.String(), deletedAfter.UnixMicro()))
+	if err != nil {
+		return nil, noRows(err, ErrNotInTrash)
+	}
+	return info, nil
+}
+
+func (s *sqliteStorage) List(ctx context.Context, userId *UserID, opts *ListOptions) ([]ResourceInfo, string, error) {
+	cursor, err := ParsePageToken(opts)
+	if err != nil {
+		return nil, "", err
+	}
+
+	query, args := buildSQLiteListQuery(userId, opts, cursor)
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var (
+		resources = make([]ResourceInfo, 0, opts.PageSize)
+		last      *ListCursor
+		nextToken string
+	)
+
+	for rows.Next() {
+		if len(resources) == opts.PageSize {
+			nextToken = last.Token(opts)
+			break
+		}
+
+		var rowID int64
+		info, err := scanSQLiteResourceInfo(rows, &rowID)
+		if err != nil {
+			return nil, "", err
+		}
+		resources = append(resources, *info)
+		last = &ListCursor{
+			OrderBy: opts.OrderBy,
+			RowID:   rowID,
+			Updated: info.UpdatedAt.UnixMicro(),
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	return resources, nextToken, nil
+}
+
+// buildSQLiteListQuery is buildListQuery for SQLite placeholders and times.
+func buildSQLiteListQuery(userId *UserID, opts *ListOptions, cursor *ListCursor) (string, []any) {
+	var (
+		query strings.Builder
+		args  = []any{userId.String()}
+	)
+
+	query.WriteString(_sqliteListResources)
+
+	if opts.DeletedOnly {
+		query.WriteString(` and is_deleted=1`)
+	} else if !opts.IncludeDeleted {
+		query.WriteString(` and is_deleted=0`)
+	}
+
+	if opts.UpdatedAfter != nil {
+		args = append(args, opts.UpdatedAfter.UnixMicro())
+		query.WriteString(` and last_update>?`)
+	}
+
+	if opts.Kind != nil {
+		args = append(args, *opts.Kind)
+		query.WriteString(` and kind=?`)
+	}
+
+	switch opts.OrderBy {
+	case ListOrderUpdated:
+		if cursor != nil {
+			args = append(args, cursor.Updated, cursor.RowID)
+			query.WriteString(` and (last_update, id)>(?, ?)`)
+		}
+		query.WriteString(` order by last_update, id`)
+	default:
+		if cursor != nil {
+			args = append(args, cursor.RowID)
+			query.WriteString(` and id>?`)
+		}
+		query.WriteString(` order by id`)
+	}
+
+	args = append(args, opts.PageSize+1)
+	query.WriteString(` limit ?;`)
+
+	return query.String(), args
+}
+
+func (s *sqliteStorage) Stat(ctx context.Context, user *UserID, id *ResourceID) (*ResourceInfo, error) {
+	info, err := scanSQLiteResourceInfo(s.db.QueryRowContext(ctx, _sqliteStatResource, id.String(), user.String()))
+	if err != nil {
+		return nil, noRows(err, ErrResourceNotFound)
+	}
+	return info, nil
+}
+
+// sqliteQuerier is either a database or a transaction.
+type sqliteQuerier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// sqliteRow is either a single row or a row of a result set.
+type sqliteRow interface {
+	Scan(dest ...any) error
+}
+
+func scanSQLiteResourceInfo(row sqliteRow, extra ...any) (*ResourceInfo, error) {
+	var (
+		info     = &ResourceInfo{}
+		id       string
+		byteSize int64
+		version  int64
+		created  int64
+		updated  int64
+	)
+	dest := []any{&id, &info.Salt, &info.Metadata, &info.Kind, &info.Digest, &byteSize, &version,
+		&created, &updated, &info.IsDeleted}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	resourceID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	info.ID = ResourceID(resourceID)
+	info.ByteSize = uint64(byteSize)
+	info.Version = uint64(version)
+	info.CreatedAt = time.UnixMicro(created)
+	info.UpdatedAt = time.UnixMicro(updated)
+	return info, nil
+}
+
+// sqlitePlaceholders returns a list of n placeholders for an in clause.
+func sqlitePlaceholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// withTx runs fn in a transaction which is committed when fn succeeds.
+func (s *sqliteStorage) withTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	_sqliteBatchDeleteResource = `update user_data set is_deleted=1, last_update=?
+					where user_id=? and resource_id=? and is_deleted=0
+					returning resource_id, salt, metadata, kind, sha256, byte_size, version, created, last_update, is_deleted;`
+
+	_sqliteSavepoint         = `savepoint batch_item;`
+	_sqliteReleaseSavepoint  = `release batch_item;`
+	_sqliteRollbackSavepoint = `rollback to batch_item;`
+)
+
+// Batch runs all items in a single transaction. Every item of a non atomic batch
+// runs in its own savepoint, so a failed item doesn't affect the rest.
+func (s *sqliteStorage) Batch(ctx context.Context, user *UserID, items []BatchItem, opts *BatchOptions) ([]BatchResult, error) {
+	results := make([]BatchResult, len(items))
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		for i := range items {
+			if opts.Atomic {
+				results[i].Info, results[i].Err = runSQLiteBatchItem(ctx, tx, user, &items[i], opts)
+				if results[i].Err != nil {
+					abortBatch(results, i)
+					return errBatchRollback
+				}
+				continue
+			}
+
+			if _, err := tx.ExecContext(ctx, _sqliteSavepoint); err != nil {
+				return err
+			}
+			results[i].Info, results[i].Err = runSQLiteBatchItem(ctx, tx, user, &items[i], opts)
+			if results[i].Err != nil {
+				if _, err := tx.ExecContext(ctx, _sqliteRollbackSavepoint); err != nil {
+					return err
+				}
+			}
+			if _, err := tx.ExecContext(ctx, _sqliteReleaseSavepoint); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errBatchRollback) {
+		return nil, err
+	}
+	return results, nil
+}
+
+func runSQLiteBatchItem(ctx context.Context, tx *sql.Tx, user *UserID, item *BatchItem, opts *BatchOptions) (*ResourceInfo, error) {
+	var (
+		info *ResourceInfo
+		err  error
+		now  = time.Now().UnixMicro()
+	)
+	switch item.Op {
+	case BatchStat:
+		info, err = scanSQLiteResourceInfo(tx.QueryRowContext(ctx, _sqliteStatResource, item.ID.String(), user.String()))
+	case BatchDelete:
+		info, err = scanSQLiteResourceInfo(tx.QueryRowContext(ctx, _sqliteBatchDeleteResource,
+			now, user.String(), item.ID.String()))
+	case BatchRestore:
+		info, err = scanSQLiteResourceInfo(tx.QueryRowContext(ctx, _sqliteRestoreResource,
+			now, user.String(), item.ID.String(), opts.RestorableSince.UnixMicro()))
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotInTrash
+		}
+	default:
+		return nil, fmt.Errorf("unknown batch operation %d", item.Op)
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrResourceNotFound
+	}
+	return info, err
+}
@@ -0,0 +1,123 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+var _ BlobKeyStore = (*sqliteStorage)(nil)
+
+const (
+	_sqliteAddBlobKey    = `insert into blob_keys (blob_id, kek_id, wrapped_key, created, last_update) values (?1, ?2, ?3, ?4, ?4);`
+	_sqliteGetBlobKey    = `select kek_id, wrapped_key from blob_keys where blob_id=?;`
+	_sqliteUpdateBlobKey = `update blob_keys set kek_id=?2, wrapped_key=?3, last_update=?4 where blob_id=?1;`
+	_sqliteDeleteBlobKey = `delete from blob_keys where blob_id=?;`
+	_sqliteKEKIDs        = `select distinct kek_id from blob_keys;`
+	_sqliteStaleBlobKeys = `select blob_id, kek_id, wrapped_key from blob_keys where kek_id<>? limit ?;`
+
+	_sqliteDropBlobSegments = `delete from blob_segments where blob_id=? and pos>=?;`
+	_sqliteAddBlobSegment   = `insert into blob_segments (blob_id, pos, nonce) values (?, ?, ?);`
+	_sqliteBlobSegments     = `select pos, nonce from blob_segments where blob_id=? order by pos;`
+)
+
+func (s *sqliteStorage) AddBlobKey(ctx context.Context, key *BlobKey) error {
+	_, err := s.db.ExecContext(ctx, _sqliteAddBlobKey, string(key.BlobID), key.KEKID, key.Wrapped, time.Now().UnixMicro())
+	return err
+}
+
+func (s *sqliteStorage) BlobKey(ctx context.Context, id BlobID) (*BlobKey, error) {
+	key := &BlobKey{BlobID: id}
+	if err := s.db.QueryRowContext(ctx, _sqliteGetBlobKey, string(id)).Scan(&key.KEKID, &key.Wrapped); err != nil {
+		return nil, noRows(err, ErrBlobKeyNotFound)
+	}
+	return key, nil
+}
+
+func (s *sqliteStorage) UpdateBlobKey(ctx context.Context, key *BlobKey) error {
+	_, err := s.db.ExecContext(ctx, _sqliteUpdateBlobKey, string(key.BlobID), key.KEKID, key.Wrapped, time.Now().UnixMicro())
+	return err
+}
+
+func (s *sqliteStorage) DeleteBlobKey(ctx context.Context, id BlobID) error {
+	_, err := s.db.ExecContext(ctx, _sqliteDeleteBlobKey, string(id))
+	return err
+}
+
+func (s *sqliteStorage) KEKIDs(ctx context.Context) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, _sqliteKEKIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	ids := make([]string, 0)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (s *sqliteStorage) StaleBlobKeys(ctx context.Context, kekID string, limit int) ([]BlobKey, error) {
+	rows, err := s.db.QueryContext(ctx, _sqliteStaleBlobKeys, kekID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	keys := make([]BlobKey, 0)
+	for rows.Next() {
+		var (
+			key BlobKey
+			id  string
+		)
+		if err := rows.Scan(&id, &key.KEKID, &key.Wrapped); err != nil {
+			return nil, err
+		}
+		key.BlobID = BlobID(id)
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+func (s *sqliteStorage) AddBlobSegment(ctx context.Context, id BlobID, segment *BlobSegment) error {
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, _sqliteDropBlobSegments, string(id), int64(segment.Offset)); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, _sqliteAddBlobSegment, string(id), int64(segment.Offset), segment.Nonce)
+		return err
+	})
+}
+
+func (s *sqliteStorage) BlobSegments(ctx context.Context, id BlobID) ([]BlobSegment, error) {
+	rows, err := s.db.QueryContext(ctx, _sqliteBlobSegments, string(id))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	segments := make([]BlobSegment, 0)
+	for rows.Next() {
+		var (
+			segment BlobSegment
+			pos     int64
+		)
+		if err := rows.Scan(&pos, &segment.Nonce); err != nil {
+			return nil, err
+		}
+		segment.Offset = uint64(pos)
+		segments = append(segments, segment)
+	}
+	return segments, rows.Err()
+}
@@ -0,0 +1,166 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+const (
+	_sqliteGetChunks = `select sha256, blob_id, byte_size from user_chunks where user_id=? and sha256 in (%s);`
+	_sqliteAddChunk  = `insert into user_chunks (user_id, sha256, blob_id, byte_size, created) values (?, ?, ?, ?, ?)
+					on conflict (user_id, sha256) do nothing;`
+	_sqliteAddResourceChunk = `insert into resource_chunks (resource_id, seq, user_id, sha256) values (?, ?, ?, ?);`
+	_sqliteReferenceChunk   = `update user_chunks set ref_count=ref_count+1 where user_id=? and sha256=?;`
+	_sqliteResourceChunks   = `select c.sha256, c.blob_id, c.byte_size from resource_chunks r
+					join user_chunks c on c.user_id=r.user_id and c.sha256=r.sha256
+					where r.resource_id=? and r.user_id=? order by r.seq;`
+)
+
+func (s *sqliteStorage) MissingChunks(ctx context.Context, user *UserID, digests [][]byte) ([][]byte, error) {
+	existing, err := s.chunksByDigest(ctx, user, digests)
+	if err != nil {
+		return nil, err
+	}
+
+	missing := make([][]byte, 0)
+	for _, digest := range digests {
+		if _, ok := existing[hex.EncodeToString(digest)]; !ok {
+			missing = append(missing, digest)
+		}
+	}
+	return missing, nil
+}
+
+func (s *sqliteStorage) Chunks(ctx context.Context, user *UserID, digests [][]byte) ([]Chunk, error) {
+	existing, err := s.chunksByDigest(ctx, user, digests)
+	if err != nil {
+		return nil, err
+	}
+
+	chunks := make([]Chunk, 0, len(digests))
+	for _, digest := range digests {
+		chunk, ok := existing[hex.EncodeToString(digest)]
+		if !ok {
+			return nil, fmt.Errorf("%w: %x", ErrChunkMissing, digest)
+		}
+		chunks = append(chunks, chunk)
+	}
+	return chunks, nil
+}
+
+func (s *sqliteStorage) AddChunk(ctx context.Context, user *UserID, chunk *Chunk) (bool, error) {
+	res, err := s.db.ExecContext(ctx, _sqliteAddChunk, user.String(), chunk.Digest, string(chunk.Blob),
+		int64(chunk.ByteSize), time.Now().UnixMicro())
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected != 0, nil
+}
+
+// AddChunkedResource adds a resource without a blob of its own. References are
+// counted per manifest entry, so a chunk repeated in a resource is counted twice.
+func (s *sqliteStorage) AddChunkedResource(ctx context.Context, user *UserID, id *ResourceID, meta *ResourceMeta, digests [][]byte) (*ResourceInfo, error) {
+	var info *ResourceInfo
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		if info, err = s.addResource(ctx, tx, user, id, sql.NullString{}, meta); err != nil {
+			return err
+		}
+
+		for i, digest := range digests {
+			res, err := tx.ExecContext(ctx, _sqliteReferenceChunk, user.String(), digest)
+			if err != nil {
+				return err
+			}
+			affected, err := res.RowsAffected()
+			if err != nil {
+				return err
+			}
+			if affected == 0 {
+				return fmt.Errorf("%w: %x", ErrChunkMissing, digest)
+			}
+
+			if _, err := tx.ExecContext(ctx, _sqliteAddResourceChunk, id.String(), i, user.String(), digest); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+func (s *sqliteStorage) ResourceChunks(ctx context.Context, user *UserID, id *ResourceID) ([]Chunk, error) {
+	rows, err := s.db.QueryContext(ctx, _sqliteResourceChunks, id.String(), user.String())
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	chunks := make([]Chunk, 0)
+	for rows.Next() {
+		chunk, err := scanSQLiteChunk(rows)
+		if err != nil {
+			return nil, err
+		}
+		chunks = append(chunks, *chunk)
+	}
+	return chunks, rows.Err()
+}
+
+// chunksByDigest returns existing chunks of a user keyed by hex encoded digests.
+func (s *sqliteStorage) chunksByDigest(ctx context.Context, user *UserID, digests [][]byte) (map[string]Chunk, error) {
+	chunks := make(map[string]Chunk, len(digests))
+	if len(digests) == 0 {
+		return chunks, nil
+	}
+
+	args := make([]any, 0, len(digests)+1)
+	args = append(args, user.String())
+	for _, digest := range digests {
+		args = append(args, digest)
+	}
+
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(_sqliteGetChunks, sqlitePlaceholders(len(digests))), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	for rows.Next() {
+		chunk, err := scanSQLiteChunk(rows)
+		if err != nil {
+			return nil, err
+		}
+		chunks[hex.EncodeToString(chunk.Digest)] = *chunk
+	}
+	return chunks, rows.Err()
+}
+
+func scanSQLiteChunk(row sqliteRow) (*Chunk, error) {
+	var (
+		chunk    = &Chunk{}
+		blob     string
+		byteSize int64
+	)
+	if err := row.Scan(&chunk.Digest, &blob, &byteSize); err != nil {
+		return nil, err
+	}
+
+	chunk.Blob = BlobID(blob)
+	chunk.ByteSize = uint64(byteSize)
+	return chunk, nil
+}
@@ -0,0 +1,124 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	_sqliteSelectDeletedResources = `select resource_id, coalesce(blob_id, '') from user_data
+					where is_deleted=1 and last_update<? limit ?;`
+	_sqliteSelectUserDeletedResources = `select resource_id, coalesce(blob_id, '') from user_data
+					where user_id=? and is_deleted=1 limit ?;`
+	_sqliteReleaseResourceChunks = `update user_chunks set ref_count=user_chunks.ref_count-r.refs
+					from (select user_id, sha256, count(*) as refs from resource_chunks
+						where resource_id in (%s) group by user_id, sha256) r
+					where user_chunks.user_id=r.user_id and user_chunks.sha256=r.sha256;`
+	_sqliteDeleteResourceChunks = `delete from resource_chunks where resource_id in (%s);`
+	_sqlitePurgeResources       = `delete from user_data where resource_id in (%s);`
+	_sqlitePurgeChunks          = `delete from user_chunks where (user_id, sha256) in
+					(select user_id, sha256 from user_chunks where ref_count<=0 and created<? limit ?)
+					returning blob_id;`
+	_sqliteUnreferencedBlobs = `select b.column1 from (values %s) as b
+					where not exists (select 1 from user_data where blob_id=b.column1)
+					and not exists (select 1 from upload_sessions where blob_id=b.column1)
+					and not exists (select 1 from user_chunks where blob_id=b.column1);`
+)
+
+func (s *sqliteStorage) PurgeDeleted(ctx context.Context, deletedBefore time.Time, limit int) (int, []BlobID, error) {
+	return s.purgeResources(ctx, limit, _sqliteSelectDeletedResources, deletedBefore.UnixMicro(), limit)
+}
+
+func (s *sqliteStorage) PurgeUserDeleted(ctx context.Context, user *UserID, limit int) (int, []BlobID, error) {
+	return s.purgeResources(ctx, limit, _sqliteSelectUserDeletedResources, user.String(), limit)
+}
+
+// purgeResources removes resources selected by selectQuery. A transaction
+// holds the database lock, so there is no need to lock rows like Postgres does.
+func (s *sqliteStorage) purgeResources(ctx context.Context, limit int, selectQuery string, args ...any) (int, []BlobID, error) {
+	var (
+		ids   = make([]any, 0, limit)
+		blobs = make([]BlobID, 0, limit)
+	)
+
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx, selectQuery, args...)
+		if err != nil {
+			return err
+		}
+
+		for rows.Next() {
+			var id, blob string
+			if err := rows.Scan(&id, &blob); err != nil {
+				_ = rows.Close()
+				return err
+			}
+			ids = append(ids, id)
+			if blob != "" {
+				blobs = append(blobs, BlobID(blob))
+			}
+		}
+		_ = rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		if len(ids) == 0 {
+			return nil
+		}
+
+		placeholders := sqlitePlaceholders(len(ids))
+		for _, query := range []string{_sqliteReleaseResourceChunks, _sqliteDeleteResourceChunks, _sqlitePurgeResources} {
+			if _, err := tx.ExecContext(ctx, fmt.Sprintf(query, placeholders), ids...); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return len(ids), blobs, nil
+}
+
+func (s *sqliteStorage) PurgeChunks(ctx context.Context, createdBefore time.Time, limit int) ([]BlobID, error) {
+	return s.queryBlobs(ctx, _sqlitePurgeChunks, createdBefore.UnixMicro(), limit)
+}
+
+func (s *sqliteStorage) UnreferencedBlobs(ctx context.Context, blobs []BlobID) ([]BlobID, error) {
+	if len(blobs) == 0 {
+		return []BlobID{}, nil
+	}
+
+	ids := make([]any, 0, len(blobs))
+	for _, b := range blobs {
+		ids = append(ids, string(b))
+	}
+	values := strings.TrimSuffix(strings.Repeat("(?), ", len(ids)), ", ")
+	query := fmt.Sprintf(_sqliteUnreferencedBlobs, values)
+	return s.queryBlobs(ctx, query, ids...)
+}
+
+func (s *sqliteStorage) queryBlobs(ctx context.Context, query string, args ...any) ([]BlobID, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	blobs := make([]BlobID, 0)
+	for rows.Next() {
+		var blob string
+		if err := rows.Scan(&blob); err != nil {
+			return nil, err
+		}
+		blobs = append(blobs, BlobID(blob))
+	}
+	return blobs, rows.Err()
+}
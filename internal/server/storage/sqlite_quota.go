@@ -0,0 +1,47 @@
+package storage
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	_sqliteGetUsage = `select
+					(select coalesce(sum(byte_size), 0) from user_data where user_id=?1 and blob_id is not null) +
+					(select coalesce(sum(byte_size), 0) from upload_sessions where user_id=?1) +
+					(select coalesce(sum(byte_size), 0) from user_chunks where user_id=?1),
+					(select count(*) from user_data where user_id=?1) +
+					(select count(*) from upload_sessions where user_id=?1);`
+	_sqliteGetQuota = `select max_bytes, max_items from user_quotas where user_id=?;`
+	_sqliteSetQuota = `insert into user_quotas (user_id, max_bytes, max_items, created, last_update) values (?1, ?2, ?3, ?4, ?4)
+					on conflict (user_id) do update set max_bytes=excluded.max_bytes, max_items=excluded.max_items, last_update=excluded.last_update;`
+	_sqliteDeleteQuota = `delete from user_quotas where user_id=?;`
+)
+
+func (s *sqliteStorage) Usage(ctx context.Context, user *UserID) (*Usage, error) {
+	var bytes, items int64
+	if err := s.db.QueryRowContext(ctx, _sqliteGetUsage, user.String()).Scan(&bytes, &items); err != nil {
+		return nil, err
+	}
+	return &Usage{Bytes: uint64(bytes), Items: uint64(items)}, nil
+}
+
+func (s *sqliteStorage) Quota(ctx context.Context, user *UserID) (*Quota, error) {
+	var maxBytes, maxItems int64
+	err := s.db.QueryRowContext(ctx, _sqliteGetQuota, user.String()).Scan(&maxBytes, &maxItems)
+	if err != nil {
+		return nil, noRows(err, ErrQuotaNotSet)
+	}
+	return &Quota{MaxBytes: uint64(maxBytes), MaxItems: uint64(maxItems)}, nil
+}
+
+func (s *sqliteStorage) SetQuota(ctx context.Context, user *UserID, quota *Quota) error {
+	_, err := s.db.ExecContext(ctx, _sqliteSetQuota, user.String(), int64(quota.MaxBytes), int64(quota.MaxItems),
+		time.Now().UnixMicro())
+	return err
+}
+
+func (s *sqliteStorage) DeleteQuota(ctx context.Context, user *UserID) error {
+	_, err := s.db.ExecContext(ctx, _sqliteDeleteQuota, user.String())
+	return err
+}
@@ -0,0 +1,109 @@
+package storage
+
+// _sqliteSchema mirrors the Postgres migrations. Times are kept as unix
+// microseconds, so they compare and order like Postgres timestamps.
+var _sqliteSchema = []string{
+	`create table if not exists users (
+		id text primary key,
+		login text not null unique,
+		key_salt blob not null,
+		salt blob not null,
+		secret blob not null,
+		created integer not null,
+		last_update integer not null,
+		is_deleted integer not null default 0
+	);`,
+
+	`create table if not exists user_data (
+		id integer primary key autoincrement,
+		user_id text not null references users(id),
+		resource_id text not null unique,
+		blob_id text unique,
+		salt blob not null,
+		metadata blob,
+		kind text not null default '',
+		sha256 blob,
+		byte_size integer not null default 0,
+		version integer not null default 1,
+		created integer not null,
+		last_update integer not null,
+		is_deleted integer not null default 0
+	);`,
+	`create index if not exists user_data_user_id_id_idx on user_data (user_id, id);`,
+	`create index if not exists user_data_user_id_last_update_idx on user_data (user_id, last_update, id);`,
+	`create index if not exists user_data_deleted_last_update_idx on user_data (last_update) where is_deleted;`,
+
+	`create table if not exists upload_sessions (
+		id text primary key,
+		user_id text not null references users(id),
+		blob_id text not null,
+		salt blob not null,
+		metadata blob,
+		kind text not null default '',
+		sha256 blob,
+		byte_size integer not null,
+		committed_offset integer not null default 0,
+		created integer not null,
+		last_update integer not null
+	);`,
+	`create index if not exists upload_sessions_last_update_idx on upload_sessions (last_update);`,
+	`create index if not exists upload_sessions_blob_id_idx on upload_sessions (blob_id);`,
+	`create index if not exists upload_sessions_user_id_idx on upload_sessions (user_id);`,
+
+	`create table if not exists user_chunks (
+		user_id text not null references users(id),
+		sha256 blob not null,
+		blob_id text not null unique,
+		byte_size integer not null,
+		ref_count integer not null default 0,
+		created integer not null,
+
+		primary key (user_id, sha256)
+	);`,
+	`create index if not exists user_chunks_unreferenced_idx on user_chunks (created) where ref_count <= 0;`,
+
+	`create table if not exists resource_chunks (
+		resource_id text not null references user_data(resource_id),
+		seq integer not null,
+		user_id text not null,
+		sha256 blob not null,
+
+		primary key (resource_id, seq),
+		foreign key (user_id, sha256) references user_chunks(user_id, sha256)
+	);`,
+
+	`create table if not exists user_quotas (
+		user_id text primary key references users(id),
+		max_bytes integer not null,
+		max_items integer not null,
+		created integer not null,
+		last_update integer not null
+	);`,
+
+	`create table if not exists blob_keys (
+		blob_id text primary key,
+		kek_id text not null,
+		wrapped_key blob not null,
+		created integer not null,
+		last_update integer not null
+	);`,
+	`create index if not exists blob_keys_kek_id_idx on blob_keys (kek_id);`,
+	`create table if not exists blob_segments (
+		blob_id text not null references blob_keys(blob_id) on delete cascade,
+		pos integer not null,
+		nonce blob not null,
+		primary key (blob_id, pos)
+	);`,
+
+	`create table if not exists blobs (
+		id text primary key,
+		last_update integer not null
+	);`,
+	`create table if not exists blob_parts (
+		blob_id text not null references blobs(id) on delete cascade,
+		pos integer not null,
+		data blob not null,
+
+		primary key (blob_id, pos)
+	);`,
+}
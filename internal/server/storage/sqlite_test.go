@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteDriverDSN(t *testing.T) {
+	dsn, err := sqliteDriverDSN("sqlite:///var/lib/gophkeeper/keeper.db")
+	assert.NoError(t, err)
+	assert.Equal(t, "file:/var/lib/gophkeeper/keeper.db?_busy_timeout=5000&_foreign_keys=1&_journal_mode=WAL", dsn)
+
+	dsn, err = sqliteDriverDSN("sqlite://keeper.db?_busy_timeout=100&cache=shared")
+	assert.NoError(t, err)
+	assert.Equal(t, "file:keeper.db?_busy_timeout=100&_foreign_keys=1&_journal_mode=WAL&cache=shared", dsn)
+
+	_, err = sqliteDriverDSN("sqlite://")
+	assert.Error(t, err)
+	_, err = sqliteDriverDSN("postgres://localhost/keeper")
+	assert.Error(t, err)
+
+	assert.True(t, IsSQLiteDSN("sqlite://keeper.db"))
+	assert.False(t, IsSQLiteDSN("postgres://localhost/keeper"))
+}
+
+func TestSQLiteStorage(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "keeper.db")
+	meta, err := NewSQLiteUserService(ctx, SQLiteScheme+path, time.Second)
+	require.NoError(t, err)
+
+	s := NewBlobStorage(meta, NewSQLiteBlobStore(meta))
+	user := addTestUser(t, ctx, meta)
+
+	data := []byte("resource data")
+	digest := sha256.Sum256(data)
+	session, err := s.CreateUpload(ctx, user, &ResourceMeta{Salt: []byte("salt"), ByteSize: uint64(len(data)), Digest: digest[:]})
+	require.NoError(t, err)
+	_, err = s.AppendUpload(ctx, user, &session.ID, 0, data[:5])
+	require.NoError(t, err)
+	_, err = s.AppendUpload(ctx, user, &session.ID, 5, data[5:])
+	require.NoError(t, err)
+	info, err := s.FinalizeUpload(ctx, user, &session.ID)
+	require.NoError(t, err)
+
+	// Data survives a restart.
+	require.NoError(t, meta.Close())
+	meta, err = NewSQLiteUserService(ctx, SQLiteScheme+path, time.Second)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = meta.Close()
+	})
+	s = NewBlobStorage(meta, NewSQLiteBlobStore(meta))
+
+	res, err := s.Open(ctx, user, &info.ID)
+	require.NoError(t, err)
+	remote, err := io.ReadAll(res)
+	assert.NoError(t, err)
+	assert.Equal(t, data, remote)
+	assert.NoError(t, res.Close())
+
+	require.NoError(t, s.Delete(ctx, user, &info.ID))
+	stats, err := s.CollectGarbage(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.Tombstones)
+	assert.Equal(t, 1, stats.Blobs)
+
+	_, err = s.Open(ctx, user, &info.ID)
+	assert.ErrorIs(t, err, ErrResourceNotFound)
+}
@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	_sqliteAddUpload = `insert into upload_sessions (id, user_id, blob_id, salt, metadata, kind, byte_size, sha256, created, last_update)
+					values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+					returning id, blob_id, salt, metadata, kind, sha256, byte_size, committed_offset, created, last_update;`
+	_sqliteGetUpload = `select id, blob_id, salt, metadata, kind, sha256, byte_size, committed_offset, created, last_update
+					from upload_sessions where id=? and user_id=?;`
+	_sqliteAdvanceUpload = `update upload_sessions set committed_offset=?, last_update=?
+					where id=? and user_id=? and committed_offset=?
+					returning id, blob_id, salt, metadata, kind, sha256, byte_size, committed_offset, created, last_update;`
+	_sqliteDeleteUpload  = `delete from upload_sessions where id=?;`
+	_sqliteExpireUploads = `delete from upload_sessions where last_update<? returning blob_id;`
+)
+
+func (s *sqliteStorage) AddUpload(ctx context.Context, user *UserID, blob BlobID, meta *ResourceMeta) (*UploadSession, error) {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UnixMicro()
+	return scanSQLiteUploadSession(s.db.QueryRowContext(ctx, _sqliteAddUpload, id.String(), user.String(), string(blob),
+		meta.Salt, meta.Metadata, meta.Kind, int64(meta.ByteSize), meta.Digest, now, now))
+}
+
+func (s *sqliteStorage) GetUpload(ctx context.Context, user *UserID, id *UploadID) (*UploadSession, error) {
+	session, err := scanSQLiteUploadSession(s.db.QueryRowContext(ctx, _sqliteGetUpload, id.String(), user.String()))
+	if err != nil {
+		return nil, noRows(err, ErrUploadNotFound)
+	}
+	return session, nil
+}
+
+func (s *sqliteStorage) AdvanceUpload(ctx context.Context, user *UserID, id *UploadID, offset, size uint64) (*UploadSession, error) {
+	session, err := scanSQLiteUploadSession(s.db.QueryRowContext(ctx, _sqliteAdvanceUpload,
+		int64(offset+size), time.Now().UnixMicro(), id.String(), user.String(), int64(offset)))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrUploadOffsetMismatch
+	}
+	return session, err
+}
+
+func (s *sqliteStorage) FinalizeUpload(ctx context.Context, user *UserID, id *UploadID, resourceID *ResourceID) (*ResourceInfo, error) {
+	var info *ResourceInfo
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		session, err := scanSQLiteUploadSession(tx.QueryRowContext(ctx, _sqliteGetUpload, id.String(), user.String()))
+		if err != nil {
+			return noRows(err, ErrUploadNotFound)
+		}
+
+		if session.Offset != session.ByteSize {
+			return ErrUploadIncomplete
+		}
+
+		blob := sql.NullString{String: string(session.blob), Valid: true}
+		if info, err = s.addResource(ctx, tx, user, resourceID, blob, &session.Meta); err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, _sqliteDeleteUpload, id.String())
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+func (s *sqliteStorage) ExpireUploads(ctx context.Context, idleSince time.Time) ([]BlobID, error) {
+	return s.queryBlobs(ctx, _sqliteExpireUploads, idleSince.UnixMicro())
+}
+
+func scanSQLiteUploadSession(row sqliteRow) (*UploadSession, error) {
+	var (
+		session  = &UploadSession{}
+		id       string
+		blob     string
+		byteSize int64
+		offset   int64
+		created  int64
+		updated  int64
+	)
+	err := row.Scan(&id, &blob, &session.Meta.Salt, &session.Meta.Metadata, &session.Meta.Kind,
+		&session.Meta.Digest, &byteSize, &offset, &created, &updated)
+	if err != nil {
+		return nil, err
+	}
+
+	uploadID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	session.ID = UploadID(uploadID)
+	session.blob = BlobID(blob)
+	session.ByteSize = uint64(byteSize)
+	session.Meta.ByteSize = session.ByteSize
+	session.Offset = uint64(offset)
+	session.CreatedAt = time.UnixMicro(created)
+	session.UpdatedAt = time.UnixMicro(updated)
+	return session, nil
+}
@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"context"
+	"time"
+)
+
+var ErrNotInTrash = NewError(CodeNotFound, "resource is not in trash")
+
+// TrashStorage gives access to deleted resources until garbage collection
+// removes them.
+type TrashStorage interface {
+	// Restore undeletes a resource deleted after deletedAfter.
+	// It fails with ErrNotInTrash when there is no such resource.
+	Restore(ctx context.Context, user *UserID, id *ResourceID, deletedAfter time.Time) (*ResourceInfo, error)
+	// EmptyTrash removes all deleted resources of a user with their data.
+	// It returns a number of removed resources.
+	EmptyTrash(ctx context.Context, user *UserID) (int, error)
+}
+
+func (b *blobStorage) Restore(ctx context.Context, user *UserID, id *ResourceID, deletedAfter time.Time) (*ResourceInfo, error) {
+	return b.meta.Restore(ctx, user, id, deletedAfter)
+}
+
+// EmptyTrash deletes blobs right away, while chunks released by removed
+// resources are left for garbage collection as other resources may share them.
+func (b *blobStorage) EmptyTrash(ctx context.Context, user *UserID) (int, error) {
+	var (
+		result error
+		total  int
+	)
+	for {
+		purged, blobs, err := b.meta.PurgeUserDeleted(ctx, user, _gcBatchSize)
+		if err != nil {
+			return total, err
+		}
+		total += purged
+		b.deleteBlobs(ctx, blobs, &result)
+		if purged < _gcBatchSize {
+			return total, result
+		}
+	}
+}
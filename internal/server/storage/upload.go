@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrUploadNotFound       = NewError(CodeNotFound, "upload not found")
+	ErrUploadOffsetMismatch = NewError(CodeFailedPrecondition, "upload offset mismatch")
+	ErrUploadTooLarge       = NewError(CodeOutOfRange, "upload data is larger than expected")
+	ErrUploadIncomplete     = NewError(CodeFailedPrecondition, "upload is incomplete")
+)
+
+type UploadID uuid.UUID
+
+func (u UploadID) String() string {
+	return uuid.UUID(u).String()
+}
+
+func NewUploadIDFromString(u string) (*UploadID, error) {
+	id, err := uuid.Parse(u)
+	if err != nil {
+		return nil, err
+	}
+	res := UploadID(id)
+	return &res, nil
+}
+
+// UploadSession is a partially uploaded resource. Every appended chunk is committed
+// immediately, so a session survives client reconnects until it expires.
+type UploadSession struct {
+	ID        UploadID
+	Meta      ResourceMeta
+	ByteSize  uint64
+	Offset    uint64
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	blob BlobID
+}
+
+type UploadStorage interface {
+	// CreateUpload starts a session for a resource of meta.ByteSize bytes.
+	CreateUpload(ctx context.Context, user *UserID, meta *ResourceMeta) (*UploadSession, error)
+	GetUpload(ctx context.Context, user *UserID, id *UploadID) (*UploadSession, error)
+	// AppendUpload writes data at offset which must be equal to the committed offset of a session.
+	AppendUpload(ctx context.Context, user *UserID, id *UploadID, offset uint64, data []byte) (*UploadSession, error)
+	// FinalizeUpload verifies data of a complete session and turns it into a resource.
+	FinalizeUpload(ctx context.Context, user *UserID, id *UploadID) (*ResourceInfo, error)
+	// ExpireUploads removes sessions which were not updated since idleSince.
+	ExpireUploads(ctx context.Context, idleSince time.Time) (int, error)
+}
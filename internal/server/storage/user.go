@@ -7,6 +7,11 @@ import (
 	"github.com/google/uuid"
 )
 
+var (
+	ErrUserNotFound = NewError(CodeNotFound, "user not found")
+	ErrUserExists   = NewError(CodeAlreadyExists, "user already exists")
+)
+
 type UserID uuid.UUID
 
 func (u UserID) String() string {
@@ -1,31 +1,93 @@
 package storage
 
 import (
+	"bytes"
 	"context"
+	"fmt"
 	"io"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+var (
+	ErrResourceNotFound = NewError(CodeNotFound, "resource not found")
+	ErrSizeMismatch     = NewError(CodeInvalidArgument, "resource size mismatch")
+	ErrDigestMismatch   = NewError(CodeDataLoss, "resource digest mismatch")
+)
+
 type ResourceID uuid.UUID
 
 func (r ResourceID) String() string {
 	return uuid.UUID(r).String()
 }
 
+// ResourceMeta holds client supplied information about a new resource.
+type ResourceMeta struct {
+	Salt []byte
+	// Metadata is an opaque client encrypted blob describing the resource.
+	Metadata []byte
+	Kind     string
+	ByteSize uint64
+	// Digest is a SHA-256 digest of the resource data. It isn't checked when empty.
+	Digest []byte
+}
+
+// Verify checks that the stored data matches the declared size and digest.
+func (m *ResourceMeta) Verify(size uint64, digest []byte) error {
+	if size != m.ByteSize {
+		return fmt.Errorf("%w: %d bytes declared, %d bytes received", ErrSizeMismatch, m.ByteSize, size)
+	}
+	if len(m.Digest) != 0 && !bytes.Equal(m.Digest, digest) {
+		return ErrDigestMismatch
+	}
+	return nil
+}
+
+// ResourceInfo describes a stored resource without its content.
+type ResourceInfo struct {
+	ID        ResourceID
+	Salt      []byte
+	Metadata  []byte
+	Kind      string
+	Digest    []byte
+	ByteSize  uint64
+	Version   uint64
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	IsDeleted bool
+}
+
+// Resource is an opened resource. Close commits a new resource while Abort discards it.
+// One of them must be called to release the resource.
 type Resource interface {
 	io.Closer
 	io.Writer
 	io.Reader
+	io.Seeker
+
+	Abort() error
 
 	GetId() *ResourceID
 	IsDeleted() bool
 	Salt() ([]byte, error)
+	Digest() ([]byte, error)
 }
 
 type Storage interface {
-	Create(ctx context.Context, user *UserID, salt []byte) (Resource, error)
+	Create(ctx context.Context, user *UserID, meta *ResourceMeta) (Resource, error)
 	Open(ctx context.Context, user *UserID, id *ResourceID) (Resource, error)
 	Delete(ctx context.Context, user *UserID, id *ResourceID) error
-	List(ctx context.Context, user *UserID) ([]Resource, error)
+	// List returns a page of resources and a token of the next page.
+	// The token is empty when there are no more resources.
+	List(ctx context.Context, user *UserID, opts *ListOptions) ([]ResourceInfo, string, error)
+	Stat(ctx context.Context, user *UserID, id *ResourceID) (*ResourceInfo, error)
+	// Batch runs items in a single transaction. It returns a result for every item
+	// in order, so failures of items are reported by the results.
+	Batch(ctx context.Context, user *UserID, items []BatchItem, opts *BatchOptions) ([]BatchResult, error)
+
+	UploadStorage
+	ChunkStorage
+	TrashStorage
+	QuotaStorage
 }
@@ -0,0 +1,108 @@
+package tracing
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	pb "github.com/r4start/goph-keeper/pkg/grpc/proto"
+)
+
+type infoServer struct {
+	pb.UnimplementedInfoServer
+	spanContext trace.SpanContext
+}
+
+func (s *infoServer) ServerInfo(ctx context.Context, _ *pb.ServerInfoRequest) (*pb.ServerInfo, error) {
+	s.spanContext = trace.SpanContextFromContext(ctx)
+	return &pb.ServerInfo{}, nil
+}
+
+func TestInterceptors_Propagation(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	lis := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(StreamServerInterceptor()))
+	info := &infoServer{}
+	pb.RegisterInfoServer(srv, info)
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+	defer srv.Stop()
+
+	conn, err := grpc.DialContext(context.Background(), "",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return lis.Dial()
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(UnaryClientInterceptor()),
+		grpc.WithChainStreamInterceptor(StreamClientInterceptor()))
+	require.NoError(t, err)
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	ctx, root := Start(context.Background(), "root")
+	_, err = pb.NewInfoClient(conn).ServerInfo(ctx, &pb.ServerInfoRequest{})
+	require.NoError(t, err)
+	End(root, nil)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+
+	byKind := make(map[trace.SpanKind]sdktrace.ReadOnlySpan)
+	for _, s := range spans {
+		byKind[s.SpanKind()] = s
+	}
+	client, server := byKind[trace.SpanKindClient], byKind[trace.SpanKindServer]
+	require.NotNil(t, client)
+	require.NotNil(t, server)
+
+	assert.Equal(t, "gophkeeper.Info/ServerInfo", server.Name())
+	assert.Equal(t, root.SpanContext().TraceID(), server.SpanContext().TraceID())
+	assert.Equal(t, client.SpanContext().SpanID(), server.Parent().SpanID())
+	assert.Equal(t, server.SpanContext().SpanID(), info.spanContext.SpanID())
+}
+
+func TestSetup_FileExporter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traces.json")
+
+	shutdown, err := Setup(context.Background(), &Config{
+		ServiceName: "test",
+		Exporter:    ExporterFile,
+		FilePath:    path,
+	})
+	require.NoError(t, err)
+
+	_, span := Start(context.Background(), "file-span")
+	End(span, nil)
+	require.NoError(t, shutdown(context.Background()))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "file-span")
+	assert.Contains(t, string(data), `"Value":"test"`)
+}
+
+func TestSetup_UnknownExporter(t *testing.T) {
+	_, err := Setup(context.Background(), &Config{Exporter: "zipkin"})
+	assert.Error(t, err)
+}
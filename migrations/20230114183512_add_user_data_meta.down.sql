@@ -0,0 +1,4 @@
+alter table user_data
+    drop column if exists metadata,
+    drop column if exists byte_size,
+    drop column if exists version;
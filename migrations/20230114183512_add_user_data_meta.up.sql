@@ -0,0 +1,4 @@
+alter table user_data
+    add column metadata bytea,
+    add column byte_size bigint not null default 0,
+    add column version bigint not null default 1;
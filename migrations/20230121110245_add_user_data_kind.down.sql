@@ -0,0 +1,4 @@
+drop index if exists user_data_user_id_last_update_idx;
+drop index if exists user_data_user_id_id_idx;
+
+alter table user_data drop column if exists kind;
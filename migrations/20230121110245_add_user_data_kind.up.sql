@@ -0,0 +1,4 @@
+alter table user_data add column kind varchar(64) not null default '';
+
+create index user_data_user_id_id_idx on user_data (user_id, id);
+create index user_data_user_id_last_update_idx on user_data (user_id, last_update, id);
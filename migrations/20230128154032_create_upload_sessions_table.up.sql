@@ -0,0 +1,17 @@
+create table upload_sessions (
+    id uuid primary key,
+    user_id uuid not null,
+    data_id oid not null,
+    salt bytea not null,
+    metadata bytea,
+    kind varchar(64) not null default '',
+    byte_size bigint not null,
+    committed_offset bigint not null default 0,
+    created timestamptz not null default now(),
+    last_update timestamptz not null default now(),
+
+    foreign key (user_id)
+      references users(id)
+);
+
+create index upload_sessions_last_update_idx on upload_sessions (last_update);
@@ -0,0 +1,2 @@
+alter table upload_sessions drop column if exists sha256;
+alter table user_data drop column if exists sha256;
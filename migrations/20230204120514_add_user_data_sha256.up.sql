@@ -0,0 +1,2 @@
+alter table user_data add column sha256 bytea;
+alter table upload_sessions add column sha256 bytea;
@@ -0,0 +1,20 @@
+-- Only large object blobs have a data_id. Blobs of other stores would lose
+-- their references, so the rollback refuses to run while any exist.
+do $$
+begin
+    if exists (select 1 from user_data where blob_id !~ '^[0-9]+$')
+        or exists (select 1 from upload_sessions where blob_id !~ '^[0-9]+$') then
+        raise exception 'can''t roll back: resources or upload sessions refer to blobs which are not large objects';
+    end if;
+end
+$$;
+
+alter table upload_sessions add column data_id oid not null default 0;
+update upload_sessions set data_id = blob_id::oid;
+alter table upload_sessions drop column blob_id;
+
+drop index if exists user_data_blob_id_idx;
+alter table user_data add column data_id oid not null default 0;
+update user_data set data_id = blob_id::oid;
+alter table user_data add constraint user_data_user_id_data_id_key unique (user_id, data_id);
+alter table user_data drop column blob_id;
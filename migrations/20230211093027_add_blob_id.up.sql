@@ -0,0 +1,10 @@
+alter table user_data add column blob_id varchar(64);
+update user_data set blob_id = data_id::text;
+alter table user_data alter column blob_id set not null;
+alter table user_data drop column data_id;
+create unique index user_data_blob_id_idx on user_data (blob_id);
+
+alter table upload_sessions add column blob_id varchar(64);
+update upload_sessions set blob_id = data_id::text;
+alter table upload_sessions alter column blob_id set not null;
+alter table upload_sessions drop column data_id;
@@ -0,0 +1,14 @@
+-- Chunked resources have no blob and would lose their data, so the rollback
+-- refuses to run while any exist.
+do $$
+begin
+    if exists (select 1 from user_data where blob_id is null) then
+        raise exception 'can''t roll back: chunked resources exist';
+    end if;
+end
+$$;
+
+drop table resource_chunks;
+drop table user_chunks;
+
+alter table user_data alter column blob_id set not null;
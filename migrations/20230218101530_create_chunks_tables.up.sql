@@ -0,0 +1,30 @@
+create table user_chunks (
+    user_id uuid not null,
+    sha256 bytea not null,
+    blob_id varchar(64) not null unique,
+    byte_size bigint not null,
+    ref_count bigint not null default 0,
+    created timestamptz not null default now(),
+
+    primary key (user_id, sha256),
+
+    foreign key (user_id)
+      references users(id)
+);
+
+create table resource_chunks (
+    resource_id uuid not null,
+    seq integer not null,
+    user_id uuid not null,
+    sha256 bytea not null,
+
+    primary key (resource_id, seq),
+
+    foreign key (resource_id)
+      references user_data(resource_id),
+
+    foreign key (user_id, sha256)
+      references user_chunks(user_id, sha256)
+);
+
+alter table user_data alter column blob_id drop not null;
@@ -0,0 +1,3 @@
+drop index upload_sessions_blob_id_idx;
+drop index user_chunks_unreferenced_idx;
+drop index user_data_deleted_last_update_idx;
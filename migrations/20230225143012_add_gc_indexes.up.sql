@@ -0,0 +1,3 @@
+create index user_data_deleted_last_update_idx on user_data (last_update) where is_deleted;
+create index user_chunks_unreferenced_idx on user_chunks (created) where ref_count <= 0;
+create index upload_sessions_blob_id_idx on upload_sessions (blob_id);
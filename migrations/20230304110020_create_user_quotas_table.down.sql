@@ -0,0 +1,2 @@
+drop index upload_sessions_user_id_idx;
+drop table user_quotas;
@@ -0,0 +1,12 @@
+create table user_quotas (
+    user_id uuid primary key,
+    max_bytes bigint not null,
+    max_items bigint not null,
+    created timestamptz not null default now(),
+    last_update timestamptz not null default now(),
+
+    foreign key (user_id)
+      references users(id)
+);
+
+create index upload_sessions_user_id_idx on upload_sessions (user_id);
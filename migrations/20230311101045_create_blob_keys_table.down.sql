@@ -0,0 +1,2 @@
+drop index blob_keys_kek_id_idx;
+drop table blob_keys;
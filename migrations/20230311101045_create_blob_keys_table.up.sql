@@ -0,0 +1,9 @@
+create table blob_keys (
+    blob_id varchar(64) primary key,
+    kek_id varchar(64) not null,
+    wrapped_key bytea not null,
+    created timestamptz not null default now(),
+    last_update timestamptz not null default now()
+);
+
+create index blob_keys_kek_id_idx on blob_keys (kek_id);
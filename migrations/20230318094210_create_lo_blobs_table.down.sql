@@ -0,0 +1 @@
+drop table lo_blobs;
@@ -0,0 +1,14 @@
+create table lo_blobs (
+    oid oid primary key,
+    created timestamptz not null default now(),
+    last_update timestamptz not null default now()
+);
+
+-- Large objects created before are tracked only when something refers to them,
+-- other large objects of the database are never touched.
+insert into lo_blobs (oid)
+    select blob_id::oid from user_data where blob_id ~ '^[0-9]+$'
+    union select blob_id::oid from upload_sessions where blob_id ~ '^[0-9]+$'
+    union select blob_id::oid from user_chunks where blob_id ~ '^[0-9]+$';
+
+delete from lo_blobs b where not exists (select 1 from pg_largeobject_metadata where oid=b.oid);
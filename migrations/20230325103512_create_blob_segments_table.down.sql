@@ -0,0 +1 @@
+drop table blob_segments;
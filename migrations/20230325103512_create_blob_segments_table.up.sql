@@ -0,0 +1,6 @@
+create table blob_segments (
+    blob_id varchar(64) not null references blob_keys(blob_id) on delete cascade,
+    pos bigint not null,
+    nonce bytea not null,
+    primary key (blob_id, pos)
+);
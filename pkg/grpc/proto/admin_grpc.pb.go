@@ -0,0 +1,257 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.21.12
+// source: proto/admin.proto
+
+package gophkeeper
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// AdminClient is the client API for Admin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AdminClient interface {
+	// CollectGarbage runs garbage collection right away and waits for it to finish.
+	CollectGarbage(ctx context.Context, in *GarbageCollectionRequest, opts ...grpc.CallOption) (*GarbageCollectionStats, error)
+	// GarbageCollectionStatus returns statistics of the last garbage collection run.
+	GarbageCollectionStatus(ctx context.Context, in *GarbageCollectionRequest, opts ...grpc.CallOption) (*GarbageCollectionStats, error)
+	GetQuota(ctx context.Context, in *QuotaRequest, opts ...grpc.CallOption) (*Quota, error)
+	// SetQuota overrides the default quota of a user.
+	SetQuota(ctx context.Context, in *Quota, opts ...grpc.CallOption) (*Quota, error)
+	// ResetQuota brings back the default quota of a user.
+	ResetQuota(ctx context.Context, in *QuotaRequest, opts ...grpc.CallOption) (*Quota, error)
+}
+
+type adminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminClient(cc grpc.ClientConnInterface) AdminClient {
+	return &adminClient{cc}
+}
+
+func (c *adminClient) CollectGarbage(ctx context.Context, in *GarbageCollectionRequest, opts ...grpc.CallOption) (*GarbageCollectionStats, error) {
+	out := new(GarbageCollectionStats)
+	err := c.cc.Invoke(ctx, "/gophkeeper.Admin/CollectGarbage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) GarbageCollectionStatus(ctx context.Context, in *GarbageCollectionRequest, opts ...grpc.CallOption) (*GarbageCollectionStats, error) {
+	out := new(GarbageCollectionStats)
+	err := c.cc.Invoke(ctx, "/gophkeeper.Admin/GarbageCollectionStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) GetQuota(ctx context.Context, in *QuotaRequest, opts ...grpc.CallOption) (*Quota, error) {
+	out := new(Quota)
+	err := c.cc.Invoke(ctx, "/gophkeeper.Admin/GetQuota", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) SetQuota(ctx context.Context, in *Quota, opts ...grpc.CallOption) (*Quota, error) {
+	out := new(Quota)
+	err := c.cc.Invoke(ctx, "/gophkeeper.Admin/SetQuota", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ResetQuota(ctx context.Context, in *QuotaRequest, opts ...grpc.CallOption) (*Quota, error) {
+	out := new(Quota)
+	err := c.cc.Invoke(ctx, "/gophkeeper.Admin/ResetQuota", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminServer is the server API for Admin service.
+// All implementations must embed UnimplementedAdminServer
+// for forward compatibility
+type AdminServer interface {
+	// CollectGarbage runs garbage collection right away and waits for it to finish.
+	CollectGarbage(context.Context, *GarbageCollectionRequest) (*GarbageCollectionStats, error)
+	// GarbageCollectionStatus returns statistics of the last garbage collection run.
+	GarbageCollectionStatus(context.Context, *GarbageCollectionRequest) (*GarbageCollectionStats, error)
+	GetQuota(context.Context, *QuotaRequest) (*Quota, error)
+	// SetQuota overrides the default quota of a user.
+	SetQuota(context.Context, *Quota) (*Quota, error)
+	// ResetQuota brings back the default quota of a user.
+	ResetQuota(context.Context, *QuotaRequest) (*Quota, error)
+	mustEmbedUnimplementedAdminServer()
+}
+
+// UnimplementedAdminServer must be embedded to have forward compatible implementations.
+type UnimplementedAdminServer struct {
+}
+
+func (UnimplementedAdminServer) CollectGarbage(context.Context, *GarbageCollectionRequest) (*GarbageCollectionStats, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CollectGarbage not implemented")
+}
+func (UnimplementedAdminServer) GarbageCollectionStatus(context.Context, *GarbageCollectionRequest) (*GarbageCollectionStats, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GarbageCollectionStatus not implemented")
+}
+func (UnimplementedAdminServer) GetQuota(context.Context, *QuotaRequest) (*Quota, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetQuota not implemented")
+}
+func (UnimplementedAdminServer) SetQuota(context.Context, *Quota) (*Quota, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetQuota not implemented")
+}
+func (UnimplementedAdminServer) ResetQuota(context.Context, *QuotaRequest) (*Quota, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResetQuota not implemented")
+}
+func (UnimplementedAdminServer) mustEmbedUnimplementedAdminServer() {}
+
+// UnsafeAdminServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdminServer will
+// result in compilation errors.
+type UnsafeAdminServer interface {
+	mustEmbedUnimplementedAdminServer()
+}
+
+func RegisterAdminServer(s grpc.ServiceRegistrar, srv AdminServer) {
+	s.RegisterService(&Admin_ServiceDesc, srv)
+}
+
+func _Admin_CollectGarbage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GarbageCollectionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).CollectGarbage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gophkeeper.Admin/CollectGarbage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).CollectGarbage(ctx, req.(*GarbageCollectionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_GarbageCollectionStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GarbageCollectionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).GarbageCollectionStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gophkeeper.Admin/GarbageCollectionStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).GarbageCollectionStatus(ctx, req.(*GarbageCollectionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_GetQuota_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QuotaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).GetQuota(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gophkeeper.Admin/GetQuota",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).GetQuota(ctx, req.(*QuotaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_SetQuota_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Quota)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).SetQuota(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gophkeeper.Admin/SetQuota",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).SetQuota(ctx, req.(*Quota))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_ResetQuota_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QuotaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ResetQuota(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gophkeeper.Admin/ResetQuota",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ResetQuota(ctx, req.(*QuotaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Admin_ServiceDesc is the grpc.ServiceDesc for Admin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Admin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "gophkeeper.Admin",
+	HandlerType: (*AdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CollectGarbage",
+			Handler:    _Admin_CollectGarbage_Handler,
+		},
+		{
+			MethodName: "GarbageCollectionStatus",
+			Handler:    _Admin_GarbageCollectionStatus_Handler,
+		},
+		{
+			MethodName: "GetQuota",
+			Handler:    _Admin_GetQuota_Handler,
+		},
+		{
+			MethodName: "SetQuota",
+			Handler:    _Admin_SetQuota_Handler,
+		},
+		{
+			MethodName: "ResetQuota",
+			Handler:    _Admin_ResetQuota_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/admin.proto",
+}
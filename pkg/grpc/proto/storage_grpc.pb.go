@@ -22,10 +22,30 @@ const _ = grpc.SupportPackageIsVersion7
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type StorageClient interface {
+	// List streams all resources matching the filters of a request, page
+	// size and token are ignored. ListPage returns them page by page.
 	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (Storage_ListClient, error)
+	ListPage(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (Storage_ListPageClient, error)
 	Add(ctx context.Context, opts ...grpc.CallOption) (Storage_AddClient, error)
-	Get(ctx context.Context, in *Resource, opts ...grpc.CallOption) (Storage_GetClient, error)
+	Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (Storage_GetClient, error)
 	Delete(ctx context.Context, in *Resource, opts ...grpc.CallOption) (*ResourceOperationResponse, error)
+	Stat(ctx context.Context, in *Resource, opts ...grpc.CallOption) (*Resource, error)
+	// Batch runs many operations in a single transaction.
+	Batch(ctx context.Context, in *BatchRequest, opts ...grpc.CallOption) (*BatchResponse, error)
+	CreateUpload(ctx context.Context, in *CreateUploadRequest, opts ...grpc.CallOption) (*UploadSession, error)
+	GetUpload(ctx context.Context, in *UploadSessionRequest, opts ...grpc.CallOption) (*UploadSession, error)
+	AppendUpload(ctx context.Context, opts ...grpc.CallOption) (Storage_AppendUploadClient, error)
+	FinalizeUpload(ctx context.Context, in *UploadSessionRequest, opts ...grpc.CallOption) (*ResourceOperationResponse, error)
+	MissingChunks(ctx context.Context, in *ChunkList, opts ...grpc.CallOption) (*ChunkList, error)
+	PutChunks(ctx context.Context, opts ...grpc.CallOption) (Storage_PutChunksClient, error)
+	AddChunked(ctx context.Context, in *AddChunkedRequest, opts ...grpc.CallOption) (*ResourceOperationResponse, error)
+	// ListDeleted lists deleted resources which can still be restored.
+	ListDeleted(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (Storage_ListDeletedClient, error)
+	Restore(ctx context.Context, in *Resource, opts ...grpc.CallOption) (*Resource, error)
+	// EmptyTrash removes all deleted resources of a user for good.
+	EmptyTrash(ctx context.Context, in *EmptyTrashRequest, opts ...grpc.CallOption) (*EmptyTrashResponse, error)
+	// Usage reports storage consumed by a user and the user's quota.
+	Usage(ctx context.Context, in *UsageRequest, opts ...grpc.CallOption) (*UsageResponse, error)
 }
 
 type storageClient struct {
@@ -68,8 +88,40 @@ func (x *storageListClient) Recv() (*Resource, error) {
 	return m, nil
 }
 
+func (c *storageClient) ListPage(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (Storage_ListPageClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Storage_ServiceDesc.Streams[1], "/gophkeeper.Storage/ListPage", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &storageListPageClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Storage_ListPageClient interface {
+	Recv() (*ListResponse, error)
+	grpc.ClientStream
+}
+
+type storageListPageClient struct {
+	grpc.ClientStream
+}
+
+func (x *storageListPageClient) Recv() (*ListResponse, error) {
+	m := new(ListResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *storageClient) Add(ctx context.Context, opts ...grpc.CallOption) (Storage_AddClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Storage_ServiceDesc.Streams[1], "/gophkeeper.Storage/Add", opts...)
+	stream, err := c.cc.NewStream(ctx, &Storage_ServiceDesc.Streams[2], "/gophkeeper.Storage/Add", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -102,8 +154,8 @@ func (x *storageAddClient) CloseAndRecv() (*ResourceOperationResponse, error) {
 	return m, nil
 }
 
-func (c *storageClient) Get(ctx context.Context, in *Resource, opts ...grpc.CallOption) (Storage_GetClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Storage_ServiceDesc.Streams[2], "/gophkeeper.Storage/Get", opts...)
+func (c *storageClient) Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (Storage_GetClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Storage_ServiceDesc.Streams[3], "/gophkeeper.Storage/Get", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -143,14 +195,224 @@ func (c *storageClient) Delete(ctx context.Context, in *Resource, opts ...grpc.C
 	return out, nil
 }
 
+func (c *storageClient) Stat(ctx context.Context, in *Resource, opts ...grpc.CallOption) (*Resource, error) {
+	out := new(Resource)
+	err := c.cc.Invoke(ctx, "/gophkeeper.Storage/Stat", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) Batch(ctx context.Context, in *BatchRequest, opts ...grpc.CallOption) (*BatchResponse, error) {
+	out := new(BatchResponse)
+	err := c.cc.Invoke(ctx, "/gophkeeper.Storage/Batch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) CreateUpload(ctx context.Context, in *CreateUploadRequest, opts ...grpc.CallOption) (*UploadSession, error) {
+	out := new(UploadSession)
+	err := c.cc.Invoke(ctx, "/gophkeeper.Storage/CreateUpload", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) GetUpload(ctx context.Context, in *UploadSessionRequest, opts ...grpc.CallOption) (*UploadSession, error) {
+	out := new(UploadSession)
+	err := c.cc.Invoke(ctx, "/gophkeeper.Storage/GetUpload", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) AppendUpload(ctx context.Context, opts ...grpc.CallOption) (Storage_AppendUploadClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Storage_ServiceDesc.Streams[4], "/gophkeeper.Storage/AppendUpload", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &storageAppendUploadClient{stream}
+	return x, nil
+}
+
+type Storage_AppendUploadClient interface {
+	Send(*UploadChunk) error
+	CloseAndRecv() (*UploadSession, error)
+	grpc.ClientStream
+}
+
+type storageAppendUploadClient struct {
+	grpc.ClientStream
+}
+
+func (x *storageAppendUploadClient) Send(m *UploadChunk) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *storageAppendUploadClient) CloseAndRecv() (*UploadSession, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(UploadSession)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *storageClient) FinalizeUpload(ctx context.Context, in *UploadSessionRequest, opts ...grpc.CallOption) (*ResourceOperationResponse, error) {
+	out := new(ResourceOperationResponse)
+	err := c.cc.Invoke(ctx, "/gophkeeper.Storage/FinalizeUpload", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) MissingChunks(ctx context.Context, in *ChunkList, opts ...grpc.CallOption) (*ChunkList, error) {
+	out := new(ChunkList)
+	err := c.cc.Invoke(ctx, "/gophkeeper.Storage/MissingChunks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) PutChunks(ctx context.Context, opts ...grpc.CallOption) (Storage_PutChunksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Storage_ServiceDesc.Streams[5], "/gophkeeper.Storage/PutChunks", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &storagePutChunksClient{stream}
+	return x, nil
+}
+
+type Storage_PutChunksClient interface {
+	Send(*Chunk) error
+	CloseAndRecv() (*PutChunksResponse, error)
+	grpc.ClientStream
+}
+
+type storagePutChunksClient struct {
+	grpc.ClientStream
+}
+
+func (x *storagePutChunksClient) Send(m *Chunk) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *storagePutChunksClient) CloseAndRecv() (*PutChunksResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(PutChunksResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *storageClient) AddChunked(ctx context.Context, in *AddChunkedRequest, opts ...grpc.CallOption) (*ResourceOperationResponse, error) {
+	out := new(ResourceOperationResponse)
+	err := c.cc.Invoke(ctx, "/gophkeeper.Storage/AddChunked", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) ListDeleted(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (Storage_ListDeletedClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Storage_ServiceDesc.Streams[6], "/gophkeeper.Storage/ListDeleted", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &storageListDeletedClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Storage_ListDeletedClient interface {
+	Recv() (*ListResponse, error)
+	grpc.ClientStream
+}
+
+type storageListDeletedClient struct {
+	grpc.ClientStream
+}
+
+func (x *storageListDeletedClient) Recv() (*ListResponse, error) {
+	m := new(ListResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *storageClient) Restore(ctx context.Context, in *Resource, opts ...grpc.CallOption) (*Resource, error) {
+	out := new(Resource)
+	err := c.cc.Invoke(ctx, "/gophkeeper.Storage/Restore", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) EmptyTrash(ctx context.Context, in *EmptyTrashRequest, opts ...grpc.CallOption) (*EmptyTrashResponse, error) {
+	out := new(EmptyTrashResponse)
+	err := c.cc.Invoke(ctx, "/gophkeeper.Storage/EmptyTrash", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) Usage(ctx context.Context, in *UsageRequest, opts ...grpc.CallOption) (*UsageResponse, error) {
+	out := new(UsageResponse)
+	err := c.cc.Invoke(ctx, "/gophkeeper.Storage/Usage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // StorageServer is the server API for Storage service.
 // All implementations must embed UnimplementedStorageServer
 // for forward compatibility
 type StorageServer interface {
+	// List streams all resources matching the filters of a request, page
+	// size and token are ignored. ListPage returns them page by page.
 	List(*ListRequest, Storage_ListServer) error
+	ListPage(*ListRequest, Storage_ListPageServer) error
 	Add(Storage_AddServer) error
-	Get(*Resource, Storage_GetServer) error
+	Get(*GetRequest, Storage_GetServer) error
 	Delete(context.Context, *Resource) (*ResourceOperationResponse, error)
+	Stat(context.Context, *Resource) (*Resource, error)
+	// Batch runs many operations in a single transaction.
+	Batch(context.Context, *BatchRequest) (*BatchResponse, error)
+	CreateUpload(context.Context, *CreateUploadRequest) (*UploadSession, error)
+	GetUpload(context.Context, *UploadSessionRequest) (*UploadSession, error)
+	AppendUpload(Storage_AppendUploadServer) error
+	FinalizeUpload(context.Context, *UploadSessionRequest) (*ResourceOperationResponse, error)
+	MissingChunks(context.Context, *ChunkList) (*ChunkList, error)
+	PutChunks(Storage_PutChunksServer) error
+	AddChunked(context.Context, *AddChunkedRequest) (*ResourceOperationResponse, error)
+	// ListDeleted lists deleted resources which can still be restored.
+	ListDeleted(*ListRequest, Storage_ListDeletedServer) error
+	Restore(context.Context, *Resource) (*Resource, error)
+	// EmptyTrash removes all deleted resources of a user for good.
+	EmptyTrash(context.Context, *EmptyTrashRequest) (*EmptyTrashResponse, error)
+	// Usage reports storage consumed by a user and the user's quota.
+	Usage(context.Context, *UsageRequest) (*UsageResponse, error)
 	mustEmbedUnimplementedStorageServer()
 }
 
@@ -161,15 +423,57 @@ type UnimplementedStorageServer struct {
 func (UnimplementedStorageServer) List(*ListRequest, Storage_ListServer) error {
 	return status.Errorf(codes.Unimplemented, "method List not implemented")
 }
+func (UnimplementedStorageServer) ListPage(*ListRequest, Storage_ListPageServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListPage not implemented")
+}
 func (UnimplementedStorageServer) Add(Storage_AddServer) error {
 	return status.Errorf(codes.Unimplemented, "method Add not implemented")
 }
-func (UnimplementedStorageServer) Get(*Resource, Storage_GetServer) error {
+func (UnimplementedStorageServer) Get(*GetRequest, Storage_GetServer) error {
 	return status.Errorf(codes.Unimplemented, "method Get not implemented")
 }
 func (UnimplementedStorageServer) Delete(context.Context, *Resource) (*ResourceOperationResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
 }
+func (UnimplementedStorageServer) Stat(context.Context, *Resource) (*Resource, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Stat not implemented")
+}
+func (UnimplementedStorageServer) Batch(context.Context, *BatchRequest) (*BatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Batch not implemented")
+}
+func (UnimplementedStorageServer) CreateUpload(context.Context, *CreateUploadRequest) (*UploadSession, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateUpload not implemented")
+}
+func (UnimplementedStorageServer) GetUpload(context.Context, *UploadSessionRequest) (*UploadSession, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUpload not implemented")
+}
+func (UnimplementedStorageServer) AppendUpload(Storage_AppendUploadServer) error {
+	return status.Errorf(codes.Unimplemented, "method AppendUpload not implemented")
+}
+func (UnimplementedStorageServer) FinalizeUpload(context.Context, *UploadSessionRequest) (*ResourceOperationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FinalizeUpload not implemented")
+}
+func (UnimplementedStorageServer) MissingChunks(context.Context, *ChunkList) (*ChunkList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MissingChunks not implemented")
+}
+func (UnimplementedStorageServer) PutChunks(Storage_PutChunksServer) error {
+	return status.Errorf(codes.Unimplemented, "method PutChunks not implemented")
+}
+func (UnimplementedStorageServer) AddChunked(context.Context, *AddChunkedRequest) (*ResourceOperationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddChunked not implemented")
+}
+func (UnimplementedStorageServer) ListDeleted(*ListRequest, Storage_ListDeletedServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListDeleted not implemented")
+}
+func (UnimplementedStorageServer) Restore(context.Context, *Resource) (*Resource, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Restore not implemented")
+}
+func (UnimplementedStorageServer) EmptyTrash(context.Context, *EmptyTrashRequest) (*EmptyTrashResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EmptyTrash not implemented")
+}
+func (UnimplementedStorageServer) Usage(context.Context, *UsageRequest) (*UsageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Usage not implemented")
+}
 func (UnimplementedStorageServer) mustEmbedUnimplementedStorageServer() {}
 
 // UnsafeStorageServer may be embedded to opt out of forward compatibility for this service.
@@ -204,6 +508,27 @@ func (x *storageListServer) Send(m *Resource) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Storage_ListPage_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StorageServer).ListPage(m, &storageListPageServer{stream})
+}
+
+type Storage_ListPageServer interface {
+	Send(*ListResponse) error
+	grpc.ServerStream
+}
+
+type storageListPageServer struct {
+	grpc.ServerStream
+}
+
+func (x *storageListPageServer) Send(m *ListResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _Storage_Add_Handler(srv interface{}, stream grpc.ServerStream) error {
 	return srv.(StorageServer).Add(&storageAddServer{stream})
 }
@@ -231,7 +556,7 @@ func (x *storageAddServer) Recv() (*ResourceOperationData, error) {
 }
 
 func _Storage_Get_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(Resource)
+	m := new(GetRequest)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
@@ -269,6 +594,259 @@ func _Storage_Delete_Handler(srv interface{}, ctx context.Context, dec func(inte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Storage_Stat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Resource)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).Stat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gophkeeper.Storage/Stat",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).Stat(ctx, req.(*Resource))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_Batch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).Batch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gophkeeper.Storage/Batch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).Batch(ctx, req.(*BatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_CreateUpload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateUploadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).CreateUpload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gophkeeper.Storage/CreateUpload",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).CreateUpload(ctx, req.(*CreateUploadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_GetUpload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UploadSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).GetUpload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gophkeeper.Storage/GetUpload",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).GetUpload(ctx, req.(*UploadSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_AppendUpload_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(StorageServer).AppendUpload(&storageAppendUploadServer{stream})
+}
+
+type Storage_AppendUploadServer interface {
+	SendAndClose(*UploadSession) error
+	Recv() (*UploadChunk, error)
+	grpc.ServerStream
+}
+
+type storageAppendUploadServer struct {
+	grpc.ServerStream
+}
+
+func (x *storageAppendUploadServer) SendAndClose(m *UploadSession) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *storageAppendUploadServer) Recv() (*UploadChunk, error) {
+	m := new(UploadChunk)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Storage_FinalizeUpload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UploadSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).FinalizeUpload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gophkeeper.Storage/FinalizeUpload",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).FinalizeUpload(ctx, req.(*UploadSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_MissingChunks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChunkList)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).MissingChunks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gophkeeper.Storage/MissingChunks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).MissingChunks(ctx, req.(*ChunkList))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_PutChunks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(StorageServer).PutChunks(&storagePutChunksServer{stream})
+}
+
+type Storage_PutChunksServer interface {
+	SendAndClose(*PutChunksResponse) error
+	Recv() (*Chunk, error)
+	grpc.ServerStream
+}
+
+type storagePutChunksServer struct {
+	grpc.ServerStream
+}
+
+func (x *storagePutChunksServer) SendAndClose(m *PutChunksResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *storagePutChunksServer) Recv() (*Chunk, error) {
+	m := new(Chunk)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Storage_AddChunked_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddChunkedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).AddChunked(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gophkeeper.Storage/AddChunked",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).AddChunked(ctx, req.(*AddChunkedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_ListDeleted_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StorageServer).ListDeleted(m, &storageListDeletedServer{stream})
+}
+
+type Storage_ListDeletedServer interface {
+	Send(*ListResponse) error
+	grpc.ServerStream
+}
+
+type storageListDeletedServer struct {
+	grpc.ServerStream
+}
+
+func (x *storageListDeletedServer) Send(m *ListResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Storage_Restore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Resource)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).Restore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gophkeeper.Storage/Restore",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).Restore(ctx, req.(*Resource))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_EmptyTrash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmptyTrashRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).EmptyTrash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gophkeeper.Storage/EmptyTrash",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).EmptyTrash(ctx, req.(*EmptyTrashRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_Usage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).Usage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gophkeeper.Storage/Usage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).Usage(ctx, req.(*UsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Storage_ServiceDesc is the grpc.ServiceDesc for Storage service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -280,6 +858,46 @@ var Storage_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Delete",
 			Handler:    _Storage_Delete_Handler,
 		},
+		{
+			MethodName: "Stat",
+			Handler:    _Storage_Stat_Handler,
+		},
+		{
+			MethodName: "Batch",
+			Handler:    _Storage_Batch_Handler,
+		},
+		{
+			MethodName: "CreateUpload",
+			Handler:    _Storage_CreateUpload_Handler,
+		},
+		{
+			MethodName: "GetUpload",
+			Handler:    _Storage_GetUpload_Handler,
+		},
+		{
+			MethodName: "FinalizeUpload",
+			Handler:    _Storage_FinalizeUpload_Handler,
+		},
+		{
+			MethodName: "MissingChunks",
+			Handler:    _Storage_MissingChunks_Handler,
+		},
+		{
+			MethodName: "AddChunked",
+			Handler:    _Storage_AddChunked_Handler,
+		},
+		{
+			MethodName: "Restore",
+			Handler:    _Storage_Restore_Handler,
+		},
+		{
+			MethodName: "EmptyTrash",
+			Handler:    _Storage_EmptyTrash_Handler,
+		},
+		{
+			MethodName: "Usage",
+			Handler:    _Storage_Usage_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -287,6 +905,11 @@ var Storage_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _Storage_List_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "ListPage",
+			Handler:       _Storage_ListPage_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "Add",
 			Handler:       _Storage_Add_Handler,
@@ -297,6 +920,21 @@ var Storage_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _Storage_Get_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "AppendUpload",
+			Handler:       _Storage_AppendUpload_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "PutChunks",
+			Handler:       _Storage_PutChunks_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "ListDeleted",
+			Handler:       _Storage_ListDeleted_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "proto/storage.proto",
 }